@@ -21,6 +21,7 @@ package tools
 
 import (
 	// Tool imports for go:generate.
+	_ "connectrpc.com/connect/cmd/protoc-gen-connect-go"
 	_ "github.com/fjl/gencodec"
 	_ "golang.org/x/tools/cmd/stringer"
 	_ "google.golang.org/protobuf/cmd/protoc-gen-go"
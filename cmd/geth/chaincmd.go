@@ -46,6 +46,16 @@ import (
 )
 
 var (
+	historyFormatFlag = &cli.StringFlag{
+		Name:  "format",
+		Usage: "Format of the history archives (era1 for pre-merge history from genesis, erae for any range)",
+		Value: "era1",
+	}
+	stateFileFlag = &cli.StringFlag{
+		Name:  "state-file",
+		Usage: "State file to initialize the state from, as written by 'geth snapshot export-state'",
+	}
+
 	initCommand = &cli.Command{
 		Action:    initGenesis,
 		Name:      "init",
@@ -55,13 +65,21 @@ var (
 			utils.CachePreimagesFlag,
 			utils.OverridePrague,
 			utils.OverrideVerkle,
+			stateFileFlag,
 		}, utils.DatabaseFlags),
 		Description: `
 The init command initializes a new genesis block and definition for the network.
 This is a destructive action and changes the network in which you will be
 participating.
 
-It expects the genesis file as argument.`,
+It expects the genesis file as argument.
+
+With --state-file, the state of a later block is also imported from a state file
+written by 'geth snapshot export-state', replacing any existing state. The block
+must already be present in the local chain, e.g. imported with
+'geth import-history --format erae'. The tries and the snapshot are regenerated
+from the file and the state root is checked against the block header, which then
+becomes the head.`,
 	}
 	dumpGenesisCommand = &cli.Command{
 		Action:    dumpGenesis,
@@ -142,10 +160,15 @@ be gzipped.`,
 		Name:      "import-history",
 		Usage:     "Import an Era archive",
 		ArgsUsage: "<dir>",
-		Flags:     slices.Concat([]cli.Flag{utils.TxLookupLimitFlag, utils.TransactionHistoryFlag}, utils.DatabaseFlags, utils.NetworkFlags),
+		Flags:     slices.Concat([]cli.Flag{utils.TxLookupLimitFlag, utils.TransactionHistoryFlag, historyFormatFlag}, utils.DatabaseFlags, utils.NetworkFlags),
 		Description: `
 The import-history command will import blocks and their corresponding receipts
-from Era archives.
+from Era archives. Each archive is verified against its checksum and the root
+in its file name before import.
+
+Era1 archives (--format era1) are imported starting from genesis. Execution era
+archives (--format erae) may start at any block up to the next one missing from
+the local chain, blocks which are already present are skipped.
 `,
 	}
 	exportHistoryCommand = &cli.Command{
@@ -153,10 +176,17 @@ from Era archives.
 		Name:      "export-history",
 		Usage:     "Export blockchain history to Era archives",
 		ArgsUsage: "<dir> <first> <last>",
-		Flags:     utils.DatabaseFlags,
+		Flags:     slices.Concat([]cli.Flag{historyFormatFlag}, utils.DatabaseFlags),
 		Description: `
 The export-history command will export blocks and their corresponding receipts
 into Era archives. Eras are typically packaged in steps of 8192 blocks.
+
+Era1 archives (--format era1) also record the total difficulty and are meant for
+the pre-merge history. Execution era archives (--format erae) can hold any range
+of blocks, the ones at the ends of the range may be partial. They only record
+the total difficulty and the accumulator for pre-merge blocks. The archive files
+are named after their accumulator root, or the hash of their last block if they
+have none, and listed in checksums.txt.
 `,
 	}
 	importPreimagesCommand = &cli.Command{
@@ -252,6 +282,11 @@ func initGenesis(ctx *cli.Context) error {
 	}
 	log.Info("Successfully wrote genesis state", "database", "chaindata", "hash", hash)
 
+	if ctx.IsSet(stateFileFlag.Name) {
+		if err := utils.ImportState(chaindb, triedb, ctx.String(stateFileFlag.Name)); err != nil {
+			utils.Fatalf("Failed to import state: %v", err)
+		}
+	}
 	return nil
 }
 
@@ -421,6 +456,10 @@ func importHistory(ctx *cli.Context) error {
 	chain, db := utils.MakeChain(ctx, stack, false)
 	defer db.Close()
 
+	format, err := era.ParseFormat(ctx.String(historyFormatFlag.Name))
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
 	var (
 		start   = time.Now()
 		dir     = ctx.Args().Get(0)
@@ -444,7 +483,7 @@ func importHistory(ctx *cli.Context) error {
 		// present in directory.
 		var networks []string
 		for _, n := range params.NetworkNames {
-			entries, err := era.ReadDir(dir, n)
+			entries, err := format.ReadDir(dir, n)
 			if err != nil {
 				return fmt.Errorf("error reading %s: %w", dir, err)
 			}
@@ -453,7 +492,7 @@ func importHistory(ctx *cli.Context) error {
 			}
 		}
 		if len(networks) == 0 {
-			return fmt.Errorf("no era1 files found in %s", dir)
+			return fmt.Errorf("no %s files found in %s", format, dir)
 		}
 		if len(networks) > 1 {
 			return errors.New("multiple networks found, use a network flag to specify desired network")
@@ -461,7 +500,7 @@ func importHistory(ctx *cli.Context) error {
 		network = networks[0]
 	}
 
-	if err := utils.ImportHistory(chain, dir, network); err != nil {
+	if err := utils.ImportHistory(chain, dir, network, format); err != nil {
 		return err
 	}
 	fmt.Printf("Import done in %v\n", time.Since(start))
@@ -474,7 +513,10 @@ func exportHistory(ctx *cli.Context) error {
 	if ctx.Args().Len() != 3 {
 		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
 	}
-
+	format, err := era.ParseFormat(ctx.String(historyFormatFlag.Name))
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
 
@@ -495,8 +537,10 @@ func exportHistory(ctx *cli.Context) error {
 	if head := chain.CurrentSnapBlock(); uint64(last) > head.Number.Uint64() {
 		utils.Fatalf("Export error: block number %d larger than head block %d\n", uint64(last), head.Number.Uint64())
 	}
-	err := utils.ExportHistory(chain, dir, uint64(first), uint64(last), uint64(era.MaxEra1Size))
-	if err != nil {
+	if first > last {
+		utils.Fatalf("Export error: first block %d larger than last block %d\n", first, last)
+	}
+	if err := utils.ExportHistory(chain, dir, uint64(first), uint64(last), uint64(era.MaxEra1Size), format); err != nil {
 		utils.Fatalf("Export error: %v\n", err)
 	}
 	fmt.Printf("Export done in %v\n", time.Since(start))
@@ -218,6 +218,14 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 	if ctx.IsSet(utils.GraphQLEnabledFlag.Name) {
 		utils.RegisterGraphQLService(stack, backend, filterSystem, &cfg.Node)
 	}
+	// Export the chain database if requested.
+	if ctx.Bool(utils.RemoteDBServeFlag.Name) {
+		utils.RegisterRemoteDBService(stack, eth.ChainDb(), false)
+	}
+	// Configure gRPC if requested.
+	if ctx.IsSet(utils.GRPCEnabledFlag.Name) {
+		utils.RegisterGRPCService(stack, backend, filterSystem, &cfg.Node)
+	}
 	// Add the Ethereum Stats daemon if requested.
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
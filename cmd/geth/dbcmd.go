@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -38,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
@@ -83,6 +85,10 @@ Remove blockchain and state databases`,
 			dbMetadataCmd,
 			dbCheckStateContentCmd,
 			dbInspectHistoryCmd,
+			dbVerifyCmd,
+			dbBackupCmd,
+			dbRestoreCmd,
+			dbServeCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -102,6 +108,49 @@ Remove blockchain and state databases`,
 		Description: `This command iterates the entire database for 32-byte keys, looking for rlp-encoded trie nodes.
 For each trie node encountered, it checks that the key corresponds to the keccak256(value). If this is not true, this indicates
 a data corruption.`,
+	}
+	dbVerifyRepairFlag = &cli.BoolFlag{
+		Name:  "repair",
+		Usage: "Fix the repairable inconsistencies found in the database",
+	}
+	dbVerifyCmd = &cli.Command{
+		Action: verifyDB,
+		Name:   "verify",
+		Usage:  "Verify the consistency of the chain data in the database",
+		Flags:  slices.Concat([]cli.Flag{dbVerifyRepairFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command walks the canonical chain from the head header and checks the
+canonical hash and header number mappings, the headers, bodies and receipts across
+the key-value store and the ancient store, the transaction index entries and the
+path-based state lookups.
+
+With --repair, the mappings and index entries derivable from the canonical chain
+are rewritten and the dangling entries are deleted. Missing chain data can't be
+recovered by this command.`,
+	}
+	dbBackupCmd = &cli.Command{
+		Action:    backupDB,
+		Name:      "backup",
+		ArgsUsage: "<dir>",
+		Usage:     "Create a consistent backup of the chain database",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command copies the chain database into the given directory: a checkpoint
+of the key-value store and the ancient stores up to the boundary frozen at that
+time. If the directory contains a previous backup, only the ancient data appended
+since is copied.
+
+Only the pebble database supports backups. The same backup can be created on a
+running node via admin.backup.`,
+	}
+	dbRestoreCmd = &cli.Command{
+		Action:    restoreDB,
+		Name:      "restore",
+		ArgsUsage: "<dir>",
+		Usage:     "Restore the chain database from a backup and verify it",
+		Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command copies a backup created by 'geth db backup' or admin.backup into the
+data directory, placing the ancient stores at --datadir.ancient if given. The chain
+database must not exist yet. The consistency of the restored database is verified
+the same way as by 'geth db verify'.`,
 	}
 	dbStatCmd = &cli.Command{
 		Action: dbStats,
@@ -179,6 +228,25 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		Flags:       slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "Exports the specified chain data to an RLP encoded stream, optionally gzip-compressed.",
 	}
+	dbServeCmd = &cli.Command{
+		Action: serveDB,
+		Name:   "serve",
+		Usage:  "Export the chain database over authenticated RPC",
+		Flags: slices.Concat(utils.NetworkFlags, utils.DatabaseFlags, []cli.Flag{
+			utils.AuthListenFlag,
+			utils.AuthPortFlag,
+			utils.AuthVirtualHostsFlag,
+			utils.JWTSecretFlag,
+			utils.RemoteDBWritableFlag,
+		}),
+		Description: `This command exports the chain database in the remotedb namespace on the
+authenticated RPC endpoint (--authrpc.*), without running a blockchain on it.
+Other nodes can attach to it with --remotedb and --remotedb.jwtsecret.
+
+Unlike --remotedb.serve on a running node, the database can be exported with
+--remotedb.writable, since the datadir lock guarantees that no local chain is
+operating on it.`,
+	}
 	dbMetadataCmd = &cli.Command{
 		Action:      showMetaData,
 		Name:        "metadata",
@@ -332,6 +400,74 @@ func inspect(ctx *cli.Context) error {
 	return rawdb.InspectDatabase(db, prefix, start)
 }
 
+func verifyDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	repair := ctx.Bool(dbVerifyRepairFlag.Name)
+	db := utils.MakeChainDatabase(ctx, stack, !repair)
+	defer db.Close()
+
+	return rawdb.VerifyDatabase(db, repair)
+}
+
+func backupDB(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	backupper, ok := db.(ethdb.Backupper)
+	if !ok {
+		return errors.New("database backup is not supported")
+	}
+	return backupper.Backup(ctx.Args().First())
+}
+
+func restoreDB(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	var (
+		dir      = ctx.Args().First()
+		kvsrc    = filepath.Join(dir, rawdb.BackupChainDataDir)
+		ancients = filepath.Join(dir, rawdb.BackupAncientDir)
+	)
+	if _, err := os.Stat(kvsrc); err != nil {
+		return fmt.Errorf("invalid backup directory: %v", err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		chaindata = stack.ResolvePath("chaindata")
+		ancient   = stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name))
+	)
+	if _, err := os.Stat(chaindata); err == nil {
+		return fmt.Errorf("chain database %s already exists", chaindata)
+	}
+	start := time.Now()
+	log.Info("Restoring chain database", "dir", chaindata, "ancient", ancient)
+	if err := os.CopyFS(chaindata, os.DirFS(kvsrc)); err != nil {
+		return err
+	}
+	if _, err := os.Stat(ancients); err == nil {
+		if err := os.CopyFS(ancient, os.DirFS(ancients)); err != nil {
+			return err
+		}
+	}
+	log.Info("Restored chain database", "elapsed", common.PrettyDuration(time.Since(start)))
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	return rawdb.VerifyDatabase(db, false)
+}
+
 func checkStateContent(ctx *cli.Context) error {
 	var (
 		prefix []byte
@@ -907,3 +1043,29 @@ func inspectHistory(ctx *cli.Context) error {
 	}
 	return inspectStorage(triedb, start, end, address, slot, ctx.Bool("raw"))
 }
+
+func serveDB(ctx *cli.Context) error {
+	if ctx.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", ctx.Args().Slice())
+	}
+	// Only the authenticated RPC endpoint is needed, keep networking off.
+	cfg := loadBaseConfig(ctx)
+	cfg.Node.P2P.ListenAddr = ""
+	cfg.Node.P2P.NoDiscovery = true
+	cfg.Node.P2P.MaxPeers = 0
+
+	stack, err := node.New(&cfg.Node)
+	if err != nil {
+		return err
+	}
+	defer stack.Close()
+
+	writable := ctx.Bool(utils.RemoteDBWritableFlag.Name)
+	db := utils.MakeChainDatabase(ctx, stack, !writable)
+	defer db.Close()
+
+	utils.RegisterRemoteDBService(stack, db, writable)
+	utils.StartNode(ctx, stack, false)
+	stack.Wait()
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
@@ -148,6 +149,22 @@ as the backend data source, making this command a lot faster.
 
 The argument is interpreted as block number or hash. If none is provided, the latest
 block is used.
+`,
+			},
+			{
+				Name:      "export-state",
+				Usage:     "Export the state of a block into a state file",
+				ArgsUsage: "<file> [<blockHash> | <blockNum>]",
+				Action:    snapshotExportState,
+				Flags:     slices.Concat(utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+The export-state command writes the flat state (accounts, storage and codes) of
+a block into a compact state file, using the snapshot as the data source. The
+file ends with a manifest listing the hashes of its chunks, and can be imported
+into a new node with 'geth init --state-file'.
+
+The block is interpreted as block number or hash. If none is provided, the head
+block is used. If the file name ends with .gz, the output is gzipped.
 `,
 			},
 			{
@@ -661,6 +678,54 @@ func snapshotExportPreimages(ctx *cli.Context) error {
 	return utils.ExportSnapshotPreimages(chaindb, snaptree, ctx.Args().First(), root)
 }
 
+// snapshotExportState exports the state of a block into a state file.
+func snapshotExportState(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		utils.Fatalf("This command requires one or two arguments.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	var header *types.Header
+	if ctx.NArg() > 1 {
+		arg := ctx.Args().Get(1)
+		if hashish(arg) {
+			hash := common.HexToHash(arg)
+			if number := rawdb.ReadHeaderNumber(chaindb, hash); number != nil {
+				header = rawdb.ReadHeader(chaindb, hash, *number)
+			}
+		} else {
+			number, err := strconv.ParseUint(arg, 10, 64)
+			if err != nil {
+				return err
+			}
+			header = rawdb.ReadHeader(chaindb, rawdb.ReadCanonicalHash(chaindb, number), number)
+		}
+	} else if block := rawdb.ReadHeadBlock(chaindb); block != nil {
+		header = block.Header()
+	}
+	if header == nil {
+		return errors.New("block not found")
+	}
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, true, false)
+	defer triedb.Close()
+
+	snapConfig := snapshot.Config{
+		CacheSize:  256,
+		Recovery:   false,
+		NoBuild:    true,
+		AsyncBuild: false,
+	}
+	snaptree, err := snapshot.New(snapConfig, chaindb, triedb, header.Root)
+	if err != nil {
+		return err
+	}
+	return utils.ExportState(chaindb, snaptree, ctx.Args().First(), header)
+}
+
 // checkAccount iterates the snap data layers, and looks up the given account
 // across all layers.
 func checkAccount(ctx *cli.Context) error {
@@ -25,6 +25,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"os"
 	"os/signal"
@@ -48,6 +49,8 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/urfave/cli/v2"
 )
 
@@ -245,24 +248,34 @@ func readList(filename string) ([]string, error) {
 	return strings.Split(string(b), "\n"), nil
 }
 
-// ImportHistory imports Era1 files containing historical block information,
-// starting from genesis. The assumption is held that the provided chain
-// segment in Era1 file should all be canonical and verified.
-func ImportHistory(chain *core.BlockChain, dir string, network string) error {
-	if chain.CurrentSnapBlock().Number.BitLen() != 0 {
+// ImportHistory imports Era1 or execution era files containing historical block
+// information. Era1 archives are imported starting from genesis, execution era
+// archives may start at any block up to the next one missing from the local
+// chain. Every archive is checked against its checksum and accumulator, and
+// the bodies and receipts of the blocks against their headers, before import.
+func ImportHistory(chain *core.BlockChain, dir string, network string, format era.Format) error {
+	if format == era.FormatEra1 && chain.CurrentSnapBlock().Number.BitLen() != 0 {
 		return errors.New("history import only supported when starting from genesis")
 	}
-	entries, err := era.ReadDir(dir, network)
+	entries, err := format.ReadDir(dir, network)
 	if err != nil {
 		return fmt.Errorf("error reading %s: %w", dir, err)
 	}
+	// The checksums are mandatory, they are the only binding of the archives to
+	// the ones published by the exporter.
 	checksums, err := readList(filepath.Join(dir, "checksums.txt"))
 	if err != nil {
-		return fmt.Errorf("unable to read checksums.txt: %w", err)
+		return fmt.Errorf("unable to read checksums.txt, required for import: %w", err)
 	}
 	if len(checksums) != len(entries) {
 		return fmt.Errorf("expected equal number of checksums and entries, have: %d checksums, %d entries", len(checksums), len(entries))
 	}
+	// Era1 archives are written straight into the ancient store, the execution
+	// ones may start above the frozen items and are inserted into the active one.
+	var ancientLimit uint64
+	if format == era.FormatEra1 {
+		ancientLimit = math.MaxUint64
+	}
 	var (
 		start    = time.Now()
 		reported = time.Now()
@@ -288,11 +301,22 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 			h.Reset()
 			buf.Reset()
 
-			// Import all block data from Era1.
+			// Validate the accumulator against the block hashes.
 			e, err := era.From(f)
 			if err != nil {
 				return fmt.Errorf("error opening era: %w", err)
 			}
+			if e.Format() != format {
+				return fmt.Errorf("unexpected archive format in %s: have %s, want %s", filename, e.Format(), format)
+			}
+			hashes, err := verifyAccumulator(e, filename)
+			if err != nil {
+				return fmt.Errorf("invalid era %s: %w", filename, err)
+			}
+			if head := chain.CurrentSnapBlock().Number.Uint64(); e.Start() > head+1 {
+				return fmt.Errorf("missing history between %d and %d", head+1, e.Start())
+			}
+			// Import all block data from the archive.
 			it, err := era.NewIterator(e)
 			if err != nil {
 				return fmt.Errorf("error making era reader: %w", err)
@@ -302,14 +326,23 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 				if err != nil {
 					return fmt.Errorf("error reading block %d: %w", it.Number(), err)
 				}
-				if block.Number().BitLen() == 0 {
-					continue // skip genesis
+				if block.Hash() != hashes[it.Number()-e.Start()] {
+					return fmt.Errorf("block %d not covered by accumulator", it.Number())
+				}
+				if block.NumberU64() <= chain.CurrentSnapBlock().Number.Uint64() {
+					continue // skip genesis and already present blocks
+				}
+				if parent := chain.CurrentSnapBlock(); block.ParentHash() != parent.Hash() {
+					return fmt.Errorf("block %d not linked to local chain: parent %x, want %x", it.Number(), block.ParentHash(), parent.Hash())
 				}
 				receipts, err := it.Receipts()
 				if err != nil {
 					return fmt.Errorf("error reading receipts %d: %w", it.Number(), err)
 				}
-				if _, err := chain.InsertReceiptChain([]*types.Block{block}, []types.Receipts{receipts}, 2^64-1); err != nil {
+				if err := verifyBlockContent(block, receipts); err != nil {
+					return fmt.Errorf("invalid block %d: %w", it.Number(), err)
+				}
+				if _, err := chain.InsertReceiptChain([]*types.Block{block}, []types.Receipts{receipts}, ancientLimit); err != nil {
 					return fmt.Errorf("error inserting body %d: %w", it.Number(), err)
 				}
 				imported += 1
@@ -321,7 +354,7 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 					reported = time.Now()
 				}
 			}
-			return nil
+			return it.Error()
 		}()
 		if err != nil {
 			return err
@@ -331,6 +364,82 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 	return nil
 }
 
+// verifyAccumulator checks the headers of an archive against each other and
+// against the root embedded in the file name. The root is the accumulator of
+// archives storing the total difficulty, which is recomputed and checked against
+// the stored one, and the hash of the last block for execution era archives of
+// post-merge blocks. The hashes of the blocks are returned.
+func verifyAccumulator(e *era.Era, filename string) ([]common.Hash, error) {
+	var (
+		hashes []common.Hash
+		tds    []*big.Int
+		td     *big.Int
+		err    error
+	)
+	if e.HasTotalDifficulty() {
+		if td, err = e.InitialTD(); err != nil {
+			return nil, fmt.Errorf("error reading total difficulty: %w", err)
+		}
+	}
+	for n := e.Start(); n < e.Start()+e.Count(); n++ {
+		header, err := e.GetHeaderByNumber(n)
+		if err != nil {
+			return nil, fmt.Errorf("error reading header %d: %w", n, err)
+		}
+		if len(hashes) > 0 && header.ParentHash != hashes[len(hashes)-1] {
+			return nil, fmt.Errorf("header %d not linked to its parent", n)
+		}
+		hashes = append(hashes, header.Hash())
+		if td != nil {
+			td.Add(td, header.Difficulty)
+			tds = append(tds, new(big.Int).Set(td))
+		}
+	}
+	if len(hashes) == 0 {
+		return nil, errors.New("empty archive")
+	}
+	root := hashes[len(hashes)-1]
+	if td != nil {
+		if root, err = era.ComputeAccumulator(hashes, tds); err != nil {
+			return nil, fmt.Errorf("error computing accumulator: %w", err)
+		}
+		want, err := e.Accumulator()
+		if err != nil {
+			return nil, fmt.Errorf("error reading accumulator: %w", err)
+		}
+		if root != want {
+			return nil, fmt.Errorf("accumulator mismatch: have %s, want %s", root, want)
+		}
+	}
+	if parts := strings.Split(filename, "-"); len(parts) != 3 || !strings.HasPrefix(parts[2], root.Hex()[2:10]) {
+		return nil, fmt.Errorf("file name does not match root %s", root)
+	}
+	return hashes, nil
+}
+
+// verifyBlockContent checks the body and the receipts of a block against the
+// commitments in its header.
+func verifyBlockContent(block *types.Block, receipts types.Receipts) error {
+	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != block.TxHash() {
+		return fmt.Errorf("tx root mismatch: have %x, want %x", hash, block.TxHash())
+	}
+	if hash := types.CalcUncleHash(block.Uncles()); hash != block.UncleHash() {
+		return fmt.Errorf("uncle root mismatch: have %x, want %x", hash, block.UncleHash())
+	}
+	if want := block.Header().WithdrawalsHash; want != nil {
+		if block.Withdrawals() == nil {
+			return errors.New("missing withdrawals")
+		}
+		if hash := types.DeriveSha(block.Withdrawals(), trie.NewStackTrie(nil)); hash != *want {
+			return fmt.Errorf("withdrawals root mismatch: have %x, want %x", hash, *want)
+		}
+	}
+	if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != block.ReceiptHash() {
+		return fmt.Errorf("receipt root mismatch: have %x, want %x", hash, block.ReceiptHash())
+	}
+	return nil
+}
+
 func missingBlocks(chain *core.BlockChain, blocks []*types.Block) []*types.Block {
 	head := chain.CurrentBlock()
 	for i, block := range blocks {
@@ -401,9 +510,10 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ExportHistory exports blockchain history into the specified directory,
-// following the Era format.
-func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) error {
-	log.Info("Exporting blockchain history", "dir", dir)
+// following the Era1 or execution era format. The archives are aligned to the
+// multiples of step, the ones at the ends of the range may be partial.
+func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64, format era.Format) error {
+	log.Info("Exporting blockchain history", "dir", dir, "format", format)
 	if head := bc.CurrentBlock().Number.Uint64(); head < last {
 		log.Warn("Last block beyond head, setting last = head", "head", head, "last", last)
 		last = head
@@ -422,25 +532,32 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 		buf       = bytes.NewBuffer(nil)
 		checksums []string
 	)
-	td := new(big.Int)
-	for i := uint64(0); i < first; i++ {
-		td.Add(td, bc.GetHeaderByNumber(i).Difficulty)
+	// The total difficulty is tracked by Era1 archives, and by the execution era
+	// archives starting before the merge.
+	var td *big.Int
+	initialTd := func(number uint64) *big.Int {
+		td := new(big.Int)
+		for i := uint64(0); i < number; i++ {
+			td.Add(td, bc.GetHeaderByNumber(i).Difficulty)
+		}
+		return td
 	}
-	for i := first; i <= last; i += step {
+	if format == era.FormatEra1 {
+		td = initialTd(first)
+	}
+	for i := first; i <= last; i = (i/step + 1) * step {
+		epoch := int(i / step)
 		err := func() error {
-			filename := filepath.Join(dir, era.Filename(network, int(i/step), common.Hash{}))
+			filename := filepath.Join(dir, format.Filename(network, epoch, common.Hash{}))
 			f, err := os.Create(filename)
 			if err != nil {
 				return fmt.Errorf("could not create era file: %w", err)
 			}
 			defer f.Close()
 
-			w := era.NewBuilder(f)
-			for j := uint64(0); j < step && j <= last-i; j++ {
-				var (
-					n     = i + j
-					block = bc.GetBlockByNumber(n)
-				)
+			w := era.NewBuilderWithFormat(f, format)
+			for n := i; n < uint64(epoch+1)*step && n <= last; n++ {
+				block := bc.GetBlockByNumber(n)
 				if block == nil {
 					return fmt.Errorf("export failed on #%d: not found", n)
 				}
@@ -448,19 +565,31 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 				if receipts == nil {
 					return fmt.Errorf("export failed on #%d: receipts not found", n)
 				}
-				td.Add(td, block.Difficulty())
-				if err := w.Add(block, receipts, new(big.Int).Set(td)); err != nil {
+				if format == era.FormatEraE && n == i {
+					if block.Difficulty().Sign() == 0 {
+						td = nil
+					} else if td == nil {
+						td = initialTd(n)
+					}
+				}
+				var blockTd *big.Int
+				if td != nil {
+					td.Add(td, block.Difficulty())
+					blockTd = new(big.Int).Set(td)
+				}
+				if err := w.Add(block, receipts, blockTd); err != nil {
 					return err
 				}
 			}
 			root, err := w.Finalize()
 			if err != nil {
-				return fmt.Errorf("export failed to finalize %d: %w", step/i, err)
+				return fmt.Errorf("export failed to finalize %d: %w", epoch, err)
 			}
 			// Set correct filename with root.
-			os.Rename(filename, filepath.Join(dir, era.Filename(network, int(i/step), root)))
-
-			// Compute checksum of entire Era1.
+			if err := os.Rename(filename, filepath.Join(dir, format.Filename(network, epoch, root))); err != nil {
+				return err
+			}
+			// Compute checksum of entire archive.
 			if _, err := f.Seek(0, io.SeekStart); err != nil {
 				return err
 			}
@@ -481,8 +610,9 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 		}
 	}
 
-	os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(strings.Join(checksums, "\n")), os.ModePerm)
-
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(strings.Join(checksums, "\n")), os.ModePerm); err != nil {
+		return err
+	}
 	log.Info("Exported blockchain to", "dir", dir)
 
 	return nil
@@ -661,6 +791,124 @@ func ExportSnapshotPreimages(chaindb ethdb.Database, snaptree *snapshot.Tree, fn
 	return nil
 }
 
+// ExportState exports the state of the given block into a state file, using
+// the snapshot as the data source.
+func ExportState(chaindb ethdb.Database, snaptree *snapshot.Tree, fn string, header *types.Header) error {
+	log.Info("Exporting state", "file", fn, "number", header.Number, "root", header.Root)
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	// Enable gzip compressing if file name has gz suffix.
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		gz := gzip.NewWriter(writer)
+		defer gz.Close()
+		writer = gz
+	}
+	buf := bufio.NewWriter(writer)
+	defer buf.Flush()
+
+	manifest, err := snapshot.ExportState(buf, snaptree, chaindb, header.Number.Uint64(), header.Hash(), header.Root)
+	if err != nil {
+		return err
+	}
+	log.Info("Exported state", "file", fn, "chunks", len(manifest.Chunks))
+	return nil
+}
+
+// ImportState initializes the state of the database from a state file. The
+// block the state belongs to must be present in the local chain, its header
+// being used to check the state root. Any existing state is replaced and the
+// block is set as the head of the chain.
+func ImportState(chaindb ethdb.Database, triedb *triedb.Database, fn string) error {
+	if triedb.IsVerkle() {
+		return errors.New("state files are not supported in verkle mode")
+	}
+	log.Info("Importing state", "file", fn)
+
+	// Verify the file in a dry run before touching the database: the existing
+	// state is only wiped if the state of the file matches its root.
+	manifest, err := readStateFile(fn, snapshot.VerifyStateFile)
+	if err != nil {
+		return err
+	}
+	hash := rawdb.ReadCanonicalHash(chaindb, manifest.Number)
+	if hash != manifest.Hash {
+		return fmt.Errorf("block #%d [%x..] not found in the local chain, import the chain history first", manifest.Number, manifest.Hash[:4])
+	}
+	header := rawdb.ReadHeader(chaindb, hash, manifest.Number)
+	if header == nil || !rawdb.HasBody(chaindb, hash, manifest.Number) {
+		return fmt.Errorf("block #%d [%x..] missing from the local chain", manifest.Number, hash[:4])
+	}
+	if header.Root != manifest.Root {
+		return fmt.Errorf("state root mismatch with block #%d: have %x, want %x", manifest.Number, manifest.Root, header.Root)
+	}
+	// Wipe the existing flat state, and the trie nodes in path scheme, which
+	// would be mixed up with the imported state.
+	var (
+		hashScheme = triedb.Scheme() == rawdb.HashScheme
+		prefixes   = [][]byte{rawdb.SnapshotAccountPrefix, rawdb.SnapshotStoragePrefix}
+	)
+	if !hashScheme {
+		prefixes = append(prefixes, rawdb.TrieNodeAccountPrefix, rawdb.TrieNodeStoragePrefix)
+	}
+	for _, prefix := range prefixes {
+		end := []byte{prefix[0] + 1}
+		if err := rawdb.SafeDeleteRange(chaindb, prefix, end, hashScheme, func(bool) bool { return false }); err != nil {
+			return fmt.Errorf("failed to wipe state: %w", err)
+		}
+	}
+	// Import the state, regenerating and verifying the tries.
+	if _, err := readStateFile(fn, func(r io.Reader) (*snapshot.StateManifest, error) {
+		return snapshot.ImportState(r, chaindb, triedb.Scheme())
+	}); err != nil {
+		return err
+	}
+	if !hashScheme {
+		if err := triedb.Enable(header.Root); err != nil {
+			return err
+		}
+	}
+	// Set the block as the head of the chain, the headers and bodies beyond it
+	// are retained.
+	batch := chaindb.NewBatch()
+	rawdb.WriteHeadBlockHash(batch, hash)
+	if number := rawdb.ReadHeaderNumber(chaindb, rawdb.ReadHeadHeaderHash(chaindb)); number == nil || *number < manifest.Number {
+		rawdb.WriteHeadHeaderHash(batch, hash)
+	}
+	if number := rawdb.ReadHeaderNumber(chaindb, rawdb.ReadHeadFastBlockHash(chaindb)); number == nil || *number < manifest.Number {
+		rawdb.WriteHeadFastBlockHash(batch, hash)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Imported state", "number", manifest.Number, "hash", hash, "root", header.Root,
+		"accounts", manifest.Accounts, "slots", manifest.Slots, "codes", manifest.Codes)
+	return nil
+}
+
+// readStateFile opens a state file, potentially unwrapping the gzip stream,
+// and passes it to the given reader function.
+func readStateFile(fn string, read func(io.Reader) (*snapshot.StateManifest, error)) (*snapshot.StateManifest, error) {
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	return read(reader)
+}
+
 // exportHeader is used in the export/import flow. When we do an export,
 // the first element we output is the exportHeader.
 // Whenever a backwards-incompatible change is made, the Version header
@@ -42,7 +42,9 @@ import (
 	"github.com/ethereum/go-ethereum/common/fdlimit"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -55,9 +57,11 @@ import (
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
 	"github.com/ethereum/go-ethereum/ethdb/remotedb"
 	"github.com/ethereum/go-ethereum/ethstats"
 	"github.com/ethereum/go-ethereum/graphql"
+	"github.com/ethereum/go-ethereum/grpc"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/flags"
 	"github.com/ethereum/go-ethereum/log"
@@ -100,17 +104,38 @@ var (
 		Usage:    "URL for remote database",
 		Category: flags.LoggingCategory,
 	}
+	RemoteDBJWTSecretFlag = &flags.DirectoryFlag{
+		Name:     "remotedb.jwtsecret",
+		Usage:    "Path to a JWT secret to use for authenticating to the remote database",
+		Category: flags.LoggingCategory,
+	}
 	DBEngineFlag = &cli.StringFlag{
 		Name:     "db.engine",
 		Usage:    "Backing database implementation to use ('pebble' or 'leveldb')",
 		Value:    node.DefaultConfig.DBEngine,
 		Category: flags.EthCategory,
 	}
+	DBProfileFlag = &cli.StringFlag{
+		Name:     "db.profile",
+		Usage:    "Tuning profile of the pebble database ('default', 'validator', 'rpc' or 'archive')",
+		Value:    node.DefaultConfig.DBProfile,
+		Category: flags.EthCategory,
+	}
+	DBNamespacesFlag = &cli.BoolFlag{
+		Name:     "db.namespaces",
+		Usage:    "Store the transaction and log indices in separate pebble instances (permanent once enabled; the existing indices are moved on the first startup, which blocks until done)",
+		Category: flags.EthCategory,
+	}
 	AncientFlag = &flags.DirectoryFlag{
 		Name:     "datadir.ancient",
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
+	AncientRemoteFlag = &cli.StringFlag{
+		Name:     "datadir.ancient.remote",
+		Usage:    "URL of an S3 compatible bucket to offload the sealed ancient data files to (s3://<bucket>[/<prefix>][?endpoint=<url>&region=<region>])",
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -265,12 +290,34 @@ var (
 		Usage:    "Scheme to use for storing ethereum state ('hash' or 'path')",
 		Category: flags.StateCategory,
 	}
+	StateOnlinePruningFlag = &cli.BoolFlag{
+		Name:     "state.prune.online",
+		Usage:    "Prune the stale state in background while not syncing, only relevant in state.scheme=hash",
+		Category: flags.StateCategory,
+	}
+	StateOnlinePruningBloomSizeFlag = &cli.Uint64Flag{
+		Name:     "state.prune.online.bloomsize",
+		Usage:    "Megabytes of memory allocated to bloom-filter for online state pruning",
+		Value:    ethconfig.Defaults.StateOnlinePruningBloomSize,
+		Category: flags.StateCategory,
+	}
+	StateOnlinePruningIntervalFlag = &cli.DurationFlag{
+		Name:     "state.prune.online.interval",
+		Usage:    "Time interval between two online state pruning runs",
+		Value:    ethconfig.Defaults.StateOnlinePruningInterval,
+		Category: flags.StateCategory,
+	}
 	StateHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.state",
 		Usage:    "Number of recent blocks to retain state history for, only relevant in state.scheme=path (default = 90,000 blocks, 0 = entire chain)",
 		Value:    ethconfig.Defaults.StateHistory,
 		Category: flags.StateCategory,
 	}
+	StateHistoryIndexFlag = &cli.BoolFlag{
+		Name:     "history.state.index",
+		Usage:    "Index the state history to serve historical state within the --history.state window, only relevant in state.scheme=path",
+		Category: flags.StateCategory,
+	}
 	TransactionHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.transactions",
 		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
@@ -279,10 +326,16 @@ var (
 	}
 	ChainHistoryFlag = &cli.StringFlag{
 		Name:     "history.chain",
-		Usage:    `Blockchain history retention ("all" or "postmerge")`,
+		Usage:    `Blockchain history retention ("all", "postmerge" or "recent")`,
 		Value:    ethconfig.Defaults.HistoryMode.String(),
 		Category: flags.StateCategory,
 	}
+	ChainHistoryRetentionFlag = &cli.StringFlag{
+		Name:     "history.chain.retention",
+		Usage:    `Number of recent blocks to retain chain history for with --history.chain=recent, or days with a "d" suffix (e.g. "30d", minimum = 90,000 blocks)`,
+		Value:    strconv.FormatUint(ethconfig.Defaults.HistoryRetention, 10),
+		Category: flags.StateCategory,
+	}
 	LogHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.logs",
 		Usage:    "Number of recent blocks to maintain log search index for (default = about one year, 0 = entire chain)",
@@ -422,6 +475,27 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Lifetime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolAdmissionPluginFlag = &cli.StringFlag{
+		Name:     "txpool.admission.plugin",
+		Usage:    "Path of a Go plugin implementing the transaction admission policy",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAdmissionURLFlag = &cli.StringFlag{
+		Name:     "txpool.admission.url",
+		Usage:    "JSON-RPC endpoint of a transaction admission policy service",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAdmissionTimeoutFlag = &cli.DurationFlag{
+		Name:     "txpool.admission.timeout",
+		Usage:    "Maximum time to wait for the admission policy verdict on a transaction",
+		Value:    ethconfig.Defaults.TxPoolAdmission.Timeout,
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolAdmissionFailOpenFlag = &cli.BoolFlag{
+		Name:     "txpool.admission.failopen",
+		Usage:    "Accept transactions if the admission policy cannot be consulted (default = reject)",
+		Category: flags.TxPoolCategory,
+	}
 	// Blob transaction pool settings
 	BlobPoolDataDirFlag = &cli.StringFlag{
 		Name:     "blobpool.datadir",
@@ -579,6 +653,11 @@ var (
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	RPCResponseCacheFlag = &cli.IntFlag{
+		Name:     "rpc.responsecache",
+		Usage:    "Megabytes of memory allocated to caching responses of immutable historical queries (0 = disabled)",
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -603,6 +682,11 @@ var (
 		Usage:    "Path to a JWT secret to use for authenticated RPC endpoints",
 		Category: flags.APICategory,
 	}
+	RPCAPIKeysFlag = &flags.DirectoryFlag{
+		Name:     "rpc.apikeys",
+		Usage:    "Path to a JSON file of API keys required on the HTTP and WS endpoints (reloaded on change)",
+		Category: flags.APICategory,
+	}
 
 	// Logging and debug settings
 	EthStatsURLFlag = &cli.StringFlag{
@@ -676,6 +760,11 @@ var (
 		Value:    "",
 		Category: flags.APICategory,
 	}
+	HTTPSSEFlag = &cli.BoolFlag{
+		Name:     "http.sse",
+		Usage:    "Enable subscriptions as server-sent event streams on the HTTP-RPC server",
+		Category: flags.APICategory,
+	}
 	GraphQLEnabledFlag = &cli.BoolFlag{
 		Name:     "graphql",
 		Usage:    "Enable GraphQL on the HTTP-RPC server. Note that GraphQL can only be started if an HTTP server is started as well.",
@@ -693,6 +782,33 @@ var (
 		Value:    strings.Join(node.DefaultConfig.GraphQLVirtualHosts, ","),
 		Category: flags.APICategory,
 	}
+	GRPCEnabledFlag = &cli.BoolFlag{
+		Name:     "grpc",
+		Usage:    "Enable the gRPC/Connect eth API on the HTTP-RPC server. Note that gRPC can only be started if an HTTP server is started as well, and not together with API keys, rate limits or method filters.",
+		Category: flags.APICategory,
+	}
+	RemoteDBServeFlag = &cli.BoolFlag{
+		Name:     "remotedb.serve",
+		Usage:    "Export the chain database read-only in the remotedb namespace on the authenticated RPC endpoint",
+		Category: flags.APICategory,
+	}
+	RemoteDBWritableFlag = &cli.BoolFlag{
+		Name:     "remotedb.writable",
+		Usage:    "Accept writes to the database exported by 'geth db serve' (refused while the local chain is running)",
+		Category: flags.APICategory,
+	}
+	GRPCCORSDomainFlag = &cli.StringFlag{
+		Name:     "grpc.corsdomain",
+		Usage:    "Comma separated list of domains from which to accept cross origin gRPC-Web and Connect requests (browser enforced)",
+		Value:    "",
+		Category: flags.APICategory,
+	}
+	GRPCVirtualHostsFlag = &cli.StringFlag{
+		Name:     "grpc.vhosts",
+		Usage:    "Comma separated list of virtual hostnames from which to accept gRPC requests (server enforced). Accepts '*' wildcard.",
+		Value:    strings.Join(node.DefaultConfig.GRPCVirtualHosts, ","),
+		Category: flags.APICategory,
+	}
 	WSEnabledFlag = &cli.BoolFlag{
 		Name:     "ws",
 		Usage:    "Enable the WS-RPC server",
@@ -755,6 +871,16 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Maximum sustained number of calls per second per client on the HTTP and WS endpoints (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitBurstFlag = &cli.IntFlag{
+		Name:     "rpc.ratelimit.burst",
+		Usage:    "Maximum number of calls per client permitted in a burst above the rate limit",
+		Category: flags.APICategory,
+	}
 
 	// Network Settings
 	MaxPeersFlag = &cli.IntFlag{
@@ -977,8 +1103,12 @@ var (
 	DatabaseFlags = []cli.Flag{
 		DataDirFlag,
 		AncientFlag,
+		AncientRemoteFlag,
 		RemoteDBFlag,
+		RemoteDBJWTSecretFlag,
 		DBEngineFlag,
+		DBProfileFlag,
+		DBNamespacesFlag,
 		StateSchemeFlag,
 		HttpHeaderFlag,
 	}
@@ -1181,6 +1311,9 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(HTTPPathPrefixFlag.Name) {
 		cfg.HTTPPathPrefix = ctx.String(HTTPPathPrefixFlag.Name)
 	}
+	if ctx.IsSet(HTTPSSEFlag.Name) {
+		cfg.HTTPSSE = ctx.Bool(HTTPSSEFlag.Name)
+	}
 	if ctx.IsSet(AllowUnprotectedTxs.Name) {
 		cfg.AllowUnprotectedTxs = ctx.Bool(AllowUnprotectedTxs.Name)
 	}
@@ -1192,6 +1325,13 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+
+	if ctx.IsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCRateLimit.Default.Rate = ctx.Float64(RPCRateLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitBurstFlag.Name) {
+		cfg.RPCRateLimit.Default.Burst = ctx.Int(RPCRateLimitBurstFlag.Name)
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1205,6 +1345,16 @@ func setGraphQL(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setGRPC applies the gRPC related command line flags to the config.
+func setGRPC(ctx *cli.Context, cfg *node.Config) {
+	if ctx.IsSet(GRPCCORSDomainFlag.Name) {
+		cfg.GRPCCors = SplitAndTrim(ctx.String(GRPCCORSDomainFlag.Name))
+	}
+	if ctx.IsSet(GRPCVirtualHostsFlag.Name) {
+		cfg.GRPCVirtualHosts = SplitAndTrim(ctx.String(GRPCVirtualHostsFlag.Name))
+	}
+}
+
 // setWS creates the WebSocket RPC listener interface string from the set
 // command line flags, returning empty if the HTTP endpoint is disabled.
 func setWS(ctx *cli.Context, cfg *node.Config) {
@@ -1363,6 +1513,7 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
+	setGRPC(ctx, cfg)
 	setWS(ctx, cfg)
 	setNodeUserIdent(ctx, cfg)
 	SetDataDir(ctx, cfg)
@@ -1371,6 +1522,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(JWTSecretFlag.Name) {
 		cfg.JWTSecret = ctx.String(JWTSecretFlag.Name)
 	}
+	if ctx.IsSet(RPCAPIKeysFlag.Name) {
+		cfg.APIKeyFile = ctx.String(RPCAPIKeysFlag.Name)
+	}
 	if ctx.IsSet(EnablePersonal.Name) {
 		log.Warn(fmt.Sprintf("Option --%s is deprecated. The 'personal' RPC namespace has been removed.", EnablePersonal.Name))
 	}
@@ -1405,6 +1559,19 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 		log.Info(fmt.Sprintf("Using %s as db engine", dbEngine))
 		cfg.DBEngine = dbEngine
 	}
+	if ctx.IsSet(DBProfileFlag.Name) {
+		profile := ctx.String(DBProfileFlag.Name)
+		if _, err := pebble.ProfileByName(profile); err != nil {
+			Fatalf("Invalid choice for db.profile: %v", err)
+		}
+		cfg.DBProfile = profile
+	}
+	if ctx.IsSet(DBNamespacesFlag.Name) {
+		cfg.DBNamespaces = ctx.Bool(DBNamespacesFlag.Name)
+	}
+	if ctx.IsSet(AncientRemoteFlag.Name) {
+		cfg.AncientRemote = ctx.String(AncientRemoteFlag.Name)
+	}
 	// deprecation notice for log debug flags (TODO: find a more appropriate place to put these?)
 	if ctx.IsSet(LogBacktraceAtFlag.Name) {
 		log.Warn("log.backtrace flag is deprecated")
@@ -1507,6 +1674,21 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	}
 }
 
+func setTxPoolAdmission(ctx *cli.Context, cfg *txpool.AdmissionConfig) {
+	if ctx.IsSet(TxPoolAdmissionPluginFlag.Name) {
+		cfg.Plugin = ctx.String(TxPoolAdmissionPluginFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAdmissionURLFlag.Name) {
+		cfg.URL = ctx.String(TxPoolAdmissionURLFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAdmissionTimeoutFlag.Name) {
+		cfg.Timeout = ctx.Duration(TxPoolAdmissionTimeoutFlag.Name)
+	}
+	if ctx.IsSet(TxPoolAdmissionFailOpenFlag.Name) {
+		cfg.FailOpen = ctx.Bool(TxPoolAdmissionFailOpenFlag.Name)
+	}
+}
+
 func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
 	if ctx.IsSet(BlobPoolDataDirFlag.Name) {
 		cfg.Datadir = ctx.String(BlobPoolDataDirFlag.Name)
@@ -1579,6 +1761,7 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	setEtherbase(ctx, cfg)
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
+	setTxPoolAdmission(ctx, &cfg.TxPoolAdmission)
 	setBlobPool(ctx, &cfg.BlobPool)
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
@@ -1619,6 +1802,13 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			Fatalf("--%s: %v", ChainHistoryFlag.Name, err)
 		}
 	}
+	if ctx.IsSet(ChainHistoryRetentionFlag.Name) {
+		retention, err := history.ParseRetention(ctx.String(ChainHistoryRetentionFlag.Name))
+		if err != nil {
+			Fatalf("--%s: %v", ChainHistoryRetentionFlag.Name, err)
+		}
+		cfg.HistoryRetention = retention
+	}
 
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.Uint64(NetworkIdFlag.Name)
@@ -1649,9 +1839,21 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(StateHistoryIndexFlag.Name) {
+		cfg.StateHistoryIndex = ctx.Bool(StateHistoryIndexFlag.Name)
+	}
 	if ctx.IsSet(StateSchemeFlag.Name) {
 		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
 	}
+	if ctx.IsSet(StateOnlinePruningFlag.Name) {
+		cfg.StateOnlinePruning = ctx.Bool(StateOnlinePruningFlag.Name)
+	}
+	if ctx.IsSet(StateOnlinePruningBloomSizeFlag.Name) {
+		cfg.StateOnlinePruningBloomSize = ctx.Uint64(StateOnlinePruningBloomSizeFlag.Name)
+	}
+	if ctx.IsSet(StateOnlinePruningIntervalFlag.Name) {
+		cfg.StateOnlinePruningInterval = ctx.Duration(StateOnlinePruningIntervalFlag.Name)
+	}
 	// Parse transaction history flag, if user is still using legacy config
 	// file with 'TxLookupLimit' configured, copy the value to 'TransactionHistory'.
 	if cfg.TransactionHistory == ethconfig.Defaults.TransactionHistory && cfg.TxLookupLimit != ethconfig.Defaults.TxLookupLimit {
@@ -1730,6 +1932,9 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
 		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
 	}
+	if ctx.IsSet(RPCResponseCacheFlag.Name) {
+		cfg.RPCResponseCache = ctx.Int(RPCResponseCacheFlag.Name)
+	}
 	if ctx.IsSet(NoDiscoverFlag.Name) {
 		cfg.EthDiscoveryURLs, cfg.SnapDiscoveryURLs = []string{}, []string{}
 	} else if ctx.IsSet(DNSDiscoveryFlag.Name) {
@@ -1998,6 +2203,14 @@ func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSyst
 	}
 }
 
+// RegisterGRPCService adds the gRPC/Connect eth API to the node.
+func RegisterGRPCService(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cfg *node.Config) {
+	err := grpc.New(stack, backend, filterSystem, cfg.GRPCCors, cfg.GRPCVirtualHosts)
+	if err != nil {
+		Fatalf("Failed to register the gRPC service: %v", err)
+	}
+}
+
 // RegisterFilterAPI adds the eth log filtering RPC API to the node.
 func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconfig.Config) *filters.FilterSystem {
 	filterSystem := filters.NewFilterSystem(backend, filters.Config{
@@ -2010,6 +2223,18 @@ func RegisterFilterAPI(stack *node.Node, backend ethapi.Backend, ethcfg *ethconf
 	return filterSystem
 }
 
+// RegisterRemoteDBService exports the chain database in the remotedb namespace
+// on the authenticated RPC endpoint. Writes must only be enabled if no local
+// blockchain is operating on the same database.
+func RegisterRemoteDBService(stack *node.Node, db ethdb.Database, writable bool) {
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace:     "remotedb",
+		Service:       remotedb.NewAPI(db, writable),
+		Authenticated: true,
+	}})
+	log.Info("Exported chain database over authenticated RPC", "writable", writable)
+}
+
 // RegisterFullSyncTester adds the full-sync tester service into node.
 func RegisterFullSyncTester(stack *node.Node, eth *eth.Ethereum, target common.Hash) {
 	catalyst.RegisterFullSyncTester(stack, eth, target)
@@ -2094,7 +2319,15 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 	switch {
 	case ctx.IsSet(RemoteDBFlag.Name):
 		log.Info("Using remote db", "url", ctx.String(RemoteDBFlag.Name), "headers", len(ctx.StringSlice(HttpHeaderFlag.Name)))
-		client, err := DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), ctx.StringSlice(HttpHeaderFlag.Name))
+		var opts []rpc.ClientOption
+		if ctx.IsSet(RemoteDBJWTSecretFlag.Name) {
+			secret, err := node.LoadJWTSecret(ctx.String(RemoteDBJWTSecretFlag.Name))
+			if err != nil {
+				Fatalf("Could not load JWT secret: %v", err)
+			}
+			opts = append(opts, rpc.WithHTTPAuth(node.NewJWTAuth([32]byte(secret))))
+		}
+		client, err := DialRPCWithHeaders(ctx.String(RemoteDBFlag.Name), ctx.StringSlice(HttpHeaderFlag.Name), opts...)
 		if err != nil {
 			break
 		}
@@ -2130,7 +2363,7 @@ func IsNetworkPreset(ctx *cli.Context) bool {
 	return false
 }
 
-func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error) {
+func DialRPCWithHeaders(endpoint string, headers []string, opts ...rpc.ClientOption) (*rpc.Client, error) {
 	if endpoint == "" {
 		return nil, errors.New("endpoint must be specified")
 	}
@@ -2139,7 +2372,6 @@ func DialRPCWithHeaders(endpoint string, headers []string) (*rpc.Client, error)
 		// these prefixes.
 		endpoint = endpoint[4:]
 	}
-	var opts []rpc.ClientOption
 	if len(headers) > 0 {
 		customHeaders := make(http.Header)
 		for _, h := range headers {
@@ -2207,6 +2439,13 @@ func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*core.BlockCh
 		cache.Preimages = true
 		log.Info("Enabling recording of key preimages since archive mode is used")
 	}
+	// Open the chain with the configured history mode, but don't prune the
+	// chain history any further.
+	if ctx.IsSet(ChainHistoryFlag.Name) {
+		if err := cache.ChainHistoryMode.UnmarshalText([]byte(ctx.String(ChainHistoryFlag.Name))); err != nil {
+			Fatalf("--%s: %v", ChainHistoryFlag.Name, err)
+		}
+	}
 	if !ctx.Bool(SnapshotFlag.Name) {
 		cache.SnapshotLimit = 0 // Disabled
 	} else if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheSnapshotFlag.Name) {
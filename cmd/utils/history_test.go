@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"io"
+	"math"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -27,6 +28,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -44,19 +46,31 @@ var (
 	step  uint64 = 16
 )
 
-func TestHistoryImportAndExport(t *testing.T) {
+// newHistoryTestChain creates a chain of count blocks with transactions.
+func newHistoryTestChain(t *testing.T) (*core.BlockChain, *core.Genesis) {
+	return newMergingHistoryTestChain(t, math.MaxUint64)
+}
+
+// newMergingHistoryTestChain creates a chain of count blocks with transactions,
+// which are proof-of-stake blocks from the merge block on.
+func newMergingHistoryTestChain(t *testing.T, merge uint64) (*core.BlockChain, *core.Genesis) {
 	var (
 		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
 		address = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.TestChainConfig
 		genesis = &core.Genesis{
-			Config: params.TestChainConfig,
+			Config: &config,
 			Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(1000000000000000000)}},
 		}
 		signer = types.LatestSigner(genesis.Config)
+		engine = beacon.New(ethash.NewFaker())
 	)
 
 	// Generate chain.
-	db, blocks, _ := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), int(count), func(i int, g *core.BlockGen) {
+	db, blocks, _ := core.GenerateChainWithGenesis(genesis, engine, int(count), func(i int, g *core.BlockGen) {
+		if g.Number().Uint64() >= merge {
+			g.SetPoS()
+		}
 		if i == 0 {
 			return
 		}
@@ -77,20 +91,51 @@ func TestHistoryImportAndExport(t *testing.T) {
 		g.AddTx(tx)
 	})
 
+	// The terminal total difficulty is reached by the last proof-of-work block.
+	if merge <= count {
+		ttd := new(big.Int)
+		for _, block := range blocks[:merge-1] {
+			ttd.Add(ttd, block.Difficulty())
+		}
+		config.TerminalTotalDifficulty = ttd.Add(ttd, genesis.ToBlock().Difficulty())
+	}
 	// Initialize BlockChain.
-	chain, err := core.NewBlockChain(db, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, engine, vm.Config{}, nil)
 	if err != nil {
 		t.Fatalf("unable to initialize chain: %v", err)
 	}
 	if _, err := chain.InsertChain(blocks); err != nil {
 		t.Fatalf("error inserting chain: %v", err)
 	}
+	return chain, genesis
+}
+
+// newHistoryImportChain creates an empty chain to import history into.
+func newHistoryImportChain(t *testing.T, genesis *core.Genesis) *core.BlockChain {
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	if err != nil {
+		panic(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	genesis.MustCommit(db, triedb.NewDatabase(db, triedb.HashDefaults))
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("unable to initialize chain: %v", err)
+	}
+	return chain
+}
+
+func TestHistoryImportAndExport(t *testing.T) {
+	chain, genesis := newHistoryTestChain(t)
 
 	// Make temp directory for era files.
 	dir := t.TempDir()
 
 	// Export history to temp directory.
-	if err := ExportHistory(chain, dir, 0, count, step); err != nil {
+	if err := ExportHistory(chain, dir, 0, count, step, era.FormatEra1); err != nil {
 		t.Fatalf("error exporting history: %v", err)
 	}
 
@@ -158,23 +203,137 @@ func TestHistoryImportAndExport(t *testing.T) {
 	}
 
 	// Now import Era.
-	db2, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), "", "", false)
+	imported := newHistoryImportChain(t, genesis)
+	if err := ImportHistory(imported, dir, "mainnet", era.FormatEra1); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if have, want := imported.CurrentHeader(), chain.CurrentHeader(); have.Hash() != want.Hash() {
+		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
+	}
+}
+
+func TestHistoryImportAndExportRange(t *testing.T) {
+	chain, genesis := newHistoryTestChain(t)
+
+	// Export two overlapping ranges, not aligned to the epochs.
+	var (
+		head = t.TempDir()
+		tail = t.TempDir()
+	)
+	if err := ExportHistory(chain, head, 0, 50, step, era.FormatEraE); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	if err := ExportHistory(chain, tail, 40, count, step, era.FormatEraE); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	entries, err := era.FormatEraE.ReadDir(tail, "mainnet")
 	if err != nil {
-		panic(err)
+		t.Fatalf("error reading era dir: %v", err)
 	}
-	t.Cleanup(func() {
-		db2.Close()
-	})
+	if want := int(count/step - 40/step + 1); len(entries) != want {
+		t.Fatalf("era file count mismatch: have %d, want %d", len(entries), want)
+	}
+	e, err := era.Open(filepath.Join(tail, entries[0]))
+	if err != nil {
+		t.Fatalf("error opening era: %v", err)
+	}
+	if e.Start() != 40 || e.Count() != 8 {
+		t.Fatalf("partial era range mismatch: have %d+%d, want 40+8", e.Start(), e.Count())
+	}
+	e.Close()
 
-	genesis.MustCommit(db2, triedb.NewDatabase(db2, triedb.HashDefaults))
-	imported, err := core.NewBlockChain(db2, nil, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+	// The tail can't be imported without the blocks preceding it.
+	imported := newHistoryImportChain(t, genesis)
+	if err := ImportHistory(imported, tail, "mainnet", era.FormatEraE); err == nil {
+		t.Fatal("history with gap imported")
+	}
+	if err := ImportHistory(imported, head, "mainnet", era.FormatEraE); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	// Archives not matching the root in their name are rejected.
+	var (
+		name    = entries[0]
+		renamed = strings.Replace(name, name[len(name)-13:len(name)-5], "ffffffff", 1)
+	)
+	if err := os.Rename(filepath.Join(tail, name), filepath.Join(tail, renamed)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportHistory(imported, tail, "mainnet", era.FormatEraE); err == nil {
+		t.Fatal("history with mismatching root imported")
+	}
+	if err := os.Rename(filepath.Join(tail, renamed), filepath.Join(tail, name)); err != nil {
+		t.Fatal(err)
+	}
+	// Archives without checksums are rejected.
+	checksums := filepath.Join(tail, "checksums.txt")
+	if err := os.Rename(checksums, checksums+".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportHistory(imported, tail, "mainnet", era.FormatEraE); err == nil {
+		t.Fatal("history without checksums imported")
+	}
+	if err := os.Rename(checksums+".bak", checksums); err != nil {
+		t.Fatal(err)
+	}
+	if err := ImportHistory(imported, tail, "mainnet", era.FormatEraE); err != nil {
+		t.Fatalf("failed to import chain: %v", err)
+	}
+	if have, want := imported.CurrentHeader(), chain.CurrentHeader(); have.Hash() != want.Hash() {
+		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
+	}
+	for n := uint64(1); n <= count; n++ {
+		want := chain.GetBlockByNumber(n)
+		if receipts := imported.GetReceiptsByHash(want.Hash()); len(receipts) != len(want.Transactions()) {
+			t.Fatalf("receipts of block %d missing", n)
+		}
+	}
+}
+
+func TestHistoryImportAndExportMerge(t *testing.T) {
+	var (
+		merge          = uint64(72)
+		chain, genesis = newMergingHistoryTestChain(t, merge)
+		dir            = t.TempDir()
+	)
+	if block := chain.GetBlockByNumber(merge); block.Difficulty().Sign() != 0 {
+		t.Fatalf("block %d not merged", merge)
+	}
+	if err := ExportHistory(chain, dir, 0, count, step, era.FormatEraE); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	entries, err := era.FormatEraE.ReadDir(dir, "mainnet")
 	if err != nil {
-		t.Fatalf("unable to initialize chain: %v", err)
+		t.Fatalf("error reading era dir: %v", err)
 	}
-	if err := ImportHistory(imported, dir, "mainnet"); err != nil {
+	// Only the archives starting before the merge store the total difficulty,
+	// the others are named after their last block.
+	for i, name := range entries {
+		e, err := era.Open(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("error opening era: %v", err)
+		}
+		if want := e.Start() < merge; e.HasTotalDifficulty() != want {
+			t.Fatalf("archive %d: total difficulty presence mismatch: have %t, want %t", i, e.HasTotalDifficulty(), want)
+		}
+		if e.Start() >= merge {
+			last := chain.GetHeaderByNumber(e.Start() + e.Count() - 1).Hash()
+			if !strings.Contains(name, last.Hex()[2:10]) {
+				t.Fatalf("archive %d: name %s does not match last block %s", i, name, last)
+			}
+		}
+		e.Close()
+	}
+	imported := newHistoryImportChain(t, genesis)
+	if err := ImportHistory(imported, dir, "mainnet", era.FormatEraE); err != nil {
 		t.Fatalf("failed to import chain: %v", err)
 	}
 	if have, want := imported.CurrentHeader(), chain.CurrentHeader(); have.Hash() != want.Hash() {
 		t.Fatalf("imported chain does not match expected, have (%d, %s) want (%d, %s)", have.Number, have.Hash(), want.Number, want.Hash())
 	}
+	for n := uint64(1); n <= count; n++ {
+		want := chain.GetBlockByNumber(n)
+		if receipts := imported.GetReceiptsByHash(want.Hash()); len(receipts) != len(want.Transactions()) {
+			t.Fatalf("receipts of block %d missing", n)
+		}
+	}
 }
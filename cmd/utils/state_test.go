@@ -0,0 +1,120 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+	"github.com/ethereum/go-ethereum/internal/era"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+)
+
+// Tests that a node can be bootstrapped from the chain history and a state file
+// exported from another node.
+func TestStateImportAndExport(t *testing.T) {
+	chain, genesis := newHistoryTestChain(t)
+	defer chain.Stop()
+
+	var (
+		dir  = t.TempDir()
+		fn   = filepath.Join(t.TempDir(), "state.gz")
+		head = chain.CurrentBlock()
+	)
+	if err := ExportHistory(chain, dir, 0, count, step, era.FormatEraE); err != nil {
+		t.Fatalf("error exporting history: %v", err)
+	}
+	if err := ExportState(chain.StateCache().TrieDB().Disk(), chain.Snapshots(), fn, head); err != nil {
+		t.Fatalf("error exporting state: %v", err)
+	}
+	source, err := chain.StateAt(head.Root)
+	if err != nil {
+		t.Fatalf("error opening state: %v", err)
+	}
+	for _, scheme := range []string{rawdb.HashScheme, rawdb.PathScheme} {
+		// Use a persistent database, the state histories of the path scheme
+		// would be lost between the restarts otherwise.
+		datadir := t.TempDir()
+		pdb, err := pebble.New(datadir, 0, 0, "", false, true)
+		if err != nil {
+			t.Fatalf("error creating key-value database: %v", err)
+		}
+		db, err := rawdb.NewDatabaseWithFreezer(pdb, filepath.Join(datadir, "ancient"), "", false)
+		if err != nil {
+			t.Fatalf("error creating database: %v", err)
+		}
+		defer db.Close()
+
+		config := triedb.HashDefaults
+		if scheme == rawdb.PathScheme {
+			config = &triedb.Config{PathDB: pathdb.Defaults}
+		}
+		cacheConfig := core.DefaultCacheConfigWithScheme(scheme)
+
+		// The state can't be imported before the block is known locally
+		tdb := triedb.NewDatabase(db, config)
+		genesis.MustCommit(db, tdb)
+		if err := ImportState(db, tdb, fn); err == nil {
+			t.Fatalf("%s: state imported without chain history", scheme)
+		}
+		tdb.Close()
+
+		imported, err := core.NewBlockChain(db, cacheConfig, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+		if err != nil {
+			t.Fatalf("%s: unable to initialize chain: %v", scheme, err)
+		}
+		if err := ImportHistory(imported, dir, "mainnet", era.FormatEraE); err != nil {
+			t.Fatalf("%s: error importing history: %v", scheme, err)
+		}
+		imported.Stop()
+
+		tdb = triedb.NewDatabase(db, config)
+		if err := ImportState(db, tdb, fn); err != nil {
+			t.Fatalf("%s: error importing state: %v", scheme, err)
+		}
+		tdb.Close()
+
+		// Reopen the chain and check that the head state is available
+		imported, err = core.NewBlockChain(db, cacheConfig, genesis, nil, ethash.NewFaker(), vm.Config{}, nil)
+		if err != nil {
+			t.Fatalf("%s: unable to initialize chain: %v", scheme, err)
+		}
+		defer imported.Stop()
+
+		if have := imported.CurrentBlock(); have.Hash() != head.Hash() {
+			t.Fatalf("%s: head mismatch: have #%d [%x..], want #%d [%x..]", scheme, have.Number, have.Hash().Bytes()[:4], head.Number, head.Hash().Bytes()[:4])
+		}
+		if have := rawdb.ReadSnapshotRoot(db); have != head.Root {
+			t.Fatalf("%s: snapshot root mismatch: have %x, want %x", scheme, have, head.Root)
+		}
+		state, err := imported.StateAt(head.Root)
+		if err != nil {
+			t.Fatalf("%s: error opening imported state: %v", scheme, err)
+		}
+		recipient := common.Address{0xaa}
+		if have, want := state.GetBalance(recipient), source.GetBalance(recipient); have.Cmp(want) != 0 {
+			t.Fatalf("%s: balance mismatch: have %v, want %v", scheme, have, want)
+		}
+	}
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -111,6 +112,9 @@ const (
 	receiptsCacheLimit = 32
 	txLookupCacheLimit = 1024
 
+	onlinePruningBloomSize = 2048           // Default memory allowance (MB) for the bloom filter of online state pruning
+	onlinePruningInterval  = 24 * time.Hour // Default time interval between two online state pruning runs
+
 	// BlockChainVersion ensures that an incompatible database forces a resync from scratch.
 	//
 	// Changelog:
@@ -157,7 +161,12 @@ type CacheConfig struct {
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
 	StateHistory        uint64        // Number of blocks from head whose state histories are reserved.
+	StateHistoryIndex   bool          // Whether to index the state histories for serving historical state
 	StateScheme         string        // Scheme used to store ethereum states and merkle tree nodes on top
+	StateOnlinePruning  bool          // Whether to prune the stale state in background (hash scheme only)
+
+	StateOnlinePruningBloomSize uint64        // Memory allowance (MB) for the bloom filter of online state pruning
+	StateOnlinePruningInterval  time.Duration // Time interval between two online state pruning runs
 
 	SnapshotNoBuild bool // Whether the background generation is allowed
 	SnapshotWait    bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
@@ -165,6 +174,10 @@ type CacheConfig struct {
 	// This defines the cutoff block for history expiry.
 	// Blocks before this number may be unavailable in the chain database.
 	ChainHistoryMode history.HistoryMode
+
+	// This defines the number of recent blocks whose history is retained in the
+	// KeepRecent history mode. Zero means the history is not pruned any further.
+	ChainHistoryRetention uint64
 }
 
 // triedbConfig derives the configures for trie database.
@@ -180,9 +193,10 @@ func (c *CacheConfig) triedbConfig(isVerkle bool) *triedb.Config {
 	}
 	if c.StateScheme == rawdb.PathScheme {
 		config.PathDB = &pathdb.Config{
-			StateHistory:    c.StateHistory,
-			CleanCacheSize:  c.TrieCleanLimit * 1024 * 1024,
-			WriteBufferSize: c.TrieDirtyLimit * 1024 * 1024,
+			StateHistory:        c.StateHistory,
+			CleanCacheSize:      c.TrieCleanLimit * 1024 * 1024,
+			WriteBufferSize:     c.TrieDirtyLimit * 1024 * 1024,
+			EnableStateIndexing: c.StateHistoryIndex,
 		}
 	}
 	return config
@@ -241,6 +255,8 @@ type BlockChain struct {
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	statedb       *state.CachingDB                 // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
+	historyPruner *historyPruner                   // Chain history pruner, might be nil if not enabled
+	statePruner   *pruner.OnlinePruner             // Online state pruner, might be nil if not enabled
 
 	hc               *HeaderChain
 	rmLogsFeed       event.Feed
@@ -494,6 +510,34 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, genesis *Genesis
 	if txLookupLimit != nil {
 		bc.txIndexer = newTxIndexer(*txLookupLimit, bc)
 	}
+	// Start history pruner if the chain history is continuously pruned.
+	if cacheConfig.ChainHistoryMode == history.KeepRecent && cacheConfig.ChainHistoryRetention != 0 {
+		bc.historyPruner = newHistoryPruner(cacheConfig.ChainHistoryRetention, bc)
+	}
+	// Start online state pruner if it's enabled. It relies on the snapshot to
+	// track the recent states and is meaningless for archive nodes.
+	if cacheConfig.StateOnlinePruning {
+		switch {
+		case bc.triedb.Scheme() != rawdb.HashScheme:
+			log.Warn("Online state pruning is only supported in hash scheme")
+		case cacheConfig.TrieDirtyDisabled:
+			log.Warn("Online state pruning is not supported for archive node")
+		case bc.snaps == nil:
+			log.Warn("Online state pruning requires the snapshot")
+		default:
+			config := pruner.OnlineConfig{
+				BloomSize: cacheConfig.StateOnlinePruningBloomSize,
+				Interval:  cacheConfig.StateOnlinePruningInterval,
+			}
+			if config.BloomSize == 0 {
+				config.BloomSize = onlinePruningBloomSize
+			}
+			if config.Interval == 0 {
+				config.Interval = onlinePruningInterval
+			}
+			bc.statePruner = pruner.NewOnlinePruner(bc.db, bc.triedb, bc.snaps, config)
+		}
+	}
 	return bc, nil
 }
 
@@ -648,6 +692,17 @@ func (bc *BlockChain) initializeHistoryPruning(latest uint64) error {
 		bc.historyPrunePoint.Store(predefinedPoint)
 		return nil
 
+	case history.KeepRecent:
+		// The pruning point is moved along with the chain head, start from the
+		// current tail of the database.
+		if freezerTail > 0 {
+			bc.historyPrunePoint.Store(&history.PrunePoint{
+				BlockNumber: freezerTail,
+				BlockHash:   rawdb.ReadCanonicalHash(bc.db, freezerTail),
+			})
+		}
+		return nil
+
 	default:
 		return fmt.Errorf("invalid history mode: %d", bc.cacheConfig.ChainHistoryMode)
 	}
@@ -1184,6 +1239,14 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	// Signal shutdown history pruner.
+	if bc.historyPruner != nil {
+		bc.historyPruner.close()
+	}
+	// Signal shutdown online state pruner.
+	if bc.statePruner != nil {
+		bc.statePruner.Close()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
@@ -1200,6 +1263,14 @@ func (bc *BlockChain) stopWithoutSaving() {
 	bc.chainmu.Close()
 }
 
+// SetSyncing installs the function reporting whether the node is syncing, which
+// background maintenance such as the online state pruning is held back for.
+func (bc *BlockChain) SetSyncing(syncing func() bool) {
+	if bc.statePruner != nil {
+		bc.statePruner.SetSyncing(syncing)
+	}
+}
+
 // Stop stops the blockchain service. If any imports are currently in progress
 // it will abort them using the procInterrupt.
 func (bc *BlockChain) Stop() {
@@ -4390,3 +4390,73 @@ func testInsertChainWithCutoff(t *testing.T, cutoff uint64, ancientLimit uint64,
 		}
 	}
 }
+
+// Tests that the state no longer maintained by the path database can still be
+// accessed if the state histories are indexed.
+func TestHistoricState(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address   = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.Address{0xaa}
+		funds     = big.NewInt(params.Ether)
+		gspec     = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{address: {Balance: funds}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+		engine = ethash.NewFaker()
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 160, func(i int, block *BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(block.TxNonce(address), recipient, big.NewInt(int64(i+1)), params.TxGas, block.header.BaseFee, nil), signer, key)
+		if err != nil {
+			panic(err)
+		}
+		block.AddTx(tx)
+	})
+	db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	defer db.Close()
+
+	config := DefaultCacheConfigWithScheme(rawdb.PathScheme)
+	config.StateHistoryIndex = true
+	chain, err := NewBlockChain(db, config, gspec, nil, engine, vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("Failed to insert block %d: %v", n, err)
+	}
+	root := blocks[9].Root()
+	if _, err := chain.StateAt(root); err == nil {
+		t.Fatal("Stale state is still maintained")
+	}
+	// Wait until the state histories are indexed
+	var statedb *state.StateDB
+	for i := 0; ; i++ {
+		if statedb, err = chain.HistoricState(root); err == nil {
+			break
+		}
+		if i > 500 {
+			t.Fatalf("Failed to open historic state: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, db := range []*state.StateDB{statedb, statedb.Copy()} {
+		if balance := db.GetBalance(recipient); balance.Uint64() != 55 {
+			t.Fatalf("Unexpected balance, want: 55, got: %v", balance)
+		}
+		if nonce := db.GetNonce(address); nonce != 10 {
+			t.Fatalf("Unexpected nonce, want: 10, got: %d", nonce)
+		}
+		if db.Exist(common.Address{0xbb}) {
+			t.Fatal("Unexpected account")
+		}
+	}
+	// The historic state is read-only
+	statedb.AddBalance(recipient, uint256.NewInt(1), 0)
+	statedb.IntermediateRoot(true)
+	if statedb.Error() == nil {
+		t.Fatal("Historic state is modified")
+	}
+}
@@ -122,6 +122,20 @@ var (
 	// Message validation errors:
 	ErrEmptyAuthList   = errors.New("EIP-7702 transaction with empty auth list")
 	ErrSetCodeTxCreate = errors.New("EIP-7702 transaction cannot be used to create contract")
+
+	// -- Timed transaction errors --
+
+	// ErrTxNotYetValid is returned if a timed transaction is included in a
+	// block before its validity window opens.
+	ErrTxNotYetValid = types.ErrTxNotYetValid
+
+	// ErrTxExpired is returned if a timed transaction is included in a block
+	// after its validity window closed.
+	ErrTxExpired = types.ErrTxExpired
+
+	// ErrTxInvalidWindow is returned if a timed transaction specifies a
+	// validity window which closes before it opens.
+	ErrTxInvalidWindow = errors.New("transaction validity window closes before it opens")
 )
 
 // EIP-7702 state transition errors.
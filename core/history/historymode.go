@@ -18,6 +18,8 @@ package history
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
@@ -32,10 +34,18 @@ const (
 
 	// KeepPostMerge sets the history pruning point to the merge activation block.
 	KeepPostMerge
+
+	// KeepRecent continuously moves the history pruning point along with the
+	// chain head, retaining only a configured window of recent blocks.
+	KeepRecent
 )
 
+// BlocksPerDay is the number of blocks produced per day with 12 second slots,
+// used for converting history retention windows given in days.
+const BlocksPerDay = 24 * 60 * 60 / 12
+
 func (m HistoryMode) IsValid() bool {
-	return m <= KeepPostMerge
+	return m <= KeepRecent
 }
 
 func (m HistoryMode) String() string {
@@ -44,6 +54,8 @@ func (m HistoryMode) String() string {
 		return "all"
 	case KeepPostMerge:
 		return "postmerge"
+	case KeepRecent:
+		return "recent"
 	default:
 		return fmt.Sprintf("invalid HistoryMode(%d)", m)
 	}
@@ -64,12 +76,28 @@ func (m *HistoryMode) UnmarshalText(text []byte) error {
 		*m = KeepAll
 	case "postmerge":
 		*m = KeepPostMerge
+	case "recent":
+		*m = KeepRecent
 	default:
-		return fmt.Errorf(`unknown sync mode %q, want "all" or "postmerge"`, text)
+		return fmt.Errorf(`unknown history mode %q, want "all", "postmerge" or "recent"`, text)
 	}
 	return nil
 }
 
+// ParseRetention parses a history retention window, given either as a number of
+// blocks or as a number of days with a "d" suffix, into a number of blocks.
+func ParseRetention(s string) (uint64, error) {
+	days := strings.HasSuffix(s, "d")
+	n, err := strconv.ParseUint(strings.TrimSuffix(s, "d"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid history retention %q", s)
+	}
+	if days {
+		n *= BlocksPerDay
+	}
+	return n, nil
+}
+
 type PrunePoint struct {
 	BlockNumber uint64
 	BlockHash   common.Hash
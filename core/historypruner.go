@@ -0,0 +1,156 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// historyPruner is the module responsible for continuously pruning the chain
+// history outside of the configured retention window, in the KeepRecent history
+// mode.
+//
+// Pruning happens in two steps. The history pruning cutoff is moved forward
+// first, which makes the APIs report the blocks below as pruned and lets the
+// transaction indexer and the log indexer drop their indexes below it. The block
+// bodies and receipts below the cutoff are only removed from the freezer in the
+// following round, once the transaction indexer has settled and never above its
+// tail, so that nothing still being indexed or unindexed is removed beforehand.
+type historyPruner struct {
+	// retention is the number of recent blocks [HEAD-N+1, HEAD] whose bodies
+	// and receipts are retained.
+	retention uint64
+
+	chain  *BlockChain
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// newHistoryPruner initializes the history pruner.
+func newHistoryPruner(retention uint64, chain *BlockChain) *historyPruner {
+	pruner := &historyPruner{
+		retention: retention,
+		chain:     chain,
+		term:      make(chan chan struct{}),
+		closed:    make(chan struct{}),
+	}
+	go pruner.loop()
+
+	log.Info("Initialized chain history pruner", "retention", retention)
+	return pruner
+}
+
+// prune truncates the chain history up to the previously announced cutoff and
+// announces the next cutoff according to the given chain head.
+func (pruner *historyPruner) prune(head uint64) {
+	var (
+		chain     = pruner.chain
+		db        = chain.db
+		cutoff, _ = chain.HistoryPruningCutoff()
+	)
+	tail, err := db.Tail()
+	if err != nil {
+		log.Error("Failed to retrieve chain history tail", "err", err)
+		return
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		log.Error("Failed to retrieve chain history head", "err", err)
+		return
+	}
+	// Remove the history below the announced cutoff, which is no longer
+	// indexed for transactions. Skip it while the transactions are still
+	// being indexed, possibly below the cutoff.
+	target := cutoff
+	if indexer := chain.txIndexer; indexer != nil {
+		progress, err := indexer.txIndexProgress()
+		if err != nil || !progress.Done() {
+			target = tail
+		} else if txTail := rawdb.ReadTxIndexTail(db); txTail == nil {
+			target = tail
+		} else {
+			target = min(target, *txTail)
+		}
+	}
+	if target > tail {
+		if _, err := db.TruncateTail(target); err != nil {
+			log.Error("Failed to prune chain history", "tail", target, "err", err)
+			return
+		}
+		log.Debug("Pruned chain history", "items", target-tail, "tail", target)
+	}
+	// Announce the next cutoff, limited to the history already frozen as the
+	// rest can't be truncated.
+	if head+1 <= pruner.retention {
+		return
+	}
+	next := min(head+1-pruner.retention, frozen)
+	if next <= cutoff {
+		return
+	}
+	chain.historyPrunePoint.Store(&history.PrunePoint{
+		BlockNumber: next,
+		BlockHash:   rawdb.ReadCanonicalHash(db, next),
+	})
+	log.Debug("Updated chain history cutoff", "number", next)
+}
+
+// loop is the scheduler of the pruner, pruning the chain history whenever the
+// chain head is updated.
+func (pruner *historyPruner) loop() {
+	defer close(pruner.closed)
+
+	var (
+		done   chan struct{} // Non-nil if background routine is active
+		headCh = make(chan ChainHeadEvent)
+		sub    = pruner.chain.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case h := <-headCh:
+			if done == nil {
+				done = make(chan struct{})
+				go func(head uint64, done chan struct{}) {
+					defer close(done)
+					pruner.prune(head)
+				}(h.Header.Number.Uint64(), done)
+			}
+		case <-done:
+			done = nil
+		case ch := <-pruner.term:
+			if done != nil {
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// close shuts down the pruner. Safe to be called for multiple times.
+func (pruner *historyPruner) close() {
+	ch := make(chan struct{})
+	select {
+	case pruner.term <- ch:
+		<-ch
+	case <-pruner.closed:
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the chain history outside of the retention window is pruned along
+// with the transaction indexes in the KeepRecent history mode.
+func TestHistoryPrunerKeepRecent(t *testing.T) {
+	var (
+		testBankKey, _  = crypto.GenerateKey()
+		testBankAddress = crypto.PubkeyToAddress(testBankKey.PublicKey)
+		testBankFunds   = big.NewInt(1000000000000000000)
+
+		gspec = &Genesis{
+			Config:  params.TestChainConfig,
+			Alloc:   types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		engine    = ethash.NewFaker()
+		nonce     = uint64(0)
+		chainHead = uint64(128)
+		retention = uint64(32)
+		limit     = uint64(0)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, engine, int(chainHead), func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.HexToAddress("0xdeadbeef"), big.NewInt(1000), params.TxGas, big.NewInt(10*params.InitialBaseFee), nil), types.HomesteadSigner{}, testBankKey)
+		gen.AddTx(tx)
+		nonce += 1
+	})
+	db, _ := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	defer db.Close()
+
+	config := DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	config.ChainHistoryMode = history.KeepRecent
+	config.ChainHistoryRetention = retention
+	chain, err := NewBlockChain(db, config, gspec, nil, engine, vm.Config{}, &limit)
+	if err != nil {
+		t.Fatalf("Failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+
+	// Insert the entire chain into the freezer, so that it can be pruned
+	if n, err := chain.InsertReceiptChain(blocks, receipts, chainHead+1); err != nil {
+		t.Fatalf("Failed to insert block %d: %v", n, err)
+	}
+	// Announce the chain head until the history is pruned
+	var (
+		head = blocks[len(blocks)-1].Header()
+		want = chainHead + 1 - retention
+	)
+	for i := 0; ; i++ {
+		chain.chainHeadFeed.Send(ChainHeadEvent{Header: head})
+		if tail, _ := db.Tail(); tail == want {
+			break
+		}
+		if i > 500 {
+			tail, _ := db.Tail()
+			t.Fatalf("Chain history is not pruned, want tail %d, got %d", want, tail)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if cutoff, hash := chain.HistoryPruningCutoff(); cutoff != want || hash != blocks[want-1].Hash() {
+		t.Fatalf("Unexpected cutoff, want %d, got %d", want, cutoff)
+	}
+	verify(t, db, blocks, want)
+	for _, block := range blocks {
+		body := chain.GetBody(block.Hash())
+		if block.NumberU64() < want && body != nil {
+			t.Fatalf("Block body %d is not pruned", block.NumberU64())
+		}
+		if block.NumberU64() >= want && body == nil {
+			t.Fatalf("Block body %d is missing", block.NumberU64())
+		}
+	}
+}
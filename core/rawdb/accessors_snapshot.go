@@ -196,6 +196,32 @@ func DeleteSnapshotRecoveryNumber(db ethdb.KeyValueWriter) {
 	}
 }
 
+// ReadOnlinePruningMarker retrieves the state root targeted by an interrupted
+// online state pruning, along with the database key up to which the stale trie
+// nodes have been swept. False is returned if no pruning is in progress.
+func ReadOnlinePruningMarker(db ethdb.KeyValueReader) (common.Hash, []byte, bool) {
+	data, _ := db.Get(onlinePruningKey)
+	if len(data) < common.HashLength {
+		return common.Hash{}, nil, false
+	}
+	return common.BytesToHash(data[:common.HashLength]), data[common.HashLength:], true
+}
+
+// WriteOnlinePruningMarker stores the sweep progress of the online state pruning.
+func WriteOnlinePruningMarker(db ethdb.KeyValueWriter, root common.Hash, marker []byte) {
+	if err := db.Put(onlinePruningKey, append(root.Bytes(), marker...)); err != nil {
+		log.Crit("Failed to store online pruning marker", "err", err)
+	}
+}
+
+// DeleteOnlinePruningMarker deletes the sweep progress of the online state
+// pruning, marking it as finished.
+func DeleteOnlinePruningMarker(db ethdb.KeyValueWriter) {
+	if err := db.Delete(onlinePruningKey); err != nil {
+		log.Crit("Failed to remove online pruning marker", "err", err)
+	}
+}
+
 // ReadSnapshotSyncStatus retrieves the serialized sync status saved at shutdown.
 func ReadSnapshotSyncStatus(db ethdb.KeyValueReader) []byte {
 	data, _ := db.Get(snapshotSyncStatusKey)
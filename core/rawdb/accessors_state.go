@@ -268,3 +268,83 @@ func WriteStateHistory(db ethdb.AncientWriter, id uint64, meta []byte, accountIn
 		return nil
 	})
 }
+
+// ReadStateHistoryIndexHead retrieves the id of the latest indexed state history.
+func ReadStateHistoryIndexHead(db ethdb.KeyValueReader) *uint64 {
+	data, err := db.Get(headStateHistoryIndexKey)
+	if err != nil || len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteStateHistoryIndexHead stores the id of the latest indexed state history.
+func WriteStateHistoryIndexHead(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Put(headStateHistoryIndexKey, encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store the state history index head", "err", err)
+	}
+}
+
+// DeleteStateHistoryIndexHead removes the id of the latest indexed state history.
+func DeleteStateHistoryIndexHead(db ethdb.KeyValueWriter) {
+	if err := db.Delete(headStateHistoryIndexKey); err != nil {
+		log.Crit("Failed to remove the state history index head", "err", err)
+	}
+}
+
+// seekHistoryIndexBlock returns the first index block within the prefix whose
+// last id is not lower than the given id.
+func seekHistoryIndexBlock(db ethdb.Iteratee, prefix []byte, id uint64) (uint64, []byte) {
+	it := db.NewIterator(prefix, encodeBlockNumber(id))
+	defer it.Release()
+
+	if !it.Next() || len(it.Key()) != len(prefix)+8 {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(it.Key()[len(prefix):]), common.CopyBytes(it.Value())
+}
+
+// SeekAccountHistoryIndexBlock retrieves the first state history index block of
+// the account whose last id is not lower than the given id. The id of the block
+// is returned along with its content, which is nil if there is no such block.
+func SeekAccountHistoryIndexBlock(db ethdb.Iteratee, accountHash common.Hash, id uint64) (uint64, []byte) {
+	prefix := accountHistoryIndexKey(accountHash, 0)
+	return seekHistoryIndexBlock(db, prefix[:len(prefix)-8], id)
+}
+
+// WriteAccountHistoryIndexBlock stores a state history index block of the account.
+func WriteAccountHistoryIndexBlock(db ethdb.KeyValueWriter, accountHash common.Hash, last uint64, blob []byte) {
+	if err := db.Put(accountHistoryIndexKey(accountHash, last), blob); err != nil {
+		log.Crit("Failed to store account history index", "err", err)
+	}
+}
+
+// DeleteAccountHistoryIndexBlock removes a state history index block of the account.
+func DeleteAccountHistoryIndexBlock(db ethdb.KeyValueWriter, accountHash common.Hash, last uint64) {
+	if err := db.Delete(accountHistoryIndexKey(accountHash, last)); err != nil {
+		log.Crit("Failed to delete account history index", "err", err)
+	}
+}
+
+// SeekStorageHistoryIndexBlock retrieves the first state history index block of
+// the storage slot whose last id is not lower than the given id. The id of the
+// block is returned along with its content, which is nil if there is no such block.
+func SeekStorageHistoryIndexBlock(db ethdb.Iteratee, accountHash, storageHash common.Hash, id uint64) (uint64, []byte) {
+	prefix := storageHistoryIndexKey(accountHash, storageHash, 0)
+	return seekHistoryIndexBlock(db, prefix[:len(prefix)-8], id)
+}
+
+// WriteStorageHistoryIndexBlock stores a state history index block of the storage slot.
+func WriteStorageHistoryIndexBlock(db ethdb.KeyValueWriter, accountHash, storageHash common.Hash, last uint64, blob []byte) {
+	if err := db.Put(storageHistoryIndexKey(accountHash, storageHash, last), blob); err != nil {
+		log.Crit("Failed to store storage history index", "err", err)
+	}
+}
+
+// DeleteStorageHistoryIndexBlock removes a state history index block of the storage slot.
+func DeleteStorageHistoryIndexBlock(db ethdb.KeyValueWriter, accountHash, storageHash common.Hash, last uint64) {
+	if err := db.Delete(storageHistoryIndexKey(accountHash, storageHash, last)); err != nil {
+		log.Crit("Failed to delete storage history index", "err", err)
+	}
+}
@@ -56,7 +56,7 @@ type chainFreezer struct {
 //     state freezer (e.g. dev mode).
 //   - if non-empty directory is given, initializes the regular file-based
 //     state freezer.
-func newChainFreezer(datadir string, namespace string, readonly bool) (*chainFreezer, error) {
+func newChainFreezer(datadir string, namespace string, readonly bool, remote RemoteStore) (*chainFreezer, error) {
 	var (
 		err     error
 		freezer ethdb.AncientStore
@@ -64,7 +64,16 @@ func newChainFreezer(datadir string, namespace string, readonly bool) (*chainFre
 	if datadir == "" {
 		freezer = NewMemoryFreezer(readonly, chainFreezerTableConfigs)
 	} else {
-		freezer, err = NewFreezer(datadir, namespace, readonly, freezerTableSize, chainFreezerTableConfigs)
+		tables := chainFreezerTableConfigs
+		if remote != nil {
+			tier := newRemoteTier(remote)
+			tables = make(map[string]freezerTableConfig)
+			for name, config := range chainFreezerTableConfigs {
+				config.remote = tier
+				tables[name] = config
+			}
+		}
+		freezer, err = NewFreezer(datadir, namespace, readonly, freezerTableSize, tables)
 	}
 	if err != nil {
 		return nil, err
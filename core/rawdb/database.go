@@ -191,6 +191,14 @@ func resolveChainFreezerDir(ancient string) string {
 // storage. The passed ancient indicates the path of root ancient directory
 // where the chain freezer can be opened.
 func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace string, readonly bool) (ethdb.Database, error) {
+	return NewDatabaseWithTieredFreezer(db, ancient, namespace, readonly, nil)
+}
+
+// NewDatabaseWithTieredFreezer creates a high level database on top of a given
+// key-value data store with a freezer, whose sealed data files are offloaded to
+// the given remote store. If the remote store is nil, the data files are kept
+// locally, as with NewDatabaseWithFreezer.
+func NewDatabaseWithTieredFreezer(db ethdb.KeyValueStore, ancient string, namespace string, readonly bool, remote RemoteStore) (ethdb.Database, error) {
 	// Create the idle freezer instance. If the given ancient directory is empty,
 	// in-memory chain freezer is used (e.g. dev mode); otherwise the regular
 	// file-based freezer is created.
@@ -198,7 +206,7 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace st
 	if chainFreezerDir != "" {
 		chainFreezerDir = resolveChainFreezerDir(chainFreezerDir)
 	}
-	frdb, err := newChainFreezer(chainFreezerDir, namespace, readonly)
+	frdb, err := newChainFreezer(chainFreezerDir, namespace, readonly, remote)
 	if err != nil {
 		printChainMetadata(db)
 		return nil, err
@@ -373,6 +381,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		hashNumPairings    stat
 		legacyTries        stat
 		stateLookups       stat
+		stateIndexes       stat
 		accountTries       stat
 		storageTries       stat
 		codes              stat
@@ -427,6 +436,10 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			legacyTries.Add(size)
 		case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
 			stateLookups.Add(size)
+		case bytes.HasPrefix(key, StateHistoryAccountIndexPrefix) && len(key) == len(StateHistoryAccountIndexPrefix)+common.HashLength+8:
+			stateIndexes.Add(size)
+		case bytes.HasPrefix(key, StateHistoryStorageIndexPrefix) && len(key) == len(StateHistoryStorageIndexPrefix)+2*common.HashLength+8:
+			stateIndexes.Add(size)
 		case IsAccountTrieNode(key):
 			accountTries.Add(size)
 		case IsStorageTrieNode(key):
@@ -472,7 +485,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				verkleTries.Add(size)
 			case bytes.HasPrefix(remain, stateIDPrefix) && len(remain) == len(stateIDPrefix)+common.HashLength:
 				verkleStateLookups.Add(size)
-			case bytes.Equal(remain, persistentStateIDKey):
+			case bytes.HasPrefix(remain, StateHistoryIndexPrefix):
+				stateIndexes.Add(size)
+			case bytes.Equal(remain, persistentStateIDKey), bytes.Equal(remain, headStateHistoryIndexKey):
 				metadata.Add(size)
 			case bytes.Equal(remain, trieJournalKey):
 				metadata.Add(size)
@@ -517,6 +532,7 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Contract codes", codes.Size(), codes.Count()},
 		{"Key-Value store", "Hash trie nodes", legacyTries.Size(), legacyTries.Count()},
 		{"Key-Value store", "Path trie state lookups", stateLookups.Size(), stateLookups.Count()},
+		{"Key-Value store", "Path trie state history index", stateIndexes.Size(), stateIndexes.Count()},
 		{"Key-Value store", "Path trie account nodes", accountTries.Size(), accountTries.Count()},
 		{"Key-Value store", "Path trie storage nodes", storageTries.Size(), storageTries.Count()},
 		{"Key-Value store", "Verkle trie nodes", verkleTries.Size(), verkleTries.Count()},
@@ -566,7 +582,7 @@ var knownMetadataKeys = [][]byte{
 	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
-	filterMapsRangeKey,
+	filterMapsRangeKey, headStateHistoryIndexKey, onlinePruningKey,
 }
 
 // printChainMetadata prints out chain metadata to stderr.
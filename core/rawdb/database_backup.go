@@ -0,0 +1,428 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// BackupChainDataDir is the directory of the key-value store within a backup.
+	BackupChainDataDir = "chaindata"
+
+	// BackupAncientDir is the directory of the ancient stores within a backup.
+	BackupAncientDir = "ancient"
+
+	// backupCopyBufferSize is the size of the chunks the freezer data files are
+	// copied in.
+	backupCopyBufferSize = 1024 * 1024
+)
+
+// checkpointer is implemented by the key-value stores capable of creating
+// consistent point-in-time copies of themselves.
+type checkpointer interface {
+	Checkpoint(dir string) error
+}
+
+// Backup implements ethdb.Backupper, creating a consistent copy of the database
+// in the given directory while it's in use: a checkpoint of the key-value store
+// along with the ancient stores up to the boundary frozen at checkpoint time.
+//
+// The freezer tables are copied incrementally: if the directory contains a
+// previous backup, only the items appended since are copied, as long as the
+// already copied ones are unchanged. An interrupted backup leaves the directory
+// in an inconsistent state, it needs to be repeated before it can be restored.
+func (frdb *freezerdb) Backup(dir string) error {
+	kvdb := frdb.KeyValueStore
+	if db, ok := kvdb.(*nofreezedb); ok {
+		kvdb = db.KeyValueStore
+	}
+	store, ok := kvdb.(checkpointer)
+	if !ok {
+		return errors.New("key-value store does not support checkpoints")
+	}
+	chain, ok := frdb.chainFreezer.AncientStore.(*Freezer)
+	if !ok {
+		return errors.New("in-memory ancient store does not support backups")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var (
+		start    = time.Now()
+		kvdir    = filepath.Join(dir, BackupChainDataDir)
+		tmpdir   = kvdir + ".tmp"
+		ancients = filepath.Join(dir, BackupAncientDir)
+		frozen   uint64
+	)
+	if err := os.RemoveAll(tmpdir); err != nil {
+		return err
+	}
+	// Create the checkpoint while holding the freezer lock, ensuring that no
+	// blocks are moved to the ancient store in the meantime. The blocks being
+	// frozen are only deleted from the key-value store after that, so all of
+	// them are present in either one of the copies.
+	err := chain.ReadAncients(func(op ethdb.AncientReaderOp) error {
+		var err error
+		if frozen, err = op.Ancients(); err != nil {
+			return err
+		}
+		return store.Checkpoint(tmpdir)
+	})
+	if err != nil {
+		os.RemoveAll(tmpdir)
+		return err
+	}
+	log.Info("Created key-value store checkpoint", "frozen", frozen, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	for name, table := range chain.tables {
+		if err := backupFreezerTable(chain.datadir, filepath.Join(ancients, ChainFreezerName), name, table.config, frozen); err != nil {
+			return fmt.Errorf("failed to back up chain freezer table %s: %w", name, err)
+		}
+	}
+	// The state histories are written before the persistent state id, so all
+	// of the histories referenced by the checkpoint are available. The newer
+	// ones are truncated once the backup is opened.
+	if ReadStateScheme(frdb) == PathScheme {
+		src := filepath.Join(frdb.ancientRoot, MerkleStateFreezerName)
+		if _, err := os.Stat(src); err == nil {
+			id := ReadPersistentStateID(frdb)
+			for name, config := range stateFreezerTableConfigs {
+				if err := backupFreezerTable(src, filepath.Join(ancients, MerkleStateFreezerName), name, config, id); err != nil {
+					return fmt.Errorf("failed to back up state freezer table %s: %w", name, err)
+				}
+			}
+		}
+	}
+	if err := os.RemoveAll(kvdir); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpdir, kvdir); err != nil {
+		return err
+	}
+	log.Info("Created database backup", "dir", dir, "frozen", frozen, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// backupFreezerTable copies the items of the given table below the limit from
+// the source directory into the destination one. The table might be in use, the
+// files are only appended to while the items below the limit are immutable.
+//
+// If the destination contains a previous copy of the table, its items also in
+// the source are verified to be the same, and only the missing ones are copied.
+func backupFreezerTable(srcdir, dstdir, name string, config freezerTableConfig, items uint64) error {
+	if err := os.MkdirAll(dstdir, 0755); err != nil {
+		return err
+	}
+	idxName := freezerIndexFileName(name, config.noSnappy)
+
+	// Load the index of the source table up to the requested item
+	srcIndex, srcOffset, err := readBackupIndex(filepath.Join(srcdir, idxName), items)
+	if err != nil {
+		return err
+	}
+	meta, err := os.Open(filepath.Join(srcdir, fmt.Sprintf("%s.meta", name)))
+	if err != nil {
+		return err
+	}
+	srcMeta, err := newMetadata(meta)
+	meta.Close()
+	if err != nil {
+		return err
+	}
+	// Load the index of the previous backup, if there's any, and figure out
+	// how much of it can be retained. The entries of the shared items must be
+	// the same, and the data of the last one too, as the index alone doesn't
+	// reveal the items rewritten in place, e.g. after a rewind.
+	dstIndex, dstOffset, err := readBackupIndex(filepath.Join(dstdir, idxName), math.MaxUint64)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Warn("Discarding unreadable freezer table backup", "table", name, "err", err)
+	}
+	var (
+		srcItems = srcOffset + uint64(len(srcIndex)/indexEntrySize) - 1
+		dstItems = dstOffset + uint64(len(dstIndex)/indexEntrySize) - 1
+		shared   uint64 // Number of items retained from the previous backup
+	)
+	if dstIndex != nil && dstOffset <= srcOffset && srcOffset < min(srcItems, dstItems) {
+		var (
+			limit   = min(srcItems, dstItems)
+			srcPart = srcIndex[indexEntrySize : (limit-srcOffset+1)*indexEntrySize]
+			dstPart = dstIndex[(srcOffset-dstOffset+1)*indexEntrySize : (limit-dstOffset+1)*indexEntrySize]
+		)
+		if bytes.Equal(srcPart, dstPart) {
+			same, err := sameBackupItem(srcdir, dstdir, name, config, srcIndex, limit-1-srcOffset)
+			if err != nil {
+				return err
+			}
+			if same {
+				shared = limit - srcOffset
+			}
+		}
+	}
+	// Drop the data files not referenced anymore, and truncate the rest to the
+	// retained items
+	var (
+		first   = backupIndexEntry(srcIndex, 0).filenum
+		last    = backupIndexEntry(srcIndex, uint64(len(srcIndex)/indexEntrySize)-1).filenum
+		srcSize = backupFileSizes(srcIndex, uint64(len(srcIndex)/indexEntrySize)-1)
+		dstSize = make(map[uint32]uint32)
+	)
+	if dstIndex != nil {
+		if shared > 0 {
+			dstSize = backupFileSizes(dstIndex, srcOffset-dstOffset+shared)
+		}
+		dstFirst := backupIndexEntry(dstIndex, 0).filenum
+		dstLast := backupIndexEntry(dstIndex, uint64(len(dstIndex)/indexEntrySize)-1).filenum
+		for num := dstFirst; num <= dstLast; num++ {
+			if num < first || num > last {
+				os.Remove(filepath.Join(dstdir, freezerDataFileName(name, config.noSnappy, num)))
+			}
+		}
+	}
+	var copied int64
+	for num := first; num <= last; num++ {
+		n, err := backupDataFile(srcdir, dstdir, freezerDataFileName(name, config.noSnappy, num), config.remote, int64(dstSize[num]), int64(srcSize[num]))
+		if err != nil {
+			return err
+		}
+		copied += n
+	}
+	// Write out the index and the metadata, all of the items are flushed
+	if err := writeBackupFile(filepath.Join(dstdir, idxName), srcIndex); err != nil {
+		return err
+	}
+	meta, err = openFreezerFileTruncated(filepath.Join(dstdir, fmt.Sprintf("%s.meta", name)))
+	if err != nil {
+		return err
+	}
+	m := &freezerTableMeta{
+		file:        meta,
+		version:     freezerTableV2,
+		virtualTail: max(srcOffset, min(srcMeta.virtualTail, srcItems)),
+		flushOffset: int64(len(srcIndex)),
+	}
+	if err := m.write(true); err != nil {
+		meta.Close()
+		return err
+	}
+	if err := meta.Close(); err != nil {
+		return err
+	}
+	log.Debug("Backed up freezer table", "table", name, "items", srcItems, "retained", shared, "copied", common.StorageSize(copied))
+	return nil
+}
+
+// readBackupIndex reads the index file of a freezer table, returning the index
+// entries up to the given item (or all of them if unlimited) along with the
+// number of items deleted from the table.
+func readBackupIndex(path string, items uint64) ([]byte, uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	size := stat.Size() / indexEntrySize * indexEntrySize
+	if size == 0 {
+		return nil, 0, errors.New("empty index file")
+	}
+	var first [indexEntrySize]byte
+	if _, err := f.ReadAt(first[:], 0); err != nil {
+		return nil, 0, err
+	}
+	var entry indexEntry
+	entry.unmarshalBinary(first[:])
+	offset := uint64(entry.offset)
+
+	if items != math.MaxUint64 {
+		items = max(items, offset)
+		want := int64(items-offset+1) * indexEntrySize
+		if want > size {
+			return nil, 0, fmt.Errorf("index contains %d items, want %d", offset+uint64(size/indexEntrySize)-1, items)
+		}
+		size = want
+	}
+	index := make([]byte, size)
+	if _, err := f.ReadAt(index, 0); err != nil {
+		return nil, 0, err
+	}
+	return index, offset, nil
+}
+
+// backupIndexEntry decodes the n'th entry of the given index.
+func backupIndexEntry(index []byte, n uint64) indexEntry {
+	var entry indexEntry
+	entry.unmarshalBinary(index[n*indexEntrySize:])
+	return entry
+}
+
+// backupFileSizes returns the sizes of the data files holding the first count
+// items of the given index.
+func backupFileSizes(index []byte, count uint64) map[uint32]uint32 {
+	sizes := make(map[uint32]uint32)
+	for i := uint64(1); i <= count; i++ {
+		entry := backupIndexEntry(index, i)
+		sizes[entry.filenum] = entry.offset
+	}
+	return sizes
+}
+
+// sameBackupItem checks whether the n'th item of the table (relative to the
+// first stored one) is the same in the source and the destination directories.
+func sameBackupItem(srcdir, dstdir, name string, config freezerTableConfig, index []byte, n uint64) (bool, error) {
+	var (
+		start = backupIndexEntry(index, n)
+		end   = backupIndexEntry(index, n+1)
+	)
+	if n == 0 {
+		start = indexEntry{filenum: end.filenum} // first item, see getIndices
+	}
+	from, to, num := start.bounds(&end)
+
+	file := freezerDataFileName(name, config.noSnappy, num)
+	src, err := openBackupSource(srcdir, file, config.remote)
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+
+	dst, err := os.Open(filepath.Join(dstdir, file))
+	if err != nil {
+		return false, nil
+	}
+	defer dst.Close()
+
+	var (
+		have = make([]byte, to-from)
+		want = make([]byte, to-from)
+	)
+	if _, err := dst.ReadAt(have, int64(from)); err != nil {
+		return false, nil
+	}
+	if _, err := src.ReadAt(want, int64(from)); err != nil {
+		return false, err
+	}
+	return bytes.Equal(have, want), nil
+}
+
+// backupDataFile copies the given range of a freezer data file, retaining the
+// content of the destination file before it.
+func backupDataFile(srcdir, dstdir, file string, remote *remoteTier, from, to int64) (int64, error) {
+	dst, err := os.OpenFile(filepath.Join(dstdir, file), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	if err := truncateFreezerFile(dst, from); err != nil {
+		return 0, err
+	}
+	if from < to {
+		src, err := openBackupSource(srcdir, file, remote)
+		if err != nil {
+			return 0, err
+		}
+		defer src.Close()
+
+		buf := make([]byte, backupCopyBufferSize)
+		for off := from; off < to; {
+			chunk := buf[:min(int64(len(buf)), to-off)]
+			if _, err := src.ReadAt(chunk, off); err != nil {
+				return 0, err
+			}
+			if _, err := dst.Write(chunk); err != nil {
+				return 0, err
+			}
+			off += int64(len(chunk))
+		}
+	}
+	if err := dst.Sync(); err != nil {
+		return 0, err
+	}
+	return max(to-from, 0), nil
+}
+
+// backupSource is a freezer data file being copied.
+type backupSource interface {
+	io.ReaderAt
+	io.Closer
+}
+
+// remoteBackupSource is a freezer data file offloaded to the remote tier.
+type remoteBackupSource struct {
+	store RemoteStore
+	name  string
+}
+
+func (s *remoteBackupSource) ReadAt(p []byte, off int64) (int, error) {
+	if err := s.store.ReadAt(s.name, p, off); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *remoteBackupSource) Close() error { return nil }
+
+// openBackupSource opens a freezer data file for copying, falling back to the
+// remote tier if it's not present locally.
+func openBackupSource(dir, file string, remote *remoteTier) (backupSource, error) {
+	f, err := os.Open(filepath.Join(dir, file))
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) || remote == nil {
+		return nil, err
+	}
+	return &remoteBackupSource{store: remote.store, name: file}, nil
+}
+
+// writeBackupFile atomically replaces the content of the given file.
+func writeBackupFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
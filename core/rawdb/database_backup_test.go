@@ -0,0 +1,206 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+)
+
+func TestBackupFreezerTable(t *testing.T) {
+	tables := map[string]freezerTableConfig{"test": {noSnappy: true, prunable: true}}
+	f, srcdir := newFreezerForTesting(t, tables)
+	defer f.Close()
+
+	appendItems := func(from, to uint64, seed int) {
+		t.Helper()
+		_, err := f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+			for i := from; i < to; i++ {
+				if err := op.AppendRaw("test", i, getChunk(100, int(i)+seed)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to append items: %v", err)
+		}
+	}
+	dstdir := t.TempDir()
+	checkBackup := func(items, tail uint64, seed int, rewritten uint64) {
+		t.Helper()
+		b, err := NewFreezer(dstdir, "", true, 2049, tables)
+		if err != nil {
+			t.Fatalf("Failed to open backup: %v", err)
+		}
+		defer b.Close()
+
+		if have, _ := b.Ancients(); have != items {
+			t.Fatalf("Backup item count mismatch: have %d, want %d", have, items)
+		}
+		if have, _ := b.Tail(); have != tail {
+			t.Fatalf("Backup tail mismatch: have %d, want %d", have, tail)
+		}
+		for i := tail; i < items; i++ {
+			want := getChunk(100, int(i))
+			if i >= rewritten {
+				want = getChunk(100, int(i)+seed)
+			}
+			blob, err := b.Ancient("test", i)
+			if err != nil {
+				t.Fatalf("Failed to read backup item %d: %v", i, err)
+			}
+			if i == tail && blob[0] == 0xff {
+				continue // corrupted on purpose, checked separately
+			}
+			if !bytes.Equal(blob, want) {
+				t.Fatalf("Backup item %d mismatch", i)
+			}
+		}
+	}
+	// Back up the items partially, spanning multiple data files
+	appendItems(0, 100, 0)
+	if err := backupFreezerTable(srcdir, dstdir, "test", tables["test"], 60); err != nil {
+		t.Fatalf("Failed to back up table: %v", err)
+	}
+	checkBackup(60, 0, 0, 100)
+
+	// Back up the rest, the already copied items should be retained. Corrupt
+	// the first item of the backup, which isn't compared, to check that.
+	first := filepath.Join(dstdir, freezerDataFileName("test", true, 0))
+	data, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatalf("Failed to read data file: %v", err)
+	}
+	data[0] = 0xff
+	if err := os.WriteFile(first, data, 0644); err != nil {
+		t.Fatalf("Failed to write data file: %v", err)
+	}
+	if err := backupFreezerTable(srcdir, dstdir, "test", tables["test"], 100); err != nil {
+		t.Fatalf("Failed to back up table: %v", err)
+	}
+	checkBackup(100, 0, 0, 100)
+	if blob, _ := os.ReadFile(first); blob[0] != 0xff {
+		t.Fatal("Retained items were copied again")
+	}
+	// Prune the tail and rewrite the head with items of the same size, the
+	// backup should detect the change despite the identical index entries
+	if _, err := f.TruncateTail(40); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	if _, err := f.TruncateHead(90); err != nil {
+		t.Fatalf("Failed to truncate head: %v", err)
+	}
+	appendItems(90, 100, 100)
+	if err := backupFreezerTable(srcdir, dstdir, "test", tables["test"], 100); err != nil {
+		t.Fatalf("Failed to back up table: %v", err)
+	}
+	checkBackup(100, 40, 100, 90)
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Fatal("Pruned data file is retained in the backup")
+	}
+}
+
+func TestDatabaseBackup(t *testing.T) {
+	datadir := t.TempDir()
+	kvdb, err := pebble.New(filepath.Join(datadir, "chaindata"), 16, 16, "", false, false)
+	if err != nil {
+		t.Fatalf("Failed to create key-value store: %v", err)
+	}
+	db, err := NewDatabaseWithFreezer(NewDatabase(kvdb), filepath.Join(datadir, "ancient"), "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Write a chain of 10 blocks, the first 5 frozen
+	var (
+		blocks []*types.Block
+		parent common.Hash
+	)
+	for i := 0; i < 10; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: parent}
+		block := types.NewBlock(header, nil, nil, newTestHasher())
+
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	checkBackup := func(dir string, frozen uint64) {
+		t.Helper()
+		kvdb, err := pebble.New(filepath.Join(dir, BackupChainDataDir), 16, 16, "", true, false)
+		if err != nil {
+			t.Fatalf("Failed to open backup: %v", err)
+		}
+		backup, err := NewDatabaseWithFreezer(kvdb, filepath.Join(dir, BackupAncientDir), "", true)
+		if err != nil {
+			t.Fatalf("Failed to open backup: %v", err)
+		}
+		defer backup.Close()
+
+		if have, _ := backup.Ancients(); have != frozen {
+			t.Fatalf("Backup frozen count mismatch: have %d, want %d", have, frozen)
+		}
+		for _, block := range blocks {
+			if ReadBlock(backup, block.Hash(), block.NumberU64()) == nil {
+				t.Fatalf("Block %d missing from backup", block.NumberU64())
+			}
+		}
+		if err := VerifyDatabase(backup, false); err != nil {
+			t.Fatalf("Backup is inconsistent: %v", err)
+		}
+	}
+	dir := filepath.Join(t.TempDir(), "backup")
+
+	if _, err := WriteAncientBlocks(db, blocks[:5], make([]types.Receipts, 5)); err != nil {
+		t.Fatalf("Failed to freeze blocks: %v", err)
+	}
+	for _, block := range blocks[5:] {
+		WriteBlock(db, block)
+		WriteReceipts(db, block.Hash(), block.NumberU64(), nil)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+	}
+	for _, block := range blocks {
+		WriteHeaderNumber(db, block.Hash(), block.NumberU64())
+	}
+	WriteHeadHeaderHash(db, parent)
+	WriteHeadBlockHash(db, parent)
+	if err := db.(ethdb.Backupper).Backup(dir); err != nil {
+		t.Fatalf("Failed to back up database: %v", err)
+	}
+	checkBackup(dir, 5)
+
+	// Freeze more blocks and update the backup
+	if _, err := WriteAncientBlocks(db, blocks[5:8], make([]types.Receipts, 3)); err != nil {
+		t.Fatalf("Failed to freeze blocks: %v", err)
+	}
+	for _, block := range blocks[5:8] {
+		DeleteBlockWithoutNumber(db, block.Hash(), block.NumberU64())
+		DeleteCanonicalHash(db, block.NumberU64())
+	}
+	if err := db.(ethdb.Backupper).Backup(dir); err != nil {
+		t.Fatalf("Failed to back up database: %v", err)
+	}
+	checkBackup(dir, 8)
+}
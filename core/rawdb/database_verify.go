@@ -0,0 +1,378 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/olekukonko/tablewriter"
+)
+
+// verifyStat tracks the inconsistencies found in a category of database entries.
+type verifyStat struct {
+	name     string
+	found    int
+	repaired int
+}
+
+// databaseVerifier checks the consistency of the chain data across the key-value
+// store and the ancient store, optionally repairing the inconsistencies.
+type databaseVerifier struct {
+	db     ethdb.Database
+	repair bool
+	batch  ethdb.Batch
+
+	canonHashes   verifyStat
+	headers       verifyStat
+	headerNumbers verifyStat
+	bodies        verifyStat
+	receipts      verifyStat
+	staleBlocks   verifyStat
+	txLookups     verifyStat
+	stateIDs      verifyStat
+
+	start  time.Time
+	logged time.Time
+}
+
+// VerifyDatabase checks the consistency of the chain data in the database:
+// the canonical hash and header number mappings, the availability of headers,
+// bodies and receipts along the canonical chain, the stale block data left in
+// the key-value store after freezing, the transaction index entries and the
+// path-based state ID mappings.
+//
+// If repair is set, the inconsistencies which can be fixed from the remaining
+// data are fixed in place, e.g. by rewriting the mappings and indexes derivable
+// from the canonical chain or deleting the dangling entries. Missing chain data
+// can't be recovered, an error is returned if any unrepaired issue is left.
+func VerifyDatabase(db ethdb.Database, repair bool) error {
+	v := &databaseVerifier{
+		db:            db,
+		repair:        repair,
+		batch:         db.NewBatch(),
+		canonHashes:   verifyStat{name: "Block number->hash"},
+		headers:       verifyStat{name: "Headers"},
+		headerNumbers: verifyStat{name: "Block hash->number"},
+		bodies:        verifyStat{name: "Bodies"},
+		receipts:      verifyStat{name: "Receipt lists"},
+		staleBlocks:   verifyStat{name: "Stale block data"},
+		txLookups:     verifyStat{name: "Transaction index"},
+		stateIDs:      verifyStat{name: "Path trie state lookups"},
+		start:         time.Now(),
+		logged:        time.Now(),
+	}
+	headBlock, err := v.verifyCanonicalChain()
+	if err != nil {
+		return err
+	}
+	if err := v.verifyFrozenLeftovers(); err != nil {
+		return err
+	}
+	if err := v.verifyTxLookups(headBlock); err != nil {
+		return err
+	}
+	if err := v.verifyStateIDs(); err != nil {
+		return err
+	}
+	if err := v.flush(true); err != nil {
+		return err
+	}
+	return v.summarize()
+}
+
+// report records an inconsistency in the given category, fixing it if it's
+// repairable and repair is enabled.
+func (v *databaseVerifier) report(stat *verifyStat, fix func(ethdb.KeyValueWriter), msg string, ctx ...interface{}) error {
+	stat.found++
+	if fix == nil || !v.repair {
+		log.Warn(msg, append(ctx, "repairable", fix != nil)...)
+		return nil
+	}
+	log.Info(msg, append(ctx, "repaired", true)...)
+	fix(v.batch)
+	stat.repaired++
+	return v.flush(false)
+}
+
+// flush writes out the accumulated repairs if the batch is large enough, or
+// unconditionally if force is set.
+func (v *databaseVerifier) flush(force bool) error {
+	if v.batch.ValueSize() == 0 || (!force && v.batch.ValueSize() < ethdb.IdealBatchSize) {
+		return nil
+	}
+	if err := v.batch.Write(); err != nil {
+		return err
+	}
+	v.batch.Reset()
+	return nil
+}
+
+// verifyCanonicalChain walks the canonical chain backwards from the head header
+// along the parent hashes, checking the mappings and block data of each block.
+// The number of the head block is returned.
+func (v *databaseVerifier) verifyCanonicalChain() (uint64, error) {
+	headHash := ReadHeadHeaderHash(v.db)
+	if headHash == (common.Hash{}) {
+		return 0, fmt.Errorf("head header is not available")
+	}
+	number := ReadHeaderNumber(v.db, headHash)
+	if number == nil {
+		return 0, fmt.Errorf("head header %x is not indexed", headHash)
+	}
+	var (
+		frozen, _ = v.db.Ancients()
+		tail, _   = v.db.Tail() // the first block with body and receipts available
+		txTail    = ReadTxIndexTail(v.db)
+
+		headBlockHash = ReadHeadBlockHash(v.db)
+		headBlock     uint64
+		full          bool // whether the blocks being walked should be complete
+		hash          = headHash
+	)
+	log.Info("Verifying canonical chain", "head", *number, "frozen", frozen, "tail", tail)
+
+	for n := *number; ; n-- {
+		header := ReadHeader(v.db, hash, n)
+		if header == nil {
+			v.report(&v.headers, nil, "Missing canonical header", "number", n, "hash", hash)
+			log.Error("Canonical chain is broken, skipping the older blocks", "number", n)
+			break
+		}
+		// Ensure the number->hash and hash->number mappings point to the block
+		if canon := ReadCanonicalHash(v.db, n); canon != hash {
+			var fix func(ethdb.KeyValueWriter)
+			if n >= frozen {
+				fix = func(w ethdb.KeyValueWriter) { WriteCanonicalHash(w, hash, n) }
+			}
+			if err := v.report(&v.canonHashes, fix, "Inconsistent canonical hash", "number", n, "have", canon, "want", hash); err != nil {
+				return 0, err
+			}
+		}
+		if num := ReadHeaderNumber(v.db, hash); num == nil || *num != n {
+			fix := func(w ethdb.KeyValueWriter) { WriteHeaderNumber(w, hash, n) }
+			if err := v.report(&v.headerNumbers, fix, "Inconsistent header number", "number", n, "hash", hash); err != nil {
+				return 0, err
+			}
+		}
+		// Ensure the block data is available below the head block
+		if hash == headBlockHash {
+			full, headBlock = true, n
+		}
+		if full && n >= tail {
+			if err := v.verifyBlockData(hash, n, txTail); err != nil {
+				return 0, err
+			}
+		}
+		if time.Since(v.logged) > 8*time.Second {
+			log.Info("Verifying canonical chain", "number", n, "elapsed", common.PrettyDuration(time.Since(v.start)))
+			v.logged = time.Now()
+		}
+		if n == 0 {
+			break
+		}
+		hash = header.ParentHash
+	}
+	if headBlockHash != (common.Hash{}) && !full {
+		v.report(&v.headers, nil, "Head block is not canonical", "hash", headBlockHash)
+	}
+	return headBlock, nil
+}
+
+// verifyBlockData checks that the body and receipts of the given canonical block
+// are present, and that its transactions are indexed if they should be.
+func (v *databaseVerifier) verifyBlockData(hash common.Hash, number uint64, txTail *uint64) error {
+	if !HasReceipts(v.db, hash, number) {
+		v.report(&v.receipts, nil, "Missing canonical receipts", "number", number, "hash", hash)
+	}
+	body := ReadBody(v.db, hash, number)
+	if body == nil {
+		v.report(&v.bodies, nil, "Missing canonical body", "number", number, "hash", hash)
+		return nil
+	}
+	if txTail == nil || number < *txTail {
+		return nil
+	}
+	for _, tx := range body.Transactions {
+		txhash := tx.Hash()
+		if entry := ReadTxLookupEntry(v.db, txhash); entry == nil || *entry != number {
+			fix := func(w ethdb.KeyValueWriter) { WriteTxLookupEntries(w, number, []common.Hash{txhash}) }
+			if err := v.report(&v.txLookups, fix, "Inconsistent transaction index", "number", number, "tx", txhash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyFrozenLeftovers checks that the key-value store doesn't contain any
+// block data of the frozen blocks, except for the genesis. Such leftovers may
+// stay if the node crashes right after freezing the blocks.
+func (v *databaseVerifier) verifyFrozenLeftovers() error {
+	frozen, _ := v.db.Ancients()
+	if frozen <= 1 {
+		return nil
+	}
+	for _, nh := range ReadAllHashesInRange(v.db, 1, frozen-1) {
+		var (
+			hash   = nh.Hash
+			number = nh.Number
+			canon  = ReadCanonicalHash(v.db, number) == hash
+		)
+		fix := func(w ethdb.KeyValueWriter) {
+			// The hash->number mapping is retained for the canonical blocks
+			if canon {
+				DeleteBlockWithoutNumber(w, hash, number)
+			} else {
+				DeleteBlock(w, hash, number)
+			}
+		}
+		if err := v.report(&v.staleBlocks, fix, "Stale block in key-value store", "number", number, "hash", hash, "canonical", canon); err != nil {
+			return err
+		}
+	}
+	numbers, hashes := ReadAllCanonicalHashes(v.db, 1, frozen, math.MaxInt)
+	for i, number := range numbers {
+		fix := func(w ethdb.KeyValueWriter) { DeleteCanonicalHash(w, number) }
+		if err := v.report(&v.canonHashes, fix, "Stale canonical hash in key-value store", "number", number, "hash", hashes[i]); err != nil {
+			return err
+		}
+	}
+	// Canonical hashes above the head header are dangling as well
+	headHash := ReadHeadHeaderHash(v.db)
+	if number := ReadHeaderNumber(v.db, headHash); number != nil {
+		numbers, hashes := ReadAllCanonicalHashes(v.db, *number+1, math.MaxUint64, math.MaxInt)
+		for i, number := range numbers {
+			fix := func(w ethdb.KeyValueWriter) { DeleteCanonicalHash(w, number) }
+			if err := v.report(&v.canonHashes, fix, "Dangling canonical hash above head", "number", number, "hash", hashes[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyTxLookups checks that the transaction index doesn't contain entries
+// pointing above the head block or below the tail of the index.
+func (v *databaseVerifier) verifyTxLookups(headBlock uint64) error {
+	it := v.db.NewIterator(txLookupPrefix, nil)
+	defer it.Release()
+
+	txTail := ReadTxIndexTail(v.db)
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(txLookupPrefix)+common.HashLength {
+			continue
+		}
+		number := DecodeTxLookupEntry(it.Value(), v.db)
+		if number == nil {
+			continue
+		}
+		if *number <= headBlock && (txTail == nil || *number >= *txTail) {
+			continue
+		}
+		txhash := common.BytesToHash(key[len(txLookupPrefix):])
+		fix := func(w ethdb.KeyValueWriter) { DeleteTxLookupEntry(w, txhash) }
+		if err := v.report(&v.txLookups, fix, "Stale transaction index", "number", *number, "tx", txhash); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// verifyStateIDs checks that the path-based state ID mappings only refer to the
+// state histories available in the state freezer.
+func (v *databaseVerifier) verifyStateIDs() error {
+	if ReadStateScheme(v.db) != PathScheme {
+		return nil
+	}
+	datadir, err := v.db.AncientDatadir()
+	if err != nil || datadir == "" {
+		log.Info("Skipping state lookup verification, no state history available")
+		return nil
+	}
+	freezer, err := NewStateFreezer(datadir, false, true)
+	if err != nil {
+		return err
+	}
+	defer freezer.Close()
+
+	head, err := freezer.Ancients()
+	if err != nil {
+		return err
+	}
+	tail, err := freezer.Tail()
+	if err != nil {
+		return err
+	}
+	if persistent := ReadPersistentStateID(v.db); persistent > head {
+		v.report(&v.stateIDs, nil, "Persistent state is ahead of state histories", "id", persistent, "histories", head)
+	}
+	it := v.db.NewIterator(stateIDPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(stateIDPrefix)+common.HashLength || len(it.Value()) != 8 {
+			continue
+		}
+		// The lookups of the states whose histories were truncated (except for
+		// the genesis one), or not yet written are dangling.
+		id := binary.BigEndian.Uint64(it.Value())
+		if id <= head && (id == 0 || id > tail) {
+			continue
+		}
+		root := common.BytesToHash(key[len(stateIDPrefix):])
+		fix := func(w ethdb.KeyValueWriter) { DeleteStateID(w, root) }
+		if err := v.report(&v.stateIDs, fix, "Dangling state lookup", "root", root, "id", id, "tail", tail, "head", head); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// summarize prints the verification results and returns an error if any of the
+// inconsistencies is left unrepaired.
+func (v *databaseVerifier) summarize() error {
+	var (
+		stats      [][]string
+		unrepaired int
+	)
+	for _, stat := range []*verifyStat{
+		&v.canonHashes, &v.headerNumbers, &v.headers, &v.bodies, &v.receipts,
+		&v.staleBlocks, &v.txLookups, &v.stateIDs,
+	} {
+		stats = append(stats, []string{stat.name, fmt.Sprintf("%d", stat.found), fmt.Sprintf("%d", stat.repaired)})
+		unrepaired += stat.found - stat.repaired
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Category", "Inconsistencies", "Repaired"})
+	table.AppendBulk(stats)
+	table.Render()
+
+	if unrepaired > 0 {
+		return fmt.Errorf("database has %d unrepaired inconsistencies", unrepaired)
+	}
+	log.Info("Database verified", "elapsed", common.PrettyDuration(time.Since(v.start)))
+	return nil
+}
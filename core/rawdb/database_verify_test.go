@@ -0,0 +1,116 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+func TestVerifyDatabase(t *testing.T) {
+	db, err := NewDatabaseWithFreezer(memorydb.New(), "", "", false)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Write a chain of 10 blocks with a transaction each (except for the genesis),
+	// the first 5 frozen
+	var (
+		blocks   []*types.Block
+		receipts []types.Receipts
+		parent   common.Hash
+	)
+	for i := 0; i < 10; i++ {
+		var txs types.Transactions
+		if i > 0 {
+			txs = append(txs, types.NewTransaction(uint64(i), common.Address{0x1}, big.NewInt(1), 21000, big.NewInt(1), nil))
+		}
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: parent}
+		block := types.NewBlock(header, &types.Body{Transactions: txs}, nil, newTestHasher())
+
+		WriteBlock(db, block)
+		WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{})
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		WriteTxLookupEntriesByBlock(db, block)
+
+		blocks = append(blocks, block)
+		receipts = append(receipts, types.Receipts{})
+		parent = block.Hash()
+	}
+	WriteHeadHeaderHash(db, parent)
+	WriteHeadBlockHash(db, parent)
+	WriteTxIndexTail(db, 0)
+
+	// Freeze the first blocks, leaving the frozen data in the key-value store
+	// as if the node crashed after freezing.
+	if _, err := WriteAncientBlocks(db, blocks[:5], receipts[:5]); err != nil {
+		t.Fatalf("Failed to freeze blocks: %v", err)
+	}
+	if err := VerifyDatabase(db, false); err == nil {
+		t.Fatal("Stale block data is not detected")
+	}
+	if err := VerifyDatabase(db, true); err != nil {
+		t.Fatalf("Failed to repair stale block data: %v", err)
+	}
+	if !HasHeader(db, blocks[3].Hash(), 3) || ReadHeaderNumber(db, blocks[3].Hash()) == nil {
+		t.Fatal("Frozen block is not retained")
+	}
+	if has, _ := db.Has(headerKey(3, blocks[3].Hash())); has {
+		t.Fatal("Stale header is not deleted")
+	}
+	// Corrupt the mappings and the transaction index
+	DeleteHeaderNumber(db, blocks[6].Hash())
+	WriteCanonicalHash(db, common.Hash{0xff}, 7)
+	WriteCanonicalHash(db, common.Hash{0xff}, 12)
+	DeleteTxLookupEntry(db, blocks[8].Transactions()[0].Hash())
+	WriteTxLookupEntries(db, 20, []common.Hash{{0xee}})
+
+	if err := VerifyDatabase(db, false); err == nil {
+		t.Fatal("Inconsistencies are not detected")
+	}
+	if err := VerifyDatabase(db, true); err != nil {
+		t.Fatalf("Failed to repair database: %v", err)
+	}
+	if err := VerifyDatabase(db, false); err != nil {
+		t.Fatalf("Database is inconsistent after repair: %v", err)
+	}
+	if n := ReadHeaderNumber(db, blocks[6].Hash()); n == nil || *n != 6 {
+		t.Fatal("Header number is not repaired")
+	}
+	if ReadCanonicalHash(db, 7) != blocks[7].Hash() {
+		t.Fatal("Canonical hash is not repaired")
+	}
+	if ReadCanonicalHash(db, 12) != (common.Hash{}) {
+		t.Fatal("Dangling canonical hash is not deleted")
+	}
+	if n := ReadTxLookupEntry(db, blocks[8].Transactions()[0].Hash()); n == nil || *n != 8 {
+		t.Fatal("Transaction index is not repaired")
+	}
+	if ReadTxLookupEntry(db, common.Hash{0xee}) != nil {
+		t.Fatal("Stale transaction index is not deleted")
+	}
+	// Missing block data is not repairable
+	DeleteBody(db, blocks[9].Hash(), 9)
+	if err := VerifyDatabase(db, true); err == nil {
+		t.Fatal("Missing body is not detected")
+	}
+}
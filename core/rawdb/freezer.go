@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -71,6 +72,9 @@ type Freezer struct {
 	tables       map[string]*freezerTable // Data tables for storing everything
 	instanceLock *flock.Flock             // File-system lock to prevent double opens
 	closeOnce    sync.Once
+
+	offloadStop context.CancelFunc // Terminates the offloading to the remote tier, nil if not running
+	offloadWg   sync.WaitGroup
 }
 
 // NewFreezer creates a freezer instance for maintaining immutable ordered
@@ -152,12 +156,33 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 	// Create the write batch.
 	freezer.writeBatch = newFreezerBatch(freezer)
 
+	// Start offloading the sealed data files if any table has a remote tier.
+	if !readonly {
+		for _, table := range freezer.tables {
+			if table.config.remote == nil {
+				continue
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			freezer.offloadStop = cancel
+			freezer.offloadWg.Add(1)
+			go func() {
+				defer freezer.offloadWg.Done()
+				freezer.offload(ctx)
+			}()
+			break
+		}
+	}
+
 	log.Info("Opened ancient database", "database", datadir, "readonly", readonly)
 	return freezer, nil
 }
 
 // Close terminates the chain freezer, closing all the data files.
 func (f *Freezer) Close() error {
+	if f.offloadStop != nil {
+		f.offloadStop()
+		f.offloadWg.Wait()
+	}
 	f.writeLock.Lock()
 	defer f.writeLock.Unlock()
 
@@ -0,0 +1,306 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// remoteChunkSize is the granularity of the reads from the remote tier,
+	// and the size of the entries in the read cache.
+	remoteChunkSize = 1024 * 1024
+
+	// remoteCacheSize is the maximum size of the read cache of the remote tier.
+	remoteCacheSize = 256 * 1024 * 1024
+
+	// remoteKeepFiles is the number of the most recent sealed data files which
+	// are always retained locally, besides the head file.
+	remoteKeepFiles = 1
+
+	// remoteOffloadInterval is the time interval between two offloading runs.
+	remoteOffloadInterval = time.Minute
+)
+
+var (
+	remoteReadMeter   = metrics.NewRegisteredMeter("ancient/remote/read", nil)
+	remoteUploadMeter = metrics.NewRegisteredMeter("ancient/remote/upload", nil)
+	remoteCacheHit    = metrics.NewRegisteredMeter("ancient/remote/cache/hit", nil)
+	remoteCacheMiss   = metrics.NewRegisteredMeter("ancient/remote/cache/miss", nil)
+)
+
+// RemoteStore is an object storage, e.g. an S3 compatible bucket, where the
+// sealed data files of the freezer tables are offloaded to.
+type RemoteStore interface {
+	// Upload stores the content of the given reader under the given name.
+	Upload(ctx context.Context, name string, r io.ReaderAt, size int64) error
+
+	// ReadAt reads len(p) bytes of the named object, starting at the given offset.
+	ReadAt(name string, p []byte, off int64) error
+
+	// Size returns the size of the named object. An error wrapping os.ErrNotExist
+	// is returned if the object is not present.
+	Size(name string) (int64, error)
+
+	// Delete removes the named object.
+	Delete(name string) error
+}
+
+// remoteTier is the remote storage tier of a freezer, shared by all its tables.
+type remoteTier struct {
+	store  RemoteStore
+	cache  *lru.SizeConstrainedCache[remoteChunk, []byte]
+	lastId atomic.Uint64 // Last identifier assigned to a remote data file
+}
+
+// remoteFile is a data file offloaded to the remote tier.
+type remoteFile struct {
+	id   uint64 // Unique identifier of the file, distinguishing the cached chunks
+	size int64  // Size of the file
+}
+
+// remoteChunk identifies a chunk of a data file in the remote tier.
+type remoteChunk struct {
+	file  uint64
+	index int64
+}
+
+// newRemoteTier creates the remote tier on top of the given object storage.
+func newRemoteTier(store RemoteStore) *remoteTier {
+	return &remoteTier{
+		store: store,
+		cache: lru.NewSizeConstrainedCache[remoteChunk, []byte](remoteCacheSize),
+	}
+}
+
+// track assigns an identifier to a data file of the given size in the remote tier.
+func (tier *remoteTier) track(size int64) remoteFile {
+	return remoteFile{id: tier.lastId.Add(1), size: size}
+}
+
+// readAt reads len(p) bytes of the named data file, starting at the given offset.
+// The data is fetched in chunks which are cached for the subsequent reads.
+func (tier *remoteTier) readAt(name string, file remoteFile, p []byte, off int64) error {
+	for len(p) > 0 {
+		var (
+			index = off / remoteChunkSize
+			start = index * remoteChunkSize
+			key   = remoteChunk{file: file.id, index: index}
+		)
+		chunk, ok := tier.cache.Get(key)
+		if ok {
+			remoteCacheHit.Mark(1)
+		} else {
+			remoteCacheMiss.Mark(1)
+			chunk = make([]byte, min(remoteChunkSize, file.size-start))
+			if err := tier.store.ReadAt(name, chunk, start); err != nil {
+				return err
+			}
+			remoteReadMeter.Mark(int64(len(chunk)))
+			tier.cache.Add(key, chunk)
+		}
+		if off-start >= int64(len(chunk)) {
+			return io.ErrUnexpectedEOF
+		}
+		n := copy(p, chunk[off-start:])
+		p, off = p[n:], off+int64(n)
+	}
+	return nil
+}
+
+// fileName returns the name of the data file with the given number.
+func (t *freezerTable) fileName(num uint32) string {
+	return freezerDataFileName(t.name, t.config.noSnappy, num)
+}
+
+// openRemote checks whether the data file with the given number is offloaded
+// to the remote tier, tracking it if so. Local data files take precedence, as
+// a crash might happen after the upload but before the removal of the local
+// copy. The caller must hold the write lock.
+func (t *freezerTable) openRemote(num uint32) (bool, error) {
+	tier := t.config.remote
+	if tier == nil {
+		return false, nil
+	}
+	name := t.fileName(num)
+	if _, err := os.Stat(filepath.Join(t.path, name)); err == nil {
+		return false, nil
+	}
+	size, err := tier.store.Size(name)
+	if err != nil {
+		return false, err
+	}
+	t.remoteFiles[num] = tier.track(size)
+	return true, nil
+}
+
+// offload moves the sealed data files to the remote tier, except for the most
+// recent ones. The files are immutable, so they are uploaded without holding
+// the lock, and the local copies are only removed once they're safely stored.
+func (t *freezerTable) offload(ctx context.Context) error {
+	for ctx.Err() == nil {
+		var (
+			num   uint32
+			found bool
+		)
+		t.lock.RLock()
+		if t.index != nil {
+			for i := t.tailId; i+remoteKeepFiles < t.headId; i++ {
+				if _, ok := t.remoteFiles[i]; !ok {
+					num, found = i, true
+					break
+				}
+			}
+		}
+		t.lock.RUnlock()
+
+		if !found {
+			return nil
+		}
+		if err := t.offloadFile(ctx, num); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// offloadFile uploads the data file with the given number to the remote tier
+// and removes the local copy.
+func (t *freezerTable) offloadFile(ctx context.Context, num uint32) error {
+	var (
+		tier  = t.config.remote
+		name  = t.fileName(num)
+		path  = filepath.Join(t.path, name)
+		start = time.Now()
+	)
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tier.store.Upload(ctx, name, f, stat.Size()); err != nil {
+		return err
+	}
+	if size, err := tier.store.Size(name); err != nil {
+		return err
+	} else if size != stat.Size() {
+		return fmt.Errorf("remote data file %s size mismatch: have %d, want %d", name, size, stat.Size())
+	}
+	remoteUploadMeter.Mark(stat.Size())
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	// The file might be truncated away in the meantime, drop the uploaded copy
+	if t.index == nil || num < t.tailId || num >= t.headId {
+		return tier.store.Delete(name)
+	}
+	t.releaseFile(num)
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	t.remoteFiles[num] = tier.track(stat.Size())
+	t.logger.Info("Offloaded freezer data file", "file", name, "size", common.StorageSize(stat.Size()), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// fetchFile downloads the data file with the given number from the remote tier
+// and removes the remote copy, e.g. when it becomes the head file again. The
+// caller must hold the write lock.
+func (t *freezerTable) fetchFile(num uint32) error {
+	var (
+		tier = t.config.remote
+		name = t.fileName(num)
+		size = t.remoteFiles[num].size
+	)
+	f, err := openFreezerFileTruncated(filepath.Join(t.path, name))
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, remoteChunkSize)
+	for off := int64(0); off < size; off += remoteChunkSize {
+		chunk := buf[:min(remoteChunkSize, size-off)]
+		if err := tier.store.ReadAt(name, chunk, off); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write(chunk); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	delete(t.remoteFiles, num)
+	t.logger.Info("Restored freezer data file", "file", name, "size", common.StorageSize(size))
+	return tier.store.Delete(name)
+}
+
+// releaseRemoteFiles removes the offloaded data files outside of the range
+// [tail, head] from the remote tier. The caller must hold the write lock.
+func (t *freezerTable) releaseRemoteFiles(tail, head uint32) {
+	for num := range t.remoteFiles {
+		if num >= tail && num <= head {
+			continue
+		}
+		delete(t.remoteFiles, num)
+		if err := t.config.remote.store.Delete(t.fileName(num)); err != nil {
+			t.logger.Warn("Failed to delete remote data file", "file", t.fileName(num), "err", err)
+		}
+	}
+}
+
+// offload runs the offloading of the sealed data files of all tables to the
+// remote tier periodically, until the freezer is closed.
+func (f *Freezer) offload(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			for _, table := range f.tables {
+				if err := table.offload(ctx); err != nil && ctx.Err() == nil {
+					table.logger.Error("Failed to offload freezer data files", "err", err)
+				}
+			}
+			timer.Reset(remoteOffloadInterval)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// memoryRemoteStore is an in-memory remote store used for testing.
+type memoryRemoteStore struct {
+	objects map[string][]byte
+	lock    sync.Mutex
+}
+
+func newMemoryRemoteStore() *memoryRemoteStore {
+	return &memoryRemoteStore{objects: make(map[string][]byte)}
+}
+
+func (s *memoryRemoteStore) Upload(ctx context.Context, name string, r io.ReaderAt, size int64) error {
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil && err != io.EOF {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.objects[name] = data
+	return nil
+}
+
+func (s *memoryRemoteStore) ReadAt(name string, p []byte, off int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, ok := s.objects[name]
+	if !ok {
+		return fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	if off+int64(len(p)) > int64(len(data)) {
+		return io.ErrUnexpectedEOF
+	}
+	copy(p, data[off:])
+	return nil
+}
+
+func (s *memoryRemoteStore) Size(name string) (int64, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, ok := s.objects[name]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return int64(len(data)), nil
+}
+
+func (s *memoryRemoteStore) Delete(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.objects, name)
+	return nil
+}
+
+func TestFreezerRemoteTier(t *testing.T) {
+	var (
+		rm, wm, sg = metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge()
+		dir        = t.TempDir()
+		store      = newMemoryRemoteStore()
+		config     = freezerTableConfig{noSnappy: true, remote: newRemoteTier(store)}
+	)
+	// Write 10 items of 20 bytes, spread into five data files
+	f, err := newTable(dir, "remote", rm, wm, sg, 40, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeChunks(t, f, 10, 20)
+
+	// Offload the sealed files except for the most recent one
+	if err := f.offload(context.Background()); err != nil {
+		t.Fatalf("Failed to offload data files: %v", err)
+	}
+	for num := uint32(0); num < 5; num++ {
+		_, local := os.Stat(filepath.Join(dir, f.fileName(num)))
+		_, remote := store.objects[f.fileName(num)]
+		if want := num < 3; remote != want || (local == nil) == want {
+			t.Fatalf("Data file %d: remote %t, local %t", num, remote, local == nil)
+		}
+	}
+	checkRetrieve(t, f, map[uint64][]byte{
+		0: getChunk(20, 0),
+		3: getChunk(20, 3),
+		5: getChunk(20, 5),
+		9: getChunk(20, 9),
+	})
+	// Reopen the table, the offloaded files should be tracked again
+	f.Close()
+	config.remote = newRemoteTier(store)
+	f, err = newTable(dir, "remote", rm, wm, sg, 40, config, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for i := 0; i < 10; i++ {
+		checkRetrieve(t, f, map[uint64][]byte{uint64(i): getChunk(20, i)})
+	}
+	// Truncating the tail should remove the stale remote files
+	if err := f.truncateTail(4); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.objects) != 1 {
+		t.Fatalf("Unexpected remote files after tail truncation: %d", len(store.objects))
+	}
+	checkRetrieveError(t, f, map[uint64]error{3: errOutOfBounds})
+	checkRetrieve(t, f, map[uint64][]byte{4: getChunk(20, 4)})
+
+	// Truncating the head into an offloaded file should restore it locally
+	if err := f.truncateHead(5); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.objects) != 0 {
+		t.Fatalf("Unexpected remote files after head truncation: %d", len(store.objects))
+	}
+	checkRetrieve(t, f, map[uint64][]byte{4: getChunk(20, 4)})
+	checkRetrieveError(t, f, map[uint64]error{5: errOutOfBounds})
+
+	// Appending to the restored head file should work as usual
+	batch := f.newBatch()
+	if err := batch.AppendRaw(5, getChunk(20, 0x55)); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatal(err)
+	}
+	checkRetrieve(t, f, map[uint64][]byte{4: getChunk(20, 4), 5: getChunk(20, 0x55)})
+}
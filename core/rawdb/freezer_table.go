@@ -112,6 +112,8 @@ type freezerTable struct {
 	headId uint32              // number of the currently active head file
 	tailId uint32              // number of the earliest file
 
+	remoteFiles map[uint32]remoteFile // data files offloaded to the remote tier
+
 	metadata *freezerTableMeta // metadata of the table
 	lastSync time.Time         // Timestamp when the last sync was performed
 
@@ -137,12 +139,7 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, err
 	}
-	var idxName string
-	if config.noSnappy {
-		idxName = fmt.Sprintf("%s.ridx", name) // raw index file
-	} else {
-		idxName = fmt.Sprintf("%s.cidx", name) // compressed index file
-	}
+	idxName := freezerIndexFileName(name, config.noSnappy)
 	var (
 		err   error
 		index *os.File
@@ -180,6 +177,7 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 		metadata:    metadata,
 		lastSync:    time.Now(),
 		files:       make(map[uint32]*os.File),
+		remoteFiles: make(map[uint32]remoteFile),
 		readMeter:   readMeter,
 		writeMeter:  writeMeter,
 		sizeGauge:   sizeGauge,
@@ -581,8 +579,16 @@ func (t *freezerTable) preopen() (err error) {
 	// The repair might have already opened (some) files
 	t.releaseFilesAfter(0, false)
 
-	// Open all except head in RDONLY
+	// Open all except head in RDONLY, unless they are offloaded to the remote tier
+	clear(t.remoteFiles)
 	for i := t.tailId; i < t.headId; i++ {
+		remote, err := t.openRemote(i)
+		if err != nil {
+			return err
+		}
+		if remote {
+			continue
+		}
 		if _, err = t.openFile(i, openFreezerFileForReadOnly); err != nil {
 			return err
 		}
@@ -654,6 +660,12 @@ func (t *freezerTable) truncateHead(items uint64) error {
 	}
 	// We might need to truncate back to older files
 	if expected.filenum != t.headId {
+		// If offloaded to the remote tier, bring it back for writing
+		if _, remote := t.remoteFiles[expected.filenum]; remote {
+			if err := t.fetchFile(expected.filenum); err != nil {
+				return err
+			}
+		}
 		// If already open for reading, force-reopen for writing
 		t.releaseFile(expected.filenum)
 		newHead, err := t.openFile(expected.filenum, openFreezerFileForAppend)
@@ -663,6 +675,7 @@ func (t *freezerTable) truncateHead(items uint64) error {
 		// Release any files _after the current head -- both the previous head
 		// and any files which may have been opened for reading
 		t.releaseFilesAfter(expected.filenum, true)
+		t.releaseRemoteFiles(t.tailId, expected.filenum)
 
 		// Set back the historic head
 		t.head = newHead
@@ -810,6 +823,7 @@ func (t *freezerTable) truncateTail(items uint64) error {
 	t.tailId = newTailId
 	t.itemOffset.Store(newDeleted)
 	t.releaseFilesBefore(t.tailId, true)
+	t.releaseRemoteFiles(t.tailId, t.headId)
 
 	// Move the index flush offset backward due to the deletion of an index segment.
 	// A crash may occur before the offset is updated, leaving a dangling reference
@@ -870,13 +884,7 @@ func (t *freezerTable) Close() error {
 func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error)) (f *os.File, err error) {
 	var exist bool
 	if f, exist = t.files[num]; !exist {
-		var name string
-		if t.config.noSnappy {
-			name = fmt.Sprintf("%s.%04d.rdat", t.name, num)
-		} else {
-			name = fmt.Sprintf("%s.%04d.cdat", t.name, num)
-		}
-		f, err = opener(filepath.Join(t.path, name))
+		f, err = opener(filepath.Join(t.path, t.fileName(num)))
 		if err != nil {
 			return nil, err
 		}
@@ -1043,7 +1051,14 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 		output = grow(output, length)
 		dataFile, exist := t.files[fileId]
 		if !exist {
-			return fmt.Errorf("missing data file %d", fileId)
+			file, remote := t.remoteFiles[fileId]
+			if !remote {
+				return fmt.Errorf("missing data file %d", fileId)
+			}
+			if err := t.config.remote.readAt(t.fileName(fileId), file, output[len(output)-length:], int64(start)); err != nil {
+				return fmt.Errorf("%w, fileid: %d, start: %d, length: %d", err, fileId, start, length)
+			}
+			return nil
 		}
 		if _, err := dataFile.ReadAt(output[len(output)-length:], int64(start)); err != nil {
 			return fmt.Errorf("%w, fileid: %d, start: %d, length: %d", err, fileId, start, length)
@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -76,6 +77,22 @@ func copyFrom(srcPath, destPath string, offset uint64, before func(f *os.File) e
 	return os.Rename(fname, destPath)
 }
 
+// freezerIndexFileName returns the name of the index file of a freezer table.
+func freezerIndexFileName(name string, noSnappy bool) string {
+	if noSnappy {
+		return fmt.Sprintf("%s.ridx", name) // raw index file
+	}
+	return fmt.Sprintf("%s.cidx", name) // compressed index file
+}
+
+// freezerDataFileName returns the name of a data file of a freezer table.
+func freezerDataFileName(name string, noSnappy bool, num uint32) string {
+	if noSnappy {
+		return fmt.Sprintf("%s.%04d.rdat", name, num)
+	}
+	return fmt.Sprintf("%s.%04d.cdat", name, num)
+}
+
 // openFreezerFileForAppend opens a freezer table file and seeks to the end
 func openFreezerFileForAppend(filename string) (*os.File, error) {
 	// Open the file without the O_APPEND flag
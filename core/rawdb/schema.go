@@ -46,6 +46,9 @@ var (
 	// persistentStateIDKey tracks the id of latest stored state(for path-based only).
 	persistentStateIDKey = []byte("LastStateID")
 
+	// headStateHistoryIndexKey tracks the id of the latest indexed state history(for path-based only).
+	headStateHistoryIndexKey = []byte("LastStateHistoryIndex")
+
 	// lastPivotKey tracks the last pivot block used by fast sync (to reenable on sethead).
 	lastPivotKey = []byte("LastPivot")
 
@@ -76,6 +79,10 @@ var (
 	// trieJournalKey tracks the in-memory trie node layers across restarts.
 	trieJournalKey = []byte("TrieJournal")
 
+	// onlinePruningKey tracks the sweep progress of the online state pruning
+	// across restarts.
+	onlinePruningKey = []byte("OnlinePruning")
+
 	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
 	txIndexTailKey = []byte("TransactionIndexTail")
 
@@ -117,6 +124,11 @@ var (
 	TrieNodeStoragePrefix = []byte("O") // TrieNodeStoragePrefix + accountHash + hexPath -> trie node
 	stateIDPrefix         = []byte("L") // stateIDPrefix + state root -> state id
 
+	// State history index of the path-based scheme.
+	StateHistoryIndexPrefix        = []byte("m")  // StateHistoryIndexPrefix + ... -> state history index data
+	StateHistoryAccountIndexPrefix = []byte("ma") // StateHistoryAccountIndexPrefix + account hash + last id (uint64 big endian) -> index block
+	StateHistoryStorageIndexPrefix = []byte("ms") // StateHistoryStorageIndexPrefix + account hash + storage hash + last id (uint64 big endian) -> index block
+
 	// VerklePrefix is the database prefix for Verkle trie data, which includes:
 	// (a) Trie nodes
 	// (b) In-memory trie node journal
@@ -157,6 +169,22 @@ type LegacyTxLookupEntry struct {
 	Index      uint64
 }
 
+// SeparableKeyspaces returns the prefixes of the keyspaces which may be stored
+// apart from the rest of the database, keyed by their names. They only hold
+// indices, which tolerate running ahead of their tail and head markers kept
+// in the main keyspace, so they don't need to be written atomically with it.
+//
+// Trie nodes and snapshots are deliberately not separable: they are committed
+// in the same batch as the persistent state id and snapshot root markers, and
+// a crash between writing them to different instances would leave the state
+// unrecoverable. Their tuning is left to the main instance of the profiles.
+func SeparableKeyspaces() map[string][]byte {
+	return map[string][]byte{
+		"txlookup":   txLookupPrefix,
+		"filtermaps": []byte(filterMapsPrefix),
+	}
+}
+
 // encodeBlockNumber encodes a block number as big endian uint64
 func encodeBlockNumber(number uint64) []byte {
 	enc := make([]byte, 8)
@@ -257,6 +285,25 @@ func stateIDKey(root common.Hash) []byte {
 	return append(stateIDPrefix, root.Bytes()...)
 }
 
+// accountHistoryIndexKey = StateHistoryAccountIndexPrefix + account hash + last id (uint64 big endian)
+func accountHistoryIndexKey(accountHash common.Hash, last uint64) []byte {
+	buf := make([]byte, len(StateHistoryAccountIndexPrefix)+common.HashLength+8)
+	n := copy(buf, StateHistoryAccountIndexPrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], last)
+	return buf
+}
+
+// storageHistoryIndexKey = StateHistoryStorageIndexPrefix + account hash + storage hash + last id (uint64 big endian)
+func storageHistoryIndexKey(accountHash, storageHash common.Hash, last uint64) []byte {
+	buf := make([]byte, len(StateHistoryStorageIndexPrefix)+2*common.HashLength+8)
+	n := copy(buf, StateHistoryStorageIndexPrefix)
+	n += copy(buf[n:], accountHash.Bytes())
+	n += copy(buf[n:], storageHash.Bytes())
+	binary.BigEndian.PutUint64(buf[n:], last)
+	return buf
+}
+
 // accountTrieNodeKey = TrieNodeAccountPrefix + nodePath.
 func accountTrieNodeKey(path []byte) []byte {
 	return append(TrieNodeAccountPrefix, path...)
@@ -273,6 +273,8 @@ func mustCopyTrie(t Trie) Trie {
 		return t.Copy()
 	case *trie.VerkleTrie:
 		return t.Copy()
+	case *historicTrie:
+		return t // immutable, safe to share
 	default:
 		panic(fmt.Errorf("unknown trie type %T", t))
 	}
@@ -0,0 +1,239 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/trie/utils"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+)
+
+// errHistoricState is returned if the historical state is attempted to be
+// modified or iterated.
+var errHistoricState = errors.New("historical state is read-only")
+
+// historicReader wraps a historical state reader of the path database.
+type historicReader struct {
+	reader *pathdb.HistoricalStateReader
+}
+
+// newHistoricReader constructs a reader for historical state access.
+func newHistoricReader(r *pathdb.HistoricalStateReader) *historicReader {
+	return &historicReader{reader: r}
+}
+
+// Account implements StateReader, retrieving the account specified by the address.
+//
+// The returned account might be nil if it's not existent.
+func (r *historicReader) Account(addr common.Address) (*types.StateAccount, error) {
+	account, err := r.reader.Account(addr)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, nil
+	}
+	acct := &types.StateAccount{
+		Nonce:    account.Nonce,
+		Balance:  account.Balance,
+		CodeHash: account.CodeHash,
+		Root:     common.BytesToHash(account.Root),
+	}
+	if len(acct.CodeHash) == 0 {
+		acct.CodeHash = types.EmptyCodeHash.Bytes()
+	}
+	if acct.Root == (common.Hash{}) {
+		acct.Root = types.EmptyRootHash
+	}
+	return acct, nil
+}
+
+// Storage implements StateReader, retrieving the storage slot specified by the
+// address and slot key.
+//
+// The returned storage slot might be empty if it's not existent.
+func (r *historicReader) Storage(addr common.Address, key common.Hash) (common.Hash, error) {
+	blob, err := r.reader.Storage(addr, key)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(blob) == 0 {
+		return common.Hash{}, nil
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var value common.Hash
+	value.SetBytes(content)
+	return value, nil
+}
+
+// HistoricDB is an implementation of Database interface, providing read-only
+// access to the historical states retained in the state histories of the path
+// database.
+type HistoricDB struct {
+	disk          ethdb.KeyValueStore
+	triedb        *triedb.Database
+	codeCache     *lru.SizeConstrainedCache[common.Hash, []byte]
+	codeSizeCache *lru.Cache[common.Hash, int]
+	pointCache    *utils.PointCache
+}
+
+// NewHistoricDatabase creates a historic state database with the provided data
+// sources.
+func NewHistoricDatabase(disk ethdb.KeyValueStore, triedb *triedb.Database) *HistoricDB {
+	return &HistoricDB{
+		disk:          disk,
+		triedb:        triedb,
+		codeCache:     lru.NewSizeConstrainedCache[common.Hash, []byte](codeCacheSize),
+		codeSizeCache: lru.NewCache[common.Hash, int](codeSizeCacheSize),
+		pointCache:    utils.NewPointCache(pointCacheSize),
+	}
+}
+
+// Reader implements Database interface, returning a reader of the specific state.
+func (db *HistoricDB) Reader(stateRoot common.Hash) (Reader, error) {
+	hr, err := db.triedb.HistoricReader(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return newReader(newCachingCodeReader(db.disk, db.codeCache, db.codeSizeCache), newHistoricReader(hr)), nil
+}
+
+// OpenTrie opens the main account trie. The returned trie only serves reads
+// from the historical state, the trie nodes of which are no longer available.
+func (db *HistoricDB) OpenTrie(root common.Hash) (Trie, error) {
+	hr, err := db.triedb.HistoricReader(root)
+	if err != nil {
+		return nil, err
+	}
+	return &historicTrie{root: root, reader: newHistoricReader(hr)}, nil
+}
+
+// OpenStorageTrie opens the storage trie of an account, which only serves reads
+// from the historical state.
+func (db *HistoricDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	hr, err := db.triedb.HistoricReader(stateRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &historicTrie{root: root, reader: newHistoricReader(hr)}, nil
+}
+
+// PointCache returns the cache holding points used in verkle tree key computation.
+func (db *HistoricDB) PointCache() *utils.PointCache {
+	return db.pointCache
+}
+
+// TrieDB returns the underlying trie database for managing trie nodes.
+func (db *HistoricDB) TrieDB() *triedb.Database {
+	return db.triedb
+}
+
+// Snapshot returns the underlying state snapshot, which is not available for
+// historical states.
+func (db *HistoricDB) Snapshot() *snapshot.Tree {
+	return nil
+}
+
+// historicTrie implements the Trie interface on top of a historical state
+// reader. It's read-only, all mutations are rejected.
+type historicTrie struct {
+	root   common.Hash
+	reader *historicReader
+}
+
+// GetKey implements Trie, preimages are not available.
+func (t *historicTrie) GetKey([]byte) []byte {
+	return nil
+}
+
+// GetAccount implements Trie, retrieving the account from the historical state.
+func (t *historicTrie) GetAccount(address common.Address) (*types.StateAccount, error) {
+	return t.reader.Account(address)
+}
+
+// GetStorage implements Trie, retrieving the storage slot from the historical state.
+func (t *historicTrie) GetStorage(addr common.Address, key []byte) ([]byte, error) {
+	value, err := t.reader.Storage(addr, common.BytesToHash(key))
+	if err != nil || value == (common.Hash{}) {
+		return nil, err
+	}
+	return common.TrimLeftZeroes(value[:]), nil
+}
+
+func (t *historicTrie) UpdateAccount(address common.Address, account *types.StateAccount, codeLen int) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) UpdateStorage(addr common.Address, key, value []byte) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) DeleteAccount(address common.Address) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) DeleteStorage(addr common.Address, key []byte) error {
+	return errHistoricState
+}
+
+func (t *historicTrie) UpdateContractCode(address common.Address, codeHash common.Hash, code []byte) error {
+	return errHistoricState
+}
+
+// Hash implements Trie, returning the root of the historical state as it's
+// never modified.
+func (t *historicTrie) Hash() common.Hash {
+	return t.root
+}
+
+// Commit implements Trie, nothing is committed as it's never modified.
+func (t *historicTrie) Commit(collectLeaf bool) (common.Hash, *trienode.NodeSet) {
+	return t.root, nil
+}
+
+// Witness implements Trie, trie nodes are never accessed.
+func (t *historicTrie) Witness() map[string]struct{} {
+	return nil
+}
+
+// NodeIterator implements Trie, iteration is not supported.
+func (t *historicTrie) NodeIterator(startKey []byte) (trie.NodeIterator, error) {
+	return nil, errHistoricState
+}
+
+// Prove implements Trie, proving is not supported.
+func (t *historicTrie) Prove(key []byte, proofDb ethdb.KeyValueWriter) error {
+	return errHistoricState
+}
+
+// IsVerkle implements Trie.
+func (t *historicTrie) IsVerkle() bool {
+	return false
+}
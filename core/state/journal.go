@@ -73,6 +73,25 @@ func (j *journal) reset() {
 	j.nextRevisionId = 0
 }
 
+// seal is called in place of reset at the end of a transaction if the journal
+// is retained across transactions. It drops the entries only relevant within a
+// transaction (access list, transient storage and refund changes), which are
+// reset by the next one anyway, and invalidates all revisions.
+func (j *journal) seal() {
+	entries := j.entries[:0]
+	for _, entry := range j.entries {
+		switch entry.(type) {
+		case accessListAddAccountChange, accessListAddSlotChange, transientStorageChange, refundChange:
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	clear(j.entries[len(entries):])
+	j.entries = entries
+	j.validRevisions = j.validRevisions[:0]
+	clear(j.dirties)
+}
+
 // snapshot returns an identifier for the current revision of the state.
 func (j *journal) snapshot() int {
 	id := j.nextRevisionId
@@ -217,6 +236,43 @@ func (j *journal) touchChange(address common.Address) {
 	}
 }
 
+func (j *journal) finaliseDelete(obj *stateObject, prev *mutation, destructed bool) {
+	entry := finaliseDeleteChange{
+		object:     obj,
+		destructed: destructed,
+	}
+	if prev != nil {
+		entry.mutation = prev.copy()
+	}
+	j.append(entry)
+}
+
+func (j *journal) finaliseUpdate(obj *stateObject, prev *mutation) {
+	entry := finaliseUpdateChange{
+		object:      obj,
+		dirty:       obj.dirtyStorage,
+		pending:     make(map[common.Hash]*common.Hash, len(obj.dirtyStorage)),
+		uncommitted: make(map[common.Hash]*common.Hash, len(obj.dirtyStorage)),
+		newContract: obj.newContract,
+	}
+	for key := range obj.dirtyStorage {
+		if value, ok := obj.pendingStorage[key]; ok {
+			entry.pending[key] = &value
+		} else {
+			entry.pending[key] = nil
+		}
+		if value, ok := obj.uncommittedStorage[key]; ok {
+			entry.uncommitted[key] = &value
+		} else {
+			entry.uncommitted[key] = nil
+		}
+	}
+	if prev != nil {
+		entry.mutation = prev.copy()
+	}
+	j.append(entry)
+}
+
 func (j *journal) accessListAddAccount(addr common.Address) {
 	j.append(accessListAddAccountChange{addr})
 }
@@ -288,8 +344,82 @@ type (
 		account       common.Address
 		key, prevalue common.Hash
 	}
+
+	// Changes made by finalising a transaction, only tracked if the journal
+	// is retained across transactions.
+	finaliseDeleteChange struct {
+		object     *stateObject
+		mutation   *mutation // Mutation tracked before deletion, nil if none
+		destructed bool      // Whether the object was marked as destructed
+	}
+	finaliseUpdateChange struct {
+		object      *stateObject
+		mutation    *mutation                    // Mutation tracked before update, nil if none
+		dirty       Storage                      // Dirty storage aggregated by finalisation
+		pending     map[common.Hash]*common.Hash // Pending slots overwritten, nil if not present
+		uncommitted map[common.Hash]*common.Hash // Uncommitted slots overwritten, nil if not present
+		newContract bool
+	}
 )
 
+// restoreMutation sets the tracked mutation of an account back to a previous
+// one, or drops it if none was tracked.
+func restoreMutation(s *StateDB, addr common.Address, prev *mutation) {
+	if prev == nil {
+		delete(s.mutations, addr)
+	} else {
+		s.mutations[addr] = prev.copy()
+	}
+}
+
+// restoreSlots sets the given storage slots back to their previous values, or
+// drops them if they were not present.
+func restoreSlots(storage Storage, prev map[common.Hash]*common.Hash) {
+	for key, value := range prev {
+		if value == nil {
+			delete(storage, key)
+		} else {
+			storage[key] = *value
+		}
+	}
+}
+
+func (ch finaliseDeleteChange) revert(s *StateDB) {
+	s.stateObjects[ch.object.address] = ch.object
+	restoreMutation(s, ch.object.address, ch.mutation)
+	if ch.destructed {
+		delete(s.stateObjectsDestruct, ch.object.address)
+	}
+}
+
+func (ch finaliseDeleteChange) dirtied() *common.Address {
+	return nil
+}
+
+// copy returns the entry referencing the same state object. It can't be used
+// on the copied state, which never retains the journal across transactions.
+func (ch finaliseDeleteChange) copy() journalEntry {
+	return ch
+}
+
+func (ch finaliseUpdateChange) revert(s *StateDB) {
+	ch.object.dirtyStorage = ch.dirty
+	restoreSlots(ch.object.pendingStorage, ch.pending)
+	restoreSlots(ch.object.uncommittedStorage, ch.uncommitted)
+	ch.object.newContract = ch.newContract
+	restoreMutation(s, ch.object.address, ch.mutation)
+}
+
+func (ch finaliseUpdateChange) dirtied() *common.Address {
+	return nil
+}
+
+// copy returns the entry referencing the same state object. It can't be used
+// on the copied state, which never retains the journal across transactions.
+func (ch finaliseUpdateChange) copy() journalEntry {
+	return ch
+}
+
 func (ch createObjectChange) revert(s *StateDB) {
 	delete(s.stateObjects, ch.account)
 }
@@ -0,0 +1,574 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// errPruningInterrupted is returned if the online pruning is interrupted by
+// the shutdown of the pruner.
+var errPruningInterrupted = errors.New("pruning interrupted")
+
+// syncCheckInterval is the time interval between two checks whether the node is
+// syncing, pausing a running pruning or postponing a scheduled one. It's a
+// variable so tests can lower it.
+var syncCheckInterval = 10 * time.Second
+
+// OnlineConfig includes all the configurations for online pruning.
+type OnlineConfig struct {
+	BloomSize uint64        // The Megabytes of memory allocated to bloom-filter
+	Interval  time.Duration // The time interval between two pruning runs
+}
+
+// syncBloom is a state bloom filter which is safe for concurrent use, as the
+// trie nodes are marked by the pruner and the trie database simultaneously.
+type syncBloom struct {
+	bloom *stateBloom
+	lock  sync.Mutex
+}
+
+// Put implements the KeyValueWriter interface, marking the given key.
+func (b *syncBloom) Put(key []byte, value []byte) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.bloom.Put(key, value)
+}
+
+// Delete removes the key from the key-value data store.
+func (b *syncBloom) Delete(key []byte) error { panic("not supported") }
+
+// OnlinePruner is a background service to prune the stale state of a running
+// node in the legacy hash based scheme. Unlike the offline Pruner, the state
+// keeps changing while it's running, so the workflow is slightly different:
+//
+//   - install a hook into the trie database, marking all the trie nodes
+//     persisted from now on as alive
+//   - mark the trie nodes of the recent states tracked by the snapshot diff
+//     layers, which are alive in the trie database, relative to the most
+//     recent state persisted in full
+//   - mark the entire persisted state and the genesis state
+//   - iterate the database, delete all the unmarked trie nodes in small
+//     throttled batches
+//
+// The contract codes are left untouched as they are written without going
+// through the trie database. The sweep progress is persisted periodically,
+// an interrupted pruning is resumed after the restart with a fresh marking.
+type OnlinePruner struct {
+	config   OnlineConfig
+	db       ethdb.Database
+	triedb   *triedb.Database
+	snaptree *snapshot.Tree
+
+	syncing atomic.Pointer[func() bool] // Reports whether the node is syncing, nil if unknown
+
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// NewOnlinePruner creates the online pruner and starts pruning in background.
+func NewOnlinePruner(db ethdb.Database, triedb *triedb.Database, snaptree *snapshot.Tree, config OnlineConfig) *OnlinePruner {
+	// Sanitize the bloom filter size if it's too small.
+	if config.BloomSize < 256 {
+		log.Warn("Sanitizing bloomfilter size", "provided(MB)", config.BloomSize, "updated(MB)", 256)
+		config.BloomSize = 256
+	}
+	p := &OnlinePruner{
+		config:   config,
+		db:       db,
+		triedb:   triedb,
+		snaptree: snaptree,
+		term:     make(chan chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	go p.loop()
+
+	log.Info("Initialized online state pruner", "interval", common.PrettyDuration(config.Interval))
+	return p
+}
+
+// SetSyncing installs the function reporting whether the node is syncing. The
+// pruning is not started and paused while it reports true, as the state is
+// rewritten at a high rate by the sync.
+func (p *OnlinePruner) SetSyncing(syncing func() bool) {
+	p.syncing.Store(&syncing)
+}
+
+// isSyncing reports whether the node is syncing.
+func (p *OnlinePruner) isSyncing() bool {
+	if rawdb.ReadSnapSyncStatusFlag(p.db) == rawdb.StateSyncRunning {
+		return true
+	}
+	if syncing := p.syncing.Load(); syncing != nil {
+		return (*syncing)()
+	}
+	return false
+}
+
+// loop is the scheduler of the pruner. An interrupted pruning is resumed right
+// away, otherwise the state is pruned once per configured interval. Pruning is
+// postponed while the node is syncing, and paused if the sync starts meanwhile.
+func (p *OnlinePruner) loop() {
+	defer close(p.closed)
+
+	var (
+		stop   chan struct{} // Non-nil if background routine is active
+		done   chan struct{} // Non-nil if background routine is active
+		paused bool          // Whether the active routine is stopped due to syncing
+		delay  = p.config.Interval
+	)
+	if _, _, ok := rawdb.ReadOnlinePruningMarker(p.db); ok {
+		delay = 0
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	check := time.NewTicker(syncCheckInterval)
+	defer check.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			if p.isSyncing() {
+				log.Debug("Postponing online state pruning while syncing")
+				timer.Reset(syncCheckInterval)
+				continue
+			}
+			stop = make(chan struct{})
+			done = make(chan struct{})
+			go func(stop, done chan struct{}) {
+				defer close(done)
+
+				if err := p.prune(stop); err != nil {
+					if errors.Is(err, errPruningInterrupted) {
+						log.Info("Online state pruning interrupted")
+					} else {
+						log.Error("Online state pruning failed", "err", err)
+					}
+				}
+			}(stop, done)
+		case <-check.C:
+			if stop != nil && !paused && p.isSyncing() {
+				log.Info("Pausing online state pruning while syncing")
+				close(stop)
+				paused = true
+			}
+		case <-done:
+			// A paused pruning is resumed as soon as the sync is over.
+			if paused {
+				timer.Reset(syncCheckInterval)
+			} else {
+				timer.Reset(p.config.Interval)
+			}
+			stop, done, paused = nil, nil, false
+		case ch := <-p.term:
+			if stop != nil && !paused {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background state pruner to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// Close terminates the pruner, an ongoing pruning is interrupted and resumed
+// after the restart. Safe to be called for multiple times.
+func (p *OnlinePruner) Close() {
+	ch := make(chan struct{})
+	select {
+	case p.term <- ch:
+		<-ch
+	case <-p.closed:
+	}
+}
+
+// prune runs a complete round of online pruning. It returns errPruningInterrupted
+// if the stop channel is closed in the middle.
+func (p *OnlinePruner) prune(stop chan struct{}) error {
+	start := time.Now()
+
+	block := rawdb.ReadHeadBlock(p.db)
+	if block == nil {
+		return errors.New("failed to load head block")
+	}
+	head := block.Header()
+	bloom, err := newStateBloomWithSize(p.config.BloomSize)
+	if err != nil {
+		return err
+	}
+	marker := &syncBloom{bloom: bloom}
+
+	// Mark all trie nodes persisted from now on, they are either referenced by
+	// the new states, or are about to be pruned in the next round.
+	if err := p.triedb.SetWriteHook(func(hash common.Hash) { marker.Put(hash.Bytes(), nil) }); err != nil {
+		return err
+	}
+	defer p.triedb.SetWriteHook(nil)
+
+	// Mark the trie nodes of the recent states first, they will be garbage
+	// collected from the trie database shortly otherwise. These states are
+	// diffed against the most recent state persisted in full, which is
+	// marked in its entirety afterwards.
+	base, err := p.findBase(head, stop)
+	if err != nil {
+		return err
+	}
+	if err := p.markRecent(head, base, marker, stop); err != nil {
+		return err
+	}
+	if err := markState(p.triedb, base, marker, stop); err != nil {
+		return err
+	}
+	if err := extractGenesis(p.db, marker); err != nil {
+		return err
+	}
+	log.Info("Marked alive state", "root", base, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	return p.sweep(base, marker, stop, start)
+}
+
+// findBase looks up the state of the latest canonical block which is persisted
+// in full. The presence of the root node implies the presence of the entire
+// trie, as the children are always persisted before the parents.
+func (p *OnlinePruner) findBase(head *types.Header, stop chan struct{}) (common.Hash, error) {
+	if rawdb.HasLegacyTrieNode(p.db, head.Root) {
+		return head.Root, nil
+	}
+	for number := head.Number.Uint64(); number > 0; number-- {
+		if interrupted(stop) {
+			return common.Hash{}, errPruningInterrupted
+		}
+		header := rawdb.ReadHeader(p.db, rawdb.ReadCanonicalHash(p.db, number-1), number-1)
+		if header == nil {
+			return common.Hash{}, fmt.Errorf("missing canonical header %d", number-1)
+		}
+		if rawdb.HasLegacyTrieNode(p.db, header.Root) {
+			return header.Root, nil
+		}
+	}
+	return common.Hash{}, errors.New("no persisted state")
+}
+
+// markRecent marks the trie nodes of the recent states tracked by the snapshot
+// diff layers, which are not part of the given base state.
+func (p *OnlinePruner) markRecent(head *types.Header, base common.Hash, bloom ethdb.KeyValueWriter, stop chan struct{}) error {
+	var (
+		layers = p.snaptree.Snapshots(head.Root, 128, true)
+		roots  []common.Hash
+		seen   = make(map[common.Hash]struct{})
+	)
+	// Pin the states alive in the trie database, in the order from the oldest
+	// to the newest. The states which are already garbage collected are out
+	// of the retention window, skip them.
+	for i := len(layers) - 1; i >= 0; i-- {
+		root := layers[i].Root()
+		if _, ok := seen[root]; ok || root == base || rawdb.HasLegacyTrieNode(p.db, root) {
+			continue
+		}
+		seen[root] = struct{}{}
+
+		p.triedb.Reference(root, common.Hash{})
+		if _, err := trie.NewStateTrie(trie.StateTrieID(root), p.triedb); err != nil {
+			log.Debug("Skipping unavailable recent state", "root", root)
+			continue
+		}
+		roots = append(roots, root)
+	}
+	defer func() {
+		for _, root := range roots {
+			p.triedb.Dereference(root)
+		}
+	}()
+	// Mark the difference between each pair of consecutive states, eventually
+	// covering all the trie nodes of the recent states.
+	parent := base
+	for _, root := range roots {
+		if err := markDiff(p.triedb, parent, root, bloom, stop); err != nil {
+			return err
+		}
+		parent = root
+	}
+	log.Info("Marked recent states", "states", len(roots))
+	return nil
+}
+
+// sweep deletes all the unmarked trie nodes from the database. The deletions
+// are throttled in order to leave sufficient disk bandwidth for the running
+// node, and rechecked against the bloom right before the write as the trie
+// nodes might be persisted again in the meantime.
+func (p *OnlinePruner) sweep(root common.Hash, bloom *syncBloom, stop chan struct{}, start time.Time) error {
+	_, position, ok := rawdb.ReadOnlinePruningMarker(p.db)
+	if ok {
+		log.Info("Resuming state pruning", "position", fmt.Sprintf("%#x", position))
+	}
+	rawdb.WriteOnlinePruningMarker(p.db, root, position)
+
+	var (
+		skipped, count int
+		size           common.StorageSize
+		logged         = time.Now()
+		keys           [][]byte
+		batch          = p.db.NewBatch()
+		iter           = p.db.NewIterator(nil, position)
+	)
+	defer func() { iter.Release() }()
+
+	flush := func(position []byte) error {
+		bstart := time.Now()
+
+		bloom.lock.Lock()
+		for _, key := range keys {
+			if bloom.bloom.Contain(key) {
+				skipped += 1
+				continue
+			}
+			count += 1
+			batch.Delete(key)
+		}
+		err := batch.Write()
+		bloom.lock.Unlock()
+		if err != nil {
+			return err
+		}
+		batch.Reset()
+		keys = keys[:0]
+		rawdb.WriteOnlinePruningMarker(p.db, root, position)
+
+		// Rest for as long as the batch took, giving up half of the time
+		// for the disk accesses of the node itself.
+		select {
+		case <-time.After(time.Since(bstart)):
+		case <-stop:
+			return errPruningInterrupted
+		}
+		return nil
+	}
+	for iter.Next() {
+		if interrupted(stop) {
+			return errPruningInterrupted
+		}
+		key := iter.Key()
+		if len(key) != common.HashLength {
+			continue
+		}
+		if bloom.bloom.Contain(key) {
+			skipped += 1
+			continue
+		}
+		keys = append(keys, common.CopyBytes(key))
+		size += common.StorageSize(len(key) + len(iter.Value()))
+
+		if len(keys)*common.HashLength >= ethdb.IdealBatchSize {
+			if err := flush(key); err != nil {
+				return err
+			}
+			// Recreate the iterator after every batch commit in order
+			// to allow the underlying compactor to delete the entries.
+			iter.Release()
+			iter = p.db.NewIterator(nil, key)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Pruning state data", "nodes", count, "skipped", skipped, "size", size,
+				"position", fmt.Sprintf("%#x", key[:4]), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if err := flush(nil); err != nil && !errors.Is(err, errPruningInterrupted) {
+		return err
+	}
+	// The range compaction is deliberately skipped, leaving the deleted data to
+	// the background compaction instead of stalling the running node.
+	rawdb.DeleteOnlinePruningMarker(p.db)
+	log.Info("Online state pruning successful", "nodes", count, "pruned", size, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// markState marks all the trie nodes and contract codes of the given state
+// into the bloom.
+func markState(db *triedb.Database, root common.Hash, bloom ethdb.KeyValueWriter, stop chan struct{}) error {
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), db)
+	if err != nil {
+		return err
+	}
+	accIter, err := t.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	for accIter.Next(true) {
+		hash := accIter.Hash()
+
+		// Embedded nodes don't have hash.
+		if hash != (common.Hash{}) {
+			bloom.Put(hash.Bytes(), nil)
+		}
+		// If it's a leaf node, yes we are touching an account,
+		// dig into the storage trie further.
+		if accIter.Leaf() {
+			if interrupted(stop) {
+				return errPruningInterrupted
+			}
+			var acc types.StateAccount
+			if err := rlp.DecodeBytes(accIter.LeafBlob(), &acc); err != nil {
+				return err
+			}
+			if acc.Root != types.EmptyRootHash {
+				id := trie.StorageTrieID(root, common.BytesToHash(accIter.LeafKey()), acc.Root)
+				storageTrie, err := trie.NewStateTrie(id, db)
+				if err != nil {
+					return err
+				}
+				storageIter, err := storageTrie.NodeIterator(nil)
+				if err != nil {
+					return err
+				}
+				if err := markNodes(storageIter, bloom); err != nil {
+					return err
+				}
+			}
+			if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+				bloom.Put(acc.CodeHash, nil)
+			}
+		}
+	}
+	return accIter.Error()
+}
+
+// markDiff marks the trie nodes of the state which are not present in the
+// parent state into the bloom.
+func markDiff(db *triedb.Database, parent common.Hash, root common.Hash, bloom ethdb.KeyValueWriter, stop chan struct{}) error {
+	// The parent trie is opened twice, one for iteration and one for the
+	// account lookups, as the lookups might alter the trie being iterated.
+	parentTrie, err := trie.NewStateTrie(trie.StateTrieID(parent), db)
+	if err != nil {
+		return err
+	}
+	parentAccounts, err := trie.NewStateTrie(trie.StateTrieID(parent), db)
+	if err != nil {
+		return err
+	}
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), db)
+	if err != nil {
+		return err
+	}
+	a, err := parentTrie.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	b, err := t.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	accIter, _ := trie.NewDifferenceIterator(a, b)
+	for accIter.Next(true) {
+		hash := accIter.Hash()
+		if hash != (common.Hash{}) {
+			bloom.Put(hash.Bytes(), nil)
+		}
+		if !accIter.Leaf() {
+			continue
+		}
+		if interrupted(stop) {
+			return errPruningInterrupted
+		}
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(accIter.LeafBlob(), &acc); err != nil {
+			return err
+		}
+		if acc.Root == types.EmptyRootHash {
+			continue
+		}
+		// The account is either created or modified, mark the storage trie
+		// nodes which are not present in the parent state.
+		accHash := common.BytesToHash(accIter.LeafKey())
+		prev, err := parentAccounts.GetAccountByHash(accHash)
+		if err != nil {
+			return err
+		}
+		prevRoot := types.EmptyRootHash
+		if prev != nil {
+			prevRoot = prev.Root
+		}
+		if prevRoot == acc.Root {
+			continue
+		}
+		prevTrie, err := trie.NewStateTrie(trie.StorageTrieID(parent, accHash, prevRoot), db)
+		if err != nil {
+			return err
+		}
+		storageTrie, err := trie.NewStateTrie(trie.StorageTrieID(root, accHash, acc.Root), db)
+		if err != nil {
+			return err
+		}
+		a, err := prevTrie.NodeIterator(nil)
+		if err != nil {
+			return err
+		}
+		b, err := storageTrie.NodeIterator(nil)
+		if err != nil {
+			return err
+		}
+		storageIter, _ := trie.NewDifferenceIterator(a, b)
+		if err := markNodes(storageIter, bloom); err != nil {
+			return err
+		}
+	}
+	return accIter.Error()
+}
+
+// markNodes marks all the trie nodes yielded by the iterator into the bloom.
+func markNodes(iter trie.NodeIterator, bloom ethdb.KeyValueWriter) error {
+	for iter.Next(true) {
+		hash := iter.Hash()
+		if hash != (common.Hash{}) {
+			bloom.Put(hash.Bytes(), nil)
+		}
+	}
+	return iter.Error()
+}
+
+// interrupted reports whether the stop channel is closed.
+func interrupted(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pruner
+
+import (
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+// checkState iterates the entire state, reporting an error if any trie node is missing.
+func checkState(t *testing.T, db *triedb.Database, root common.Hash) {
+	t.Helper()
+
+	bloom, err := newStateBloomWithSize(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := markState(db, root, bloom, nil); err != nil {
+		t.Fatalf("State %x is not intact: %v", root, err)
+	}
+}
+
+// writeHead writes the block with the given state root as the canonical head.
+func writeHead(db ethdb.Database, number uint64, root common.Hash) {
+	block := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number), Root: root})
+	rawdb.WriteBlock(db, block)
+	rawdb.WriteCanonicalHash(db, block.Hash(), number)
+	rawdb.WriteHeadBlockHash(db, block.Hash())
+}
+
+// onlinePruningRetained is the number of recent states retained in memory by
+// the test chain of newOnlinePruningChain.
+const onlinePruningRetained = 4
+
+// newOnlinePruningChain creates a chain of 20 blocks mutating the state in every
+// block. Only a few recent states are retained in memory, the states of blocks 5
+// and 15 are persisted in full.
+func newOnlinePruningChain(t *testing.T) (ethdb.Database, *triedb.Database, *snapshot.Tree, []common.Hash) {
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		tdb      = triedb.NewDatabase(db, triedb.HashDefaults)
+		addrs    []common.Address
+		roots    []common.Hash
+		retained = onlinePruningRetained
+	)
+	for i := 0; i < 32; i++ {
+		addrs = append(addrs, common.BigToAddress(big.NewInt(int64(i+1))))
+	}
+	// Construct the genesis state and persist it
+	sdb, _ := state.New(types.EmptyRootHash, state.NewDatabase(tdb, nil))
+	for i, addr := range addrs {
+		sdb.SetBalance(addr, uint256.NewInt(uint64(i+1)), tracing.BalanceChangeUnspecified)
+		sdb.SetState(addr, common.Hash{0x1}, common.Hash{byte(i + 1)})
+	}
+	root, _ := sdb.Commit(0, false, false)
+	tdb.Commit(root, false)
+	writeHead(db, 0, root)
+	roots = append(roots, root)
+
+	snaps, err := snapshot.New(snapshot.Config{CacheSize: 16}, db, tdb, root)
+	if err != nil {
+		t.Fatalf("Failed to create snapshot: %v", err)
+	}
+	// Extend the chain, mutating the state in every block. Only a few recent
+	// states are retained in memory, a couple of them are persisted in full.
+	cdb := state.NewDatabase(tdb, snaps)
+	for number := uint64(1); number <= 20; number++ {
+		sdb, _ := state.New(roots[len(roots)-1], cdb)
+		for i, addr := range addrs {
+			sdb.AddBalance(addr, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+			if i%4 == int(number%4) {
+				sdb.SetState(addr, common.Hash{byte(number)}, common.Hash{byte(i + 1)})
+			}
+		}
+		root, err := sdb.Commit(number, true, false)
+		if err != nil {
+			t.Fatalf("Failed to commit state: %v", err)
+		}
+		snaps.Cap(root, retained)
+		tdb.Reference(root, common.Hash{})
+		if number == 5 || number == 15 {
+			tdb.Commit(root, false)
+		}
+		if number > uint64(retained) {
+			tdb.Dereference(roots[number-uint64(retained)])
+		}
+		writeHead(db, number, root)
+		roots = append(roots, root)
+	}
+	// Stale states are persisted in the database before pruning
+	if !rawdb.HasLegacyTrieNode(db, roots[5]) {
+		t.Fatal("Stale state is not persisted")
+	}
+	return db, tdb, snaps, roots
+}
+
+func TestOnlinePruning(t *testing.T) {
+	var (
+		db, tdb, snaps, roots = newOnlinePruningChain(t)
+		retained              = onlinePruningRetained
+	)
+	p := &OnlinePruner{
+		config:   OnlineConfig{BloomSize: 1},
+		db:       db,
+		triedb:   tdb,
+		snaptree: snaps,
+	}
+	if err := p.prune(make(chan struct{})); err != nil {
+		t.Fatalf("Failed to prune state: %v", err)
+	}
+	if _, _, ok := rawdb.ReadOnlinePruningMarker(db); ok {
+		t.Fatal("Pruning marker is not removed")
+	}
+	// The stale state should be removed, while the genesis state, the base
+	// state and the recent states must be intact.
+	if rawdb.HasLegacyTrieNode(db, roots[5]) {
+		t.Fatal("Stale state is not pruned")
+	}
+	checkState(t, tdb, roots[0])
+	checkState(t, tdb, roots[15])
+	for number := len(roots) - retained; number < len(roots); number++ {
+		checkState(t, tdb, roots[number])
+	}
+}
+
+// Tests that the online pruning is held back while the node is syncing.
+func TestOnlinePruningSyncing(t *testing.T) {
+	defer func(old time.Duration) { syncCheckInterval = old }(syncCheckInterval)
+	syncCheckInterval = 10 * time.Millisecond
+
+	db, tdb, snaps, roots := newOnlinePruningChain(t)
+
+	var syncing atomic.Bool
+	syncing.Store(true)
+	p := NewOnlinePruner(db, tdb, snaps, OnlineConfig{BloomSize: 1, Interval: time.Millisecond})
+	p.SetSyncing(syncing.Load)
+	defer p.Close()
+
+	time.Sleep(200 * time.Millisecond)
+	if !rawdb.HasLegacyTrieNode(db, roots[5]) {
+		t.Fatal("State pruned while syncing")
+	}
+	// The snap sync status flag holds back the pruning too
+	syncing.Store(false)
+	rawdb.WriteSnapSyncStatusFlag(db, rawdb.StateSyncRunning)
+	time.Sleep(200 * time.Millisecond)
+	if !rawdb.HasLegacyTrieNode(db, roots[5]) {
+		t.Fatal("State pruned during snap sync")
+	}
+	rawdb.WriteSnapSyncStatusFlag(db, rawdb.StateSyncFinished)
+	for deadline := time.Now().Add(10 * time.Second); rawdb.HasLegacyTrieNode(db, roots[5]); {
+		if time.Now().After(deadline) {
+			t.Fatal("State not pruned after sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	p.Close()
+	checkState(t, tdb, roots[15])
+}
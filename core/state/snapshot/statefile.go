@@ -0,0 +1,471 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// stateFileVersion is the version of the state file format.
+const stateFileVersion = 1
+
+// stateChunkSize is the approximate size of the chunks in a state file.
+var stateChunkSize = 4 * 1024 * 1024
+
+// A state file holds the flat state (accounts, storage slots and contract codes)
+// at a given state root, in snapshot order. It's an RLP stream of
+//
+//	header | chunk* | empty-chunk | trailer
+//
+// where every chunk is a list of accounts, each with its code and a run of its
+// storage slots. The code is only included at its first occurrence, and large
+// storages are split across multiple entries, the continuations omitting the
+// account data. The trailer lists the keccak256 hashes of the encoded chunks.
+type (
+	stateFileHeader struct {
+		Version uint64
+		Number  uint64
+		Hash    common.Hash
+		Root    common.Hash
+	}
+	stateChunk struct {
+		Entries []stateEntry
+	}
+	stateEntry struct {
+		Hash    common.Hash
+		Account []byte // slim RLP, empty in storage continuations
+		Code    []byte
+		Slots   []stateSlot
+	}
+	stateSlot struct {
+		Hash  common.Hash
+		Value []byte
+	}
+	stateFileTrailer struct {
+		Accounts uint64
+		Slots    uint64
+		Codes    uint64
+		Chunks   []common.Hash
+	}
+)
+
+// StateManifest describes the contents of a state file. The chunks of the file
+// are verified against their hashes, the state assembled from the chunks is
+// verified against the root by regenerating the tries.
+type StateManifest struct {
+	Number   uint64        // Number of the block the state belongs to
+	Hash     common.Hash   // Hash of the block the state belongs to
+	Root     common.Hash   // State root
+	Accounts uint64        // Number of accounts
+	Slots    uint64        // Number of storage slots
+	Codes    uint64        // Number of distinct contract codes
+	Chunks   []common.Hash // Keccak256 hashes of the chunks
+}
+
+// ExportState writes the state with the given root, belonging to the specified
+// block, into a state file. The codes are retrieved from src.
+func ExportState(w io.Writer, t *Tree, src ethdb.KeyValueReader, number uint64, hash common.Hash, root common.Hash) (*StateManifest, error) {
+	accIt, err := t.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return nil, err // The required snapshot might not exist.
+	}
+	defer accIt.Release()
+
+	if err := rlp.Encode(w, &stateFileHeader{Version: stateFileVersion, Number: number, Hash: hash, Root: root}); err != nil {
+		return nil, err
+	}
+	var (
+		manifest = &StateManifest{Number: number, Hash: hash, Root: root}
+		codes    = make(map[common.Hash]struct{})
+		chunk    stateChunk
+		size     int
+
+		start  = time.Now()
+		logged = time.Now()
+	)
+	flush := func() error {
+		blob, err := rlp.EncodeToBytes(&chunk)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(blob); err != nil {
+			return err
+		}
+		manifest.Chunks = append(manifest.Chunks, crypto.Keccak256Hash(blob))
+		chunk.Entries, size = nil, 0
+		return nil
+	}
+	for accIt.Next() {
+		account, err := types.FullAccount(accIt.Account())
+		if err != nil {
+			return nil, err
+		}
+		entry := stateEntry{Hash: accIt.Hash(), Account: common.CopyBytes(accIt.Account())}
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			if _, ok := codes[codeHash]; !ok {
+				entry.Code = rawdb.ReadCode(src, codeHash)
+				if len(entry.Code) == 0 {
+					return nil, fmt.Errorf("missing code %x of account %x", codeHash, accIt.Hash())
+				}
+				codes[codeHash] = struct{}{}
+				manifest.Codes++
+			}
+		}
+		manifest.Accounts++
+		size += common.HashLength + len(entry.Account) + len(entry.Code)
+
+		if account.Root != types.EmptyRootHash {
+			stIt, err := t.StorageIterator(root, accIt.Hash(), common.Hash{})
+			if err != nil {
+				return nil, err
+			}
+			for stIt.Next() {
+				// Split the storage into a continuation if the chunk is full
+				if size >= stateChunkSize {
+					chunk.Entries = append(chunk.Entries, entry)
+					if err := flush(); err != nil {
+						stIt.Release()
+						return nil, err
+					}
+					entry = stateEntry{Hash: accIt.Hash()}
+				}
+				entry.Slots = append(entry.Slots, stateSlot{Hash: stIt.Hash(), Value: common.CopyBytes(stIt.Slot())})
+				size += common.HashLength + len(stIt.Slot())
+				manifest.Slots++
+			}
+			err = stIt.Error()
+			stIt.Release()
+			if err != nil {
+				return nil, err
+			}
+		}
+		chunk.Entries = append(chunk.Entries, entry)
+		if size >= stateChunkSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state", "at", accIt.Hash(), "accounts", manifest.Accounts, "slots", manifest.Slots,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return nil, err
+	}
+	if len(chunk.Entries) > 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	// Terminate the chunks with an empty one and append the trailer
+	if err := rlp.Encode(w, &stateChunk{}); err != nil {
+		return nil, err
+	}
+	trailer := &stateFileTrailer{
+		Accounts: manifest.Accounts,
+		Slots:    manifest.Slots,
+		Codes:    manifest.Codes,
+		Chunks:   manifest.Chunks,
+	}
+	if err := rlp.Encode(w, trailer); err != nil {
+		return nil, err
+	}
+	log.Info("Exported state", "root", root, "accounts", manifest.Accounts, "slots", manifest.Slots,
+		"codes", manifest.Codes, "chunks", len(manifest.Chunks), "elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, nil
+}
+
+// readStateFile decodes a state file, invoking the callback for every chunk,
+// and checks the chunks against the hashes and counters of the trailer.
+func readStateFile(r io.Reader, onChunk func(*stateChunk) error) (*StateManifest, error) {
+	s := rlp.NewStream(bufio.NewReader(r), 0)
+
+	var header stateFileHeader
+	if err := s.Decode(&header); err != nil {
+		return nil, fmt.Errorf("invalid state file header: %w", err)
+	}
+	if header.Version != stateFileVersion {
+		return nil, fmt.Errorf("unsupported state file version %d", header.Version)
+	}
+	var (
+		manifest = &StateManifest{Number: header.Number, Hash: header.Hash, Root: header.Root}
+		hashes   []common.Hash
+	)
+	for {
+		blob, err := s.Raw()
+		if err != nil {
+			return nil, fmt.Errorf("invalid state chunk %d: %w", len(hashes), err)
+		}
+		var chunk stateChunk
+		if err := rlp.DecodeBytes(blob, &chunk); err != nil {
+			return nil, fmt.Errorf("invalid state chunk %d: %w", len(hashes), err)
+		}
+		if len(chunk.Entries) == 0 {
+			break
+		}
+		hashes = append(hashes, crypto.Keccak256Hash(blob))
+		for _, entry := range chunk.Entries {
+			if len(entry.Account) > 0 {
+				manifest.Accounts++
+			}
+			if len(entry.Code) > 0 {
+				manifest.Codes++
+			}
+			manifest.Slots += uint64(len(entry.Slots))
+		}
+		if onChunk != nil {
+			if err := onChunk(&chunk); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var trailer stateFileTrailer
+	if err := s.Decode(&trailer); err != nil {
+		return nil, fmt.Errorf("invalid state file trailer: %w", err)
+	}
+	if len(trailer.Chunks) != len(hashes) {
+		return nil, fmt.Errorf("chunk count mismatch: have %d, want %d", len(hashes), len(trailer.Chunks))
+	}
+	for i, hash := range hashes {
+		if hash != trailer.Chunks[i] {
+			return nil, fmt.Errorf("chunk %d hash mismatch: have %x, want %x", i, hash, trailer.Chunks[i])
+		}
+	}
+	if manifest.Accounts != trailer.Accounts || manifest.Slots != trailer.Slots || manifest.Codes != trailer.Codes {
+		return nil, fmt.Errorf("state counters mismatch: have %d/%d/%d, want %d/%d/%d", manifest.Accounts, manifest.Slots, manifest.Codes, trailer.Accounts, trailer.Slots, trailer.Codes)
+	}
+	manifest.Chunks = hashes
+	return manifest, nil
+}
+
+// VerifyStateFile checks the chunks of a state file against the hashes in its
+// trailer and the state against the root in its header, and returns the manifest
+// of the file. The tries are assembled in memory with stack tries, nothing is
+// written, so the file can be verified before any local state is replaced.
+func VerifyStateFile(r io.Reader) (*StateManifest, error) {
+	v := newStateVerifier()
+	manifest, err := readStateFile(r, v.add)
+	if err != nil {
+		return nil, err
+	}
+	root, err := v.finish()
+	if err != nil {
+		return nil, err
+	}
+	if root != manifest.Root {
+		return nil, fmt.Errorf("state root mismatch: have %x, want %x", root, manifest.Root)
+	}
+	return manifest, nil
+}
+
+// stateVerifier computes the state root of the entries of a state file, while
+// checking their order and the code and storage roots of the accounts.
+type stateVerifier struct {
+	accounts *trie.StackTrie
+	storage  *trie.StackTrie // Storage trie of the current account
+
+	account  *types.StateAccount // Current account, nil before the first one
+	last     common.Hash         // Hash of the current account
+	lastSlot common.Hash         // Hash of the last slot of the current account
+}
+
+func newStateVerifier() *stateVerifier {
+	return &stateVerifier{
+		accounts: trie.NewStackTrie(nil),
+		storage:  trie.NewStackTrie(nil),
+	}
+}
+
+// add feeds the entries of a chunk into the tries.
+func (v *stateVerifier) add(chunk *stateChunk) error {
+	for _, entry := range chunk.Entries {
+		if len(entry.Account) > 0 {
+			if v.account != nil {
+				if bytes.Compare(entry.Hash[:], v.last[:]) <= 0 {
+					return fmt.Errorf("account %x out of order", entry.Hash)
+				}
+				if err := v.commit(); err != nil {
+					return err
+				}
+			}
+			account, err := types.FullAccount(entry.Account)
+			if err != nil {
+				return fmt.Errorf("invalid account %x: %w", entry.Hash, err)
+			}
+			v.account, v.last, v.lastSlot = account, entry.Hash, common.Hash{}
+		} else if v.account == nil || entry.Hash != v.last {
+			return fmt.Errorf("storage of unknown account %x", entry.Hash)
+		}
+		if len(entry.Code) > 0 {
+			if hash := crypto.Keccak256Hash(entry.Code); !bytes.Equal(hash[:], v.account.CodeHash) {
+				return fmt.Errorf("code mismatch of account %x: have %x, want %x", entry.Hash, hash, v.account.CodeHash)
+			}
+		}
+		for i, slot := range entry.Slots {
+			if (i > 0 || len(entry.Account) == 0) && bytes.Compare(slot.Hash[:], v.lastSlot[:]) <= 0 {
+				return fmt.Errorf("slot %x of account %x out of order", slot.Hash, entry.Hash)
+			}
+			v.lastSlot = slot.Hash
+			if err := v.storage.Update(slot.Hash[:], slot.Value); err != nil {
+				return fmt.Errorf("invalid slot %x of account %x: %w", slot.Hash, entry.Hash, err)
+			}
+		}
+	}
+	return nil
+}
+
+// commit checks the storage root of the current account and inserts it into
+// the account trie.
+func (v *stateVerifier) commit() error {
+	if root := v.storage.Hash(); root != v.account.Root {
+		return fmt.Errorf("storage root mismatch of account %x: have %x, want %x", v.last, root, v.account.Root)
+	}
+	v.storage.Reset()
+
+	blob, err := rlp.EncodeToBytes(v.account)
+	if err != nil {
+		return err
+	}
+	return v.accounts.Update(v.last[:], blob)
+}
+
+// finish commits the last account and returns the root of the account trie.
+func (v *stateVerifier) finish() (common.Hash, error) {
+	if v.account != nil {
+		if err := v.commit(); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	return v.accounts.Hash(), nil
+}
+
+// ImportState writes the flat state of a state file into the database, then
+// regenerates the account trie and all storage tries from it and checks the
+// resulting root against the one in the file. The snapshot is marked as fully
+// generated for the root on success.
+//
+// The database must not contain flat state or, in path scheme, trie nodes of
+// any other state.
+func ImportState(r io.Reader, db ethdb.KeyValueStore, scheme string) (*StateManifest, error) {
+	var (
+		batch    = db.NewBatch()
+		account  *types.StateAccount
+		last     common.Hash
+		lastSlot common.Hash
+
+		start  = time.Now()
+		logged = time.Now()
+		count  uint64
+	)
+	manifest, err := readStateFile(r, func(chunk *stateChunk) error {
+		for _, entry := range chunk.Entries {
+			if len(entry.Account) > 0 {
+				if account != nil && bytes.Compare(entry.Hash[:], last[:]) <= 0 {
+					return fmt.Errorf("account %x out of order", entry.Hash)
+				}
+				var err error
+				if account, err = types.FullAccount(entry.Account); err != nil {
+					return fmt.Errorf("invalid account %x: %w", entry.Hash, err)
+				}
+				last, lastSlot = entry.Hash, common.Hash{}
+				rawdb.WriteAccountSnapshot(batch, entry.Hash, entry.Account)
+				count++
+			} else if account == nil || entry.Hash != last {
+				return fmt.Errorf("storage of unknown account %x", entry.Hash)
+			}
+			if len(entry.Code) > 0 {
+				if hash := crypto.Keccak256Hash(entry.Code); !bytes.Equal(hash[:], account.CodeHash) {
+					return fmt.Errorf("code mismatch of account %x: have %x, want %x", entry.Hash, hash, account.CodeHash)
+				}
+				rawdb.WriteCode(batch, common.BytesToHash(account.CodeHash), entry.Code)
+			}
+			for i, slot := range entry.Slots {
+				if (i > 0 || len(entry.Account) == 0) && bytes.Compare(slot.Hash[:], lastSlot[:]) <= 0 {
+					return fmt.Errorf("slot %x of account %x out of order", slot.Hash, entry.Hash)
+				}
+				lastSlot = slot.Hash
+				rawdb.WriteStorageSnapshot(batch, entry.Hash, slot.Hash, slot.Value)
+			}
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing state", "at", last, "accounts", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	log.Info("Imported flat state, regenerating tries", "accounts", manifest.Accounts, "slots", manifest.Slots,
+		"codes", manifest.Codes, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// Regenerate the tries from the flat state persisted in the database
+	dl := &diskLayer{diskdb: db, root: manifest.Root}
+	accIt := dl.AccountIterator(common.Hash{})
+	defer accIt.Release()
+
+	root, err := generateTrieRoot(db, scheme, accIt, common.Hash{}, stackTrieGenerate, func(dst ethdb.KeyValueWriter, accountHash, codeHash common.Hash, stat *generateStats) (common.Hash, error) {
+		if codeHash != types.EmptyCodeHash && !rawdb.HasCode(db, codeHash) {
+			return common.Hash{}, fmt.Errorf("missing code %x of account %x", codeHash, accountHash)
+		}
+		storageIt := dl.StorageIterator(accountHash, common.Hash{})
+		defer storageIt.Release()
+
+		return generateTrieRoot(dst, scheme, storageIt, accountHash, stackTrieGenerate, nil, stat, false)
+	}, newGenerateStats(), true)
+	if err != nil {
+		return nil, err
+	}
+	if root != manifest.Root {
+		return nil, fmt.Errorf("state root mismatch: have %x, want %x", root, manifest.Root)
+	}
+	// Mark the snapshot as fully generated for the root
+	batch.Reset()
+	rawdb.DeleteSnapshotDisabled(batch)
+	rawdb.DeleteSnapshotRecoveryNumber(batch)
+	rawdb.DeleteSnapshotJournal(batch)
+	rawdb.WriteSnapshotRoot(batch, root)
+	journalProgress(batch, nil, nil)
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
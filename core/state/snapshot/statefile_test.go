@@ -0,0 +1,188 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/hashdb"
+	"github.com/ethereum/go-ethereum/triedb/pathdb"
+	"github.com/holiman/uint256"
+)
+
+// Tests that the state exported into a state file can be imported into an empty
+// database, regenerating the same tries and a complete snapshot.
+func TestStateFileExportImport(t *testing.T) {
+	testStateFileExportImport(t, rawdb.HashScheme)
+	testStateFileExportImport(t, rawdb.PathScheme)
+}
+
+func testStateFileExportImport(t *testing.T, scheme string) {
+	// Use tiny chunks to split the storage of the contract
+	defer func(size int) { stateChunkSize = size }(stateChunkSize)
+	stateChunkSize = 128
+
+	var (
+		helper   = newHelper(scheme)
+		code     = []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+		codeHash = crypto.Keccak256Hash(code)
+		keys     []string
+		vals     []string
+	)
+	for i := 0; i < 16; i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+		vals = append(vals, fmt.Sprintf("val-%d", i))
+	}
+	rawdb.WriteCode(helper.diskdb, codeHash, code)
+
+	stRoot := helper.makeStorageTrie("acc-1", keys, vals, true)
+	helper.addTrieAccount("acc-1", &types.StateAccount{Balance: uint256.NewInt(1), Root: stRoot, CodeHash: codeHash.Bytes()})
+	helper.addTrieAccount("acc-2", &types.StateAccount{Balance: uint256.NewInt(2), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+	helper.addTrieAccount("acc-3", &types.StateAccount{Balance: uint256.NewInt(3), Root: types.EmptyRootHash, CodeHash: codeHash.Bytes()})
+
+	root, snap := helper.CommitAndGenerate()
+	select {
+	case <-snap.genPending:
+		// Snapshot generation succeeded
+
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Snapshot generation failed")
+	}
+	defer func() {
+		stop := make(chan *generatorStats)
+		snap.genAbort <- stop
+		<-stop
+	}()
+	snaps := &Tree{layers: map[common.Hash]snapshot{root: snap}}
+
+	// Export the state and check the manifest
+	var buf bytes.Buffer
+	manifest, err := ExportState(&buf, snaps, helper.diskdb, 1, common.Hash{0x01}, root)
+	if err != nil {
+		t.Fatalf("Failed to export state: %v", err)
+	}
+	if manifest.Accounts != 3 || manifest.Slots != 16 || manifest.Codes != 1 {
+		t.Fatalf("Manifest counters mismatch: %d accounts, %d slots, %d codes", manifest.Accounts, manifest.Slots, manifest.Codes)
+	}
+	if len(manifest.Chunks) < 2 {
+		t.Fatalf("Storage not split across chunks: %d chunks", len(manifest.Chunks))
+	}
+	verified, err := VerifyStateFile(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("Failed to verify state file: %v", err)
+	}
+	if verified.Root != root || verified.Number != 1 || verified.Hash != (common.Hash{0x01}) {
+		t.Fatalf("Verified manifest mismatch: %+v", verified)
+	}
+	// Import the state into an empty database and check the regenerated tries
+	db := rawdb.NewMemoryDatabase()
+	if _, err := ImportState(bytes.NewReader(buf.Bytes()), db, scheme); err != nil {
+		t.Fatalf("Failed to import state: %v", err)
+	}
+	if have := rawdb.ReadSnapshotRoot(db); have != root {
+		t.Fatalf("Snapshot root mismatch: have %x, want %x", have, root)
+	}
+	var generator journalGenerator
+	if err := rlp.DecodeBytes(rawdb.ReadSnapshotGenerator(db), &generator); err != nil || !generator.Done {
+		t.Fatalf("Snapshot not marked as generated: %v", err)
+	}
+	if !bytes.Equal(rawdb.ReadCode(db, codeHash), code) {
+		t.Fatal("Contract code missing")
+	}
+	config := &triedb.Config{HashDB: &hashdb.Config{}}
+	if scheme == rawdb.PathScheme {
+		config = &triedb.Config{PathDB: &pathdb.Config{}}
+	}
+	tdb := triedb.NewDatabase(db, config)
+	defer tdb.Close()
+
+	accTrie, err := trie.NewStateTrie(trie.StateTrieID(root), tdb)
+	if err != nil {
+		t.Fatalf("Failed to open account trie: %v", err)
+	}
+	if blob := accTrie.MustGet([]byte("acc-1")); len(blob) == 0 {
+		t.Fatal("Failed to read account")
+	}
+	stTrie, err := trie.NewStateTrie(trie.StorageTrieID(root, hashData([]byte("acc-1")), stRoot), tdb)
+	if err != nil {
+		t.Fatalf("Failed to open storage trie: %v", err)
+	}
+	for i, key := range keys {
+		if blob := stTrie.MustGet([]byte(key)); string(blob) != vals[i] {
+			t.Fatalf("Storage slot %s mismatch: have %q, want %q", key, blob, vals[i])
+		}
+	}
+}
+
+// Tests that corrupted state files are rejected.
+func TestStateFileCorruption(t *testing.T) {
+	helper := newHelper(rawdb.HashScheme)
+	helper.addTrieAccount("acc-1", &types.StateAccount{Balance: uint256.NewInt(1), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+	helper.addTrieAccount("acc-2", &types.StateAccount{Balance: uint256.NewInt(2), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()})
+
+	root, snap := helper.CommitAndGenerate()
+	<-snap.genPending
+	defer func() {
+		stop := make(chan *generatorStats)
+		snap.genAbort <- stop
+		<-stop
+	}()
+	snaps := &Tree{layers: map[common.Hash]snapshot{root: snap}}
+
+	var buf bytes.Buffer
+	if _, err := ExportState(&buf, snaps, helper.diskdb, 1, common.Hash{}, root); err != nil {
+		t.Fatalf("Failed to export state: %v", err)
+	}
+	// Change the balance of an account, the chunk hash should not match
+	blob := bytes.Clone(buf.Bytes())
+	index := bytes.Index(blob, types.SlimAccountRLP(types.StateAccount{Balance: uint256.NewInt(2), Root: types.EmptyRootHash, CodeHash: types.EmptyCodeHash.Bytes()}))
+	if index < 0 {
+		t.Fatal("Account not found in state file")
+	}
+	blob[index+2] = 0x03
+	if _, err := VerifyStateFile(bytes.NewReader(blob)); err == nil {
+		t.Fatal("Corrupted state file verified")
+	}
+	if _, err := ImportState(bytes.NewReader(blob), rawdb.NewMemoryDatabase(), rawdb.HashScheme); err == nil {
+		t.Fatal("Corrupted state file imported")
+	}
+	// Change the root in the header, the chunks still match their hashes but
+	// the state doesn't match the root anymore
+	blob = bytes.Clone(buf.Bytes())
+	index = bytes.Index(blob, root[:])
+	if index < 0 {
+		t.Fatal("Root not found in state file")
+	}
+	blob[index] ^= 0xff
+	if _, err := VerifyStateFile(bytes.NewReader(blob)); err == nil {
+		t.Fatal("State file with mismatching root verified")
+	}
+	// Truncated files should be rejected too
+	if _, err := VerifyStateFile(bytes.NewReader(buf.Bytes()[:buf.Len()-10])); err == nil {
+		t.Fatal("Truncated state file verified")
+	}
+}
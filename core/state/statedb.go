@@ -135,6 +135,10 @@ type StateDB struct {
 	// Snapshot and RevertToSnapshot.
 	journal *journal
 
+	// Whether the journal is retained across transactions, allowing multiple
+	// finalised transactions to be reverted at once.
+	checkpoint bool
+
 	// State witness if cross validation is needed
 	witness *stateless.Witness
 
@@ -717,6 +721,43 @@ func (s *StateDB) RevertToSnapshot(revid int) {
 	s.journal.revertToSnapshot(revid, s)
 }
 
+// Checkpoint starts retaining the journal across transactions, allowing all the
+// changes made from this point on, including the ones of already finalised
+// transactions, to be reverted with RevertToCheckpoint. It must be called in
+// between transactions, after the state has been finalised. Transactions
+// executed within a checkpoint must be finalised with Finalise, the tries must
+// not be updated until the checkpoint is released.
+func (s *StateDB) Checkpoint() {
+	if s.checkpoint {
+		panic("nested state checkpoint")
+	}
+	if s.journal.length() != 0 {
+		panic("state checkpoint with unfinalised changes")
+	}
+	s.checkpoint = true
+}
+
+// RevertToCheckpoint reverts all state changes made since the last checkpoint
+// and releases it.
+func (s *StateDB) RevertToCheckpoint() {
+	if !s.checkpoint {
+		panic("no state checkpoint to revert to")
+	}
+	s.journal.revert(s, 0)
+	s.clearJournalAndRefund()
+	s.checkpoint = false
+}
+
+// DiscardCheckpoint keeps all state changes made since the last checkpoint and
+// releases it.
+func (s *StateDB) DiscardCheckpoint() {
+	if !s.checkpoint {
+		panic("no state checkpoint to discard")
+	}
+	s.clearJournalAndRefund()
+	s.checkpoint = false
+}
+
 // GetRefund returns the current value of the refund counter.
 func (s *StateDB) GetRefund() uint64 {
 	return s.refund
@@ -739,15 +780,22 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 			continue
 		}
 		if obj.selfDestructed || (deleteEmptyObjects && obj.empty()) {
+			_, destructed := s.stateObjectsDestruct[obj.address]
+			if s.checkpoint {
+				s.journal.finaliseDelete(obj, s.mutations[addr], !destructed)
+			}
 			delete(s.stateObjects, obj.address)
 			s.markDelete(addr)
 			// We need to maintain account deletions explicitly (will remain
 			// set indefinitely). Note only the first occurred self-destruct
 			// event is tracked.
-			if _, ok := s.stateObjectsDestruct[obj.address]; !ok {
+			if !destructed {
 				s.stateObjectsDestruct[obj.address] = obj
 			}
 		} else {
+			if s.checkpoint {
+				s.journal.finaliseUpdate(obj, s.mutations[addr])
+			}
 			obj.finalise()
 			s.markUpdate(addr)
 		}
@@ -761,8 +809,14 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 			log.Error("Failed to prefetch addresses", "addresses", len(addressesToPrefetch), "err", err)
 		}
 	}
-	// Invalidate journal because reverting across transactions is not allowed.
-	s.clearJournalAndRefund()
+	// Invalidate journal because reverting across transactions is not allowed,
+	// unless a checkpoint is retaining it.
+	if s.checkpoint {
+		s.journal.seal()
+		s.refund = 0
+	} else {
+		s.clearJournalAndRefund()
+	}
 }
 
 // IntermediateRoot computes the current root hash of the state trie.
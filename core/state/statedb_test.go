@@ -963,6 +963,85 @@ func TestDeleteCreateRevert(t *testing.T) {
 	}
 }
 
+// Tests that the changes of multiple finalised transactions can be reverted at
+// once with a checkpoint, or kept identically to the ones made without.
+func TestCheckpoint(t *testing.T) {
+	var (
+		a = common.BytesToAddress([]byte("a"))
+		c = common.BytesToAddress([]byte("c"))
+		d = common.BytesToAddress([]byte("d"))
+		e = common.BytesToAddress([]byte("e"))
+	)
+	state, _ := New(types.EmptyRootHash, NewDatabaseForTesting())
+	state.SetBalance(a, uint256.NewInt(1), tracing.BalanceChangeUnspecified)
+	state.SetState(a, common.Hash{1}, common.Hash{1})
+	state.SetState(a, common.Hash{2}, common.Hash{2})
+	state.SetBalance(c, uint256.NewInt(3), tracing.BalanceChangeUnspecified)
+	state.SetCode(c, []byte{0x60, 0x00})
+
+	root, _ := state.Commit(0, false, false)
+	state, _ = New(root, state.db)
+
+	// Execute a transaction before the checkpoint to have pending changes
+	state.SetState(a, common.Hash{1}, common.Hash{5})
+	state.Finalise(true)
+
+	// Two transactions touching the same accounts, deleting and creating them
+	execute := func(state *StateDB) {
+		state.SetTxContext(common.Hash{1}, 0)
+		state.SetState(a, common.Hash{1}, common.Hash{6})
+		state.SetState(a, common.Hash{3}, common.Hash{7})
+		state.AddBalance(a, uint256.NewInt(10), tracing.BalanceChangeUnspecified)
+		state.SetBalance(d, uint256.NewInt(4), tracing.BalanceChangeUnspecified)
+		state.SelfDestruct(c)
+		state.AddBalance(e, new(uint256.Int), tracing.BalanceChangeUnspecified)
+		state.AddLog(&types.Log{Address: a})
+		state.Finalise(true)
+
+		state.SetTxContext(common.Hash{2}, 1)
+		state.SetState(a, common.Hash{1}, common.Hash{1})
+		state.SetState(a, common.Hash{3}, common.Hash{})
+		state.CreateAccount(c)
+		state.SetBalance(c, uint256.NewInt(5), tracing.BalanceChangeUnspecified)
+		state.SetTransientState(a, common.Hash{1}, common.Hash{1})
+		state.AddRefund(100)
+		id := state.Snapshot()
+		state.SetState(d, common.Hash{1}, common.Hash{1})
+		state.RevertToSnapshot(id)
+		state.Finalise(true)
+	}
+	// Reverting the checkpoint should restore the state from before it
+	want := state.Copy()
+	state.Checkpoint()
+	execute(state)
+	state.RevertToCheckpoint()
+
+	if have, want := state.GetCommittedState(a, common.Hash{1}), want.GetCommittedState(a, common.Hash{1}); have != want {
+		t.Fatalf("committed slot mismatch: have %x, want %x", have, want)
+	}
+	if len(state.Logs()) != 0 {
+		t.Fatalf("logs not reverted: %d", len(state.Logs()))
+	}
+	if len(state.stateObjectsDestruct) != 0 {
+		t.Fatalf("destructions not reverted: %d", len(state.stateObjectsDestruct))
+	}
+	if have, want := state.IntermediateRoot(true), want.IntermediateRoot(true); have != want {
+		t.Fatalf("reverted root mismatch: have %x, want %x", have, want)
+	}
+	// Discarding the checkpoint should keep the changes as is
+	want = state.Copy()
+	execute(want)
+
+	state.Checkpoint()
+	execute(state)
+	state.DiscardCheckpoint()
+
+	wantRoot, _ := want.Commit(1, true, false)
+	if have, _ := state.Commit(1, true, false); have != wantRoot {
+		t.Fatalf("kept root mismatch: have %x, want %x", have, wantRoot)
+	}
+}
+
 // TestMissingTrieNodes tests that if the StateDB fails to load parts of the trie,
 // the Commit operation fails with an error
 // If we are missing trie nodes, we should not continue writing to the trie
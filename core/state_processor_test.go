@@ -18,6 +18,7 @@ package core
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 	"math"
 	"math/big"
 	"testing"
@@ -108,6 +109,19 @@ func TestStateProcessorErrors(t *testing.T) {
 		}
 		return tx
 	}
+	var mkTimedTx = func(nonce uint64, to common.Address, gasLimit uint64, gasTipCap, gasFeeCap *big.Int, validAfter, validUntil uint64) *types.Transaction {
+		tx, _ := types.SignTx(types.NewTx(&types.TimedTx{
+			Nonce:      nonce,
+			GasTipCap:  gasTipCap,
+			GasFeeCap:  gasFeeCap,
+			Gas:        gasLimit,
+			To:         &to,
+			Value:      big.NewInt(0),
+			ValidAfter: validAfter,
+			ValidUntil: validUntil,
+		}), signer, key1)
+		return tx
+	}
 
 	{ // Tests against a 'recent' chain definition
 		var (
@@ -257,6 +271,24 @@ func TestStateProcessorErrors(t *testing.T) {
 			},
 			// ErrSetCodeTxCreate cannot be tested here: it is impossible to create a SetCode-tx with nil `to`.
 			// The EstimateGas API tests test this case.
+			{ // ErrTxNotYetValid
+				txs: []*types.Transaction{
+					mkTimedTx(0, common.Address{}, params.TxGas, big.NewInt(params.InitialBaseFee), big.NewInt(params.InitialBaseFee), 1000, 0),
+				},
+				want: "could not apply tx 0 [0x575a80c4be3f48b66df04b7f6af3fe524928223c7c97def9b0be554d061ba147]: transaction validity window not yet open: address 0x71562b71999873DB5b286dF957af199Ec94617F7, validAfter: 1000, block time: 10",
+			},
+			{ // ErrTxExpired
+				txs: []*types.Transaction{
+					mkTimedTx(0, common.Address{}, params.TxGas, big.NewInt(params.InitialBaseFee), big.NewInt(params.InitialBaseFee), 0, 5),
+				},
+				want: "could not apply tx 0 [0xd492b0374c797970c1400bb96f766def5ad5f1e5c9ddc43ac9ad2151937875f1]: transaction validity window expired: address 0x71562b71999873DB5b286dF957af199Ec94617F7, validUntil: 5, block time: 10",
+			},
+			{ // ErrTxInvalidWindow
+				txs: []*types.Transaction{
+					mkTimedTx(0, common.Address{}, params.TxGas, big.NewInt(params.InitialBaseFee), big.NewInt(params.InitialBaseFee), 1000, 500),
+				},
+				want: "could not apply tx 0 [0xd4aa667291b578b6251a1667c49980384587edc942dc65c9fcea0707f8e97c7e]: transaction validity window closes before it opens: address 0x71562b71999873DB5b286dF957af199Ec94617F7, validAfter: 1000, validUntil: 500",
+			},
 		} {
 			block := GenerateBadBlock(gspec.ToBlock(), beacon.New(ethash.NewFaker()), tt.txs, gspec.Config, false)
 			_, err := blockchain.InsertChain(types.Blocks{block})
@@ -318,6 +350,37 @@ func TestStateProcessorErrors(t *testing.T) {
 		}
 	}
 
+	// ErrTxTypeNotSupported for timed transactions, we need a Prague chain
+	// without the timed transaction fork
+	{
+		var (
+			db     = rawdb.NewMemoryDatabase()
+			prague = *config
+			gspec  = &Genesis{
+				Config: &prague,
+				Alloc: types.GenesisAlloc{
+					common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7"): types.Account{
+						Balance: big.NewInt(1000000000000000000), // 1 ether
+						Nonce:   0,
+					},
+				},
+			}
+		)
+		prague.TimedTxTime = nil
+		blockchain, _ := NewBlockChain(db, nil, gspec, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil)
+		defer blockchain.Stop()
+
+		tx := mkTimedTx(0, common.Address{}, params.TxGas, big.NewInt(params.InitialBaseFee), big.NewInt(params.InitialBaseFee), 0, 1000)
+		block := GenerateBadBlock(gspec.ToBlock(), beacon.New(ethash.NewFaker()), types.Transactions{tx}, gspec.Config, false)
+		_, err := blockchain.InsertChain(types.Blocks{block})
+		if err == nil {
+			t.Fatal("block imported without errors")
+		}
+		if have, want := err.Error(), fmt.Sprintf("could not apply tx 0 [%v]: transaction type not supported", tx.Hash().Hex()); have != want {
+			t.Errorf("timed tx before fork:\nhave \"%v\"\nwant \"%v\"\n", have, want)
+		}
+	}
+
 	// ErrSenderNoEOA, for this we need the sender to have contract code
 	{
 		var (
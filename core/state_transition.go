@@ -157,6 +157,11 @@ type Message struct {
 	BlobHashes            []common.Hash
 	SetCodeAuthorizations []types.SetCodeAuthorization
 
+	// ValidAfter and ValidUntil bound the block timestamps the message can be
+	// executed at. A zero value leaves the corresponding side unbounded.
+	ValidAfter uint64
+	ValidUntil uint64
+
 	// When SkipNonceChecks is true, the message nonce is not checked against the
 	// account nonce in state.
 	// This field will be set to true for operations like RPC eth_call.
@@ -179,6 +184,8 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		Data:                  tx.Data(),
 		AccessList:            tx.AccessList(),
 		SetCodeAuthorizations: tx.SetCodeAuthorizations(),
+		ValidAfter:            tx.ValidAfter(),
+		ValidUntil:            tx.ValidUntil(),
 		SkipNonceChecks:       false,
 		SkipFromEOACheck:      false,
 		BlobHashes:            tx.BlobHashes(),
@@ -383,6 +390,23 @@ func (st *stateTransition) preCheck() error {
 			}
 		}
 	}
+	// Make sure the block falls into the validity window of timed transactions
+	if msg.ValidAfter != 0 || msg.ValidUntil != 0 {
+		if !st.evm.ChainConfig().IsTimedTx(st.evm.Context.BlockNumber, st.evm.Context.Time) {
+			return fmt.Errorf("%w: address %v, timed transactions not yet activated", ErrTxTypeNotSupported, msg.From.Hex())
+		}
+		if msg.ValidUntil != 0 && msg.ValidUntil < msg.ValidAfter {
+			return fmt.Errorf("%w: address %v, validAfter: %d, validUntil: %d", ErrTxInvalidWindow,
+				msg.From.Hex(), msg.ValidAfter, msg.ValidUntil)
+		}
+		if time := st.evm.Context.Time; time < msg.ValidAfter {
+			return fmt.Errorf("%w: address %v, validAfter: %d, block time: %d", ErrTxNotYetValid,
+				msg.From.Hex(), msg.ValidAfter, time)
+		} else if msg.ValidUntil != 0 && time > msg.ValidUntil {
+			return fmt.Errorf("%w: address %v, validUntil: %d, block time: %d", ErrTxExpired,
+				msg.From.Hex(), msg.ValidUntil, time)
+		}
+	}
 	// Check that EIP-7702 authorization list signatures are well formed.
 	if msg.SetCodeAuthorizations != nil {
 		if msg.To == nil {
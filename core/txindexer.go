@@ -116,7 +116,11 @@ func (indexer *txIndexer) run(head uint64, stop chan struct{}, done chan struct{
 	// The tail flag is existent (which means indexes in [tail, head] should be
 	// present), while the whole chain are requested for indexing.
 	if indexer.limit == 0 || head < indexer.limit {
-		if *tail > 0 {
+		if *tail < indexer.cutoff {
+			// The cutoff was moved forward as the chain history is pruned
+			// continuously, unindex the blocks below it.
+			rawdb.UnindexTransactions(indexer.db, *tail, indexer.cutoff, stop, false)
+		} else if *tail > 0 {
 			from := max(uint64(0), indexer.cutoff)
 			rawdb.IndexTransactions(indexer.db, from, *tail, stop, true)
 		}
@@ -239,6 +243,10 @@ func (indexer *txIndexer) loop(chain *BlockChain) {
 		select {
 		case h := <-headCh:
 			if done == nil {
+				// The cutoff might be moved forward if the chain history
+				// is pruned continuously.
+				indexer.cutoff, _ = chain.HistoryPruningCutoff()
+
 				stop = make(chan struct{})
 				done = make(chan struct{})
 				go indexer.run(h.Header.Number.Uint64(), stop, done)
@@ -0,0 +1,259 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"plugin"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// OriginLocal is the origin of transactions submitted locally, as opposed to the
+// ones received from the network, whose origin is the ID of the sending peer.
+const OriginLocal = "local"
+
+// admissionPluginSymbol is the name of the constructor a policy plugin needs to
+// export, with the signature func() (txpool.AdmissionPolicy, error).
+const admissionPluginSymbol = "NewAdmissionPolicy"
+
+// admissionRPCMethod is the method invoked on a remote policy service.
+const admissionRPCMethod = "txpolicy_check"
+
+var (
+	// ErrTxRejectedByPolicy is returned if a transaction is rejected by the
+	// configured admission policy.
+	ErrTxRejectedByPolicy = errors.New("transaction rejected by admission policy")
+
+	// ErrPolicyUnavailable is returned if the admission policy could not be
+	// consulted and the pool is configured to fail closed.
+	ErrPolicyUnavailable = errors.New("admission policy unavailable")
+)
+
+var (
+	admissionAcceptMeter = metrics.NewRegisteredMeter("txpool/admission/accepted", nil)
+	admissionRejectMeter = metrics.NewRegisteredMeter("txpool/admission/rejected", nil)
+	admissionFailMeter   = metrics.NewRegisteredMeter("txpool/admission/failed", nil)
+	admissionTimer       = metrics.NewRegisteredResettingTimer("txpool/admission/duration", nil)
+)
+
+// AdmissionRequest is the information passed to an admission policy about a
+// transaction about to enter the pool.
+type AdmissionRequest struct {
+	Tx     *types.Transaction `json:"tx"`     // Transaction to be admitted
+	Sender common.Address     `json:"sender"` // Sender of the transaction
+	Origin string             `json:"origin"` // OriginLocal or the ID of the peer the transaction was received from
+}
+
+// AdmissionVerdict is the decision of an admission policy about a transaction.
+type AdmissionVerdict struct {
+	Accept bool   `json:"accept"`           // Whether the transaction may enter the pool
+	Reason string `json:"reason,omitempty"` // Reason of the rejection, if any
+}
+
+// AdmissionPolicy decides whether a transaction may enter the pool. It is
+// consulted before any other validation, so it may be invoked for transactions
+// the pool would reject anyway.
+type AdmissionPolicy interface {
+	// Check returns the verdict about the admission of a transaction. An error
+	// means the policy could not reach a decision.
+	Check(ctx context.Context, req *AdmissionRequest) (*AdmissionVerdict, error)
+
+	// Close releases any resources held by the policy.
+	Close() error
+}
+
+// AdmissionConfig contains the settings of the admission policy hook.
+type AdmissionConfig struct {
+	Plugin   string        `toml:",omitempty"` // Path of a Go plugin exporting NewAdmissionPolicy
+	URL      string        `toml:",omitempty"` // JSON-RPC endpoint of a policy service (http, ws or ipc)
+	Timeout  time.Duration // Maximum time to wait for a verdict
+	FailOpen bool          // Whether to admit transactions if the policy cannot be consulted
+}
+
+// DefaultAdmissionConfig contains the default settings of the admission hook.
+var DefaultAdmissionConfig = AdmissionConfig{
+	Timeout: 500 * time.Millisecond,
+}
+
+// Enabled returns whether an admission policy is configured.
+func (c *AdmissionConfig) Enabled() bool {
+	return c.Plugin != "" || c.URL != ""
+}
+
+// Admission wraps an admission policy with the timeout and failure handling of
+// the pool.
+type Admission struct {
+	policy   AdmissionPolicy
+	timeout  time.Duration
+	failOpen bool
+}
+
+// NewAdmission creates the admission hook according to the config, loading the
+// configured plugin or connecting to the configured policy service.
+func NewAdmission(config AdmissionConfig) (*Admission, error) {
+	var (
+		policy AdmissionPolicy
+		err    error
+	)
+	switch {
+	case config.Plugin != "" && config.URL != "":
+		return nil, errors.New("both admission plugin and service configured")
+	case config.Plugin != "":
+		policy, err = loadAdmissionPlugin(config.Plugin)
+	case config.URL != "":
+		policy, err = dialAdmissionService(config.URL)
+	default:
+		return nil, errors.New("no admission policy configured")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewAdmissionWithPolicy(policy, config.Timeout, config.FailOpen), nil
+}
+
+// NewAdmissionWithPolicy creates the admission hook around an already
+// constructed policy.
+func NewAdmissionWithPolicy(policy AdmissionPolicy, timeout time.Duration, failOpen bool) *Admission {
+	if timeout <= 0 {
+		timeout = DefaultAdmissionConfig.Timeout
+	}
+	return &Admission{
+		policy:   policy,
+		timeout:  timeout,
+		failOpen: failOpen,
+	}
+}
+
+// check consults the policy about a batch of transactions concurrently, returning
+// an error for each rejected one. Transactions whose sender cannot be derived are
+// left for the subpools to reject.
+func (a *Admission) check(txs []*types.Transaction, signer types.Signer, origin string, skip func(common.Hash) bool) []error {
+	var (
+		errs = make([]error, len(txs))
+		wg   sync.WaitGroup
+	)
+	for i, tx := range txs {
+		if skip(tx.Hash()) {
+			continue
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, req *AdmissionRequest) {
+			defer wg.Done()
+			errs[i] = a.admit(req)
+		}(i, &AdmissionRequest{Tx: tx, Sender: from, Origin: origin})
+	}
+	wg.Wait()
+	return errs
+}
+
+// admit consults the policy about a single transaction.
+func (a *Admission) admit(req *AdmissionRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+	defer cancel()
+
+	start := time.Now()
+	verdict, err := a.policy.Check(ctx, req)
+	admissionTimer.UpdateSince(start)
+
+	if err == nil && verdict == nil {
+		err = errors.New("empty verdict")
+	}
+	if err != nil {
+		admissionFailMeter.Mark(1)
+		if a.failOpen {
+			log.Debug("Admission policy failed, accepting transaction", "hash", req.Tx.Hash(), "err", err)
+			return nil
+		}
+		log.Debug("Admission policy failed, rejecting transaction", "hash", req.Tx.Hash(), "err", err)
+		return fmt.Errorf("%w: %v", ErrPolicyUnavailable, err)
+	}
+	if !verdict.Accept {
+		admissionRejectMeter.Mark(1)
+		if verdict.Reason == "" {
+			return ErrTxRejectedByPolicy
+		}
+		return fmt.Errorf("%w: %s", ErrTxRejectedByPolicy, verdict.Reason)
+	}
+	admissionAcceptMeter.Mark(1)
+	return nil
+}
+
+// Close releases the resources held by the policy.
+func (a *Admission) Close() error {
+	return a.policy.Close()
+}
+
+// loadAdmissionPlugin opens a Go plugin and constructs the admission policy via
+// its exported constructor.
+func loadAdmissionPlugin(path string) (AdmissionPolicy, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open admission plugin: %w", err)
+	}
+	sym, err := p.Lookup(admissionPluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load admission plugin: %w", err)
+	}
+	constructor, ok := sym.(func() (AdmissionPolicy, error))
+	if !ok {
+		return nil, fmt.Errorf("admission plugin symbol %s has invalid type %T", admissionPluginSymbol, sym)
+	}
+	return constructor()
+}
+
+// rpcAdmissionPolicy is an admission policy consulting an external service over
+// JSON-RPC, invoking txpolicy_check with the admission request.
+type rpcAdmissionPolicy struct {
+	client *rpc.Client
+}
+
+// dialAdmissionService connects to a remote admission policy service.
+func dialAdmissionService(url string) (AdmissionPolicy, error) {
+	client, err := rpc.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial admission service: %w", err)
+	}
+	return &rpcAdmissionPolicy{client: client}, nil
+}
+
+// Check implements AdmissionPolicy.
+func (p *rpcAdmissionPolicy) Check(ctx context.Context, req *AdmissionRequest) (*AdmissionVerdict, error) {
+	var verdict *AdmissionVerdict
+	if err := p.client.CallContext(ctx, &verdict, admissionRPCMethod, req); err != nil {
+		return nil, err
+	}
+	return verdict, nil
+}
+
+// Close implements AdmissionPolicy.
+func (p *rpcAdmissionPolicy) Close() error {
+	p.client.Close()
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testAdmissionPolicy rejects transactions sent to a blocked recipient, fails
+// for transactions with a zero nonce and stalls for transactions with nonce 1.
+type testAdmissionPolicy struct {
+	blocked common.Address
+}
+
+func (p *testAdmissionPolicy) Check(ctx context.Context, req *AdmissionRequest) (*AdmissionVerdict, error) {
+	switch req.Tx.Nonce() {
+	case 0:
+		return nil, errors.New("policy failure")
+	case 1:
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if to := req.Tx.To(); to != nil && *to == p.blocked {
+		return &AdmissionVerdict{Accept: false, Reason: "sanctioned recipient from " + req.Origin}, nil
+	}
+	return &AdmissionVerdict{Accept: true}, nil
+}
+
+func (p *testAdmissionPolicy) Close() error { return nil }
+
+// testAdmissionService exposes a testAdmissionPolicy as a JSON-RPC service.
+type testAdmissionService struct {
+	policy *testAdmissionPolicy
+}
+
+func (s *testAdmissionService) Check(ctx context.Context, req *AdmissionRequest) (*AdmissionVerdict, error) {
+	return s.policy.Check(ctx, req)
+}
+
+func makeAdmissionTxs(blocked common.Address) ([]*types.Transaction, types.Signer) {
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSigner(params.TestChainConfig)
+
+	var txs []*types.Transaction
+	for nonce, to := range []common.Address{{0x01}, {0x01}, {0x01}, blocked} {
+		txs = append(txs, types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    uint64(nonce),
+			To:       &to,
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(1),
+		}))
+	}
+	return txs, signer
+}
+
+func testAdmission(t *testing.T, policy AdmissionPolicy, blocked common.Address) {
+	txs, signer := makeAdmissionTxs(blocked)
+	skip := func(common.Hash) bool { return false }
+
+	// Fail-closed: policy failures and timeouts reject the transactions
+	admission := NewAdmissionWithPolicy(policy, 50*time.Millisecond, false)
+	errs := admission.check(txs, signer, "peer1", skip)
+	for i, want := range []error{ErrPolicyUnavailable, ErrPolicyUnavailable, nil, ErrTxRejectedByPolicy} {
+		if !errors.Is(errs[i], want) {
+			t.Errorf("fail-closed tx %d: error mismatch: have %v, want %v", i, errs[i], want)
+		}
+	}
+	if errs[3] == nil || errs[3].Error() != "transaction rejected by admission policy: sanctioned recipient from peer1" {
+		t.Errorf("rejection reason mismatch: have %v", errs[3])
+	}
+	// Fail-open: policy failures and timeouts accept the transactions
+	admission = NewAdmissionWithPolicy(policy, 50*time.Millisecond, true)
+	errs = admission.check(txs, signer, OriginLocal, skip)
+	for i, want := range []error{nil, nil, nil, ErrTxRejectedByPolicy} {
+		if !errors.Is(errs[i], want) {
+			t.Errorf("fail-open tx %d: error mismatch: have %v, want %v", i, errs[i], want)
+		}
+	}
+	// Skipped transactions must not be checked at all
+	errs = admission.check(txs, signer, OriginLocal, func(common.Hash) bool { return true })
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("skipped tx %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+// Tests that the admission hook applies the verdicts of an in-process policy,
+// along with the timeout and failure handling.
+func TestAdmission(t *testing.T) {
+	blocked := common.Address{0xff}
+	testAdmission(t, &testAdmissionPolicy{blocked: blocked}, blocked)
+}
+
+// Tests that the admission hook can consult a policy service over JSON-RPC.
+func TestAdmissionService(t *testing.T) {
+	blocked := common.Address{0xff}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("txpolicy", &testAdmissionService{policy: &testAdmissionPolicy{blocked: blocked}}); err != nil {
+		t.Fatalf("failed to register policy service: %v", err)
+	}
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	admission, err := NewAdmission(AdmissionConfig{URL: httpsrv.URL})
+	if err != nil {
+		t.Fatalf("failed to create admission: %v", err)
+	}
+	defer admission.Close()
+
+	testAdmission(t, admission.policy, blocked)
+}
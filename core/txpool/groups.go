@@ -0,0 +1,345 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// maxGroupSize is the maximum number of transactions in a single group.
+	maxGroupSize = 16
+
+	// maxGroups is the maximum number of groups tracked by the pool at once.
+	maxGroups = 256
+
+	// groupTxMaxSize is the maximum size a single transaction in a group can
+	// have, mirroring the limit of the legacy pool.
+	groupTxMaxSize = 4 * 32 * 1024
+
+	// groupLifetime is the maximum amount of time a group is kept in the pool
+	// waiting for inclusion before being dropped.
+	groupLifetime = time.Hour
+)
+
+var (
+	// ErrGroupEmpty is returned if a transaction group has no members.
+	ErrGroupEmpty = errors.New("empty transaction group")
+
+	// ErrGroupTooLarge is returned if a transaction group has more members than
+	// the pool is willing to track.
+	ErrGroupTooLarge = errors.New("transaction group too large")
+
+	// ErrGroupPoolFull is returned if the pool already tracks the maximum number
+	// of transaction groups.
+	ErrGroupPoolFull = errors.New("transaction group pool is full")
+
+	// ErrGroupDuplicate is returned if a transaction is contained more than once
+	// in a group, or is already tracked by the pool.
+	ErrGroupDuplicate = errors.New("duplicate transaction in group")
+
+	// ErrGroupBlobTx is returned if a group contains a blob transaction.
+	ErrGroupBlobTx = errors.New("blob transaction in group")
+
+	// ErrGroupUnsupported is returned if a group is submitted before the chain
+	// reached Byzantium, groups can't be reverted atomically before.
+	ErrGroupUnsupported = errors.New("transaction groups not supported before byzantium")
+
+	// ErrGroupNonceGap is returned if the transactions of a sender within the
+	// group do not have consecutive nonces.
+	ErrGroupNonceGap = errors.New("non-consecutive nonces in group")
+)
+
+var (
+	groupGauge         = metrics.NewRegisteredGauge("txpool/groups", nil)
+	groupDroppedMeter  = metrics.NewRegisteredMeter("txpool/groups/dropped", nil)
+	groupIncludedMeter = metrics.NewRegisteredMeter("txpool/groups/included", nil)
+)
+
+// TxGroup is a set of transactions, potentially from different senders, which
+// must be included contiguously and in order within a single block, or not at
+// all.
+type TxGroup struct {
+	Hash common.Hash        // Identifier of the group, hash of the member hashes
+	Txs  types.Transactions // Member transactions in inclusion order
+	Time time.Time          // Time the group was first seen locally
+}
+
+// newTxGroup creates a transaction group, deriving its identifier from the
+// member transactions.
+func newTxGroup(txs types.Transactions) *TxGroup {
+	hashes := make([]byte, 0, len(txs)*common.HashLength)
+	for _, tx := range txs {
+		hashes = append(hashes, tx.Hash().Bytes()...)
+	}
+	return &TxGroup{
+		Hash: crypto.Keccak256Hash(hashes),
+		Txs:  txs,
+		Time: time.Now(),
+	}
+}
+
+// Gas returns the total gas limit of the group's transactions.
+func (g *TxGroup) Gas() uint64 {
+	var gas uint64
+	for _, tx := range g.Txs {
+		gas += tx.Gas()
+	}
+	return gas
+}
+
+// groupQueue is a FIFO queue of transaction groups awaiting inclusion. Groups
+// are not gossiped to the network and are not visible to the subpools, they
+// are only ever included by the local block builder.
+type groupQueue struct {
+	config *params.ChainConfig
+	signer types.Signer
+
+	groups []*TxGroup               // Groups in arrival order
+	lookup map[common.Hash]*TxGroup // Member transaction hash to group mapping
+	minTip *big.Int                 // Minimum gas tip required for member transactions
+	head   *types.Header            // Current head the group validity is checked against
+
+	lock sync.RWMutex // Lock protecting the queue contents
+}
+
+// newGroupQueue creates an empty transaction group queue.
+func newGroupQueue(config *params.ChainConfig, signer types.Signer, gasTip uint64, head *types.Header) *groupQueue {
+	return &groupQueue{
+		config: config,
+		signer: signer,
+		lookup: make(map[common.Hash]*TxGroup),
+		minTip: new(big.Int).SetUint64(gasTip),
+		head:   head,
+	}
+}
+
+// add validates a batch of transactions as a group against the given state and
+// appends it to the end of the queue.
+func (q *groupQueue) add(txs types.Transactions, statedb *state.StateDB, known func(common.Hash) bool) (*TxGroup, error) {
+	if len(txs) == 0 {
+		return nil, ErrGroupEmpty
+	}
+	if len(txs) > maxGroupSize {
+		return nil, fmt.Errorf("%w: have %d, max %d", ErrGroupTooLarge, len(txs), maxGroupSize)
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.groups) >= maxGroups {
+		return nil, ErrGroupPoolFull
+	}
+	if !q.config.IsByzantium(q.head.Number) {
+		return nil, ErrGroupUnsupported
+	}
+	var (
+		seen   = make(map[common.Hash]struct{})
+		nonces = make(map[common.Address]uint64)
+		costs  = make(map[common.Address]*big.Int)
+	)
+	for i, tx := range txs {
+		hash := tx.Hash()
+		if _, ok := seen[hash]; ok {
+			return nil, fmt.Errorf("%w: tx %d, hash %x", ErrGroupDuplicate, i, hash)
+		}
+		if _, ok := q.lookup[hash]; ok || known(hash) {
+			return nil, fmt.Errorf("%w: tx %d, hash %x", ErrAlreadyKnown, i, hash)
+		}
+		seen[hash] = struct{}{}
+
+		if tx.Type() == types.BlobTxType {
+			return nil, fmt.Errorf("%w: tx %d", ErrGroupBlobTx, i)
+		}
+		opts := &ValidationOptions{
+			Config: q.config,
+			Accept: 0 |
+				1<<types.LegacyTxType |
+				1<<types.AccessListTxType |
+				1<<types.DynamicFeeTxType |
+				1<<types.SetCodeTxType |
+				1<<types.TimedTxType,
+			MaxSize: groupTxMaxSize,
+			MinTip:  q.minTip,
+		}
+		if err := ValidateTransaction(tx, q.head, q.signer, opts); err != nil {
+			return nil, fmt.Errorf("tx %d: %w", i, err)
+		}
+		from, _ := types.Sender(q.signer, tx) // already validated above
+
+		// Ensure the sender's transactions within the group are consecutive
+		// and not yet stale compared to the current state
+		if next, ok := nonces[from]; ok {
+			if tx.Nonce() != next {
+				return nil, fmt.Errorf("%w: tx %d, sender %v, have %d, want %d", ErrGroupNonceGap, i, from, tx.Nonce(), next)
+			}
+		} else if have := statedb.GetNonce(from); tx.Nonce() < have {
+			return nil, fmt.Errorf("%w: tx %d, sender %v, have %d, state %d", core.ErrNonceTooLow, i, from, tx.Nonce(), have)
+		}
+		nonces[from] = tx.Nonce() + 1
+
+		// Ensure the sender can cover the cumulative costs of its transactions
+		if costs[from] == nil {
+			costs[from] = new(big.Int)
+		}
+		costs[from].Add(costs[from], tx.Cost())
+		if balance := statedb.GetBalance(from).ToBig(); balance.Cmp(costs[from]) < 0 {
+			return nil, fmt.Errorf("%w: tx %d, sender %v, balance %v, cost %v", core.ErrInsufficientFunds, i, from, balance, costs[from])
+		}
+	}
+	group := newTxGroup(txs)
+	q.groups = append(q.groups, group)
+	for _, tx := range txs {
+		q.lookup[tx.Hash()] = group
+	}
+	groupGauge.Update(int64(len(q.groups)))
+	return group, nil
+}
+
+// has returns whether a transaction is a member of any tracked group.
+func (q *groupQueue) has(hash common.Hash) bool {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	_, ok := q.lookup[hash]
+	return ok
+}
+
+// get retrieves a member transaction of any tracked group.
+func (q *groupQueue) get(hash common.Hash) *types.Transaction {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	group, ok := q.lookup[hash]
+	if !ok {
+		return nil
+	}
+	for _, tx := range group.Txs {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return nil
+}
+
+// pending returns the tracked groups in arrival order.
+func (q *groupQueue) pending() []*TxGroup {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	groups := make([]*TxGroup, len(q.groups))
+	copy(groups, q.groups)
+	return groups
+}
+
+// setGasTip updates the minimum gas tip required for new member transactions.
+// Already tracked groups are not affected.
+func (q *groupQueue) setGasTip(tip *big.Int) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.minTip = new(big.Int).Set(tip)
+}
+
+// reset drops all groups which became unincludable with the new head, either
+// because some member transaction got included (or replaced), its validity
+// window closed, or the group has been waiting for too long.
+func (q *groupQueue) reset(head *types.Header, statedb *state.StateDB) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.head = head
+
+	var (
+		kept    = q.groups[:0]
+		dropped int
+	)
+	for _, group := range q.groups {
+		if reason := q.stale(group, statedb); reason != "" {
+			log.Debug("Dropping transaction group", "hash", group.Hash, "reason", reason)
+			for _, tx := range group.Txs {
+				delete(q.lookup, tx.Hash())
+			}
+			if reason == "included" {
+				groupIncludedMeter.Mark(1)
+			} else {
+				dropped++
+			}
+			continue
+		}
+		kept = append(kept, group)
+	}
+	// Clear out the dropped tail so the groups can be garbage collected
+	for i := len(kept); i < len(q.groups); i++ {
+		q.groups[i] = nil
+	}
+	q.groups = kept
+	groupDroppedMeter.Mark(int64(dropped))
+	groupGauge.Update(int64(len(q.groups)))
+}
+
+// stale checks whether a group can still be included on top of the given state,
+// returning the reason for dropping it if not.
+func (q *groupQueue) stale(group *TxGroup, statedb *state.StateDB) string {
+	if time.Since(group.Time) > groupLifetime {
+		return "expired"
+	}
+	var stale int
+	for _, tx := range group.Txs {
+		from, _ := types.Sender(q.signer, tx) // already validated on admission
+		if tx.Nonce() < statedb.GetNonce(from) {
+			stale++
+		}
+	}
+	switch {
+	case stale == len(group.Txs):
+		// All members are stale, assume the group made it into the chain. This
+		// is a heuristic, the group is dropped either way.
+		return "included"
+	case stale > 0:
+		// Only some of the members were included, the group can't be anymore
+		return "nonce too low"
+	}
+	for _, tx := range group.Txs {
+		if until := tx.ValidUntil(); until != 0 && until <= q.head.Time {
+			return "window closed"
+		}
+	}
+	return ""
+}
+
+// clear removes all tracked groups.
+func (q *groupQueue) clear() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.groups = nil
+	q.lookup = make(map[common.Hash]*TxGroup)
+	groupGauge.Update(0)
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package txpool
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// testGroupChain is a stand-in blockchain with a fixed head and state.
+type testGroupChain struct {
+	statedb *state.StateDB
+}
+
+func (c *testGroupChain) Config() *params.ChainConfig { return params.TestChainConfig }
+
+func (c *testGroupChain) CurrentBlock() *types.Header {
+	return &types.Header{Number: big.NewInt(1), Time: 1, Difficulty: common.Big0, GasLimit: 30_000_000, BaseFee: big.NewInt(params.InitialBaseFee)}
+}
+
+func (c *testGroupChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+func (c *testGroupChain) StateAt(common.Hash) (*state.StateDB, error) { return c.statedb.Copy(), nil }
+
+// testSenderPolicy rejects all transactions of a denied sender.
+type testSenderPolicy struct {
+	denied common.Address
+}
+
+func (p *testSenderPolicy) Check(ctx context.Context, req *AdmissionRequest) (*AdmissionVerdict, error) {
+	return &AdmissionVerdict{Accept: req.Sender != p.denied, Reason: "denied sender"}, nil
+}
+
+func (p *testSenderPolicy) Close() error { return nil }
+
+// Tests that a group is only reported as included if all of its members were
+// mined, and as invalid if only some of them were.
+func TestGroupStaleReason(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		from   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		head   = &types.Header{Number: big.NewInt(1), Time: 1}
+		queue  = newGroupQueue(params.TestChainConfig, signer, 0, head)
+	)
+	transfer := func(nonce uint64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &common.Address{},
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	group := newTxGroup(types.Transactions{transfer(0), transfer(1)})
+
+	tests := []struct {
+		nonce  uint64
+		reason string
+	}{
+		{0, ""},              // nothing mined yet
+		{1, "nonce too low"}, // only the first member mined
+		{2, "included"},      // the whole group mined
+		{3, "included"},      // mined, followed by other transactions
+	}
+	for _, tt := range tests {
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		statedb.SetNonce(from, tt.nonce, 0)
+		if reason := queue.stale(group, statedb); reason != tt.reason {
+			t.Errorf("state nonce %d: reason mismatch: have %q, want %q", tt.nonce, reason, tt.reason)
+		}
+	}
+}
+
+// Tests that groups are subject to the admission policy, and are rejected as a
+// whole if any of their members is denied.
+func TestAddGroupAdmission(t *testing.T) {
+	var (
+		allowedKey, _ = crypto.GenerateKey()
+		deniedKey, _  = crypto.GenerateKey()
+		allowed       = crypto.PubkeyToAddress(allowedKey.PublicKey)
+		denied        = crypto.PubkeyToAddress(deniedKey.PublicKey)
+		signer        = types.LatestSigner(params.TestChainConfig)
+	)
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	statedb.AddBalance(allowed, uint256.NewInt(params.Ether), 0)
+	statedb.AddBalance(denied, uint256.NewInt(params.Ether), 0)
+
+	pool, err := New(0, &testGroupChain{statedb: statedb}, nil)
+	if err != nil {
+		t.Fatalf("failed to create pool: %v", err)
+	}
+	defer pool.Close()
+	pool.SetAdmission(NewAdmissionWithPolicy(&testSenderPolicy{denied: denied}, time.Second, false))
+
+	transfer := func(key *ecdsa.PrivateKey) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			To:       &common.Address{},
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	if _, err := pool.AddGroup(types.Transactions{transfer(allowedKey), transfer(deniedKey)}); !errors.Is(err, ErrTxRejectedByPolicy) {
+		t.Fatalf("group with denied sender: error mismatch: have %v, want %v", err, ErrTxRejectedByPolicy)
+	}
+	if groups := pool.PendingGroups(); len(groups) != 0 {
+		t.Fatalf("denied group queued: %d groups pending", len(groups))
+	}
+	if _, err := pool.AddGroup(types.Transactions{transfer(allowedKey)}); err != nil {
+		t.Fatalf("failed to add allowed group: %v", err)
+	}
+}
@@ -19,6 +19,7 @@ package legacypool
 
 import (
 	"errors"
+	"fmt"
 	"maps"
 	"math"
 	"math/big"
@@ -75,6 +76,10 @@ var (
 	// ErrFutureReplacePending is returned if a future transaction replaces a pending
 	// one. Future transactions should only be able to replace other future transactions.
 	ErrFutureReplacePending = errors.New("future transaction tries to replace pending")
+
+	// ErrPendingReplaceNotYetValid is returned if a timed transaction whose
+	// validity window has not yet opened tries to replace a pending one.
+	ErrPendingReplaceNotYetValid = errors.New("not yet valid transaction tries to replace pending")
 )
 
 var (
@@ -88,6 +93,7 @@ var (
 	pendingReplaceMeter   = metrics.NewRegisteredMeter("txpool/pending/replace", nil)
 	pendingRateLimitMeter = metrics.NewRegisteredMeter("txpool/pending/ratelimit", nil) // Dropped due to rate limiting
 	pendingNofundsMeter   = metrics.NewRegisteredMeter("txpool/pending/nofunds", nil)   // Dropped due to out-of-funds
+	pendingExpiredMeter   = metrics.NewRegisteredMeter("txpool/pending/expired", nil)   // Dropped due to closed validity window
 
 	// Metrics for the queued pool
 	queuedDiscardMeter   = metrics.NewRegisteredMeter("txpool/queued/discard", nil)
@@ -95,6 +101,7 @@ var (
 	queuedRateLimitMeter = metrics.NewRegisteredMeter("txpool/queued/ratelimit", nil) // Dropped due to rate limiting
 	queuedNofundsMeter   = metrics.NewRegisteredMeter("txpool/queued/nofunds", nil)   // Dropped due to out-of-funds
 	queuedEvictionMeter  = metrics.NewRegisteredMeter("txpool/queued/eviction", nil)  // Dropped due to lifetime
+	queuedExpiredMeter   = metrics.NewRegisteredMeter("txpool/queued/expired", nil)   // Dropped due to closed validity window
 
 	// General tx metrics
 	knownTxMeter       = metrics.NewRegisteredMeter("txpool/known", nil)
@@ -151,6 +158,8 @@ type Config struct {
 	GlobalQueue  uint64 // Maximum number of non-executable transaction slots for all accounts
 
 	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+
+	Policy AccountPolicy // Per-account slot limits by address class, overriding the above
 }
 
 // DefaultConfig contains the default configurations for the transaction pool.
@@ -201,6 +210,10 @@ func (config *Config) sanitize() Config {
 		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
 		conf.Lifetime = DefaultConfig.Lifetime
 	}
+	if err := conf.Policy.validate(); err != nil {
+		log.Warn("Sanitizing invalid txpool account policy", "err", err)
+		conf.Policy = AccountPolicy{}
+	}
 	return conf
 }
 
@@ -237,6 +250,7 @@ type LegacyPool struct {
 	currentHead   atomic.Pointer[types.Header] // Current head of the blockchain
 	currentState  *state.StateDB               // Current state in the blockchain head
 	pendingNonces *noncer                      // Pending state tracking virtual nonces
+	policy        *slotPolicy                  // Per-account slot limits, nil if no policy is configured
 	reserver      txpool.Reserver              // Address reserver to ensure exclusivity across subpools
 
 	pending map[common.Address]*list     // All currently processable transactions
@@ -284,15 +298,70 @@ func New(config Config, chain BlockChain) *LegacyPool {
 		initDoneCh:      make(chan struct{}),
 	}
 	pool.priced = newPricedList(pool.all)
+	pool.policy = pool.compilePolicy(config.Policy)
 
 	return pool
 }
 
+// compilePolicy converts an account policy into its lookup form, returning nil
+// if the policy is empty. The policy is expected to be validated already.
+func (pool *LegacyPool) compilePolicy(policy AccountPolicy) *slotPolicy {
+	if policy.RelayerRegistry == nil && len(policy.Allowlist) == 0 && len(policy.BalanceTiers) == 0 {
+		return nil
+	}
+	compiled, err := newSlotPolicy(policy, SlotLimits{Slots: pool.config.AccountSlots, Queue: pool.config.AccountQueue})
+	if err != nil {
+		log.Error("Failed to compile txpool account policy", "err", err)
+		return nil
+	}
+	return compiled
+}
+
+// SetPolicy replaces the per-account slot policy of the pool. Accounts already
+// exceeding their new limits are trimmed on the next pool reorganisation.
+func (pool *LegacyPool) SetPolicy(policy AccountPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	pool.mu.Lock()
+	pool.config.Policy = policy
+	pool.policy = pool.compilePolicy(policy)
+
+	accounts := newAccountSet(pool.signer)
+	for addr := range pool.queue {
+		accounts.add(addr)
+	}
+	pool.mu.Unlock()
+
+	pool.requestPromoteExecutables(accounts)
+	log.Info("Legacy pool account policy updated", "allowlist", len(policy.Allowlist), "registry", policy.RelayerRegistry, "tiers", len(policy.BalanceTiers))
+	return nil
+}
+
+// Policy returns the currently active per-account slot policy.
+func (pool *LegacyPool) Policy() AccountPolicy {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.config.Policy
+}
+
+// accountLimits returns the pending and queued slot limits of an account based
+// on the configured policy, or the global account limits if there is none. The
+// flag reports whether the account matched one of the policy's address classes.
+func (pool *LegacyPool) accountLimits(addr common.Address) (SlotLimits, bool) {
+	if pool.policy == nil {
+		return SlotLimits{Slots: pool.config.AccountSlots, Queue: pool.config.AccountQueue}, false
+	}
+	return pool.policy.limits(addr, pool.currentState)
+}
+
 // Filter returns whether the given transaction can be consumed by the legacy
-// pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
+// pool, specifically, whether it is a Legacy, AccessList, Dynamic, SetCode or
+// Timed transaction.
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.SetCodeTxType:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.SetCodeTxType, types.TimedTxType:
 		return true
 	default:
 		return false
@@ -370,10 +439,16 @@ func (pool *LegacyPool) loop() {
 		// Handle inactive account transaction eviction
 		case <-evict.C:
 			pool.mu.Lock()
-			for addr := range pool.queue {
+			for addr, queue := range pool.queue {
+				// Timed transactions are held until their validity window
+				// opens, their lifetime only starts counting from then on
+				beat := pool.beats[addr]
+				if after := queue.ValidAfter(); after > uint64(beat.Unix()) {
+					beat = time.Unix(int64(min(after, math.MaxInt64)), 0)
+				}
 				// Any old enough should be removed
-				if time.Since(pool.beats[addr]) > pool.config.Lifetime {
-					list := pool.queue[addr].Flatten()
+				if time.Since(beat) > pool.config.Lifetime {
+					list := queue.Flatten()
 					for _, tx := range list {
 						pool.removeTx(tx.Hash(), true, true)
 					}
@@ -570,7 +645,8 @@ func (pool *LegacyPool) ValidateTxBasics(tx *types.Transaction) error {
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
 			1<<types.DynamicFeeTxType |
-			1<<types.SetCodeTxType,
+			1<<types.SetCodeTxType |
+			1<<types.TimedTxType,
 		MaxSize: txMaxSize,
 		MinTip:  pool.gasTip.Load().ToBig(),
 	}
@@ -774,8 +850,33 @@ func (pool *LegacyPool) add(tx *types.Transaction) (replaced bool, err error) {
 		}
 	}
 
+	// If the sender matched a class of the account policy, enforce the class
+	// limits as a hard cap on the number of tracked transactions, unless the new
+	// one replaces an already tracked one. Unclassified accounts keep the soft
+	// global limits.
+	if limits, classified := pool.accountLimits(from); classified {
+		var (
+			tracked int
+			replace bool
+		)
+		if list := pool.pending[from]; list != nil {
+			tracked += list.Len()
+			replace = list.Contains(tx.Nonce())
+		}
+		if list := pool.queue[from]; list != nil {
+			tracked += list.Len()
+			replace = replace || list.Contains(tx.Nonce())
+		}
+		if !replace && uint64(tracked) >= limits.Slots+limits.Queue {
+			return false, fmt.Errorf("%w: sender %v has %d transactions, limit %d", txpool.ErrAccountLimitExceeded, from, tracked, limits.Slots+limits.Queue)
+		}
+	}
 	// Try to replace an existing transaction in the pending pool
 	if list := pool.pending[from]; list != nil && list.Contains(tx.Nonce()) {
+		// Pending transactions must be includable in the next block
+		if tx.ValidAfter() > pool.nextBlockTime() {
+			return false, ErrPendingReplaceNotYetValid
+		}
 		// Nonce already pending, check if required price bump is met
 		inserted, old := list.Add(tx, pool.config.PriceBump)
 		if !inserted {
@@ -1301,6 +1402,17 @@ func (pool *LegacyPool) runReorg(done chan struct{}, reset *txpoolResetRequest,
 			nonces[addr] = highestPending.Nonce() + 1
 		}
 		pool.pendingNonces.setAll(nonces)
+
+		// Queued transactions directly following the pending ones were held
+		// back by their validity window. Now that the pending nonces are up to
+		// date, retry promoting them as the window might have opened.
+		var held []common.Address
+		for addr, list := range pool.queue {
+			if list.Contains(pool.pendingNonces.get(addr)) {
+				held = append(held, addr)
+			}
+		}
+		promoted = append(promoted, pool.promoteExecutables(held)...)
 	}
 	// Ensure pool.queue and pool.pending sizes stay within the configured limits.
 	pool.truncatePending()
@@ -1434,7 +1546,10 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 	var promoted []*types.Transaction
 
 	// Iterate over all accounts and promote any executable transactions
-	gasLimit := pool.currentHead.Load().GasLimit
+	var (
+		gasLimit = pool.currentHead.Load().GasLimit
+		nextTime = pool.nextBlockTime()
+	)
 	for _, addr := range accounts {
 		list := pool.queue[addr]
 		if list == nil {
@@ -1454,8 +1569,17 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		log.Trace("Removed unpayable queued transactions", "count", len(drops))
 		queuedNofundsMeter.Mark(int64(len(drops)))
 
-		// Gather all executable transactions and promote them
-		readies := list.Ready(pool.pendingNonces.get(addr))
+		// Drop all timed transactions whose validity window closed
+		expired, _ := list.Expire(nextTime)
+		for _, tx := range expired {
+			pool.all.Remove(tx.Hash())
+		}
+		log.Trace("Removed expired queued transactions", "count", len(expired))
+		queuedExpiredMeter.Mark(int64(len(expired)))
+
+		// Gather all executable transactions and promote them, holding back
+		// any timed transactions not yet includable in the next block
+		readies := list.Ready(pool.pendingNonces.get(addr), nextTime)
 		for _, tx := range readies {
 			hash := tx.Hash()
 			if pool.promoteTx(addr, hash, tx) {
@@ -1466,7 +1590,8 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		queuedGauge.Dec(int64(len(readies)))
 
 		// Drop all transactions over the allowed limit
-		var caps = list.Cap(int(pool.config.AccountQueue))
+		limits, _ := pool.accountLimits(addr)
+		var caps = list.Cap(int(limits.Queue))
 		for _, tx := range caps {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
@@ -1474,8 +1599,8 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		}
 		queuedRateLimitMeter.Mark(int64(len(caps)))
 		// Mark all the items dropped as removed
-		pool.priced.Removed(len(forwards) + len(drops) + len(caps))
-		queuedGauge.Dec(int64(len(forwards) + len(drops) + len(caps)))
+		pool.priced.Removed(len(forwards) + len(drops) + len(expired) + len(caps))
+		queuedGauge.Dec(int64(len(forwards) + len(drops) + len(expired) + len(caps)))
 
 		// Delete the entire queue entry if it became empty.
 		if list.Empty() {
@@ -1506,7 +1631,7 @@ func (pool *LegacyPool) truncatePending() {
 	spammers := prque.New[int64, common.Address](nil)
 	for addr, list := range pool.pending {
 		// Only evict transactions from high rollers
-		if uint64(list.Len()) > pool.config.AccountSlots {
+		if limits, _ := pool.accountLimits(addr); uint64(list.Len()) > limits.Slots {
 			spammers.Push(addr, int64(list.Len()))
 		}
 	}
@@ -1548,9 +1673,19 @@ func (pool *LegacyPool) truncatePending() {
 
 	// If still above threshold, reduce to limit or min allowance
 	if pending > pool.config.GlobalSlots && len(offenders) > 0 {
-		for pending > pool.config.GlobalSlots && uint64(pool.pending[offenders[len(offenders)-1]].Len()) > pool.config.AccountSlots {
+		limits := make(map[common.Address]uint64, len(offenders))
+		for _, addr := range offenders {
+			accountLimits, _ := pool.accountLimits(addr)
+			limits[addr] = accountLimits.Slots
+		}
+		for trimmed := true; pending > pool.config.GlobalSlots && trimmed; {
+			trimmed = false
 			for _, addr := range offenders {
 				list := pool.pending[addr]
+				if uint64(list.Len()) <= limits[addr] {
+					continue
+				}
+				trimmed = true
 
 				caps := list.Cap(list.Len() - 1)
 				for _, tx := range caps {
@@ -1623,7 +1758,10 @@ func (pool *LegacyPool) truncateQueue() {
 // to trigger a re-heap is this function
 func (pool *LegacyPool) demoteUnexecutables() {
 	// Iterate over all accounts and demote any non-executable transactions
-	gasLimit := pool.currentHead.Load().GasLimit
+	var (
+		gasLimit = pool.currentHead.Load().GasLimit
+		nextTime = pool.nextBlockTime()
+	)
 	for addr, list := range pool.pending {
 		nonce := pool.currentState.GetNonce(addr)
 
@@ -1643,6 +1781,17 @@ func (pool *LegacyPool) demoteUnexecutables() {
 		}
 		pendingNofundsMeter.Mark(int64(len(drops)))
 
+		// Drop all timed transactions whose validity window closed, and queue
+		// any invalids back for later
+		expired, unlocked := list.Expire(nextTime)
+		for _, tx := range expired {
+			hash := tx.Hash()
+			pool.all.Remove(hash)
+			log.Trace("Removed expired pending transaction", "hash", hash)
+		}
+		pendingExpiredMeter.Mark(int64(len(expired)))
+		invalids = append(invalids, unlocked...)
+
 		for _, tx := range invalids {
 			hash := tx.Hash()
 			log.Trace("Demoting pending transaction", "hash", hash)
@@ -1650,7 +1799,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			// Internal shuffle shouldn't touch the lookup set.
 			pool.enqueueTx(hash, tx, false)
 		}
-		pendingGauge.Dec(int64(len(olds) + len(drops) + len(invalids)))
+		pendingGauge.Dec(int64(len(olds) + len(drops) + len(expired) + len(invalids)))
 
 		// If there's a gap in front, alert (should never happen) and postpone all transactions
 		if list.Len() > 0 && list.txs.Get(nonce) == nil {
@@ -1674,6 +1823,13 @@ func (pool *LegacyPool) demoteUnexecutables() {
 	}
 }
 
+// nextBlockTime returns the earliest timestamp the block built on top of the
+// current head can have. Timed transactions are admitted into the pending set
+// and evicted from the pool relative to this time.
+func (pool *LegacyPool) nextBlockTime() uint64 {
+	return pool.currentHead.Load().Time + 1
+}
+
 // addressByHeartbeat is an account address tagged with its last activity timestamp.
 type addressByHeartbeat struct {
 	address   common.Address
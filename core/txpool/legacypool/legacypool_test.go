@@ -30,6 +30,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/tracing"
@@ -131,6 +132,21 @@ func dynamicFeeTx(nonce uint64, gaslimit uint64, gasFee *big.Int, tip *big.Int,
 	return tx
 }
 
+func timedTx(nonce uint64, gaslimit uint64, validAfter, validUntil uint64, key *ecdsa.PrivateKey) *types.Transaction {
+	tx, _ := types.SignNewTx(key, types.LatestSignerForChainID(params.TestChainConfig.ChainID), &types.TimedTx{
+		ChainID:    params.TestChainConfig.ChainID,
+		Nonce:      nonce,
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(1),
+		Gas:        gaslimit,
+		To:         &common.Address{},
+		Value:      big.NewInt(100),
+		ValidAfter: validAfter,
+		ValidUntil: validUntil,
+	})
+	return tx
+}
+
 type unsignedAuth struct {
 	nonce uint64
 	key   *ecdsa.PrivateKey
@@ -956,6 +972,114 @@ func TestQueueAccountLimiting(t *testing.T) {
 	}
 }
 
+// Tests that the account policy assigns the slot limits of the matching address
+// class, and enforces them as hard caps on the tracked transactions.
+func TestAccountPolicy(t *testing.T) {
+	t.Parallel()
+
+	pool, _ := setupPool()
+	defer pool.Close()
+
+	var (
+		allowKey, _   = crypto.GenerateKey()
+		relayerKey, _ = crypto.GenerateKey()
+		richKey, _    = crypto.GenerateKey()
+		poorKey, _    = crypto.GenerateKey()
+
+		allowed  = crypto.PubkeyToAddress(allowKey.PublicKey)
+		relayer  = crypto.PubkeyToAddress(relayerKey.PublicKey)
+		rich     = crypto.PubkeyToAddress(richKey.PublicKey)
+		poor     = crypto.PubkeyToAddress(poorKey.PublicKey)
+		registry = common.HexToAddress("0x1000")
+	)
+	testAddBalance(pool, allowed, big.NewInt(10000000))
+	testAddBalance(pool, relayer, big.NewInt(1000000))
+	testAddBalance(pool, rich, big.NewInt(1000000000))
+	testAddBalance(pool, poor, big.NewInt(50000000))
+
+	// Register the relayer in the registry's mapping stored at slot 3
+	pool.mu.Lock()
+	key := crypto.Keccak256Hash(common.LeftPadBytes(relayer.Bytes(), 32), common.BigToHash(big.NewInt(3)).Bytes())
+	pool.currentState.SetState(registry, key, common.BytesToHash([]byte{1}))
+	pool.mu.Unlock()
+
+	policy := AccountPolicy{
+		Allowlist:       []common.Address{allowed},
+		AllowlistLimits: SlotLimits{Slots: 4, Queue: 8},
+		RelayerRegistry: &registry,
+		RelayerMapSlot:  3,
+		RelayerLimits:   SlotLimits{Queue: 2},
+		BalanceTiers: []BalanceTier{
+			{MinBalance: (*math.HexOrDecimal256)(big.NewInt(100000000)), Limits: SlotLimits{Slots: 2, Queue: 3}},
+		},
+	}
+	if err := pool.SetPolicy(policy); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	tests := []struct {
+		addr       common.Address
+		want       SlotLimits
+		classified bool
+	}{
+		{allowed, SlotLimits{Slots: 4, Queue: 8}, true},
+		{relayer, SlotLimits{Slots: testTxPoolConfig.AccountSlots, Queue: 2}, true},
+		{rich, SlotLimits{Slots: 2, Queue: 3}, true},
+		{poor, SlotLimits{Slots: testTxPoolConfig.AccountSlots, Queue: testTxPoolConfig.AccountQueue}, false},
+	}
+	for i, tt := range tests {
+		pool.mu.RLock()
+		have, classified := pool.accountLimits(tt.addr)
+		pool.mu.RUnlock()
+		if have != tt.want {
+			t.Errorf("test %d: limits mismatch: have %+v, want %+v", i, have, tt.want)
+		}
+		if classified != tt.classified {
+			t.Errorf("test %d: classification mismatch: have %v, want %v", i, classified, tt.classified)
+		}
+	}
+	// Fill up the allowlisted and tiered accounts and ensure the caps are enforced
+	for _, acc := range []struct {
+		key   *ecdsa.PrivateKey
+		limit uint64
+	}{{allowKey, 12}, {richKey, 5}} {
+		for nonce := uint64(0); nonce < acc.limit; nonce++ {
+			if err := pool.addRemoteSync(transaction(nonce, 100000, acc.key)); err != nil {
+				t.Fatalf("failed to add transaction %d: %v", nonce, err)
+			}
+		}
+		if err := pool.addRemoteSync(transaction(acc.limit, 100000, acc.key)); !errors.Is(err, txpool.ErrAccountLimitExceeded) {
+			t.Fatalf("over-limit transaction error mismatch: have %v, want %v", err, txpool.ErrAccountLimitExceeded)
+		}
+		// Replacements must still be accepted at the limit
+		if err := pool.addRemoteSync(pricedTransaction(0, 100000, big.NewInt(2), acc.key)); err != nil {
+			t.Fatalf("failed to replace transaction at limit: %v", err)
+		}
+	}
+	// Unclassified accounts must not be hard capped, only trimmed by the global
+	// limits as without a policy
+	limit := testTxPoolConfig.AccountSlots + testTxPoolConfig.AccountQueue
+	for nonce := uint64(0); nonce <= limit; nonce++ {
+		if err := pool.addRemoteSync(transaction(nonce, 100000, poorKey)); err != nil {
+			t.Fatalf("failed to add unclassified transaction %d: %v", nonce, err)
+		}
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Dropping the policy should restore the global limits
+	if err := pool.SetPolicy(AccountPolicy{}); err != nil {
+		t.Fatalf("failed to clear policy: %v", err)
+	}
+	if err := pool.addRemoteSync(transaction(5, 100000, richKey)); err != nil {
+		t.Fatalf("failed to add transaction after clearing policy: %v", err)
+	}
+	// Invalid policies must be rejected
+	invalid := AccountPolicy{BalanceTiers: []BalanceTier{{Limits: SlotLimits{Slots: 1}}}}
+	if err := pool.SetPolicy(invalid); err == nil {
+		t.Fatalf("invalid policy accepted")
+	}
+}
+
 // Tests that if the transaction count belonging to multiple accounts go above
 // some threshold, the higher transactions are dropped to prevent DOS attacks.
 //
@@ -2213,6 +2337,113 @@ func TestStatusCheck(t *testing.T) {
 	}
 }
 
+// Tests that timed transactions are held in the queue until their validity
+// window opens and are evicted once it closes.
+func TestTimedTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPoolWithConfig(params.MergedTestChainConfig)
+	defer pool.Close()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, from, big.NewInt(1000000000))
+
+	// Transactions with a closed or inverted window should be rejected outright
+	if err := pool.addRemoteSync(timedTx(0, 100000, 0, 0, key)); err != nil {
+		t.Fatalf("failed to add unbounded timed transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(timedTx(1, 100000, 200, 100, key)); !errors.Is(err, core.ErrTxInvalidWindow) {
+		t.Fatalf("inverted window error mismatch: have %v, want %v", err, core.ErrTxInvalidWindow)
+	}
+	// Not yet valid transactions should stay queued, together with any follow-ups
+	if err := pool.addRemoteSync(timedTx(1, 100000, 100, 0, key)); err != nil {
+		t.Fatalf("failed to add not yet valid transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(timedTx(2, 100000, 0, 150, key)); err != nil {
+		t.Fatalf("failed to add expiring transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(3, 100000, big.NewInt(1), big.NewInt(1), key)); err != nil {
+		t.Fatalf("failed to add untimed transaction: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 3 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 1/3", pending, queued)
+	}
+	// Replacing a pending transaction with a not yet valid one should fail
+	if err := pool.addRemoteSync(timedTx(0, 100000, 100, 0, key)); !errors.Is(err, ErrPendingReplaceNotYetValid) {
+		t.Fatalf("pending replacement error mismatch: have %v, want %v", err, ErrPendingReplaceNotYetValid)
+	}
+	// Advance the head so that the window opens, everything should be promoted
+	<-pool.requestReset(nil, &types.Header{Number: big.NewInt(1), Difficulty: common.Big0, GasLimit: 10000000, BaseFee: common.Big0, Time: 99})
+	if pending, queued := pool.Stats(); pending != 4 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 4/0", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Advance the head past the expiry, the expiring transaction should be
+	// dropped and the subsequent one demoted
+	<-pool.requestReset(nil, &types.Header{Number: big.NewInt(2), Difficulty: common.Big0, GasLimit: 10000000, BaseFee: common.Big0, Time: 150})
+	if pending, queued := pool.Stats(); pending != 2 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 2/1", pending, queued)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	// Transactions already expired at the head should be rejected
+	if err := pool.addRemoteSync(timedTx(2, 100000, 0, 150, key)); !errors.Is(err, core.ErrTxExpired) {
+		t.Fatalf("expired transaction error mismatch: have %v, want %v", err, core.ErrTxExpired)
+	}
+}
+
+// Tests that timed transactions waiting for their validity window to open are
+// not evicted from the queue after the configured lifetime.
+func TestTimedTransactionsTimeLimiting(t *testing.T) {
+	// Reduce the eviction interval to a testable amount
+	defer func(old time.Duration) { evictionInterval = old }(evictionInterval)
+	evictionInterval = time.Millisecond * 100
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.MergedTestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Lifetime = time.Second
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), newReserver())
+	defer pool.Close()
+
+	var (
+		timed, _  = crypto.GenerateKey()
+		plain, _  = crypto.GenerateKey()
+		validFrom = uint64(time.Now().Add(time.Hour).Unix())
+	)
+	testAddBalance(pool, crypto.PubkeyToAddress(timed.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(plain.PublicKey), big.NewInt(1000000000))
+
+	// Queue up a not yet valid timed transaction and a nonce-gapped one
+	if err := pool.addRemoteSync(timedTx(0, 100000, validFrom, 0, timed)); err != nil {
+		t.Fatalf("failed to add timed transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(1, 100000, big.NewInt(1), big.NewInt(1), plain)); err != nil {
+		t.Fatalf("failed to add gapped transaction: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 0 || queued != 2 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 0/2", pending, queued)
+	}
+	// Wait for the lifetime to pass, only the gapped transaction should be evicted
+	time.Sleep(2 * config.Lifetime)
+
+	if pending, queued := pool.Stats(); pending != 0 || queued != 1 {
+		t.Fatalf("pool stats mismatch: have %d/%d, want 0/1", pending, queued)
+	}
+	if pool.Get(timedTx(0, 100000, validFrom, 0, timed).Hash()) == nil {
+		t.Fatalf("timed transaction evicted before its validity window opened")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the transaction slots consumption is computed correctly
 func TestSlotCount(t *testing.T) {
 	t.Parallel()
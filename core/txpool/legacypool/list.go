@@ -210,6 +210,12 @@ func (m *SortedMap) Remove(nonce uint64) bool {
 // prevent getting into an invalid state. This is not something that should ever
 // happen but better to be self correcting than failing!
 func (m *SortedMap) Ready(start uint64) types.Transactions {
+	return m.ReadyAt(start, math.MaxUint64)
+}
+
+// ReadyAt is identical to Ready, but additionally stops at the first timed
+// transaction whose validity window has not yet opened at the given timestamp.
+func (m *SortedMap) ReadyAt(start uint64, timestamp uint64) types.Transactions {
 	// Short circuit if no transactions are available
 	if m.index.Len() == 0 || (*m.index)[0] > start {
 		return nil
@@ -217,6 +223,9 @@ func (m *SortedMap) Ready(start uint64) types.Transactions {
 	// Otherwise start accumulating incremental transactions
 	var ready types.Transactions
 	for next := (*m.index)[0]; m.index.Len() > 0 && (*m.index)[0] == next; next++ {
+		if m.items[next].ValidAfter() > timestamp {
+			break
+		}
 		ready = append(ready, m.items[next])
 		delete(m.items, next)
 		heap.Pop(m.index)
@@ -396,6 +405,44 @@ func (l *list) Filter(costLimit *uint256.Int, gasLimit uint64) (types.Transactio
 	return removed, invalids
 }
 
+// ValidAfter returns the latest opening time of the validity windows of the
+// timed transactions in the list, or zero if there are none.
+func (l *list) ValidAfter() uint64 {
+	var after uint64
+	for _, tx := range l.txs.items {
+		after = max(after, tx.ValidAfter())
+	}
+	return after
+}
+
+// Expire removes all timed transactions from the list whose validity window
+// closed before the given timestamp. Every removed transaction is returned for any
+// post-removal maintenance. Strict-mode invalidated transactions are also
+// returned.
+func (l *list) Expire(timestamp uint64) (types.Transactions, types.Transactions) {
+	removed := l.txs.Filter(func(tx *types.Transaction) bool {
+		until := tx.ValidUntil()
+		return until != 0 && until < timestamp
+	})
+	if len(removed) == 0 {
+		return nil, nil
+	}
+	var invalids types.Transactions
+	// If the list was strict, filter anything above the lowest nonce
+	if l.strict {
+		lowest := uint64(math.MaxUint64)
+		for _, tx := range removed {
+			if nonce := tx.Nonce(); lowest > nonce {
+				lowest = nonce
+			}
+		}
+		invalids = l.txs.Filter(func(tx *types.Transaction) bool { return tx.Nonce() > lowest })
+	}
+	l.subTotalCost(removed)
+	l.subTotalCost(invalids)
+	return removed, invalids
+}
+
 // Cap places a hard limit on the number of items, returning all transactions
 // exceeding that limit.
 func (l *list) Cap(threshold int) types.Transactions {
@@ -430,8 +477,11 @@ func (l *list) Remove(tx *types.Transaction) (bool, types.Transactions) {
 // Note, all transactions with nonces lower than start will also be returned to
 // prevent getting into an invalid state. This is not something that should ever
 // happen but better to be self correcting than failing!
-func (l *list) Ready(start uint64) types.Transactions {
-	txs := l.txs.Ready(start)
+//
+// Timed transactions whose validity window has not yet opened at the given
+// timestamp are held back, together with any subsequent transactions.
+func (l *list) Ready(start uint64, timestamp uint64) types.Transactions {
+	txs := l.txs.ReadyAt(start, timestamp)
 	l.subTotalCost(txs)
 	return txs
 }
@@ -0,0 +1,153 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// SlotLimits is a pair of per-account pending and queued transaction limits. A
+// zero limit means the global AccountSlots or AccountQueue value applies.
+type SlotLimits struct {
+	Slots uint64 `json:"slots"` // Number of executable transaction slots guaranteed per account
+	Queue uint64 `json:"queue"` // Maximum number of non-executable transaction slots per account
+}
+
+// BalanceTier assigns slot limits to all accounts holding at least a minimum
+// balance at the current head.
+type BalanceTier struct {
+	MinBalance *math.HexOrDecimal256 `json:"minBalance"` // Minimum balance (in wei) to qualify for the tier
+	Limits     SlotLimits            `json:"limits"`     // Slot limits granted to the tier's accounts
+}
+
+// AccountPolicy assigns per-account slot limits based on the class of the
+// sending address. The classes are checked in order: explicitly allowlisted
+// accounts, relayers registered in an on-chain contract, and lastly balance
+// tiers. Accounts not matching any class use the global account limits.
+type AccountPolicy struct {
+	Allowlist       []common.Address `json:"allowlist"`       // Accounts explicitly granted the allowlist limits
+	AllowlistLimits SlotLimits       `json:"allowlistLimits"` // Slot limits of the allowlisted accounts
+
+	// RelayerRegistry is a contract holding the relayer set as a mapping from
+	// address to boolean, stored at RelayerMapSlot. Accounts with a non-zero
+	// entry in the mapping are granted the relayer limits.
+	RelayerRegistry *common.Address `json:"relayerRegistry" toml:",omitempty"`
+	RelayerMapSlot  uint64          `json:"relayerMapSlot"` // Storage slot of the relayer mapping within the registry
+	RelayerLimits   SlotLimits      `json:"relayerLimits"`  // Slot limits of the registered relayers
+
+	BalanceTiers []BalanceTier `json:"balanceTiers"` // Balance thresholds with their slot limits
+}
+
+// validate checks the policy for any obviously invalid settings.
+func (p *AccountPolicy) validate() error {
+	for i, tier := range p.BalanceTiers {
+		if tier.MinBalance == nil {
+			return fmt.Errorf("balance tier %d: missing minimum balance", i)
+		}
+		if (*big.Int)(tier.MinBalance).Sign() < 0 {
+			return fmt.Errorf("balance tier %d: negative minimum balance", i)
+		}
+	}
+	if p.RelayerRegistry == nil && p.RelayerLimits != (SlotLimits{}) {
+		return errors.New("relayer limits set without registry contract")
+	}
+	return nil
+}
+
+// slotPolicy is the compiled form of an AccountPolicy, resolving the limits of
+// individual accounts against the pool's current state.
+type slotPolicy struct {
+	defaults  SlotLimits
+	allowlist map[common.Address]struct{}
+	allowed   SlotLimits
+
+	registry *common.Address
+	mapSlot  common.Hash
+	relayer  SlotLimits
+
+	tiers []BalanceTier // Sorted by descending minimum balance
+}
+
+// newSlotPolicy compiles an account policy, falling back to the given defaults
+// for accounts not matching any class, or classes with zero limits.
+func newSlotPolicy(policy AccountPolicy, defaults SlotLimits) (*slotPolicy, error) {
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+	p := &slotPolicy{
+		defaults:  defaults,
+		allowlist: make(map[common.Address]struct{}, len(policy.Allowlist)),
+		allowed:   policy.AllowlistLimits,
+		registry:  policy.RelayerRegistry,
+		mapSlot:   common.BigToHash(new(big.Int).SetUint64(policy.RelayerMapSlot)),
+		relayer:   policy.RelayerLimits,
+		tiers:     slices.Clone(policy.BalanceTiers),
+	}
+	for _, addr := range policy.Allowlist {
+		p.allowlist[addr] = struct{}{}
+	}
+	slices.SortStableFunc(p.tiers, func(a, b BalanceTier) int {
+		return (*big.Int)(b.MinBalance).Cmp((*big.Int)(a.MinBalance))
+	})
+	return p, nil
+}
+
+// limits returns the pending and queued slot limits of the given account, and
+// whether the account matched any of the policy's address classes.
+func (p *slotPolicy) limits(addr common.Address, statedb *state.StateDB) (SlotLimits, bool) {
+	if _, ok := p.allowlist[addr]; ok {
+		return p.fill(p.allowed), true
+	}
+	if p.registry != nil && statedb != nil && p.isRelayer(addr, statedb) {
+		return p.fill(p.relayer), true
+	}
+	if len(p.tiers) > 0 && statedb != nil {
+		balance := statedb.GetBalance(addr).ToBig()
+		for _, tier := range p.tiers {
+			if balance.Cmp((*big.Int)(tier.MinBalance)) >= 0 {
+				return p.fill(tier.Limits), true
+			}
+		}
+	}
+	return p.defaults, false
+}
+
+// isRelayer checks whether the account has a non-zero entry in the registry's
+// relayer mapping.
+func (p *slotPolicy) isRelayer(addr common.Address, statedb *state.StateDB) bool {
+	key := crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), 32), p.mapSlot.Bytes())
+	return statedb.GetState(*p.registry, key) != (common.Hash{})
+}
+
+// fill replaces any zero limits of a class with the defaults.
+func (p *slotPolicy) fill(limits SlotLimits) SlotLimits {
+	if limits.Slots == 0 {
+		limits.Slots = p.defaults.Slots
+	}
+	if limits.Queue == 0 {
+		limits.Queue = p.defaults.Queue
+	}
+	return limits
+}
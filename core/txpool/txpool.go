@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -64,10 +65,13 @@ type BlockChain interface {
 // They exit the pool when they are included in the blockchain or evicted due to
 // resource constraints.
 type TxPool struct {
-	subpools []SubPool // List of subpools for specialized transaction handling
+	subpools []SubPool   // List of subpools for specialized transaction handling
+	groups   *groupQueue // Atomic transaction groups awaiting local inclusion
 	chain    BlockChain
 	signer   types.Signer
 
+	admission atomic.Pointer[Admission] // Optional policy hook consulted before adding transactions
+
 	stateLock sync.RWMutex   // The lock for protecting state instance
 	state     *state.StateDB // Current state at the blockchain head
 
@@ -96,10 +100,12 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 	if err != nil {
 		return nil, err
 	}
+	signer := types.LatestSigner(chain.Config())
 	pool := &TxPool{
 		subpools: subpools,
+		groups:   newGroupQueue(chain.Config(), signer, gasTip, head),
 		chain:    chain,
-		signer:   types.LatestSigner(chain.Config()),
+		signer:   signer,
 		state:    statedb,
 		quit:     make(chan chan error),
 		term:     make(chan struct{}),
@@ -134,6 +140,12 @@ func (p *TxPool) Close() error {
 			errs = append(errs, err)
 		}
 	}
+	// Release the admission policy, if any
+	if admission := p.admission.Swap(nil); admission != nil {
+		if err := admission.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	// Unsubscribe anyone still listening for tx events
 	p.subs.Close()
 
@@ -194,6 +206,9 @@ func (p *TxPool) loop(head *types.Header) {
 					p.stateLock.Lock()
 					p.state = statedb
 					p.stateLock.Unlock()
+
+					// Drop any transaction groups invalidated by the new head
+					p.groups.reset(newHead, statedb)
 				}
 
 				// Busy marker injected, start a new subpool reset
@@ -263,11 +278,18 @@ func (p *TxPool) SetGasTip(tip *big.Int) {
 	for _, subpool := range p.subpools {
 		subpool.SetGasTip(tip)
 	}
+	p.groups.setGasTip(tip)
 }
 
 // Has returns an indicator whether the pool has a transaction cached with the
-// given hash.
+// given hash, either in one of the subpools or as a member of a group.
 func (p *TxPool) Has(hash common.Hash) bool {
+	return p.hasSubpool(hash) || p.groups.has(hash)
+}
+
+// hasSubpool returns an indicator whether any of the subpools has a transaction
+// cached with the given hash.
+func (p *TxPool) hasSubpool(hash common.Hash) bool {
 	for _, subpool := range p.subpools {
 		if subpool.Has(hash) {
 			return true
@@ -283,7 +305,7 @@ func (p *TxPool) Get(hash common.Hash) *types.Transaction {
 			return tx
 		}
 	}
-	return nil
+	return p.groups.get(hash)
 }
 
 // GetRLP returns a RLP-encoded transaction if it is contained in the pool.
@@ -324,13 +346,58 @@ func (p *TxPool) GetBlobs(vhashes []common.Hash) ([]*kzg4844.Blob, []*kzg4844.Pr
 	return nil, nil
 }
 
-// Add enqueues a batch of transactions into the pool if they are valid. Due
-// to the large transaction churn, add may postpone fully integrating the tx
-// to a later point to batch multiple ones together.
+// SetAdmission installs the policy hook consulted before adding transactions to
+// the pool, closing any previously installed one. A nil admission disables it.
+func (p *TxPool) SetAdmission(admission *Admission) {
+	if old := p.admission.Swap(admission); old != nil {
+		if err := old.Close(); err != nil {
+			log.Warn("Failed to close admission policy", "err", err)
+		}
+	}
+}
+
+// Add enqueues a batch of locally submitted transactions into the pool if they
+// are valid. Due to the large transaction churn, add may postpone fully
+// integrating the tx to a later point to batch multiple ones together.
 //
 // Note, if sync is set the method will block until all internal maintenance
 // related to the add is finished. Only use this during tests for determinism.
 func (p *TxPool) Add(txs []*types.Transaction, sync bool) []error {
+	return p.AddWithOrigin(txs, OriginLocal, sync)
+}
+
+// AddWithOrigin enqueues a batch of transactions into the pool if they are valid,
+// similarly to Add. The origin is either OriginLocal or the ID of the peer the
+// transactions were received from, and is passed to the admission policy.
+func (p *TxPool) AddWithOrigin(txs []*types.Transaction, origin string, sync bool) []error {
+	// Consult the admission policy first, if any, and only pass on the accepted
+	// transactions to the subpools.
+	if admission := p.admission.Load(); admission != nil {
+		var (
+			errs     = admission.check(txs, p.signer, origin, p.Has)
+			accepted = make([]*types.Transaction, 0, len(txs))
+		)
+		for i, tx := range txs {
+			if errs[i] == nil {
+				accepted = append(accepted, tx)
+			}
+		}
+		if len(accepted) == len(txs) {
+			return p.add(txs, sync)
+		}
+		added := p.add(accepted, sync)
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i], added = added[0], added[1:]
+			}
+		}
+		return errs
+	}
+	return p.add(txs, sync)
+}
+
+// add splits a batch of transactions between the subpools and adds them.
+func (p *TxPool) add(txs []*types.Transaction, sync bool) []error {
 	// Split the input transactions between the subpools. It shouldn't really
 	// happen that we receive merged batches, but better graceful than strange
 	// errors.
@@ -344,6 +411,13 @@ func (p *TxPool) Add(txs []*types.Transaction, sync bool) []error {
 		// Mark this transaction belonging to no-subpool
 		splits[i] = -1
 
+		// Members of a group may only be included along with the group, reject
+		// any attempt to add them on their own
+		if p.groups.has(tx.Hash()) {
+			splits[i] = -2
+			continue
+		}
+
 		// Try to find a subpool that accepts the transaction
 		for j, subpool := range p.subpools {
 			if subpool.Filter(tx) {
@@ -366,6 +440,11 @@ func (p *TxPool) Add(txs []*types.Transaction, sync bool) []error {
 			errs[i] = fmt.Errorf("%w: received type %d", core.ErrTxTypeNotSupported, txs[i].Type())
 			continue
 		}
+		// If the transaction is a member of a group, mark it known
+		if split == -2 {
+			errs[i] = fmt.Errorf("%w: member of a transaction group", ErrAlreadyKnown)
+			continue
+		}
 		// Find which subpool handled it and pull in the corresponding error
 		errs[i] = errsets[split][0]
 		errsets[split] = errsets[split][1:]
@@ -373,6 +452,39 @@ func (p *TxPool) Add(txs []*types.Transaction, sync bool) []error {
 	return errs
 }
 
+// AddGroup validates a batch of transactions as an atomic group and queues it
+// for inclusion by the local block builder. The member transactions must all be
+// included contiguously and in order within a single block, or not at all.
+//
+// Groups are not propagated to the network and are not tracked by the subpools.
+func (p *TxPool) AddGroup(txs types.Transactions) (common.Hash, error) {
+	// Consult the admission policy first, if any, rejecting the whole group if
+	// any of its members is denied. Oversized groups are left for the queue to
+	// reject without bothering the policy.
+	if admission := p.admission.Load(); admission != nil && len(txs) <= maxGroupSize {
+		for i, err := range admission.check(txs, p.signer, OriginLocal, p.Has) {
+			if err != nil {
+				return common.Hash{}, fmt.Errorf("tx %d: %w", i, err)
+			}
+		}
+	}
+	p.stateLock.RLock()
+	statedb := p.state.Copy()
+	p.stateLock.RUnlock()
+
+	group, err := p.groups.add(txs, statedb, p.hasSubpool)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return group.Hash, nil
+}
+
+// PendingGroups retrieves all the transaction groups awaiting inclusion, in the
+// order they were received.
+func (p *TxPool) PendingGroups() []*TxGroup {
+	return p.groups.pending()
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
@@ -507,4 +619,5 @@ func (p *TxPool) Clear() {
 	for _, subpool := range p.subpools {
 		subpool.Clear()
 	}
+	p.groups.clear()
 }
@@ -42,7 +42,7 @@ var (
 type ValidationOptions struct {
 	Config *params.ChainConfig // Chain configuration to selectively validate based on current fork rules
 
-	Accept  uint8    // Bitmap of transaction types that should be accepted for the calling pool
+	Accept  uint32   // Bitmap of transaction types that should be accepted for the calling pool
 	MaxSize uint64   // Maximum size of a transaction that the caller can meaningfully handle
 	MinTip  *big.Int // Minimum gas tip needed to allow a transaction into the caller pool
 }
@@ -82,6 +82,9 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if !rules.IsPrague && tx.Type() == types.SetCodeTxType {
 		return fmt.Errorf("%w: type %d rejected, pool not yet in Prague", core.ErrTxTypeNotSupported, tx.Type())
 	}
+	if !rules.IsTimedTx && tx.Type() == types.TimedTxType {
+		return fmt.Errorf("%w: type %d rejected, timed transactions not yet activated", core.ErrTxTypeNotSupported, tx.Type())
+	}
 	// Check whether the init code size has been exceeded
 	if rules.IsShanghai && tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
 		return fmt.Errorf("%w: code size %v, limit %v", core.ErrMaxInitCodeSizeExceeded, len(tx.Data()), params.MaxInitCodeSize)
@@ -162,6 +165,17 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 			return fmt.Errorf("set code tx must have at least one authorization tuple")
 		}
 	}
+	if tx.Type() == types.TimedTxType {
+		// Reject windows that can never open and windows which already closed
+		if until := tx.ValidUntil(); until != 0 {
+			if until < tx.ValidAfter() {
+				return fmt.Errorf("%w: validAfter %d, validUntil %d", core.ErrTxInvalidWindow, tx.ValidAfter(), until)
+			}
+			if until <= head.Time {
+				return fmt.Errorf("%w: validUntil %d, head time %d", core.ErrTxExpired, until, head.Time)
+			}
+		}
+	}
 	return nil
 }
 
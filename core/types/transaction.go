@@ -36,6 +36,8 @@ var (
 	ErrInvalidTxType        = errors.New("transaction type not valid in this context")
 	ErrTxTypeNotSupported   = errors.New("transaction type not supported")
 	ErrGasFeeCapTooLow      = errors.New("fee cap less than base fee")
+	ErrTxNotYetValid        = errors.New("transaction validity window not yet open")
+	ErrTxExpired            = errors.New("transaction validity window expired")
 	errShortTypedTx         = errors.New("typed transaction too short")
 	errInvalidYParity       = errors.New("'yParity' field must be 0 or 1")
 	errVYParityMismatch     = errors.New("'v' and 'yParity' fields do not match")
@@ -49,6 +51,7 @@ const (
 	DynamicFeeTxType = 0x02
 	BlobTxType       = 0x03
 	SetCodeTxType    = 0x04
+	TimedTxType      = 0x10
 )
 
 // Transaction is an Ethereum transaction.
@@ -71,7 +74,8 @@ func NewTx(inner TxData) *Transaction {
 
 // TxData is the underlying data of a transaction.
 //
-// This is implemented by DynamicFeeTx, LegacyTx and AccessListTx.
+// This is implemented by DynamicFeeTx, LegacyTx, AccessListTx, BlobTx, SetCodeTx
+// and TimedTx.
 type TxData interface {
 	txType() byte // returns the type ID
 	copy() TxData // creates a deep copy and initializes all fields
@@ -211,6 +215,8 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		inner = new(BlobTx)
 	case SetCodeTxType:
 		inner = new(SetCodeTx)
+	case TimedTxType:
+		inner = new(TimedTx)
 	default:
 		return nil, ErrTxTypeNotSupported
 	}
@@ -509,6 +515,40 @@ func (tx *Transaction) SetCodeAuthorities() []common.Address {
 	return auths
 }
 
+// ValidAfter returns the earliest block timestamp the transaction may be
+// included at, or zero if the transaction carries no lower bound.
+func (tx *Transaction) ValidAfter() uint64 {
+	if ttx, ok := tx.inner.(*TimedTx); ok {
+		return ttx.ValidAfter
+	}
+	return 0
+}
+
+// ValidUntil returns the latest block timestamp the transaction may be
+// included at, or zero if the transaction never expires.
+func (tx *Transaction) ValidUntil() uint64 {
+	if ttx, ok := tx.inner.(*TimedTx); ok {
+		return ttx.ValidUntil
+	}
+	return 0
+}
+
+// ValidAt checks whether the transaction is includable in a block with the
+// given timestamp, returning ErrTxNotYetValid or ErrTxExpired if not.
+func (tx *Transaction) ValidAt(time uint64) error {
+	ttx, ok := tx.inner.(*TimedTx)
+	if !ok {
+		return nil
+	}
+	if ttx.ValidAfter != 0 && time < ttx.ValidAfter {
+		return ErrTxNotYetValid
+	}
+	if ttx.ValidUntil != 0 && time > ttx.ValidUntil {
+		return ErrTxExpired
+	}
+	return nil
+}
+
 // SetTime sets the decoding time of a transaction. This is used by tests to set
 // arbitrary times and by persistent transaction pools when loading old txs from
 // disk.
@@ -0,0 +1,114 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tests that timed transactions survive RLP and JSON round trips and that the
+// validity window is covered by the signature.
+func TestTimedTxCoding(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	var (
+		signer    = NewTimedTxSigner(common.Big1)
+		recipient = common.HexToAddress("095e7baea6a6c7c4c2dfeb977efac326af552d87")
+		from      = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	tx, err := SignNewTx(key, signer, &TimedTx{
+		ChainID:    big.NewInt(1),
+		Nonce:      1,
+		To:         &recipient,
+		Gas:        21000,
+		GasTipCap:  big.NewInt(1),
+		GasFeeCap:  big.NewInt(10),
+		Data:       []byte("abcdef"),
+		ValidAfter: 100,
+		ValidUntil: 200,
+	})
+	if err != nil {
+		t.Fatalf("could not sign transaction: %v", err)
+	}
+	// Signers predating the timed transaction fork should reject the type
+	if _, err := Sender(NewPragueSigner(common.Big1), tx); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatalf("prague signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+	parsed, err := encodeDecodeBinary(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := assertEqual(parsed, tx); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err = encodeDecodeJSON(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := assertEqual(parsed, tx); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ValidAfter() != 100 || parsed.ValidUntil() != 200 {
+		t.Fatalf("window mismatch: have [%d, %d], want [100, 200]", parsed.ValidAfter(), parsed.ValidUntil())
+	}
+	if sender, err := Sender(signer, parsed); err != nil || sender != from {
+		t.Fatalf("sender mismatch: have %x (%v), want %x", sender, err, from)
+	}
+	// Tampering with the window must invalidate the signature
+	inner := tx.inner.copy().(*TimedTx)
+	inner.ValidUntil = 300
+	if sender, err := Sender(signer, NewTx(inner)); err == nil && sender == from {
+		t.Fatalf("window not covered by signature")
+	}
+	// Timed transactions must be rejected by pre-Prague signers
+	if _, err := Sender(NewCancunSigner(common.Big1), parsed); !errors.Is(err, ErrTxTypeNotSupported) {
+		t.Fatalf("cancun signer error mismatch: have %v, want %v", err, ErrTxTypeNotSupported)
+	}
+}
+
+func TestTimedTxValidAt(t *testing.T) {
+	tests := []struct {
+		after, until uint64
+		time         uint64
+		want         error
+	}{
+		{0, 0, 0, nil},
+		{0, 0, 1000, nil},
+		{100, 0, 99, ErrTxNotYetValid},
+		{100, 0, 100, nil},
+		{100, 0, 1000, nil},
+		{0, 200, 200, nil},
+		{0, 200, 201, ErrTxExpired},
+		{100, 200, 150, nil},
+		{100, 200, 50, ErrTxNotYetValid},
+		{100, 200, 250, ErrTxExpired},
+	}
+	for i, tt := range tests {
+		tx := NewTx(&TimedTx{ValidAfter: tt.after, ValidUntil: tt.until})
+		if err := tx.ValidAt(tt.time); err != tt.want {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.want)
+		}
+	}
+	// Untimed transactions are valid at any time
+	if err := NewTx(&DynamicFeeTx{}).ValidAt(0); err != nil {
+		t.Fatalf("untimed transaction rejected: %v", err)
+	}
+}
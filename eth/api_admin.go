@@ -26,6 +26,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -141,3 +142,17 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// Backup creates a consistent copy of the chain database in the given directory,
+// without stopping the node. If the directory contains a previous backup, only
+// the ancient data appended since is copied.
+func (api *AdminAPI) Backup(dir string) (bool, error) {
+	db, ok := api.eth.ChainDb().(ethdb.Backupper)
+	if !ok {
+		return false, errors.New("database backup is not supported")
+	}
+	if err := db.Backup(dir); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+)
+
+// TxPoolAdminAPI provides an API to control the transaction pool's policies. It
+// is served in the admin namespace, as it changes the node's admission rules and
+// the txpool namespace is commonly exposed to untrusted users. The policy is thus
+// managed with admin_setTxPoolPolicy and admin_txPoolPolicy, not with the
+// txpool_setPolicy method of the txpool namespace.
+type TxPoolAdminAPI struct {
+	e *Ethereum
+}
+
+// NewTxPoolAdminAPI creates a new TxPoolAdminAPI instance.
+func NewTxPoolAdminAPI(e *Ethereum) *TxPoolAdminAPI {
+	return &TxPoolAdminAPI{e}
+}
+
+// SetTxPoolPolicy replaces the per-account slot policy of the transaction pool.
+func (api *TxPoolAdminAPI) SetTxPoolPolicy(policy legacypool.AccountPolicy) (bool, error) {
+	if err := api.e.legacyPool.SetPolicy(policy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// TxPoolPolicy returns the per-account slot policy of the transaction pool.
+func (api *TxPoolAdminAPI) TxPoolPolicy() legacypool.AccountPolicy {
+	return api.e.legacyPool.Policy()
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -71,6 +72,7 @@ type Ethereum struct {
 	// core protocol objects
 	config         *ethconfig.Config
 	txPool         *txpool.TxPool
+	legacyPool     *legacypool.LegacyPool
 	localTxTracker *locals.TxTracker
 	blockchain     *core.BlockChain
 
@@ -113,6 +115,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.HistoryMode.IsValid() {
 		return nil, fmt.Errorf("invalid history mode %d", config.HistoryMode)
 	}
+	if config.HistoryMode == history.KeepRecent && config.HistoryRetention < params.FullImmutabilityThreshold {
+		// Only the frozen chain history can be pruned, which is always older
+		// than the immutability threshold.
+		log.Warn("Sanitizing chain history retention", "provided", config.HistoryRetention, "updated", params.FullImmutabilityThreshold)
+		config.HistoryRetention = params.FullImmutabilityThreshold
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Sign() <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -195,16 +203,21 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			EnablePreimageRecording: config.EnablePreimageRecording,
 		}
 		cacheConfig = &core.CacheConfig{
-			TrieCleanLimit:      config.TrieCleanCache,
-			TrieCleanNoPrefetch: config.NoPrefetch,
-			TrieDirtyLimit:      config.TrieDirtyCache,
-			TrieDirtyDisabled:   config.NoPruning,
-			TrieTimeLimit:       config.TrieTimeout,
-			SnapshotLimit:       config.SnapshotCache,
-			Preimages:           config.Preimages,
-			StateHistory:        config.StateHistory,
-			StateScheme:         scheme,
-			ChainHistoryMode:    config.HistoryMode,
+			TrieCleanLimit:              config.TrieCleanCache,
+			TrieCleanNoPrefetch:         config.NoPrefetch,
+			TrieDirtyLimit:              config.TrieDirtyCache,
+			TrieDirtyDisabled:           config.NoPruning,
+			TrieTimeLimit:               config.TrieTimeout,
+			SnapshotLimit:               config.SnapshotCache,
+			Preimages:                   config.Preimages,
+			StateHistory:                config.StateHistory,
+			StateHistoryIndex:           config.StateHistoryIndex,
+			StateScheme:                 scheme,
+			StateOnlinePruning:          config.StateOnlinePruning,
+			StateOnlinePruningBloomSize: config.StateOnlinePruningBloomSize,
+			StateOnlinePruningInterval:  config.StateOnlinePruningInterval,
+			ChainHistoryMode:            config.HistoryMode,
+			ChainHistoryRetention:       config.HistoryRetention,
 		}
 	)
 	if config.VMTrace != "" {
@@ -251,17 +264,25 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
-	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	eth.legacyPool = legacypool.New(config.TxPool, eth.blockchain)
 
 	if config.BlobPool.Datadir != "" {
 		config.BlobPool.Datadir = stack.ResolvePath(config.BlobPool.Datadir)
 	}
-	blobPool := blobpool.New(config.BlobPool, eth.blockchain, legacyPool.HasPendingAuth)
+	blobPool := blobpool.New(config.BlobPool, eth.blockchain, eth.legacyPool.HasPendingAuth)
 
-	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{eth.legacyPool, blobPool})
 	if err != nil {
 		return nil, err
 	}
+	if config.TxPoolAdmission.Enabled() {
+		admission, err := txpool.NewAdmission(config.TxPoolAdmission)
+		if err != nil {
+			return nil, err
+		}
+		eth.txPool.SetAdmission(admission)
+		log.Info("Enabled transaction admission policy", "plugin", config.TxPoolAdmission.Plugin, "url", config.TxPoolAdmission.URL, "timeout", config.TxPoolAdmission.Timeout, "failopen", config.TxPoolAdmission.FailOpen)
+	}
 
 	if !config.TxPool.NoLocals {
 		rejournal := config.TxPool.Rejournal
@@ -288,6 +309,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}); err != nil {
 		return nil, err
 	}
+	// Hold back the online state pruning while syncing
+	eth.blockchain.SetSyncing(func() bool {
+		return eth.handler.snapSync.Load() || eth.handler.downloader.Synchronising()
+	})
 
 	eth.dropper = newDropper(eth.p2pServer.MaxDialedConns(), eth.p2pServer.MaxInboundConns())
 
@@ -300,6 +325,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		log.Info("Unprotected transactions allowed")
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, config.GPO, config.Miner.GasPrice)
+	if config.RPCResponseCache > 0 {
+		stack.SetRPCResponseCache(ethapi.NewResponseCache(eth.APIBackend, uint64(config.RPCResponseCache)*1024*1024))
+	}
 
 	// Start the RPC service
 	eth.netRPCService = ethapi.NewNetAPI(eth.p2pServer, networkID)
@@ -345,6 +373,9 @@ func (s *Ethereum) APIs() []rpc.API {
 		{
 			Namespace: "miner",
 			Service:   NewMinerAPI(s),
+		}, {
+			Namespace: "admin",
+			Service:   NewTxPoolAdminAPI(s),
 		}, {
 			Namespace: "eth",
 			Service:   downloader.NewDownloaderAPI(s.handler.downloader, s.blockchain, s.eventMux),
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -49,26 +50,30 @@ var FullNodeGPO = gasprice.Config{
 
 // Defaults contains default settings for use on the Ethereum main net.
 var Defaults = Config{
-	HistoryMode:        history.KeepAll,
-	SyncMode:           SnapSync,
-	NetworkId:          0, // enable auto configuration of networkID == chainID
-	TxLookupLimit:      2350000,
-	TransactionHistory: 2350000,
-	LogHistory:         2350000,
-	StateHistory:       params.FullImmutabilityThreshold,
-	DatabaseCache:      512,
-	TrieCleanCache:     154,
-	TrieDirtyCache:     256,
-	TrieTimeout:        60 * time.Minute,
-	SnapshotCache:      102,
-	FilterLogCacheSize: 32,
-	Miner:              miner.DefaultConfig,
-	TxPool:             legacypool.DefaultConfig,
-	BlobPool:           blobpool.DefaultConfig,
-	RPCGasCap:          50000000,
-	RPCEVMTimeout:      5 * time.Second,
-	GPO:                FullNodeGPO,
-	RPCTxFeeCap:        1, // 1 ether
+	HistoryMode:                 history.KeepAll,
+	HistoryRetention:            params.FullImmutabilityThreshold,
+	SyncMode:                    SnapSync,
+	NetworkId:                   0, // enable auto configuration of networkID == chainID
+	TxLookupLimit:               2350000,
+	TransactionHistory:          2350000,
+	LogHistory:                  2350000,
+	StateHistory:                params.FullImmutabilityThreshold,
+	StateOnlinePruningBloomSize: 2048,
+	StateOnlinePruningInterval:  24 * time.Hour,
+	DatabaseCache:               512,
+	TrieCleanCache:              154,
+	TrieDirtyCache:              256,
+	TrieTimeout:                 60 * time.Minute,
+	SnapshotCache:               102,
+	FilterLogCacheSize:          32,
+	Miner:                       miner.DefaultConfig,
+	TxPool:                      legacypool.DefaultConfig,
+	BlobPool:                    blobpool.DefaultConfig,
+	TxPoolAdmission:             txpool.DefaultAdmissionConfig,
+	RPCGasCap:                   50000000,
+	RPCEVMTimeout:               5 * time.Second,
+	GPO:                         FullNodeGPO,
+	RPCTxFeeCap:                 1, // 1 ether
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -87,6 +92,10 @@ type Config struct {
 	// HistoryMode configures chain history retention.
 	HistoryMode history.HistoryMode
 
+	// HistoryRetention is the number of recent blocks whose bodies and receipts
+	// are retained in the "recent" history mode.
+	HistoryRetention uint64 `toml:",omitempty"`
+
 	// This can be set to list of enrtree:// URLs which will be queried for
 	// nodes to connect to.
 	EthDiscoveryURLs  []string
@@ -104,12 +113,22 @@ type Config struct {
 	LogNoHistory         bool   `toml:",omitempty"` // No log search index is maintained.
 	LogExportCheckpoints string // export log index checkpoints to file
 	StateHistory         uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	StateHistoryIndex    bool   `toml:",omitempty"` // Whether to index the state histories for serving historical state (path scheme only).
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
 	// consistent with persistent state.
 	StateScheme string `toml:",omitempty"`
 
+	// Whether to prune the stale state in background while the node is running
+	// (hash scheme only).
+	StateOnlinePruning bool `toml:",omitempty"`
+
+	// Megabytes of memory allocated to the bloom filter of the online state
+	// pruning, and the time between two pruning runs.
+	StateOnlinePruningBloomSize uint64        `toml:",omitempty"`
+	StateOnlinePruningInterval  time.Duration `toml:",omitempty"`
+
 	// RequiredBlocks is a set of block number -> hash mappings which must be in the
 	// canonical chain of all remote peers. Setting the option makes geth verify the
 	// presence of these blocks for every new peer connection.
@@ -137,6 +156,9 @@ type Config struct {
 	TxPool   legacypool.Config
 	BlobPool blobpool.Config
 
+	// Transaction pool admission policy options
+	TxPoolAdmission txpool.AdmissionConfig
+
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
@@ -157,6 +179,11 @@ type Config struct {
 	// send-transaction variants. The unit is ether.
 	RPCTxFeeCap float64
 
+	// RPCResponseCache is the memory allowance (MB) for caching the responses of
+	// immutable historical queries, such as calls at finalized blocks. Zero
+	// disables the cache.
+	RPCResponseCache int `toml:",omitempty"`
+
 	// OverridePrague (TODO: remove after the fork)
 	OverridePrague *uint64 `toml:",omitempty"`
 
@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
@@ -17,50 +18,58 @@ import (
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               uint64
-		SyncMode                SyncMode
-		HistoryMode             history.HistoryMode
-		EthDiscoveryURLs        []string
-		SnapDiscoveryURLs       []string
-		NoPruning               bool
-		NoPrefetch              bool
-		TxLookupLimit           uint64 `toml:",omitempty"`
-		TransactionHistory      uint64 `toml:",omitempty"`
-		LogHistory              uint64 `toml:",omitempty"`
-		LogNoHistory            bool   `toml:",omitempty"`
-		LogExportCheckpoints    string
-		StateHistory            uint64                 `toml:",omitempty"`
-		StateScheme             string                 `toml:",omitempty"`
-		RequiredBlocks          map[uint64]common.Hash `toml:"-"`
-		SkipBcVersionCheck      bool                   `toml:"-"`
-		DatabaseHandles         int                    `toml:"-"`
-		DatabaseCache           int
-		DatabaseFreezer         string
-		TrieCleanCache          int
-		TrieDirtyCache          int
-		TrieTimeout             time.Duration
-		SnapshotCache           int
-		Preimages               bool
-		FilterLogCacheSize      int
-		Miner                   miner.Config
-		TxPool                  legacypool.Config
-		BlobPool                blobpool.Config
-		GPO                     gasprice.Config
-		EnablePreimageRecording bool
-		VMTrace                 string
-		VMTraceJsonConfig       string
-		RPCGasCap               uint64
-		RPCEVMTimeout           time.Duration
-		RPCTxFeeCap             float64
-		OverridePrague          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		Genesis                     *core.Genesis `toml:",omitempty"`
+		NetworkId                   uint64
+		SyncMode                    SyncMode
+		HistoryMode                 history.HistoryMode
+		HistoryRetention            uint64 `toml:",omitempty"`
+		EthDiscoveryURLs            []string
+		SnapDiscoveryURLs           []string
+		NoPruning                   bool
+		NoPrefetch                  bool
+		TxLookupLimit               uint64 `toml:",omitempty"`
+		TransactionHistory          uint64 `toml:",omitempty"`
+		LogHistory                  uint64 `toml:",omitempty"`
+		LogNoHistory                bool   `toml:",omitempty"`
+		LogExportCheckpoints        string
+		StateHistory                uint64                 `toml:",omitempty"`
+		StateHistoryIndex           bool                   `toml:",omitempty"`
+		StateScheme                 string                 `toml:",omitempty"`
+		StateOnlinePruning          bool                   `toml:",omitempty"`
+		StateOnlinePruningBloomSize uint64                 `toml:",omitempty"`
+		StateOnlinePruningInterval  time.Duration          `toml:",omitempty"`
+		RequiredBlocks              map[uint64]common.Hash `toml:"-"`
+		SkipBcVersionCheck          bool                   `toml:"-"`
+		DatabaseHandles             int                    `toml:"-"`
+		DatabaseCache               int
+		DatabaseFreezer             string
+		TrieCleanCache              int
+		TrieDirtyCache              int
+		TrieTimeout                 time.Duration
+		SnapshotCache               int
+		Preimages                   bool
+		FilterLogCacheSize          int
+		Miner                       miner.Config
+		TxPool                      legacypool.Config
+		BlobPool                    blobpool.Config
+		TxPoolAdmission             txpool.AdmissionConfig
+		GPO                         gasprice.Config
+		EnablePreimageRecording     bool
+		VMTrace                     string
+		VMTraceJsonConfig           string
+		RPCGasCap                   uint64
+		RPCEVMTimeout               time.Duration
+		RPCTxFeeCap                 float64
+		RPCResponseCache            int     `toml:",omitempty"`
+		OverridePrague              *uint64 `toml:",omitempty"`
+		OverrideVerkle              *uint64 `toml:",omitempty"`
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
 	enc.NetworkId = c.NetworkId
 	enc.SyncMode = c.SyncMode
 	enc.HistoryMode = c.HistoryMode
+	enc.HistoryRetention = c.HistoryRetention
 	enc.EthDiscoveryURLs = c.EthDiscoveryURLs
 	enc.SnapDiscoveryURLs = c.SnapDiscoveryURLs
 	enc.NoPruning = c.NoPruning
@@ -71,7 +80,11 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.LogNoHistory = c.LogNoHistory
 	enc.LogExportCheckpoints = c.LogExportCheckpoints
 	enc.StateHistory = c.StateHistory
+	enc.StateHistoryIndex = c.StateHistoryIndex
 	enc.StateScheme = c.StateScheme
+	enc.StateOnlinePruning = c.StateOnlinePruning
+	enc.StateOnlinePruningBloomSize = c.StateOnlinePruningBloomSize
+	enc.StateOnlinePruningInterval = c.StateOnlinePruningInterval
 	enc.RequiredBlocks = c.RequiredBlocks
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
 	enc.DatabaseHandles = c.DatabaseHandles
@@ -86,6 +99,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Miner = c.Miner
 	enc.TxPool = c.TxPool
 	enc.BlobPool = c.BlobPool
+	enc.TxPoolAdmission = c.TxPoolAdmission
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.VMTrace = c.VMTrace
@@ -93,6 +107,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.RPCGasCap = c.RPCGasCap
 	enc.RPCEVMTimeout = c.RPCEVMTimeout
 	enc.RPCTxFeeCap = c.RPCTxFeeCap
+	enc.RPCResponseCache = c.RPCResponseCache
 	enc.OverridePrague = c.OverridePrague
 	enc.OverrideVerkle = c.OverrideVerkle
 	return &enc, nil
@@ -101,44 +116,51 @@ func (c Config) MarshalTOML() (interface{}, error) {
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                 *core.Genesis `toml:",omitempty"`
-		NetworkId               *uint64
-		SyncMode                *SyncMode
-		HistoryMode             *history.HistoryMode
-		EthDiscoveryURLs        []string
-		SnapDiscoveryURLs       []string
-		NoPruning               *bool
-		NoPrefetch              *bool
-		TxLookupLimit           *uint64 `toml:",omitempty"`
-		TransactionHistory      *uint64 `toml:",omitempty"`
-		LogHistory              *uint64 `toml:",omitempty"`
-		LogNoHistory            *bool   `toml:",omitempty"`
-		LogExportCheckpoints    *string
-		StateHistory            *uint64                `toml:",omitempty"`
-		StateScheme             *string                `toml:",omitempty"`
-		RequiredBlocks          map[uint64]common.Hash `toml:"-"`
-		SkipBcVersionCheck      *bool                  `toml:"-"`
-		DatabaseHandles         *int                   `toml:"-"`
-		DatabaseCache           *int
-		DatabaseFreezer         *string
-		TrieCleanCache          *int
-		TrieDirtyCache          *int
-		TrieTimeout             *time.Duration
-		SnapshotCache           *int
-		Preimages               *bool
-		FilterLogCacheSize      *int
-		Miner                   *miner.Config
-		TxPool                  *legacypool.Config
-		BlobPool                *blobpool.Config
-		GPO                     *gasprice.Config
-		EnablePreimageRecording *bool
-		VMTrace                 *string
-		VMTraceJsonConfig       *string
-		RPCGasCap               *uint64
-		RPCEVMTimeout           *time.Duration
-		RPCTxFeeCap             *float64
-		OverridePrague          *uint64 `toml:",omitempty"`
-		OverrideVerkle          *uint64 `toml:",omitempty"`
+		Genesis                     *core.Genesis `toml:",omitempty"`
+		NetworkId                   *uint64
+		SyncMode                    *SyncMode
+		HistoryMode                 *history.HistoryMode
+		HistoryRetention            *uint64 `toml:",omitempty"`
+		EthDiscoveryURLs            []string
+		SnapDiscoveryURLs           []string
+		NoPruning                   *bool
+		NoPrefetch                  *bool
+		TxLookupLimit               *uint64 `toml:",omitempty"`
+		TransactionHistory          *uint64 `toml:",omitempty"`
+		LogHistory                  *uint64 `toml:",omitempty"`
+		LogNoHistory                *bool   `toml:",omitempty"`
+		LogExportCheckpoints        *string
+		StateHistory                *uint64                `toml:",omitempty"`
+		StateHistoryIndex           *bool                  `toml:",omitempty"`
+		StateScheme                 *string                `toml:",omitempty"`
+		StateOnlinePruning          *bool                  `toml:",omitempty"`
+		StateOnlinePruningBloomSize *uint64                `toml:",omitempty"`
+		StateOnlinePruningInterval  *time.Duration         `toml:",omitempty"`
+		RequiredBlocks              map[uint64]common.Hash `toml:"-"`
+		SkipBcVersionCheck          *bool                  `toml:"-"`
+		DatabaseHandles             *int                   `toml:"-"`
+		DatabaseCache               *int
+		DatabaseFreezer             *string
+		TrieCleanCache              *int
+		TrieDirtyCache              *int
+		TrieTimeout                 *time.Duration
+		SnapshotCache               *int
+		Preimages                   *bool
+		FilterLogCacheSize          *int
+		Miner                       *miner.Config
+		TxPool                      *legacypool.Config
+		BlobPool                    *blobpool.Config
+		TxPoolAdmission             *txpool.AdmissionConfig
+		GPO                         *gasprice.Config
+		EnablePreimageRecording     *bool
+		VMTrace                     *string
+		VMTraceJsonConfig           *string
+		RPCGasCap                   *uint64
+		RPCEVMTimeout               *time.Duration
+		RPCTxFeeCap                 *float64
+		RPCResponseCache            *int    `toml:",omitempty"`
+		OverridePrague              *uint64 `toml:",omitempty"`
+		OverrideVerkle              *uint64 `toml:",omitempty"`
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -156,6 +178,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.HistoryMode != nil {
 		c.HistoryMode = *dec.HistoryMode
 	}
+	if dec.HistoryRetention != nil {
+		c.HistoryRetention = *dec.HistoryRetention
+	}
 	if dec.EthDiscoveryURLs != nil {
 		c.EthDiscoveryURLs = dec.EthDiscoveryURLs
 	}
@@ -186,9 +211,21 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.StateHistory != nil {
 		c.StateHistory = *dec.StateHistory
 	}
+	if dec.StateHistoryIndex != nil {
+		c.StateHistoryIndex = *dec.StateHistoryIndex
+	}
 	if dec.StateScheme != nil {
 		c.StateScheme = *dec.StateScheme
 	}
+	if dec.StateOnlinePruning != nil {
+		c.StateOnlinePruning = *dec.StateOnlinePruning
+	}
+	if dec.StateOnlinePruningBloomSize != nil {
+		c.StateOnlinePruningBloomSize = *dec.StateOnlinePruningBloomSize
+	}
+	if dec.StateOnlinePruningInterval != nil {
+		c.StateOnlinePruningInterval = *dec.StateOnlinePruningInterval
+	}
 	if dec.RequiredBlocks != nil {
 		c.RequiredBlocks = dec.RequiredBlocks
 	}
@@ -231,6 +268,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobPool != nil {
 		c.BlobPool = *dec.BlobPool
 	}
+	if dec.TxPoolAdmission != nil {
+		c.TxPoolAdmission = *dec.TxPoolAdmission
+	}
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
@@ -252,6 +292,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RPCTxFeeCap != nil {
 		c.RPCTxFeeCap = *dec.RPCTxFeeCap
 	}
+	if dec.RPCResponseCache != nil {
+		c.RPCResponseCache = *dec.RPCResponseCache
+	}
 	if dec.OverridePrague != nil {
 		c.OverridePrague = dec.OverridePrague
 	}
@@ -40,8 +40,17 @@ var (
 	errInvalidBlockRange      = errors.New("invalid block range params")
 	errPendingLogsUnsupported = errors.New("pending logs are not supported")
 	errExceedMaxTopics        = errors.New("exceed max topics")
+	errResumeTooOld           = errors.New("subscription resumed too far behind the chain head")
+	errBackfillTooLong        = errors.New("log subscription starts too far behind the chain head")
 )
 
+// The maximum number of blocks a resumed subscription may be behind the chain head
+const maxResumeBlocks = 1024
+
+// The maximum number of blocks a log subscription may backfill from its fromBlock.
+// This is a variable, so tests can lower it.
+var maxBackfillBlocks uint64 = 10000
+
 // The maximum number of topic criteria allowed, vm.LOG4 - vm.LOG0
 const maxTopics = 4
 
@@ -235,17 +244,31 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	next, resumed, err := api.resumeStart(ctx)
+	if err != nil {
+		return nil, err
+	}
 	rpcSub := notifier.CreateSubscription()
+	if resumed {
+		next = api.resumeHeaders(notifier, rpcSub.ID, next, nil)
+	}
 
 	go func() {
 		headers := make(chan *types.Header)
 		headersSub := api.events.SubscribeNewHeads(headers)
 		defer headersSub.Unsubscribe()
 
+		// Headers imported while subscribing may be delivered twice, skip them.
+		sent := make(map[common.Hash]bool)
+		if resumed {
+			api.resumeHeaders(notifier, rpcSub.ID, next, sent)
+		}
 		for {
 			select {
 			case h := <-headers:
-				notifier.Notify(rpcSub.ID, h)
+				if !sent[h.Hash()] {
+					notifier.Notify(rpcSub.ID, h)
+				}
 			case <-rpcSub.Err():
 				return
 			}
@@ -256,12 +279,20 @@ func (api *FilterAPI) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
 }
 
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
+//
+// If the criteria specify a fromBlock, the logs of the canonical chain starting at
+// that block are delivered first. Logs of blocks reorged while switching over to
+// new logs are delivered again with the removed flag set.
 func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
 
+	next, backfill, err := api.logsStart(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		rpcSub      = notifier.CreateSubscription()
 		matchedLogs = make(chan []*types.Log)
@@ -271,6 +302,11 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 	if err != nil {
 		return nil, err
 	}
+	if backfill {
+		b := newLogBackfill(api.sys, notifier, rpcSub, crit, next)
+		go b.run(logsSub, matchedLogs)
+		return rpcSub, nil
+	}
 
 	go func() {
 		defer logsSub.Unsubscribe()
@@ -289,6 +325,71 @@ func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subsc
 	return rpcSub, nil
 }
 
+// logsStart returns the first block of a log subscription to backfill, which is
+// either the block after the resumed one or the fromBlock of the criteria.
+func (api *FilterAPI) logsStart(ctx context.Context, crit FilterCriteria) (uint64, bool, error) {
+	if next, resumed, err := api.resumeStart(ctx); err != nil {
+		return 0, false, err
+	} else if resumed {
+		// Refuse to resume over pruned history, the subscriber would miss logs
+		if next < api.sys.backend.HistoryPruningCutoff() {
+			return 0, false, &history.PrunedHistoryError{}
+		}
+		return next, true, nil
+	}
+	if crit.FromBlock == nil || crit.BlockHash != nil {
+		return 0, false, nil
+	}
+	var next uint64
+	switch from := rpc.BlockNumber(crit.FromBlock.Int64()); {
+	case from == rpc.EarliestBlockNumber:
+		next = api.sys.backend.HistoryPruningCutoff()
+	case from > 0:
+		// Block zero is not backfilled, older clients send it by default.
+		next = uint64(from)
+	default:
+		return 0, false, nil // latest and other tags only deliver new logs
+	}
+	if next < api.sys.backend.HistoryPruningCutoff() {
+		return 0, false, &history.PrunedHistoryError{}
+	}
+	if head := api.sys.backend.CurrentHeader().Number.Uint64(); head >= next+maxBackfillBlocks {
+		return 0, false, fmt.Errorf("%w: %d blocks, limit %d", errBackfillTooLong, head-next+1, maxBackfillBlocks)
+	}
+	return next, true, nil
+}
+
+// resumeStart returns the first block to deliver if the subscription being created
+// resumes an earlier one, see rpc.ResumeBlockFromContext.
+func (api *FilterAPI) resumeStart(ctx context.Context) (uint64, bool, error) {
+	last, ok := rpc.ResumeBlockFromContext(ctx)
+	if !ok {
+		return 0, false, nil
+	}
+	if head := api.sys.backend.CurrentHeader().Number.Uint64(); head > last+maxResumeBlocks {
+		return 0, false, errResumeTooOld
+	}
+	return last + 1, true, nil
+}
+
+// resumeHeaders delivers the canonical headers from the given block up to the
+// chain head, and returns the number of the next block. The hashes of delivered
+// headers are added to sent if it is non-nil.
+func (api *FilterAPI) resumeHeaders(notifier *rpc.Notifier, id rpc.ID, next uint64, sent map[common.Hash]bool) uint64 {
+	head := api.sys.backend.CurrentHeader().Number.Uint64()
+	for ; next <= head; next++ {
+		header, err := api.sys.backend.HeaderByNumber(context.Background(), rpc.BlockNumber(next))
+		if err != nil || header == nil {
+			break
+		}
+		if sent != nil {
+			sent[header.Hash()] = true
+		}
+		notifier.Notify(id, header)
+	}
+	return next
+}
+
 // FilterCriteria represents a request to create a new filter.
 // Same as ethereum.FilterQuery but with UnmarshalJSON() method.
 type FilterCriteria ethereum.FilterQuery
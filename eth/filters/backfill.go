@@ -0,0 +1,146 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package filters
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// backfillReorgDepth is the number of blocks behind the chain head for which the
+// backfilled blocks are tracked, to reconcile them with the live logs.
+const backfillReorgDepth = 128
+
+// maxBackfillQueue is the maximum number of live log batches queued while a
+// subscription is backfilling. The subscription is dropped if it falls further
+// behind.
+const maxBackfillQueue = 1024
+
+// logBackfill delivers the historical logs of a subscription and then switches
+// over to the live logs. Live logs arriving during the backfill are queued, and
+// the ones of blocks already delivered are skipped.
+type logBackfill struct {
+	sys      *FilterSystem
+	notifier *rpc.Notifier
+	sub      *rpc.Subscription
+	crit     FilterCriteria
+
+	next      uint64               // first block not backfilled yet
+	delivered map[common.Hash]bool // recent blocks whose logs were delivered and not removed
+}
+
+func newLogBackfill(sys *FilterSystem, notifier *rpc.Notifier, sub *rpc.Subscription, crit FilterCriteria, next uint64) *logBackfill {
+	return &logBackfill{
+		sys:       sys,
+		notifier:  notifier,
+		sub:       sub,
+		crit:      crit,
+		next:      next,
+		delivered: make(map[common.Hash]bool),
+	}
+}
+
+// run backfills the logs, then delivers the live logs until the subscription ends.
+func (b *logBackfill) run(logsSub *Subscription, matchedLogs chan []*types.Log) {
+	defer logsSub.Unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- b.backfill(ctx) }()
+
+	var queued [][]*types.Log
+	for backfilling := true; backfilling; {
+		select {
+		case logs := <-matchedLogs:
+			if len(queued) >= maxBackfillQueue {
+				log.Warn("Dropping log subscription, backfill too slow", "id", b.sub.ID, "queued", len(queued))
+				return
+			}
+			queued = append(queued, logs)
+		case err := <-done:
+			if err != nil {
+				// Switching over to the live logs would leave a silent gap in
+				// the stream, drop the subscription instead.
+				log.Warn("Dropping log subscription, backfill failed", "id", b.sub.ID, "next", b.next, "err", err)
+				return
+			}
+			backfilling = false
+		case <-b.sub.Err():
+			return
+		}
+	}
+	for _, logs := range queued {
+		b.deliver(logs)
+	}
+	for {
+		select {
+		case logs := <-matchedLogs:
+			b.deliver(logs)
+		case <-b.sub.Err():
+			return
+		}
+	}
+}
+
+// backfill delivers the logs of the canonical chain up to the head. The head may
+// move while backfilling, so it is repeated until there are no new blocks.
+func (b *logBackfill) backfill(ctx context.Context) error {
+	for {
+		head := b.sys.backend.CurrentHeader().Number.Uint64()
+		if b.next > head {
+			return nil
+		}
+		filter := b.sys.NewRangeFilter(int64(b.next), int64(head), b.crit.Addresses, b.crit.Topics)
+		logs, err := filter.Logs(ctx)
+		if err != nil {
+			return err
+		}
+		for _, log := range logs {
+			if log.BlockNumber+backfillReorgDepth > head {
+				b.delivered[log.BlockHash] = true
+			}
+			b.notifier.Notify(b.sub.ID, log)
+		}
+		b.next = head + 1
+	}
+}
+
+// deliver sends live logs to the subscriber. Logs of blocks covered by the backfill
+// are only sent if they weren't delivered yet, or if they are removed by a reorg
+// and were delivered before.
+func (b *logBackfill) deliver(logs []*types.Log) {
+	var send []*types.Log
+	for _, log := range logs {
+		if log.BlockNumber < b.next && log.Removed != b.delivered[log.BlockHash] {
+			continue
+		}
+		send = append(send, log)
+	}
+	// Removals and re-additions come in batches per block, so only track them
+	// once the whole batch is handled.
+	for _, log := range send {
+		if log.BlockNumber < b.next {
+			b.delivered[log.BlockHash] = !log.Removed
+		}
+		b.notifier.Notify(b.sub.ID, log)
+	}
+}
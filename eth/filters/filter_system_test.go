@@ -17,11 +17,17 @@
 package filters
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -47,6 +54,7 @@ type testBackend struct {
 	chainFeed       event.Feed
 	pendingBlock    *types.Block
 	pendingReceipts types.Receipts
+	cutoff          uint64 // first block not pruned
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -187,7 +195,7 @@ func (b *testBackend) setPending(block *types.Block, receipts types.Receipts) {
 }
 
 func (b *testBackend) HistoryPruningCutoff() uint64 {
-	return 0
+	return b.cutoff
 }
 
 func newTestFilterSystem(db ethdb.Database, cfg Config) (*testBackend, *FilterSystem) {
@@ -255,6 +263,212 @@ func TestBlockSubscription(t *testing.T) {
 	<-sub1.Err()
 }
 
+// TestResumeBlockSubscription tests that a resumed head subscription first delivers
+// the headers after the resumed block.
+func TestResumeBlockSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db      = rawdb.NewMemoryDatabase()
+		_, sys  = newTestFilterSystem(db, Config{})
+		genesis = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		_, chain, _ = core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 10, func(i int, gen *core.BlockGen) {})
+	)
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	srv := rpc.NewServer()
+	srv.RegisterName("eth", NewFilterAPI(sys))
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv.SSEHandler())
+	defer httpsrv.Close()
+
+	query := url.Values{"method": {"eth_subscribe"}, "params": {`["newHeads"]`}}
+	req, _ := http.NewRequest(http.MethodGet, httpsrv.URL+"?"+query.Encode(), nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Last-Event-ID", "7")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var (
+		reader  = bufio.NewReader(resp.Body)
+		headers []common.Hash
+	)
+	for len(headers) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal("can't read stream:", err)
+		}
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data: ")
+		if !ok {
+			continue
+		}
+		var msg struct {
+			Params struct {
+				Result *types.Header `json:"result"`
+			} `json:"params"`
+		}
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatal("invalid event:", err)
+		}
+		if msg.Params.Result != nil {
+			headers = append(headers, msg.Params.Result.Hash())
+		}
+	}
+	for i, hash := range headers {
+		if want := chain[7+i].Hash(); hash != want {
+			t.Errorf("header %d: have %x, want %x", i, hash, want)
+		}
+	}
+}
+
+// TestLogSubscriptionBackfill tests that log subscriptions with a fromBlock first
+// deliver the logs of historical blocks and then switch over to new logs, skipping
+// the logs already delivered.
+func TestLogSubscriptionBackfill(t *testing.T) {
+	t.Parallel()
+
+	var (
+		addr    = common.BytesToAddress([]byte("jeff"))
+		genesis = &core.Genesis{
+			Config:  params.TestChainConfig,
+			BaseFee: big.NewInt(params.InitialBaseFee),
+		}
+		db, chain, receipts = core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 11, func(i int, gen *core.BlockGen) {
+			if i == 1 || i == 6 || i == 10 {
+				receipt := types.NewReceipt(nil, false, 0)
+				receipt.Logs = []*types.Log{{Address: addr, Topics: []common.Hash{}}}
+				gen.AddUncheckedReceipt(receipt)
+				gen.AddUncheckedTx(types.NewTransaction(999, common.HexToAddress("0x999"), big.NewInt(999), 999, gen.BaseFee(), nil))
+			}
+		})
+		backend, sys = newTestFilterSystem(db, Config{})
+	)
+	// Import all blocks but the last one, which is announced as new later.
+	for i, block := range chain[:10] {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+	backend.startFilterMaps(0, false, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	srv := rpc.NewServer()
+	srv.RegisterName("eth", NewFilterAPI(sys))
+	defer srv.Stop()
+	client := rpc.DialInProc(srv)
+	defer client.Close()
+
+	ch := make(chan types.Log)
+	sub, err := client.EthSubscribe(context.Background(), ch, "logs", map[string]interface{}{
+		"address":   []common.Address{addr},
+		"fromBlock": "0x3",
+	})
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	defer sub.Unsubscribe()
+
+	receive := func() types.Log {
+		t.Helper()
+		select {
+		case log := <-ch:
+			return log
+		case err := <-sub.Err():
+			t.Fatal("subscription error:", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for log")
+		}
+		return types.Log{}
+	}
+	backfilled := receive()
+	if backfilled.BlockHash != chain[6].Hash() || backfilled.Removed {
+		t.Fatalf("wrong backfilled log: %+v", backfilled)
+	}
+	// Logs of backfilled blocks are skipped, new logs and removals are delivered.
+	rawdb.WriteBlock(db, chain[10])
+	rawdb.WriteCanonicalHash(db, chain[10].Hash(), chain[10].NumberU64())
+	rawdb.WriteHeadBlockHash(db, chain[10].Hash())
+	backend.logsFeed.Send([]*types.Log{&backfilled})
+	backend.logsFeed.Send([]*types.Log{{Address: addr, Topics: []common.Hash{}, BlockNumber: 11, BlockHash: chain[10].Hash()}})
+	if log := receive(); log.BlockHash != chain[10].Hash() || log.Removed {
+		t.Fatalf("expected new log, got %+v", log)
+	}
+	removed := backfilled
+	removed.Removed = true
+	backend.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: []*types.Log{&removed}})
+	if log := receive(); log.BlockHash != chain[6].Hash() || !log.Removed {
+		t.Fatalf("expected removed log, got %+v", log)
+	}
+}
+
+// TestLogSubscriptionBackfillLimit tests that log subscriptions starting too far
+// behind the chain head or below the pruned history are rejected, instead of
+// backfilling an unbounded range or leaving a gap in the delivered logs.
+func TestLogSubscriptionBackfillLimit(t *testing.T) {
+	defer func(old uint64) { maxBackfillBlocks = old }(maxBackfillBlocks)
+	maxBackfillBlocks = 5
+
+	var (
+		genesis      = &core.Genesis{Config: params.TestChainConfig, BaseFee: big.NewInt(params.InitialBaseFee)}
+		db, chain, _ = core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 10, func(i int, gen *core.BlockGen) {})
+		backend, sys = newTestFilterSystem(db, Config{})
+	)
+	for _, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+	}
+	backend.startFilterMaps(0, false, filtermaps.DefaultParams)
+	defer backend.stopFilterMaps()
+
+	srv := rpc.NewServer()
+	srv.RegisterName("eth", NewFilterAPI(sys))
+	defer srv.Stop()
+	client := rpc.DialInProc(srv)
+	defer client.Close()
+
+	for _, tt := range []struct {
+		from string
+		ok   bool
+	}{{"earliest", false}, {"0x5", false}, {"0x6", true}, {"latest", true}} {
+		sub, err := client.EthSubscribe(context.Background(), make(chan types.Log), "logs", map[string]interface{}{"fromBlock": tt.from})
+		if tt.ok {
+			if err != nil {
+				t.Fatalf("fromBlock %s: subscription failed: %v", tt.from, err)
+			}
+			sub.Unsubscribe()
+		} else if err == nil || !strings.Contains(err.Error(), errBackfillTooLong.Error()) {
+			t.Fatalf("fromBlock %s: wrong error: %v", tt.from, err)
+		}
+	}
+	// Subscriptions must not backfill over pruned history either
+	backend.cutoff = 8
+	for _, tt := range []struct {
+		from string
+		ok   bool
+	}{{"earliest", true}, {"0x7", false}, {"0x8", true}} {
+		sub, err := client.EthSubscribe(context.Background(), make(chan types.Log), "logs", map[string]interface{}{"fromBlock": tt.from})
+		if tt.ok {
+			if err != nil {
+				t.Fatalf("fromBlock %s: subscription failed: %v", tt.from, err)
+			}
+			sub.Unsubscribe()
+		} else if err == nil || !strings.Contains(err.Error(), (&history.PrunedHistoryError{}).Error()) {
+			t.Fatalf("fromBlock %s: wrong error: %v", tt.from, err)
+		}
+	}
+}
+
 // TestPendingTxFilter tests whether pending tx filters retrieve all pending transactions that are posted to the event mux.
 func TestPendingTxFilter(t *testing.T) {
 	t.Parallel()
@@ -78,6 +78,10 @@ type txPool interface {
 	// Add should add the given transactions to the pool.
 	Add(txs []*types.Transaction, sync bool) []error
 
+	// AddWithOrigin should add the given transactions received from the given
+	// peer to the pool.
+	AddWithOrigin(txs []*types.Transaction, origin string, sync bool) []error
+
 	// Pending should return pending transactions.
 	// The slice should be modifiable by the caller.
 	Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction
@@ -196,8 +200,8 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		}
 		return p.RequestTxs(hashes)
 	}
-	addTxs := func(txs []*types.Transaction) []error {
-		return h.txpool.Add(txs, false)
+	addTxs := func(peer string, txs []*types.Transaction) []error {
+		return h.txpool.AddWithOrigin(txs, peer, false)
 	}
 	h.txFetcher = fetcher.NewTxFetcher(h.txpool.Has, addTxs, fetchTx, h.removePeer)
 	return h, nil
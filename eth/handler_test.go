@@ -122,6 +122,11 @@ func (p *testTxPool) Add(txs []*types.Transaction, sync bool) []error {
 	return make([]error, len(txs))
 }
 
+// AddWithOrigin appends a batch of transactions to the pool, ignoring the origin.
+func (p *testTxPool) AddWithOrigin(txs []*types.Transaction, origin string, sync bool) []error {
+	return p.Add(txs, sync)
+}
+
 // Pending returns all the transactions known to the pool
 func (p *testTxPool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
 	p.lock.RLock()
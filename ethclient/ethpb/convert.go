@@ -0,0 +1,414 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ethpb contains the protobuf definition of the gRPC/Connect flavour of
+// the eth API, along with conversions between its messages and the go-ethereum
+// types. The generated client and server bindings live in ethpbconnect.
+package ethpb
+
+//go:generate protoc -I. --go_out=paths=source_relative:. --connect-go_out=paths=source_relative:. eth.proto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// NewBig converts a big integer into its message encoding.
+func NewBig(n *big.Int) []byte {
+	if n == nil {
+		return nil
+	}
+	return n.Bytes()
+}
+
+// ToBig converts the message encoding of a big integer back, returning nil for
+// an empty encoding.
+func ToBig(b []byte) *big.Int {
+	if len(b) == 0 {
+		return nil
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+// ToAddress converts an address field of a message, rejecting malformed ones.
+func ToAddress(b []byte) (common.Address, error) {
+	if len(b) != common.AddressLength {
+		return common.Address{}, fmt.Errorf("invalid address length %d", len(b))
+	}
+	return common.BytesToAddress(b), nil
+}
+
+// ToHash converts a hash field of a message, rejecting malformed ones.
+func ToHash(b []byte) (common.Hash, error) {
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid hash length %d", len(b))
+	}
+	return common.BytesToHash(b), nil
+}
+
+// NewBlockNumberRef references a block by number, following the conventions of
+// ethclient: nil means the latest block and the negative rpc.BlockNumber values
+// select the corresponding tags.
+func NewBlockNumberRef(number *big.Int) *BlockRef {
+	if number == nil {
+		return nil
+	}
+	if number.Sign() >= 0 {
+		return &BlockRef{Ref: &BlockRef_Number{Number: number.Uint64()}}
+	}
+	var tag BlockTag
+	switch rpc.BlockNumber(number.Int64()) {
+	case rpc.PendingBlockNumber:
+		tag = BlockTag_BLOCK_TAG_PENDING
+	case rpc.SafeBlockNumber:
+		tag = BlockTag_BLOCK_TAG_SAFE
+	case rpc.FinalizedBlockNumber:
+		tag = BlockTag_BLOCK_TAG_FINALIZED
+	case rpc.EarliestBlockNumber:
+		tag = BlockTag_BLOCK_TAG_EARLIEST
+	default:
+		tag = BlockTag_BLOCK_TAG_LATEST
+	}
+	return &BlockRef{Ref: &BlockRef_Tag{Tag: tag}}
+}
+
+// NewBlockHashRef references a block by hash.
+func NewBlockHashRef(hash common.Hash) *BlockRef {
+	return &BlockRef{Ref: &BlockRef_Hash{Hash: hash.Bytes()}}
+}
+
+// ToBlockNumberOrHash converts the block reference into its JSON-RPC form.
+func (r *BlockRef) ToBlockNumberOrHash() (rpc.BlockNumberOrHash, error) {
+	switch ref := r.GetRef().(type) {
+	case nil:
+		return rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil
+	case *BlockRef_Number:
+		if ref.Number > math.MaxInt64 {
+			return rpc.BlockNumberOrHash{}, errors.New("block number out of range")
+		}
+		return rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(ref.Number)), nil
+	case *BlockRef_Hash:
+		hash, err := ToHash(ref.Hash)
+		if err != nil {
+			return rpc.BlockNumberOrHash{}, err
+		}
+		return rpc.BlockNumberOrHashWithHash(hash, false), nil
+	case *BlockRef_Tag:
+		switch ref.Tag {
+		case BlockTag_BLOCK_TAG_LATEST:
+			return rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil
+		case BlockTag_BLOCK_TAG_PENDING:
+			return rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber), nil
+		case BlockTag_BLOCK_TAG_SAFE:
+			return rpc.BlockNumberOrHashWithNumber(rpc.SafeBlockNumber), nil
+		case BlockTag_BLOCK_TAG_FINALIZED:
+			return rpc.BlockNumberOrHashWithNumber(rpc.FinalizedBlockNumber), nil
+		case BlockTag_BLOCK_TAG_EARLIEST:
+			return rpc.BlockNumberOrHashWithNumber(rpc.EarliestBlockNumber), nil
+		}
+		return rpc.BlockNumberOrHash{}, fmt.Errorf("unknown block tag %d", ref.Tag)
+	}
+	return rpc.BlockNumberOrHash{}, errors.New("unknown block reference")
+}
+
+// toBlockNumber converts the block reference into a block number, rejecting
+// references by hash.
+func (r *BlockRef) toBlockNumber() (*big.Int, error) {
+	ref, err := r.ToBlockNumberOrHash()
+	if err != nil {
+		return nil, err
+	}
+	number, ok := ref.Number()
+	if !ok {
+		return nil, errors.New("block hash not allowed in range")
+	}
+	return big.NewInt(number.Int64()), nil
+}
+
+// NewHeader converts a header into its message form.
+func NewHeader(header *types.Header) (*Header, error) {
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return &Header{Hash: header.Hash().Bytes(), Number: header.Number.Uint64(), Rlp: enc}, nil
+}
+
+// ToHeader decodes the header carried by the message.
+func (h *Header) ToHeader() (*types.Header, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(h.GetRlp(), header); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// NewBlock converts a block into its message form.
+func NewBlock(block *types.Block) (*Block, error) {
+	enc, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Block{Hash: block.Hash().Bytes(), Number: block.NumberU64(), Rlp: enc}, nil
+}
+
+// ToBlock decodes the block carried by the message.
+func (b *Block) ToBlock() (*types.Block, error) {
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(b.GetRlp(), block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// ToTransaction decodes the transaction carried by the message.
+func (t *Transaction) ToTransaction() (*types.Transaction, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(t.GetData()); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// NewLog converts a log into its message form.
+func NewLog(log *types.Log) *Log {
+	topics := make([][]byte, len(log.Topics))
+	for i, topic := range log.Topics {
+		topics[i] = topic.Bytes()
+	}
+	return &Log{
+		Address:     log.Address.Bytes(),
+		Topics:      topics,
+		Data:        log.Data,
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash.Bytes(),
+		TxHash:      log.TxHash.Bytes(),
+		TxIndex:     uint64(log.TxIndex),
+		Index:       uint64(log.Index),
+		Removed:     log.Removed,
+	}
+}
+
+// ToLog converts the message back into a log.
+func (l *Log) ToLog() *types.Log {
+	topics := make([]common.Hash, len(l.GetTopics()))
+	for i, topic := range l.GetTopics() {
+		topics[i] = common.BytesToHash(topic)
+	}
+	return &types.Log{
+		Address:     common.BytesToAddress(l.GetAddress()),
+		Topics:      topics,
+		Data:        l.GetData(),
+		BlockNumber: l.GetBlockNumber(),
+		BlockHash:   common.BytesToHash(l.GetBlockHash()),
+		TxHash:      common.BytesToHash(l.GetTxHash()),
+		TxIndex:     uint(l.GetTxIndex()),
+		Index:       uint(l.GetIndex()),
+		Removed:     l.GetRemoved(),
+	}
+}
+
+// NewReceipt converts a receipt with all its derived fields into its message form.
+func NewReceipt(receipt *types.Receipt) *Receipt {
+	logs := make([]*Log, len(receipt.Logs))
+	for i, log := range receipt.Logs {
+		logs[i] = NewLog(log)
+	}
+	msg := &Receipt{
+		Type:              uint32(receipt.Type),
+		Status:            receipt.Status,
+		CumulativeGasUsed: receipt.CumulativeGasUsed,
+		Logs:              logs,
+		TxHash:            receipt.TxHash.Bytes(),
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: NewBig(receipt.EffectiveGasPrice),
+		BlobGasUsed:       receipt.BlobGasUsed,
+		BlobGasPrice:      NewBig(receipt.BlobGasPrice),
+		BlockHash:         receipt.BlockHash.Bytes(),
+		BlockNumber:       receipt.BlockNumber.Uint64(),
+		TransactionIndex:  uint64(receipt.TransactionIndex),
+	}
+	if receipt.ContractAddress != (common.Address{}) {
+		msg.ContractAddress = receipt.ContractAddress.Bytes()
+	}
+	return msg
+}
+
+// ToReceipt converts the message back into a receipt.
+func (r *Receipt) ToReceipt() *types.Receipt {
+	logs := make([]*types.Log, len(r.GetLogs()))
+	for i, log := range r.GetLogs() {
+		logs[i] = log.ToLog()
+	}
+	receipt := &types.Receipt{
+		Type:              uint8(r.GetType()),
+		Status:            r.GetStatus(),
+		CumulativeGasUsed: r.GetCumulativeGasUsed(),
+		Logs:              logs,
+		TxHash:            common.BytesToHash(r.GetTxHash()),
+		ContractAddress:   common.BytesToAddress(r.GetContractAddress()),
+		GasUsed:           r.GetGasUsed(),
+		EffectiveGasPrice: ToBig(r.GetEffectiveGasPrice()),
+		BlobGasUsed:       r.GetBlobGasUsed(),
+		BlobGasPrice:      ToBig(r.GetBlobGasPrice()),
+		BlockHash:         common.BytesToHash(r.GetBlockHash()),
+		BlockNumber:       new(big.Int).SetUint64(r.GetBlockNumber()),
+		TransactionIndex:  uint(r.GetTransactionIndex()),
+	}
+	receipt.Bloom = types.CreateBloom(receipt)
+	return receipt
+}
+
+// NewFilterQuery converts a log filter query into its message form.
+func NewFilterQuery(q ethereum.FilterQuery) *FilterQuery {
+	msg := &FilterQuery{
+		FromBlock: NewBlockNumberRef(q.FromBlock),
+		ToBlock:   NewBlockNumberRef(q.ToBlock),
+	}
+	if q.BlockHash != nil {
+		msg.BlockHash = q.BlockHash.Bytes()
+	}
+	for _, addr := range q.Addresses {
+		msg.Addresses = append(msg.Addresses, addr.Bytes())
+	}
+	for _, position := range q.Topics {
+		filter := new(TopicFilter)
+		for _, topic := range position {
+			filter.Topics = append(filter.Topics, topic.Bytes())
+		}
+		msg.Topics = append(msg.Topics, filter)
+	}
+	return msg
+}
+
+// ToFilterQuery converts the message back into a log filter query.
+func (q *FilterQuery) ToFilterQuery() (ethereum.FilterQuery, error) {
+	var (
+		query ethereum.FilterQuery
+		err   error
+	)
+	if len(q.GetBlockHash()) > 0 {
+		hash, err := ToHash(q.GetBlockHash())
+		if err != nil {
+			return query, err
+		}
+		query.BlockHash = &hash
+	}
+	if q.GetFromBlock() != nil {
+		if query.FromBlock, err = q.GetFromBlock().toBlockNumber(); err != nil {
+			return query, err
+		}
+	}
+	if q.GetToBlock() != nil {
+		if query.ToBlock, err = q.GetToBlock().toBlockNumber(); err != nil {
+			return query, err
+		}
+	}
+	for _, addr := range q.GetAddresses() {
+		address, err := ToAddress(addr)
+		if err != nil {
+			return query, err
+		}
+		query.Addresses = append(query.Addresses, address)
+	}
+	for _, filter := range q.GetTopics() {
+		var position []common.Hash
+		for _, topic := range filter.GetTopics() {
+			hash, err := ToHash(topic)
+			if err != nil {
+				return query, err
+			}
+			position = append(position, hash)
+		}
+		query.Topics = append(query.Topics, position)
+	}
+	return query, nil
+}
+
+// NewCallRequest converts a call message into its message form.
+func NewCallRequest(msg ethereum.CallMsg, block *BlockRef) *CallRequest {
+	req := &CallRequest{
+		From:                 msg.From.Bytes(),
+		GasPrice:             NewBig(msg.GasPrice),
+		MaxFeePerGas:         NewBig(msg.GasFeeCap),
+		MaxPriorityFeePerGas: NewBig(msg.GasTipCap),
+		Value:                NewBig(msg.Value),
+		Data:                 msg.Data,
+		Block:                block,
+	}
+	if msg.To != nil {
+		req.To = msg.To.Bytes()
+	}
+	if msg.Gas != 0 {
+		req.Gas = &msg.Gas
+	}
+	for _, tuple := range msg.AccessList {
+		keys := make([][]byte, len(tuple.StorageKeys))
+		for i, key := range tuple.StorageKeys {
+			keys[i] = key.Bytes()
+		}
+		req.AccessList = append(req.AccessList, &AccessTuple{Address: tuple.Address.Bytes(), StorageKeys: keys})
+	}
+	return req
+}
+
+// ToCallMsg converts the message back into a call message.
+func (c *CallRequest) ToCallMsg() (ethereum.CallMsg, error) {
+	msg := ethereum.CallMsg{
+		Gas:       c.GetGas(),
+		GasPrice:  ToBig(c.GetGasPrice()),
+		GasFeeCap: ToBig(c.GetMaxFeePerGas()),
+		GasTipCap: ToBig(c.GetMaxPriorityFeePerGas()),
+		Value:     ToBig(c.GetValue()),
+		Data:      c.GetData(),
+	}
+	if len(c.GetFrom()) > 0 {
+		from, err := ToAddress(c.GetFrom())
+		if err != nil {
+			return msg, err
+		}
+		msg.From = from
+	}
+	if len(c.GetTo()) > 0 {
+		to, err := ToAddress(c.GetTo())
+		if err != nil {
+			return msg, err
+		}
+		msg.To = &to
+	}
+	for _, tuple := range c.GetAccessList() {
+		addr, err := ToAddress(tuple.GetAddress())
+		if err != nil {
+			return msg, err
+		}
+		keys := make([]common.Hash, len(tuple.GetStorageKeys()))
+		for i, key := range tuple.GetStorageKeys() {
+			if keys[i], err = ToHash(key); err != nil {
+				return msg, err
+			}
+		}
+		msg.AccessList = append(msg.AccessList, types.AccessTuple{Address: addr, StorageKeys: keys})
+	}
+	return msg, nil
+}
@@ -0,0 +1,606 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: eth.proto
+
+package ethpbconnect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	ethpb "github.com/ethereum/go-ethereum/ethclient/ethpb"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// EthServiceName is the fully-qualified name of the EthService service.
+	EthServiceName = "geth.eth.v1.EthService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// EthServiceChainIdProcedure is the fully-qualified name of the EthService's ChainId RPC.
+	EthServiceChainIdProcedure = "/geth.eth.v1.EthService/ChainId"
+	// EthServiceBlockNumberProcedure is the fully-qualified name of the EthService's BlockNumber RPC.
+	EthServiceBlockNumberProcedure = "/geth.eth.v1.EthService/BlockNumber"
+	// EthServiceGetHeaderProcedure is the fully-qualified name of the EthService's GetHeader RPC.
+	EthServiceGetHeaderProcedure = "/geth.eth.v1.EthService/GetHeader"
+	// EthServiceGetBlockProcedure is the fully-qualified name of the EthService's GetBlock RPC.
+	EthServiceGetBlockProcedure = "/geth.eth.v1.EthService/GetBlock"
+	// EthServiceGetTransactionProcedure is the fully-qualified name of the EthService's GetTransaction
+	// RPC.
+	EthServiceGetTransactionProcedure = "/geth.eth.v1.EthService/GetTransaction"
+	// EthServiceGetTransactionReceiptProcedure is the fully-qualified name of the EthService's
+	// GetTransactionReceipt RPC.
+	EthServiceGetTransactionReceiptProcedure = "/geth.eth.v1.EthService/GetTransactionReceipt"
+	// EthServiceGetBlockReceiptsProcedure is the fully-qualified name of the EthService's
+	// GetBlockReceipts RPC.
+	EthServiceGetBlockReceiptsProcedure = "/geth.eth.v1.EthService/GetBlockReceipts"
+	// EthServiceGetLogsProcedure is the fully-qualified name of the EthService's GetLogs RPC.
+	EthServiceGetLogsProcedure = "/geth.eth.v1.EthService/GetLogs"
+	// EthServiceGetBalanceProcedure is the fully-qualified name of the EthService's GetBalance RPC.
+	EthServiceGetBalanceProcedure = "/geth.eth.v1.EthService/GetBalance"
+	// EthServiceGetNonceProcedure is the fully-qualified name of the EthService's GetNonce RPC.
+	EthServiceGetNonceProcedure = "/geth.eth.v1.EthService/GetNonce"
+	// EthServiceGetCodeProcedure is the fully-qualified name of the EthService's GetCode RPC.
+	EthServiceGetCodeProcedure = "/geth.eth.v1.EthService/GetCode"
+	// EthServiceGetStorageAtProcedure is the fully-qualified name of the EthService's GetStorageAt RPC.
+	EthServiceGetStorageAtProcedure = "/geth.eth.v1.EthService/GetStorageAt"
+	// EthServiceCallProcedure is the fully-qualified name of the EthService's Call RPC.
+	EthServiceCallProcedure = "/geth.eth.v1.EthService/Call"
+	// EthServiceEstimateGasProcedure is the fully-qualified name of the EthService's EstimateGas RPC.
+	EthServiceEstimateGasProcedure = "/geth.eth.v1.EthService/EstimateGas"
+	// EthServiceSendRawTransactionProcedure is the fully-qualified name of the EthService's
+	// SendRawTransaction RPC.
+	EthServiceSendRawTransactionProcedure = "/geth.eth.v1.EthService/SendRawTransaction"
+	// EthServiceSubscribeNewHeadsProcedure is the fully-qualified name of the EthService's
+	// SubscribeNewHeads RPC.
+	EthServiceSubscribeNewHeadsProcedure = "/geth.eth.v1.EthService/SubscribeNewHeads"
+	// EthServiceSubscribeLogsProcedure is the fully-qualified name of the EthService's SubscribeLogs
+	// RPC.
+	EthServiceSubscribeLogsProcedure = "/geth.eth.v1.EthService/SubscribeLogs"
+)
+
+// EthServiceClient is a client for the geth.eth.v1.EthService service.
+type EthServiceClient interface {
+	ChainId(context.Context, *connect.Request[ethpb.ChainIdRequest]) (*connect.Response[ethpb.ChainIdResponse], error)
+	BlockNumber(context.Context, *connect.Request[ethpb.BlockNumberRequest]) (*connect.Response[ethpb.BlockNumberResponse], error)
+	GetHeader(context.Context, *connect.Request[ethpb.GetHeaderRequest]) (*connect.Response[ethpb.Header], error)
+	GetBlock(context.Context, *connect.Request[ethpb.GetBlockRequest]) (*connect.Response[ethpb.Block], error)
+	GetTransaction(context.Context, *connect.Request[ethpb.GetTransactionRequest]) (*connect.Response[ethpb.Transaction], error)
+	GetTransactionReceipt(context.Context, *connect.Request[ethpb.GetTransactionReceiptRequest]) (*connect.Response[ethpb.Receipt], error)
+	GetBlockReceipts(context.Context, *connect.Request[ethpb.GetBlockReceiptsRequest]) (*connect.Response[ethpb.GetBlockReceiptsResponse], error)
+	GetLogs(context.Context, *connect.Request[ethpb.FilterQuery]) (*connect.Response[ethpb.GetLogsResponse], error)
+	GetBalance(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetBalanceResponse], error)
+	GetNonce(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetNonceResponse], error)
+	GetCode(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetCodeResponse], error)
+	GetStorageAt(context.Context, *connect.Request[ethpb.GetStorageAtRequest]) (*connect.Response[ethpb.GetStorageAtResponse], error)
+	Call(context.Context, *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.CallResponse], error)
+	EstimateGas(context.Context, *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.EstimateGasResponse], error)
+	SendRawTransaction(context.Context, *connect.Request[ethpb.SendRawTransactionRequest]) (*connect.Response[ethpb.SendRawTransactionResponse], error)
+	SubscribeNewHeads(context.Context, *connect.Request[ethpb.SubscribeNewHeadsRequest]) (*connect.ServerStreamForClient[ethpb.Header], error)
+	SubscribeLogs(context.Context, *connect.Request[ethpb.FilterQuery]) (*connect.ServerStreamForClient[ethpb.Log], error)
+}
+
+// NewEthServiceClient constructs a client for the geth.eth.v1.EthService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewEthServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) EthServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	ethServiceMethods := ethpb.File_eth_proto.Services().ByName("EthService").Methods()
+	return &ethServiceClient{
+		chainId: connect.NewClient[ethpb.ChainIdRequest, ethpb.ChainIdResponse](
+			httpClient,
+			baseURL+EthServiceChainIdProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("ChainId")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		blockNumber: connect.NewClient[ethpb.BlockNumberRequest, ethpb.BlockNumberResponse](
+			httpClient,
+			baseURL+EthServiceBlockNumberProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("BlockNumber")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getHeader: connect.NewClient[ethpb.GetHeaderRequest, ethpb.Header](
+			httpClient,
+			baseURL+EthServiceGetHeaderProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetHeader")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getBlock: connect.NewClient[ethpb.GetBlockRequest, ethpb.Block](
+			httpClient,
+			baseURL+EthServiceGetBlockProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetBlock")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getTransaction: connect.NewClient[ethpb.GetTransactionRequest, ethpb.Transaction](
+			httpClient,
+			baseURL+EthServiceGetTransactionProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetTransaction")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getTransactionReceipt: connect.NewClient[ethpb.GetTransactionReceiptRequest, ethpb.Receipt](
+			httpClient,
+			baseURL+EthServiceGetTransactionReceiptProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetTransactionReceipt")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getBlockReceipts: connect.NewClient[ethpb.GetBlockReceiptsRequest, ethpb.GetBlockReceiptsResponse](
+			httpClient,
+			baseURL+EthServiceGetBlockReceiptsProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetBlockReceipts")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getLogs: connect.NewClient[ethpb.FilterQuery, ethpb.GetLogsResponse](
+			httpClient,
+			baseURL+EthServiceGetLogsProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetLogs")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getBalance: connect.NewClient[ethpb.GetAccountRequest, ethpb.GetBalanceResponse](
+			httpClient,
+			baseURL+EthServiceGetBalanceProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetBalance")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getNonce: connect.NewClient[ethpb.GetAccountRequest, ethpb.GetNonceResponse](
+			httpClient,
+			baseURL+EthServiceGetNonceProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetNonce")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getCode: connect.NewClient[ethpb.GetAccountRequest, ethpb.GetCodeResponse](
+			httpClient,
+			baseURL+EthServiceGetCodeProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetCode")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		getStorageAt: connect.NewClient[ethpb.GetStorageAtRequest, ethpb.GetStorageAtResponse](
+			httpClient,
+			baseURL+EthServiceGetStorageAtProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("GetStorageAt")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		call: connect.NewClient[ethpb.CallRequest, ethpb.CallResponse](
+			httpClient,
+			baseURL+EthServiceCallProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("Call")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		estimateGas: connect.NewClient[ethpb.CallRequest, ethpb.EstimateGasResponse](
+			httpClient,
+			baseURL+EthServiceEstimateGasProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("EstimateGas")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		sendRawTransaction: connect.NewClient[ethpb.SendRawTransactionRequest, ethpb.SendRawTransactionResponse](
+			httpClient,
+			baseURL+EthServiceSendRawTransactionProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("SendRawTransaction")),
+			connect.WithClientOptions(opts...),
+		),
+		subscribeNewHeads: connect.NewClient[ethpb.SubscribeNewHeadsRequest, ethpb.Header](
+			httpClient,
+			baseURL+EthServiceSubscribeNewHeadsProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("SubscribeNewHeads")),
+			connect.WithClientOptions(opts...),
+		),
+		subscribeLogs: connect.NewClient[ethpb.FilterQuery, ethpb.Log](
+			httpClient,
+			baseURL+EthServiceSubscribeLogsProcedure,
+			connect.WithSchema(ethServiceMethods.ByName("SubscribeLogs")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// ethServiceClient implements EthServiceClient.
+type ethServiceClient struct {
+	chainId               *connect.Client[ethpb.ChainIdRequest, ethpb.ChainIdResponse]
+	blockNumber           *connect.Client[ethpb.BlockNumberRequest, ethpb.BlockNumberResponse]
+	getHeader             *connect.Client[ethpb.GetHeaderRequest, ethpb.Header]
+	getBlock              *connect.Client[ethpb.GetBlockRequest, ethpb.Block]
+	getTransaction        *connect.Client[ethpb.GetTransactionRequest, ethpb.Transaction]
+	getTransactionReceipt *connect.Client[ethpb.GetTransactionReceiptRequest, ethpb.Receipt]
+	getBlockReceipts      *connect.Client[ethpb.GetBlockReceiptsRequest, ethpb.GetBlockReceiptsResponse]
+	getLogs               *connect.Client[ethpb.FilterQuery, ethpb.GetLogsResponse]
+	getBalance            *connect.Client[ethpb.GetAccountRequest, ethpb.GetBalanceResponse]
+	getNonce              *connect.Client[ethpb.GetAccountRequest, ethpb.GetNonceResponse]
+	getCode               *connect.Client[ethpb.GetAccountRequest, ethpb.GetCodeResponse]
+	getStorageAt          *connect.Client[ethpb.GetStorageAtRequest, ethpb.GetStorageAtResponse]
+	call                  *connect.Client[ethpb.CallRequest, ethpb.CallResponse]
+	estimateGas           *connect.Client[ethpb.CallRequest, ethpb.EstimateGasResponse]
+	sendRawTransaction    *connect.Client[ethpb.SendRawTransactionRequest, ethpb.SendRawTransactionResponse]
+	subscribeNewHeads     *connect.Client[ethpb.SubscribeNewHeadsRequest, ethpb.Header]
+	subscribeLogs         *connect.Client[ethpb.FilterQuery, ethpb.Log]
+}
+
+// ChainId calls geth.eth.v1.EthService.ChainId.
+func (c *ethServiceClient) ChainId(ctx context.Context, req *connect.Request[ethpb.ChainIdRequest]) (*connect.Response[ethpb.ChainIdResponse], error) {
+	return c.chainId.CallUnary(ctx, req)
+}
+
+// BlockNumber calls geth.eth.v1.EthService.BlockNumber.
+func (c *ethServiceClient) BlockNumber(ctx context.Context, req *connect.Request[ethpb.BlockNumberRequest]) (*connect.Response[ethpb.BlockNumberResponse], error) {
+	return c.blockNumber.CallUnary(ctx, req)
+}
+
+// GetHeader calls geth.eth.v1.EthService.GetHeader.
+func (c *ethServiceClient) GetHeader(ctx context.Context, req *connect.Request[ethpb.GetHeaderRequest]) (*connect.Response[ethpb.Header], error) {
+	return c.getHeader.CallUnary(ctx, req)
+}
+
+// GetBlock calls geth.eth.v1.EthService.GetBlock.
+func (c *ethServiceClient) GetBlock(ctx context.Context, req *connect.Request[ethpb.GetBlockRequest]) (*connect.Response[ethpb.Block], error) {
+	return c.getBlock.CallUnary(ctx, req)
+}
+
+// GetTransaction calls geth.eth.v1.EthService.GetTransaction.
+func (c *ethServiceClient) GetTransaction(ctx context.Context, req *connect.Request[ethpb.GetTransactionRequest]) (*connect.Response[ethpb.Transaction], error) {
+	return c.getTransaction.CallUnary(ctx, req)
+}
+
+// GetTransactionReceipt calls geth.eth.v1.EthService.GetTransactionReceipt.
+func (c *ethServiceClient) GetTransactionReceipt(ctx context.Context, req *connect.Request[ethpb.GetTransactionReceiptRequest]) (*connect.Response[ethpb.Receipt], error) {
+	return c.getTransactionReceipt.CallUnary(ctx, req)
+}
+
+// GetBlockReceipts calls geth.eth.v1.EthService.GetBlockReceipts.
+func (c *ethServiceClient) GetBlockReceipts(ctx context.Context, req *connect.Request[ethpb.GetBlockReceiptsRequest]) (*connect.Response[ethpb.GetBlockReceiptsResponse], error) {
+	return c.getBlockReceipts.CallUnary(ctx, req)
+}
+
+// GetLogs calls geth.eth.v1.EthService.GetLogs.
+func (c *ethServiceClient) GetLogs(ctx context.Context, req *connect.Request[ethpb.FilterQuery]) (*connect.Response[ethpb.GetLogsResponse], error) {
+	return c.getLogs.CallUnary(ctx, req)
+}
+
+// GetBalance calls geth.eth.v1.EthService.GetBalance.
+func (c *ethServiceClient) GetBalance(ctx context.Context, req *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetBalanceResponse], error) {
+	return c.getBalance.CallUnary(ctx, req)
+}
+
+// GetNonce calls geth.eth.v1.EthService.GetNonce.
+func (c *ethServiceClient) GetNonce(ctx context.Context, req *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetNonceResponse], error) {
+	return c.getNonce.CallUnary(ctx, req)
+}
+
+// GetCode calls geth.eth.v1.EthService.GetCode.
+func (c *ethServiceClient) GetCode(ctx context.Context, req *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetCodeResponse], error) {
+	return c.getCode.CallUnary(ctx, req)
+}
+
+// GetStorageAt calls geth.eth.v1.EthService.GetStorageAt.
+func (c *ethServiceClient) GetStorageAt(ctx context.Context, req *connect.Request[ethpb.GetStorageAtRequest]) (*connect.Response[ethpb.GetStorageAtResponse], error) {
+	return c.getStorageAt.CallUnary(ctx, req)
+}
+
+// Call calls geth.eth.v1.EthService.Call.
+func (c *ethServiceClient) Call(ctx context.Context, req *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.CallResponse], error) {
+	return c.call.CallUnary(ctx, req)
+}
+
+// EstimateGas calls geth.eth.v1.EthService.EstimateGas.
+func (c *ethServiceClient) EstimateGas(ctx context.Context, req *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.EstimateGasResponse], error) {
+	return c.estimateGas.CallUnary(ctx, req)
+}
+
+// SendRawTransaction calls geth.eth.v1.EthService.SendRawTransaction.
+func (c *ethServiceClient) SendRawTransaction(ctx context.Context, req *connect.Request[ethpb.SendRawTransactionRequest]) (*connect.Response[ethpb.SendRawTransactionResponse], error) {
+	return c.sendRawTransaction.CallUnary(ctx, req)
+}
+
+// SubscribeNewHeads calls geth.eth.v1.EthService.SubscribeNewHeads.
+func (c *ethServiceClient) SubscribeNewHeads(ctx context.Context, req *connect.Request[ethpb.SubscribeNewHeadsRequest]) (*connect.ServerStreamForClient[ethpb.Header], error) {
+	return c.subscribeNewHeads.CallServerStream(ctx, req)
+}
+
+// SubscribeLogs calls geth.eth.v1.EthService.SubscribeLogs.
+func (c *ethServiceClient) SubscribeLogs(ctx context.Context, req *connect.Request[ethpb.FilterQuery]) (*connect.ServerStreamForClient[ethpb.Log], error) {
+	return c.subscribeLogs.CallServerStream(ctx, req)
+}
+
+// EthServiceHandler is an implementation of the geth.eth.v1.EthService service.
+type EthServiceHandler interface {
+	ChainId(context.Context, *connect.Request[ethpb.ChainIdRequest]) (*connect.Response[ethpb.ChainIdResponse], error)
+	BlockNumber(context.Context, *connect.Request[ethpb.BlockNumberRequest]) (*connect.Response[ethpb.BlockNumberResponse], error)
+	GetHeader(context.Context, *connect.Request[ethpb.GetHeaderRequest]) (*connect.Response[ethpb.Header], error)
+	GetBlock(context.Context, *connect.Request[ethpb.GetBlockRequest]) (*connect.Response[ethpb.Block], error)
+	GetTransaction(context.Context, *connect.Request[ethpb.GetTransactionRequest]) (*connect.Response[ethpb.Transaction], error)
+	GetTransactionReceipt(context.Context, *connect.Request[ethpb.GetTransactionReceiptRequest]) (*connect.Response[ethpb.Receipt], error)
+	GetBlockReceipts(context.Context, *connect.Request[ethpb.GetBlockReceiptsRequest]) (*connect.Response[ethpb.GetBlockReceiptsResponse], error)
+	GetLogs(context.Context, *connect.Request[ethpb.FilterQuery]) (*connect.Response[ethpb.GetLogsResponse], error)
+	GetBalance(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetBalanceResponse], error)
+	GetNonce(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetNonceResponse], error)
+	GetCode(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetCodeResponse], error)
+	GetStorageAt(context.Context, *connect.Request[ethpb.GetStorageAtRequest]) (*connect.Response[ethpb.GetStorageAtResponse], error)
+	Call(context.Context, *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.CallResponse], error)
+	EstimateGas(context.Context, *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.EstimateGasResponse], error)
+	SendRawTransaction(context.Context, *connect.Request[ethpb.SendRawTransactionRequest]) (*connect.Response[ethpb.SendRawTransactionResponse], error)
+	SubscribeNewHeads(context.Context, *connect.Request[ethpb.SubscribeNewHeadsRequest], *connect.ServerStream[ethpb.Header]) error
+	SubscribeLogs(context.Context, *connect.Request[ethpb.FilterQuery], *connect.ServerStream[ethpb.Log]) error
+}
+
+// NewEthServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewEthServiceHandler(svc EthServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	ethServiceMethods := ethpb.File_eth_proto.Services().ByName("EthService").Methods()
+	ethServiceChainIdHandler := connect.NewUnaryHandler(
+		EthServiceChainIdProcedure,
+		svc.ChainId,
+		connect.WithSchema(ethServiceMethods.ByName("ChainId")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceBlockNumberHandler := connect.NewUnaryHandler(
+		EthServiceBlockNumberProcedure,
+		svc.BlockNumber,
+		connect.WithSchema(ethServiceMethods.ByName("BlockNumber")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetHeaderHandler := connect.NewUnaryHandler(
+		EthServiceGetHeaderProcedure,
+		svc.GetHeader,
+		connect.WithSchema(ethServiceMethods.ByName("GetHeader")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetBlockHandler := connect.NewUnaryHandler(
+		EthServiceGetBlockProcedure,
+		svc.GetBlock,
+		connect.WithSchema(ethServiceMethods.ByName("GetBlock")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetTransactionHandler := connect.NewUnaryHandler(
+		EthServiceGetTransactionProcedure,
+		svc.GetTransaction,
+		connect.WithSchema(ethServiceMethods.ByName("GetTransaction")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetTransactionReceiptHandler := connect.NewUnaryHandler(
+		EthServiceGetTransactionReceiptProcedure,
+		svc.GetTransactionReceipt,
+		connect.WithSchema(ethServiceMethods.ByName("GetTransactionReceipt")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetBlockReceiptsHandler := connect.NewUnaryHandler(
+		EthServiceGetBlockReceiptsProcedure,
+		svc.GetBlockReceipts,
+		connect.WithSchema(ethServiceMethods.ByName("GetBlockReceipts")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetLogsHandler := connect.NewUnaryHandler(
+		EthServiceGetLogsProcedure,
+		svc.GetLogs,
+		connect.WithSchema(ethServiceMethods.ByName("GetLogs")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetBalanceHandler := connect.NewUnaryHandler(
+		EthServiceGetBalanceProcedure,
+		svc.GetBalance,
+		connect.WithSchema(ethServiceMethods.ByName("GetBalance")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetNonceHandler := connect.NewUnaryHandler(
+		EthServiceGetNonceProcedure,
+		svc.GetNonce,
+		connect.WithSchema(ethServiceMethods.ByName("GetNonce")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetCodeHandler := connect.NewUnaryHandler(
+		EthServiceGetCodeProcedure,
+		svc.GetCode,
+		connect.WithSchema(ethServiceMethods.ByName("GetCode")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceGetStorageAtHandler := connect.NewUnaryHandler(
+		EthServiceGetStorageAtProcedure,
+		svc.GetStorageAt,
+		connect.WithSchema(ethServiceMethods.ByName("GetStorageAt")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceCallHandler := connect.NewUnaryHandler(
+		EthServiceCallProcedure,
+		svc.Call,
+		connect.WithSchema(ethServiceMethods.ByName("Call")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceEstimateGasHandler := connect.NewUnaryHandler(
+		EthServiceEstimateGasProcedure,
+		svc.EstimateGas,
+		connect.WithSchema(ethServiceMethods.ByName("EstimateGas")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceSendRawTransactionHandler := connect.NewUnaryHandler(
+		EthServiceSendRawTransactionProcedure,
+		svc.SendRawTransaction,
+		connect.WithSchema(ethServiceMethods.ByName("SendRawTransaction")),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceSubscribeNewHeadsHandler := connect.NewServerStreamHandler(
+		EthServiceSubscribeNewHeadsProcedure,
+		svc.SubscribeNewHeads,
+		connect.WithSchema(ethServiceMethods.ByName("SubscribeNewHeads")),
+		connect.WithHandlerOptions(opts...),
+	)
+	ethServiceSubscribeLogsHandler := connect.NewServerStreamHandler(
+		EthServiceSubscribeLogsProcedure,
+		svc.SubscribeLogs,
+		connect.WithSchema(ethServiceMethods.ByName("SubscribeLogs")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/geth.eth.v1.EthService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EthServiceChainIdProcedure:
+			ethServiceChainIdHandler.ServeHTTP(w, r)
+		case EthServiceBlockNumberProcedure:
+			ethServiceBlockNumberHandler.ServeHTTP(w, r)
+		case EthServiceGetHeaderProcedure:
+			ethServiceGetHeaderHandler.ServeHTTP(w, r)
+		case EthServiceGetBlockProcedure:
+			ethServiceGetBlockHandler.ServeHTTP(w, r)
+		case EthServiceGetTransactionProcedure:
+			ethServiceGetTransactionHandler.ServeHTTP(w, r)
+		case EthServiceGetTransactionReceiptProcedure:
+			ethServiceGetTransactionReceiptHandler.ServeHTTP(w, r)
+		case EthServiceGetBlockReceiptsProcedure:
+			ethServiceGetBlockReceiptsHandler.ServeHTTP(w, r)
+		case EthServiceGetLogsProcedure:
+			ethServiceGetLogsHandler.ServeHTTP(w, r)
+		case EthServiceGetBalanceProcedure:
+			ethServiceGetBalanceHandler.ServeHTTP(w, r)
+		case EthServiceGetNonceProcedure:
+			ethServiceGetNonceHandler.ServeHTTP(w, r)
+		case EthServiceGetCodeProcedure:
+			ethServiceGetCodeHandler.ServeHTTP(w, r)
+		case EthServiceGetStorageAtProcedure:
+			ethServiceGetStorageAtHandler.ServeHTTP(w, r)
+		case EthServiceCallProcedure:
+			ethServiceCallHandler.ServeHTTP(w, r)
+		case EthServiceEstimateGasProcedure:
+			ethServiceEstimateGasHandler.ServeHTTP(w, r)
+		case EthServiceSendRawTransactionProcedure:
+			ethServiceSendRawTransactionHandler.ServeHTTP(w, r)
+		case EthServiceSubscribeNewHeadsProcedure:
+			ethServiceSubscribeNewHeadsHandler.ServeHTTP(w, r)
+		case EthServiceSubscribeLogsProcedure:
+			ethServiceSubscribeLogsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedEthServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedEthServiceHandler struct{}
+
+func (UnimplementedEthServiceHandler) ChainId(context.Context, *connect.Request[ethpb.ChainIdRequest]) (*connect.Response[ethpb.ChainIdResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.ChainId is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) BlockNumber(context.Context, *connect.Request[ethpb.BlockNumberRequest]) (*connect.Response[ethpb.BlockNumberResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.BlockNumber is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetHeader(context.Context, *connect.Request[ethpb.GetHeaderRequest]) (*connect.Response[ethpb.Header], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetHeader is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetBlock(context.Context, *connect.Request[ethpb.GetBlockRequest]) (*connect.Response[ethpb.Block], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetBlock is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetTransaction(context.Context, *connect.Request[ethpb.GetTransactionRequest]) (*connect.Response[ethpb.Transaction], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetTransaction is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetTransactionReceipt(context.Context, *connect.Request[ethpb.GetTransactionReceiptRequest]) (*connect.Response[ethpb.Receipt], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetTransactionReceipt is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetBlockReceipts(context.Context, *connect.Request[ethpb.GetBlockReceiptsRequest]) (*connect.Response[ethpb.GetBlockReceiptsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetBlockReceipts is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetLogs(context.Context, *connect.Request[ethpb.FilterQuery]) (*connect.Response[ethpb.GetLogsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetLogs is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetBalance(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetBalanceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetBalance is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetNonce(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetNonceResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetNonce is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetCode(context.Context, *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetCodeResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetCode is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) GetStorageAt(context.Context, *connect.Request[ethpb.GetStorageAtRequest]) (*connect.Response[ethpb.GetStorageAtResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.GetStorageAt is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) Call(context.Context, *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.CallResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.Call is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) EstimateGas(context.Context, *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.EstimateGasResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.EstimateGas is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) SendRawTransaction(context.Context, *connect.Request[ethpb.SendRawTransactionRequest]) (*connect.Response[ethpb.SendRawTransactionResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.SendRawTransaction is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) SubscribeNewHeads(context.Context, *connect.Request[ethpb.SubscribeNewHeadsRequest], *connect.ServerStream[ethpb.Header]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.SubscribeNewHeads is not implemented"))
+}
+
+func (UnimplementedEthServiceHandler) SubscribeLogs(context.Context, *connect.Request[ethpb.FilterQuery], *connect.ServerStream[ethpb.Log]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("geth.eth.v1.EthService.SubscribeLogs is not implemented"))
+}
@@ -0,0 +1,408 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package wallet implements a managed transaction sender on top of ethclient.
+//
+// A Sender assigns nonces to the transactions of an account, also when used from
+// many goroutines, and tracks every sent transaction until it is confirmed. Stuck
+// transactions are resubmitted with bumped fees, and confirmations are only
+// reported once the including block is buried deep enough in the canonical chain.
+// The events emitted by a confirmed transaction can be decoded with DecodeEvents.
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// ErrNonceUsed is returned when waiting for a transaction whose nonce was used
+// by another transaction of the account.
+var ErrNonceUsed = errors.New("nonce used by another transaction")
+
+// Messages of the node errors handled by the sender. Errors returned over RPC
+// lose their identity, so they are matched by message.
+const (
+	errMsgNonceTooLow  = "nonce too low"
+	errMsgAlreadyKnown = "already known"
+)
+
+// Backend is the chain access needed by a Sender. It is implemented by
+// ethclient.Client and the client of the simulated backend.
+type Backend interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Config contains the settings of a Sender.
+type Config struct {
+	// GasPrice is used as the fee cap and tip of all transactions if set, instead
+	// of deriving them from the chain. Transactions with fixed fees are
+	// resubmitted unchanged.
+	GasPrice *big.Int
+
+	// MaxFeeCap limits the fee cap of transactions bumped on resubmission.
+	MaxFeeCap *big.Int
+
+	GasMargin        uint64        // percentage added to gas estimates (default: 20)
+	FeeBump          uint64        // percentage the fees are raised by on resubmission (default: 10)
+	Confirmations    uint64        // number of blocks including and on top of the transaction (default: 1)
+	ResubmitInterval time.Duration // time after which unconfirmed transactions are resubmitted (default: 1min)
+	PollInterval     time.Duration // time between receipt lookups (default: 1s)
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.GasMargin == 0 {
+		cfg.GasMargin = 20
+	}
+	if cfg.FeeBump < 10 {
+		cfg.FeeBump = 10 // the minimum accepted by the transaction pool
+	}
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = 1
+	}
+	if cfg.ResubmitInterval == 0 {
+		cfg.ResubmitInterval = time.Minute
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Second
+	}
+	return cfg
+}
+
+// Request describes a transaction to be sent.
+type Request struct {
+	To    *common.Address // nil for contract creations
+	Value *big.Int
+	Data  []byte
+	Gas   uint64 // estimated if zero
+}
+
+// Sender sends the transactions of an account.
+type Sender struct {
+	backend Backend
+	from    common.Address
+	sign    bind.SignerFn
+	config  Config
+
+	mu      sync.Mutex // serializes the submission of new transactions
+	chainID *big.Int
+	nonce   *uint64 // next nonce, nil if it must be read from the pending state
+}
+
+// New creates a sender of the transactions of the given account.
+func New(backend Backend, from common.Address, sign bind.SignerFn, config Config) *Sender {
+	return &Sender{
+		backend: backend,
+		from:    from,
+		sign:    sign,
+		config:  config.withDefaults(),
+	}
+}
+
+// From returns the account sending the transactions.
+func (s *Sender) From() common.Address {
+	return s.from
+}
+
+// Send signs and submits a transaction with the next nonce of the account.
+func (s *Sender) Send(ctx context.Context, req Request) (*Tx, error) {
+	gas := req.Gas
+	if gas == 0 {
+		estimate, err := s.backend.EstimateGas(ctx, ethereum.CallMsg{
+			From:  s.from,
+			To:    req.To,
+			Value: req.Value,
+			Data:  req.Data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		gas = estimate + estimate*s.config.GasMargin/100
+	}
+	tip, feeCap, err := s.fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.chainID == nil {
+		if s.chainID, err = s.backend.ChainID(ctx); err != nil {
+			return nil, err
+		}
+	}
+	value := req.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	// Nonces are only consumed by transactions accepted by the node. If the
+	// account sent transactions elsewhere, the nonce is re-read and the
+	// submission retried once.
+	for attempt := 0; ; attempt++ {
+		if s.nonce == nil {
+			nonce, err := s.backend.PendingNonceAt(ctx, s.from)
+			if err != nil {
+				return nil, err
+			}
+			s.nonce = &nonce
+		}
+		tx, err := s.sign(s.from, types.NewTx(&types.DynamicFeeTx{
+			ChainID:   s.chainID,
+			Nonce:     *s.nonce,
+			GasTipCap: tip,
+			GasFeeCap: feeCap,
+			Gas:       gas,
+			To:        req.To,
+			Value:     value,
+			Data:      req.Data,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		err = s.backend.SendTransaction(ctx, tx)
+		if err == nil {
+			*s.nonce++
+			return &Tx{sender: s, versions: []*types.Transaction{tx}, submitted: time.Now()}, nil
+		}
+		// The node may have accepted the transaction despite the error, e.g. if
+		// the connection dropped after the submission, so the nonce is re-read
+		// on the next attempt either way.
+		s.nonce = nil
+		if !isError(err, errMsgNonceTooLow) || attempt > 0 {
+			return nil, err
+		}
+	}
+}
+
+// fees returns the tip and fee cap of new transactions.
+func (s *Sender) fees(ctx context.Context) (*big.Int, *big.Int, error) {
+	if s.config.GasPrice != nil {
+		return s.config.GasPrice, s.config.GasPrice, nil
+	}
+	tip, err := s.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	head, err := s.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	feeCap := new(big.Int).Set(tip)
+	if head.BaseFee != nil {
+		feeCap.Add(feeCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	}
+	return tip, feeCap, nil
+}
+
+// Tx is a transaction submitted by a Sender. It may be resubmitted with different
+// fees while waiting for it, so its hash can change.
+type Tx struct {
+	sender *Sender
+
+	mu        sync.Mutex
+	versions  []*types.Transaction // submitted versions, the latest one last
+	submitted time.Time            // time of the latest submission
+	included  bool                 // whether a version was included in a block
+}
+
+// Transaction returns the latest submitted version of the transaction.
+func (t *Tx) Transaction() *types.Transaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.versions[len(t.versions)-1]
+}
+
+// Hash returns the hash of the latest submitted version of the transaction.
+func (t *Tx) Hash() common.Hash {
+	return t.Transaction().Hash()
+}
+
+// Nonce returns the nonce of the transaction.
+func (t *Tx) Nonce() uint64 {
+	return t.Transaction().Nonce()
+}
+
+// Wait waits until a version of the transaction is confirmed, and returns its
+// receipt. The receipt is only returned once the including block is part of the
+// canonical chain with the configured number of confirmations. Transactions which
+// aren't included within the resubmission interval are resubmitted.
+func (t *Tx) Wait(ctx context.Context) (*types.Receipt, error) {
+	ticker := time.NewTicker(t.sender.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := t.check(ctx)
+		switch {
+		case receipt != nil:
+			return receipt, nil
+		case errors.Is(err, ErrNonceUsed):
+			return nil, err
+		case err != nil:
+			log.Debug("Failed to check transaction", "nonce", t.Nonce(), "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// check looks up whether the transaction is confirmed, and resubmits it if it is
+// overdue. Errors are transient, except for ErrNonceUsed.
+func (t *Tx) check(ctx context.Context) (*types.Receipt, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Read the nonce before the receipts, so a used nonce is always accompanied
+	// by the receipt of the transaction using it.
+	backend := t.sender.backend
+	nonce, err := backend.NonceAt(ctx, t.sender.from, nil)
+	if err != nil {
+		return nil, err
+	}
+	var lookupErr error
+	for i := len(t.versions) - 1; i >= 0; i-- {
+		receipt, err := backend.TransactionReceipt(ctx, t.versions[i].Hash())
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			lookupErr = err // e.g. transaction indexing in progress
+			continue
+		}
+		t.included = true
+		head, err := backend.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if head.Number.Uint64() < receipt.BlockNumber.Uint64()+t.sender.config.Confirmations-1 {
+			return nil, nil
+		}
+		// Don't trust the receipt of a reorged block, the lookup may lag behind.
+		header, err := backend.HeaderByNumber(ctx, receipt.BlockNumber)
+		if err != nil || header.Hash() != receipt.BlockHash {
+			return nil, nil
+		}
+		return receipt, nil
+	}
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+	// None of the versions is included, maybe another transaction took the nonce.
+	if nonce > t.versions[0].Nonce() {
+		if t.included {
+			return nil, nil // reorged out, wait for the receipt of the new chain
+		}
+		return nil, ErrNonceUsed
+	}
+	t.included = false
+	if time.Since(t.submitted) >= t.sender.config.ResubmitInterval {
+		t.resubmit(ctx)
+	}
+	return nil, nil
+}
+
+// resubmit sends the transaction again, with bumped fees unless they are fixed.
+func (t *Tx) resubmit(ctx context.Context) {
+	var (
+		s      = t.sender
+		latest = t.versions[len(t.versions)-1]
+		tx     = latest
+	)
+	if s.config.GasPrice == nil {
+		tip := bump(latest.GasTipCap(), s.config.FeeBump)
+		feeCap := bump(latest.GasFeeCap(), s.config.FeeBump)
+		if s.config.MaxFeeCap == nil || feeCap.Cmp(s.config.MaxFeeCap) <= 0 {
+			bumped, err := s.sign(s.from, types.NewTx(&types.DynamicFeeTx{
+				ChainID:   latest.ChainId(),
+				Nonce:     latest.Nonce(),
+				GasTipCap: tip,
+				GasFeeCap: feeCap,
+				Gas:       latest.Gas(),
+				To:        latest.To(),
+				Value:     latest.Value(),
+				Data:      latest.Data(),
+			}))
+			if err != nil {
+				log.Warn("Failed to sign resubmitted transaction", "nonce", latest.Nonce(), "err", err)
+				return
+			}
+			tx = bumped
+		}
+	}
+	err := s.backend.SendTransaction(ctx, tx)
+	switch {
+	case err == nil:
+		if tx != latest {
+			t.versions = append(t.versions, tx)
+		}
+	case isError(err, errMsgAlreadyKnown):
+	default:
+		log.Debug("Failed to resubmit transaction", "hash", tx.Hash(), "nonce", tx.Nonce(), "err", err)
+	}
+	t.submitted = time.Now()
+}
+
+// bump raises a fee by the given percentage, and at least by one.
+func bump(fee *big.Int, percent uint64) *big.Int {
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(fee) <= 0 {
+		bumped.Add(fee, common.Big1)
+	}
+	return bumped
+}
+
+// isError reports whether err is the node error with the given message.
+func isError(err error, msg string) bool {
+	return strings.Contains(err.Error(), msg)
+}
+
+// DecodeEvents decodes the logs of the given event emitted by a contract in a
+// receipt, using the unpack function of its abigen binding, for example
+// UnpackTransferEvent. Logs of other contracts and events are skipped.
+func DecodeEvents[Ev any](receipt *types.Receipt, contract common.Address, event abi.Event, unpack func(*types.Log) (*Ev, error)) ([]*Ev, error) {
+	var events []*Ev
+	for _, log := range receipt.Logs {
+		if log.Address != contract || len(log.Topics) == 0 || log.Topics[0] != event.ID {
+			continue
+		}
+		ev, err := unpack(log)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+	return events, nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package wallet
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr   = crypto.PubkeyToAddress(testKey.PublicKey)
+	testTo     = common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+)
+
+func newTestSender(t *testing.T, config Config, options ...func(*node.Config, *ethconfig.Config)) (*simulated.Backend, *Sender) {
+	t.Helper()
+
+	sim := simulated.NewBackend(types.GenesisAlloc{testAddr: {Balance: big.NewInt(params.Ether)}}, options...)
+	t.Cleanup(func() { sim.Close() })
+	chainID, err := sim.Client().ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.PollInterval = 10 * time.Millisecond
+	opts := bind.NewKeyedTransactor(testKey, chainID)
+	return sim, New(sim.Client(), testAddr, opts.Signer, config)
+}
+
+// waitCommitting waits for a transaction, committing blocks while waiting.
+func waitCommitting(t *testing.T, sim *simulated.Backend, tx *Tx) (*types.Receipt, uint64) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var (
+		commits uint64
+		done    = make(chan struct{})
+		receipt *types.Receipt
+		err     error
+	)
+	go func() {
+		defer close(done)
+		receipt, err = tx.Wait(ctx)
+	}()
+	for {
+		select {
+		case <-done:
+			if err != nil {
+				t.Fatalf("wait failed: %v", err)
+			}
+			return receipt, commits
+		case <-time.After(50 * time.Millisecond):
+			sim.Commit()
+			commits++
+		}
+	}
+}
+
+func TestConcurrentSend(t *testing.T) {
+	sim, sender := newTestSender(t, Config{})
+
+	var (
+		wg   sync.WaitGroup
+		txs  = make([]*Tx, 10)
+		errs = make([]error, 10)
+	)
+	for i := range txs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			txs[i], errs[i] = sender.Send(context.Background(), Request{To: &testTo, Value: big.NewInt(1)})
+		}(i)
+	}
+	wg.Wait()
+
+	nonces := make(map[uint64]bool)
+	for i, tx := range txs {
+		if errs[i] != nil {
+			t.Fatalf("send %d failed: %v", i, errs[i])
+		}
+		nonces[tx.Nonce()] = true
+	}
+	if len(nonces) != len(txs) {
+		t.Fatalf("nonces not unique: %v", nonces)
+	}
+	sim.Commit()
+	for _, tx := range txs {
+		receipt, _ := waitCommitting(t, sim, tx)
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Errorf("transaction %d failed", tx.Nonce())
+		}
+	}
+}
+
+func TestConfirmations(t *testing.T) {
+	sim, sender := newTestSender(t, Config{Confirmations: 3})
+
+	tx, err := sender.Send(context.Background(), Request{To: &testTo, Value: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, _ := waitCommitting(t, sim, tx)
+	head, err := sim.Client().BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head < receipt.BlockNumber.Uint64()+2 {
+		t.Fatalf("receipt returned too early: included in %d, head %d", receipt.BlockNumber, head)
+	}
+}
+
+func TestNonceResync(t *testing.T) {
+	sim, sender := newTestSender(t, Config{})
+
+	if _, err := sender.Send(context.Background(), Request{To: &testTo, Gas: params.TxGas}); err != nil {
+		t.Fatal(err)
+	}
+	// Send a transaction with the next nonce behind the sender's back.
+	client := sim.Client()
+	chainID, _ := client.ChainID(context.Background())
+	head, _ := client.HeaderByNumber(context.Background(), nil)
+	external := types.MustSignNewTx(testKey, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: new(big.Int).Add(head.BaseFee, big.NewInt(2*params.GWei)),
+		Gas:       params.TxGas,
+		To:        &testTo,
+	})
+	if err := client.SendTransaction(context.Background(), external); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	tx, err := sender.Send(context.Background(), Request{To: &testTo, Gas: params.TxGas})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 2 {
+		t.Fatalf("wrong nonce after resync: have %d, want 2", tx.Nonce())
+	}
+}
+
+// lostResponseBackend submits transactions, but reports the first submission
+// as failed, as if the connection dropped before the response arrived.
+type lostResponseBackend struct {
+	simulated.Client
+	lost *bool
+}
+
+func (b lostResponseBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.Client.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	if !*b.lost {
+		*b.lost = true
+		return errors.New("connection reset by peer")
+	}
+	return nil
+}
+
+func TestNonceLostResponse(t *testing.T) {
+	sim, sender := newTestSender(t, Config{})
+	sender.backend = lostResponseBackend{Client: sim.Client(), lost: new(bool)}
+
+	if _, err := sender.Send(context.Background(), Request{To: &testTo, Gas: params.TxGas}); err == nil {
+		t.Fatal("lost response not reported")
+	}
+	// The transaction made it into the pool, its nonce must not be reused.
+	tx, err := sender.Send(context.Background(), Request{To: &testTo, Gas: params.TxGas})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 {
+		t.Fatalf("wrong nonce after lost response: have %d, want 1", tx.Nonce())
+	}
+}
+
+// lowTipBackend suggests a tip below the one required by the miner.
+type lowTipBackend struct {
+	simulated.Client
+}
+
+func (b lowTipBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(params.GWei), nil
+}
+
+func TestResubmit(t *testing.T) {
+	// Require a tip above the suggested one, so only bumped transactions get in.
+	tip := big.NewInt(2 * params.GWei)
+	sim, sender := newTestSender(t, Config{ResubmitInterval: time.Millisecond}, simulated.WithMinerMinTip(tip))
+	sender.backend = lowTipBackend{sim.Client()}
+
+	tx, err := sender.Send(context.Background(), Request{To: &testTo, Gas: params.TxGas, Value: big.NewInt(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := tx.Hash()
+	receipt, _ := waitCommitting(t, sim, tx)
+	if receipt.TxHash == first {
+		t.Fatal("first version included despite low tip")
+	}
+	included, _, err := sim.Client().TransactionByHash(context.Background(), receipt.TxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if included.GasTipCap().Cmp(tip) < 0 {
+		t.Fatalf("included tip %v below minimum", included.GasTipCap())
+	}
+}
+
+func TestNonceUsed(t *testing.T) {
+	// A fixed fee below the base fee keeps the transaction pending.
+	sim, sender := newTestSender(t, Config{GasPrice: big.NewInt(1), ResubmitInterval: time.Hour})
+
+	tx, err := sender.Send(context.Background(), Request{To: &testTo, Gas: params.TxGas})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the transaction behind the sender's back.
+	client := sim.Client()
+	chainID, _ := client.ChainID(context.Background())
+	head, _ := client.HeaderByNumber(context.Background(), nil)
+	replacement := types.MustSignNewTx(testKey, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     tx.Nonce(),
+		GasTipCap: big.NewInt(params.GWei),
+		GasFeeCap: new(big.Int).Add(head.BaseFee, big.NewInt(2*params.GWei)),
+		Gas:       params.TxGas,
+		To:        &testTo,
+	})
+	if err := client.SendTransaction(context.Background(), replacement); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := tx.Wait(ctx); !errors.Is(err, ErrNonceUsed) {
+		t.Fatalf("expected ErrNonceUsed, got %v", err)
+	}
+}
+
+type testTransfer struct {
+	From  common.Address
+	Value *big.Int
+}
+
+// Tests that the events of a contract are decoded from a receipt, skipping the
+// logs of other events and contracts.
+func TestDecodeEvents(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(`[
+		{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "value", "type": "uint256"}]},
+		{"type": "event", "name": "Approval", "inputs": [{"name": "value", "type": "uint256"}]}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		transfer = parsed.Events["Transfer"]
+		approval = parsed.Events["Approval"]
+		contract = common.HexToAddress("0xc0ffee")
+		value, _ = transfer.Inputs.NonIndexed().Pack(big.NewInt(42))
+		from     = common.BytesToHash(testAddr.Bytes())
+	)
+	unpack := func(log *types.Log) (*testTransfer, error) {
+		out := new(testTransfer)
+		if err := parsed.UnpackIntoInterface(out, "Transfer", log.Data); err != nil {
+			return nil, err
+		}
+		if err := abi.ParseTopics(out, abi.Arguments{transfer.Inputs[0]}, log.Topics[1:]); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	receipt := &types.Receipt{Logs: []*types.Log{
+		{Address: contract, Topics: []common.Hash{transfer.ID, from}, Data: value},
+		{Address: contract, Topics: []common.Hash{approval.ID}, Data: value},
+		{Address: testTo, Topics: []common.Hash{transfer.ID, from}, Data: value},
+		{Address: contract},
+	}}
+	events, err := DecodeEvents(receipt, contract, transfer, unpack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("wrong number of events: have %d, want 1", len(events))
+	}
+	if events[0].From != testAddr || events[0].Value.Cmp(big.NewInt(42)) != 0 {
+		t.Fatalf("wrong event: %+v", events[0])
+	}
+}
@@ -69,6 +69,14 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// Backupper wraps the Backup method of a backing data store.
+type Backupper interface {
+	// Backup creates a consistent copy of the data store in the given directory,
+	// without interrupting the operations on it. If the directory contains a
+	// previous backup, only the data appended since is copied where possible.
+	Backup(dir string) error
+}
+
 // KeyValueStore contains all the methods required to allow handling different
 // key-value data stores backing the high level database.
 type KeyValueStore interface {
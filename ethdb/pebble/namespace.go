@@ -0,0 +1,461 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pebble
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Namespace is a keyspace stored in a separate pebble instance, so that it can
+// be tuned for its access pattern independently from the rest of the data.
+type Namespace struct {
+	Name   string // Name of the namespace, also the directory of its instance
+	Prefix []byte // Prefix of the keys belonging to the namespace
+}
+
+// NamespacedDatabase is a key-value store made up of multiple pebble instances:
+// the keys of each namespace are stored in a dedicated one, the rest of them in
+// the main one.
+//
+// Batches spanning multiple namespaces are not written atomically. The ones of
+// the namespaces are written first, so the data stored in them is never behind
+// the pointers in the main keyspace after a crash, only ahead of them. Only the
+// keyspaces tolerating this can be split off the main one.
+type NamespacedDatabase struct {
+	main   *Database
+	spaces []*namespaceDB
+}
+
+// namespaceDB is the pebble instance of a namespace.
+type namespaceDB struct {
+	Namespace
+	db *Database
+}
+
+// NewNamespaced opens a pebble database with its namespaces stored in separate
+// instances within its directory, each configured with the tuning of the given
+// profile. A namespace is only split off if requested, or if it was already in
+// the past: once a namespace is split off, it stays separated. The existing
+// keys of a newly split off namespace are moved out of the main instance.
+//
+// If no namespaces are in use, a regular pebble database is returned.
+func NewNamespaced(file string, cache int, handles int, namespace string, readonly bool, ephemeral bool, profile *Profile, spaces []Namespace, split bool) (ethdb.KeyValueStore, error) {
+	return openNamespaced(file, cache, handles, namespace, readonly, ephemeral, profile, spaces, split, nil)
+}
+
+// openNamespaced opens a namespaced pebble database on the given filesystem, the
+// default one if nil.
+func openNamespaced(file string, cache int, handles int, namespace string, readonly bool, ephemeral bool, profile *Profile, spaces []Namespace, split bool, fs vfs.FS) (ethdb.KeyValueStore, error) {
+	if fs == nil {
+		fs = vfs.Default
+	}
+	// Figure out which namespaces are in use and split the resources
+	var (
+		active      []Namespace
+		mainCache   = cache
+		mainHandles = handles
+	)
+	for _, space := range spaces {
+		if _, err := fs.Stat(filepath.Join(file, space.Name)); err == nil || (split && !readonly) {
+			active = append(active, space)
+
+			share := profile.namespace(space.Name).CacheShare
+			mainCache -= cache * share / 100
+			mainHandles -= handles * share / 100
+		}
+	}
+	main, err := open(file, mainCache, mainHandles, namespace, readonly, ephemeral, profile.Main, fs)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) == 0 {
+		return main, nil
+	}
+	ndb := &NamespacedDatabase{main: main}
+	for _, space := range active {
+		var (
+			tuning = profile.namespace(space.Name)
+			path   = filepath.Join(file, space.Name)
+		)
+		db, err := open(path, cache*tuning.CacheShare/100, handles*tuning.CacheShare/100, namespace+space.Name+"/", readonly, ephemeral, tuning, fs)
+		if err != nil {
+			ndb.Close()
+			return nil, fmt.Errorf("failed to open namespace %s: %v", space.Name, err)
+		}
+		ndb.spaces = append(ndb.spaces, &namespaceDB{Namespace: space, db: db})
+	}
+	if !readonly {
+		for _, space := range ndb.spaces {
+			if err := ndb.migrate(space); err != nil {
+				ndb.Close()
+				return nil, fmt.Errorf("failed to move keys to namespace %s: %v", space.Name, err)
+			}
+		}
+	}
+	return ndb, nil
+}
+
+// migrate moves the keys of the given namespace left in the main instance, e.g.
+// after the namespace is split off, into the instance of the namespace. It's
+// safe to be interrupted, the keys are only deleted once all are copied.
+func (d *NamespacedDatabase) migrate(space *namespaceDB) error {
+	it := d.main.NewIterator(space.Prefix, nil)
+	defer it.Release()
+
+	if !it.Next() {
+		return it.Error()
+	}
+	var (
+		start  = time.Now()
+		logged = time.Now()
+		batch  = space.db.NewBatch()
+		count  int
+	)
+	log.Info("Moving keys into database namespace", "namespace", space.Name)
+	for ok := true; ok; ok = it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return err
+		}
+		count++
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Moving keys into database namespace", "namespace", space.Name, "keys", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if err := d.main.DeleteRange(space.Prefix, upperBound(space.Prefix)); err != nil {
+		return err
+	}
+	log.Info("Moved keys into database namespace", "namespace", space.Name, "keys", count, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// route returns the index of the namespace the given key belongs to, or -1 if
+// it belongs to the main instance.
+func (d *NamespacedDatabase) route(key []byte) int {
+	for i, space := range d.spaces {
+		if bytes.HasPrefix(key, space.Prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// instance returns the pebble instance the given key belongs to.
+func (d *NamespacedDatabase) instance(key []byte) *Database {
+	if i := d.route(key); i >= 0 {
+		return d.spaces[i].db
+	}
+	return d.main
+}
+
+// Close closes all of the pebble instances.
+func (d *NamespacedDatabase) Close() error {
+	var errs []error
+	for _, space := range d.spaces {
+		if err := space.db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := d.main.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Has retrieves if a key is present in the key-value store.
+func (d *NamespacedDatabase) Has(key []byte) (bool, error) {
+	return d.instance(key).Has(key)
+}
+
+// Get retrieves the given key if it's present in the key-value store.
+func (d *NamespacedDatabase) Get(key []byte) ([]byte, error) {
+	return d.instance(key).Get(key)
+}
+
+// Put inserts the given value into the key-value store.
+func (d *NamespacedDatabase) Put(key []byte, value []byte) error {
+	return d.instance(key).Put(key, value)
+}
+
+// Delete removes the key from the key-value store.
+func (d *NamespacedDatabase) Delete(key []byte) error {
+	return d.instance(key).Delete(key)
+}
+
+// DeleteRange deletes all of the keys (and values) in the range [start,end)
+// (inclusive on start, exclusive on end) from all of the instances.
+func (d *NamespacedDatabase) DeleteRange(start, end []byte) error {
+	for _, space := range d.spaces {
+		if err := space.db.DeleteRange(start, end); err != nil {
+			return err
+		}
+	}
+	return d.main.DeleteRange(start, end)
+}
+
+// NewBatch creates a write-only key-value store that buffers changes to its host
+// database until a final write is called.
+func (d *NamespacedDatabase) NewBatch() ethdb.Batch {
+	b := &namespacedBatch{
+		db:    d,
+		main:  d.main.NewBatch(),
+		dirty: make([]bool, len(d.spaces)),
+	}
+	for _, space := range d.spaces {
+		b.spaces = append(b.spaces, space.db.NewBatch())
+	}
+	return b
+}
+
+// NewBatchWithSize creates a write-only database batch with pre-allocated buffer.
+// The buffer is only pre-allocated for the main instance.
+func (d *NamespacedDatabase) NewBatchWithSize(size int) ethdb.Batch {
+	b := &namespacedBatch{
+		db:    d,
+		main:  d.main.NewBatchWithSize(size),
+		dirty: make([]bool, len(d.spaces)),
+	}
+	for _, space := range d.spaces {
+		b.spaces = append(b.spaces, space.db.NewBatch())
+	}
+	return b
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of database
+// content with a particular key prefix, starting at a particular initial key
+// (or after, if it does not exist). If the prefix spans multiple instances,
+// their contents are merged.
+func (d *NamespacedDatabase) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	if i := d.route(prefix); i >= 0 {
+		return d.spaces[i].db.NewIterator(prefix, start)
+	}
+	its := []ethdb.Iterator{d.main.NewIterator(prefix, start)}
+	for _, space := range d.spaces {
+		if bytes.HasPrefix(space.Prefix, prefix) {
+			its = append(its, space.db.NewIterator(prefix, start))
+		}
+	}
+	if len(its) == 1 {
+		return its[0]
+	}
+	return newMergedIterator(its)
+}
+
+// Stat returns the internal metrics of all of the instances.
+func (d *NamespacedDatabase) Stat() (string, error) {
+	var out strings.Builder
+	stat, err := d.main.Stat()
+	if err != nil {
+		return "", err
+	}
+	out.WriteString(stat)
+	for _, space := range d.spaces {
+		stat, err := space.db.Stat()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&out, "\nNamespace %s:\n%s", space.Name, stat)
+	}
+	return out.String(), nil
+}
+
+// Compact flattens the underlying data store for the given key range in all of
+// the instances.
+func (d *NamespacedDatabase) Compact(start []byte, limit []byte) error {
+	for _, space := range d.spaces {
+		if err := space.db.Compact(start, limit); err != nil {
+			return err
+		}
+	}
+	return d.main.Compact(start, limit)
+}
+
+// Checkpoint creates a point-in-time copy of the database in the given directory,
+// which must not exist yet. The instances are copied one by one, the main one
+// first, so the namespaces are never behind it in the copy.
+func (d *NamespacedDatabase) Checkpoint(dir string) error {
+	if err := d.main.Checkpoint(dir); err != nil {
+		return err
+	}
+	for _, space := range d.spaces {
+		if err := space.db.Checkpoint(filepath.Join(dir, space.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Path returns the path to the database directory.
+func (d *NamespacedDatabase) Path() string {
+	return d.main.Path()
+}
+
+// namespacedBatch is a write-only batch spanning multiple pebble instances. A
+// batch cannot be used concurrently.
+type namespacedBatch struct {
+	db     *NamespacedDatabase
+	main   ethdb.Batch
+	spaces []ethdb.Batch
+	dirty  []bool // Whether the batches of the namespaces contain any operations
+}
+
+// Put inserts the given value into the batch for later committing.
+func (b *namespacedBatch) Put(key, value []byte) error {
+	if i := b.db.route(key); i >= 0 {
+		b.dirty[i] = true
+		return b.spaces[i].Put(key, value)
+	}
+	return b.main.Put(key, value)
+}
+
+// Delete inserts the key removal into the batch for later committing.
+func (b *namespacedBatch) Delete(key []byte) error {
+	if i := b.db.route(key); i >= 0 {
+		b.dirty[i] = true
+		return b.spaces[i].Delete(key)
+	}
+	return b.main.Delete(key)
+}
+
+// ValueSize retrieves the amount of data queued up for writing.
+func (b *namespacedBatch) ValueSize() int {
+	size := b.main.ValueSize()
+	for _, space := range b.spaces {
+		size += space.ValueSize()
+	}
+	return size
+}
+
+// Write flushes any accumulated data to disk, the namespaces first.
+func (b *namespacedBatch) Write() error {
+	for i, space := range b.spaces {
+		if !b.dirty[i] {
+			continue
+		}
+		if err := space.Write(); err != nil {
+			return err
+		}
+	}
+	return b.main.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *namespacedBatch) Reset() {
+	b.main.Reset()
+	for i, space := range b.spaces {
+		space.Reset()
+		b.dirty[i] = false
+	}
+}
+
+// Replay replays the batch contents, the operations of each instance in order.
+func (b *namespacedBatch) Replay(w ethdb.KeyValueWriter) error {
+	for _, space := range b.spaces {
+		if err := space.Replay(w); err != nil {
+			return err
+		}
+	}
+	return b.main.Replay(w)
+}
+
+// mergedIterator iterates over the contents of multiple instances in ascending
+// key order. The keys of the instances are disjoint.
+type mergedIterator struct {
+	its   []ethdb.Iterator
+	valid []bool // Whether the iterators are positioned at an item
+	cur   int    // Index of the iterator at the current item, -1 if none
+	init  bool   // Whether the iterators are already positioned
+}
+
+func newMergedIterator(its []ethdb.Iterator) *mergedIterator {
+	return &mergedIterator{its: its, valid: make([]bool, len(its)), cur: -1}
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted.
+func (it *mergedIterator) Next() bool {
+	if !it.init {
+		for i, sub := range it.its {
+			it.valid[i] = sub.Next()
+		}
+		it.init = true
+	} else if it.cur >= 0 {
+		it.valid[it.cur] = it.its[it.cur].Next()
+	}
+	it.cur = -1
+	for i, sub := range it.its {
+		if it.valid[i] && (it.cur < 0 || bytes.Compare(sub.Key(), it.its[it.cur].Key()) < 0) {
+			it.cur = i
+		}
+	}
+	return it.cur >= 0
+}
+
+// Error returns any accumulated error of the merged iterators.
+func (it *mergedIterator) Error() error {
+	for _, sub := range it.its {
+		if err := sub.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Key returns the key of the current key/value pair, or nil if done.
+func (it *mergedIterator) Key() []byte {
+	if it.cur < 0 {
+		return nil
+	}
+	return it.its[it.cur].Key()
+}
+
+// Value returns the value of the current key/value pair, or nil if done.
+func (it *mergedIterator) Value() []byte {
+	if it.cur < 0 {
+		return nil
+	}
+	return it.its[it.cur].Value()
+}
+
+// Release releases associated resources of the merged iterators.
+func (it *mergedIterator) Release() {
+	for _, sub := range it.its {
+		sub.Release()
+	}
+}
@@ -17,6 +17,8 @@
 package pebble
 
 import (
+	"bytes"
+	"slices"
 	"testing"
 
 	"github.com/cockroachdb/pebble"
@@ -54,3 +56,93 @@ func BenchmarkPebbleDB(b *testing.B) {
 		}
 	})
 }
+
+// testNamespaces are the namespaces used for testing the namespaced database,
+// overlapping with the keys of the test suite.
+var testNamespaces = []Namespace{
+	{Name: "b", Prefix: []byte("kb")},
+	{Name: "3", Prefix: []byte("k3")},
+}
+
+func TestNamespacedDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			db, err := openNamespaced("", 16, 16, "", false, true, DefaultProfile, testNamespaces, true, vfs.NewMem())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return db
+		})
+	})
+	t.Run("Migration", func(t *testing.T) {
+		fs := vfs.NewMem()
+		db, err := openNamespaced("", 16, 16, "", false, true, DefaultProfile, testNamespaces, false, fs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := db.(*Database); !ok {
+			t.Fatalf("Namespaces split off without request: %T", db)
+		}
+		for _, key := range []string{"ka1", "kb1", "kb2", "k31", "kc1"} {
+			db.Put([]byte(key), []byte(key))
+		}
+		db.Close()
+
+		// Split off the namespaces, moving the existing keys
+		db, err = openNamespaced("", 16, 16, "", false, true, DefaultProfile, testNamespaces, true, fs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ndb := db.(*NamespacedDatabase)
+		for _, key := range []string{"kb1", "kb2", "k31"} {
+			if has, _ := ndb.main.Has([]byte(key)); has {
+				t.Errorf("Key %s left in main instance", key)
+			}
+			if val, err := db.Get([]byte(key)); err != nil || !bytes.Equal(val, []byte(key)) {
+				t.Errorf("Key %s not moved to namespace: %v", key, err)
+			}
+		}
+		db.Close()
+
+		// Reopen without requesting the namespaces, they should stay in use
+		db, err = openNamespaced("", 16, 16, "", false, true, DefaultProfile, testNamespaces, false, fs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+
+		var keys []string
+		it := db.NewIterator([]byte("k"), nil)
+		for it.Next() {
+			keys = append(keys, string(it.Key()))
+		}
+		it.Release()
+		if want := []string{"k31", "ka1", "kb1", "kb2", "kc1"}; !slices.Equal(keys, want) {
+			t.Fatalf("Iteration mismatch: have %v, want %v", keys, want)
+		}
+	})
+}
+
+func BenchmarkPebbleProfiles(b *testing.B) {
+	for _, profile := range profiles {
+		b.Run(profile.Name, func(b *testing.B) {
+			dbtest.BenchDatabaseSuite(b, func() ethdb.KeyValueStore {
+				db, err := open("", 64, 64, "", false, true, profile.Main, vfs.NewMem())
+				if err != nil {
+					b.Fatal(err)
+				}
+				return db
+			})
+		})
+	}
+}
+
+func BenchmarkPebbleNamespaced(b *testing.B) {
+	dbtest.BenchDatabaseSuite(b, func() ethdb.KeyValueStore {
+		db, err := openNamespaced("", 64, 64, "", false, true, DefaultProfile, testNamespaces, true, vfs.NewMem())
+		if err != nil {
+			b.Fatal(err)
+		}
+		return db
+	})
+}
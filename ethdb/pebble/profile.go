@@ -0,0 +1,217 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pebble
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+)
+
+// Tuning contains the options of a pebble instance, adjustable to the access
+// pattern of the data stored in it.
+type Tuning struct {
+	CacheShare            int   // Percentage of the database cache assigned to the instance (namespaces only)
+	MemTableShare         int   // Percentage of the instance cache used for the memory tables
+	BloomBits             int   // Bits per key of the bloom filters, zero disables them
+	BlockSize             int   // Target size of the data blocks, zero uses the pebble default
+	BaseFileSize          int64 // Target size of the level 0 files, doubled in each subsequent level
+	L0CompactionThreshold int   // Number of level 0 files triggering a compaction, zero uses the pebble default
+	L0StopWritesThreshold int   // Number of level 0 files stalling the writes, zero uses the pebble default
+	Compactions           int   // Maximum number of concurrent compactions, zero uses all CPUs
+}
+
+// Profile is a set of tunings for the database and its separately stored
+// namespaces, aimed at a particular kind of node.
+type Profile struct {
+	Name       string
+	Main       Tuning            // Tuning of the main instance
+	Namespaces map[string]Tuning // Tunings of the namespaces, the main one is used if missing
+}
+
+var (
+	// DefaultProfile is the general purpose profile, suitable for most nodes.
+	DefaultProfile = &Profile{
+		Name: "default",
+		Main: Tuning{
+			MemTableShare: 50,
+			BloomBits:     10,
+			BaseFileSize:  2 * 1024 * 1024,
+		},
+		Namespaces: map[string]Tuning{
+			"txlookup": {
+				CacheShare:    10,
+				MemTableShare: 25,
+				BloomBits:     10,
+				BaseFileSize:  8 * 1024 * 1024,
+			},
+			"filtermaps": {
+				CacheShare:    10,
+				MemTableShare: 25,
+				BloomBits:     10,
+				BaseFileSize:  8 * 1024 * 1024,
+			},
+		},
+	}
+
+	// ValidatorProfile favours the latency of block processing, assigning most
+	// of the memory to the state. The indices are rarely read, so they get by
+	// with little cache and no bloom filters.
+	ValidatorProfile = &Profile{
+		Name: "validator",
+		Main: Tuning{
+			MemTableShare:         60,
+			BloomBits:             10,
+			BaseFileSize:          4 * 1024 * 1024,
+			L0CompactionThreshold: 4,
+			L0StopWritesThreshold: 24,
+		},
+		Namespaces: map[string]Tuning{
+			"txlookup": {
+				CacheShare:    5,
+				MemTableShare: 50,
+				BaseFileSize:  16 * 1024 * 1024,
+			},
+			"filtermaps": {
+				CacheShare:    5,
+				MemTableShare: 50,
+				BaseFileSize:  16 * 1024 * 1024,
+			},
+		},
+	}
+
+	// RPCProfile favours the reads served to RPC clients, keeping the read
+	// amplification low and assigning more of the memory to the block caches
+	// of the indices.
+	RPCProfile = &Profile{
+		Name: "rpc",
+		Main: Tuning{
+			MemTableShare:         25,
+			BloomBits:             16,
+			BaseFileSize:          2 * 1024 * 1024,
+			L0CompactionThreshold: 2,
+		},
+		Namespaces: map[string]Tuning{
+			"txlookup": {
+				CacheShare:    20,
+				MemTableShare: 20,
+				BloomBits:     16,
+				BaseFileSize:  4 * 1024 * 1024,
+			},
+			"filtermaps": {
+				CacheShare:    20,
+				MemTableShare: 20,
+				BloomBits:     16,
+				BlockSize:     32 * 1024,
+				BaseFileSize:  8 * 1024 * 1024,
+			},
+		},
+	}
+
+	// ArchiveProfile favours very large databases, using fewer and larger files
+	// and blocks to reduce the size of the in-memory metadata, and tolerating
+	// larger compaction debt during the initial sync.
+	ArchiveProfile = &Profile{
+		Name: "archive",
+		Main: Tuning{
+			MemTableShare:         50,
+			BloomBits:             10,
+			BlockSize:             16 * 1024,
+			BaseFileSize:          8 * 1024 * 1024,
+			L0StopWritesThreshold: 36,
+		},
+		Namespaces: map[string]Tuning{
+			"txlookup": {
+				CacheShare:    10,
+				MemTableShare: 25,
+				BloomBits:     10,
+				BaseFileSize:  32 * 1024 * 1024,
+			},
+			"filtermaps": {
+				CacheShare:    10,
+				MemTableShare: 25,
+				BloomBits:     10,
+				BlockSize:     32 * 1024,
+				BaseFileSize:  32 * 1024 * 1024,
+			},
+		},
+	}
+
+	// profiles is the list of the builtin profiles.
+	profiles = []*Profile{DefaultProfile, ValidatorProfile, RPCProfile, ArchiveProfile}
+)
+
+// Profiles returns the names of the builtin profiles.
+func Profiles() []string {
+	var names []string
+	for _, profile := range profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+// ProfileByName returns the builtin profile with the given name, the default
+// one if the name is empty.
+func ProfileByName(name string) (*Profile, error) {
+	if name == "" {
+		return DefaultProfile, nil
+	}
+	for _, profile := range profiles {
+		if profile.Name == name {
+			return profile, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown database profile %q, available: %s", name, strings.Join(Profiles(), ", "))
+}
+
+// namespace returns the tuning of the given namespace.
+func (p *Profile) namespace(name string) Tuning {
+	if tuning, ok := p.Namespaces[name]; ok {
+		return tuning
+	}
+	return p.Main
+}
+
+// apply sets the tunable options of the pebble instance.
+func (t Tuning) apply(opt *pebble.Options) {
+	levels := make([]pebble.LevelOptions, 7)
+	for i := range levels {
+		levels[i].TargetFileSize = t.BaseFileSize << i
+		if t.BloomBits > 0 {
+			levels[i].FilterPolicy = bloom.FilterPolicy(t.BloomBits)
+		}
+		if t.BlockSize > 0 {
+			levels[i].BlockSize = t.BlockSize
+		}
+	}
+	opt.Levels = levels
+
+	if t.L0CompactionThreshold > 0 {
+		opt.L0CompactionThreshold = t.L0CompactionThreshold
+	}
+	if t.L0StopWritesThreshold > 0 {
+		opt.L0StopWritesThreshold = t.L0StopWritesThreshold
+	}
+	opt.MaxConcurrentCompactions = runtime.NumCPU
+	if t.Compactions > 0 {
+		compactions := t.Compactions
+		opt.MaxConcurrentCompactions = func() int { return compactions }
+	}
+}
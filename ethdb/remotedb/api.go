@@ -0,0 +1,214 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+const (
+	// maxBatchReads is the maximum number of keys retrievable in a single
+	// batched read request.
+	maxBatchReads = 1024
+
+	// maxIteratePairs is the maximum number of key-value pairs returned in a
+	// single iteration request.
+	maxIteratePairs = 1024
+
+	// maxIterateBytes is the soft limit of the size of the key-value pairs
+	// returned in a single iteration request.
+	maxIterateBytes = 4 * 1024 * 1024
+)
+
+var (
+	// errReadOnly is returned if a write is attempted on a database exported
+	// in read-only mode.
+	errReadOnly = errors.New("remote database is read-only")
+
+	// errNotFound is returned if a requested key is not present in the database.
+	errNotFound = errors.New("not found")
+)
+
+// BatchOp is a single write operation of a batch sent to the remote database.
+type BatchOp struct {
+	Key    hexutil.Bytes `json:"key"`
+	Value  hexutil.Bytes `json:"value,omitempty"`
+	Delete bool          `json:"delete,omitempty"`
+}
+
+// IteratePage is a chunk of the key-value pairs of an iteration, in ascending
+// key order.
+type IteratePage struct {
+	Keys   []hexutil.Bytes `json:"keys"`
+	Values []hexutil.Bytes `json:"values"`
+	More   bool            `json:"more"` // Whether more pairs are left after the page
+}
+
+// API exports a database over RPC in the "remotedb" namespace, for the Database
+// client of this package to connect to. It should only be served on authenticated
+// endpoints, as it grants raw access to the entire database.
+type API struct {
+	db       ethdb.Database
+	writable bool
+}
+
+// NewAPI creates the RPC service of the given database. Writes are rejected
+// unless the writable flag is set.
+func NewAPI(db ethdb.Database, writable bool) *API {
+	return &API{db: db, writable: writable}
+}
+
+// Has retrieves if a key is present in the database.
+func (api *API) Has(key hexutil.Bytes) (bool, error) {
+	return api.db.Has(key)
+}
+
+// Get retrieves the value of the given key, failing if it's not present.
+func (api *API) Get(key hexutil.Bytes) (hexutil.Bytes, error) {
+	has, err := api.db.Has(key)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, errNotFound
+	}
+	return api.db.Get(key)
+}
+
+// GetMany retrieves the values of the given keys, with nil in place of the
+// missing ones.
+func (api *API) GetMany(keys []hexutil.Bytes) ([]*hexutil.Bytes, error) {
+	if len(keys) > maxBatchReads {
+		return nil, fmt.Errorf("too many keys: %d > %d", len(keys), maxBatchReads)
+	}
+	values := make([]*hexutil.Bytes, len(keys))
+	for i, key := range keys {
+		has, err := api.db.Has(key)
+		if err != nil {
+			return nil, err
+		}
+		if !has {
+			continue
+		}
+		value, err := api.db.Get(key)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = (*hexutil.Bytes)(&value)
+	}
+	return values, nil
+}
+
+// Iterate returns the key-value pairs with the given prefix, starting at the
+// given position (relative to the prefix). At most limit pairs are returned,
+// the remaining ones are retrievable by continuing after the last key.
+func (api *API) Iterate(prefix hexutil.Bytes, start hexutil.Bytes, limit int) (*IteratePage, error) {
+	if limit <= 0 || limit > maxIteratePairs {
+		limit = maxIteratePairs
+	}
+	var (
+		it    = api.db.NewIterator(prefix, start)
+		page  = &IteratePage{Keys: []hexutil.Bytes{}, Values: []hexutil.Bytes{}}
+		bytes int
+	)
+	defer it.Release()
+
+	for it.Next() {
+		if len(page.Keys) >= limit || bytes >= maxIterateBytes {
+			page.More = true
+			break
+		}
+		page.Keys = append(page.Keys, common.CopyBytes(it.Key()))
+		page.Values = append(page.Values, common.CopyBytes(it.Value()))
+		bytes += len(it.Key()) + len(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Write applies the given operations atomically to the database.
+func (api *API) Write(ops []BatchOp) error {
+	if !api.writable {
+		return errReadOnly
+	}
+	batch := api.db.NewBatch()
+	for _, op := range ops {
+		var err error
+		if op.Delete {
+			err = batch.Delete(op.Key)
+		} else {
+			err = batch.Put(op.Key, op.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// DeleteRange deletes all of the keys in the range [start, end).
+func (api *API) DeleteRange(start, end hexutil.Bytes) error {
+	if !api.writable {
+		return errReadOnly
+	}
+	return api.db.DeleteRange(start, end)
+}
+
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
+func (api *API) Ancient(kind string, number uint64) (hexutil.Bytes, error) {
+	return api.db.Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index 'start'.
+func (api *API) AncientRange(kind string, start, count, maxBytes uint64) ([]hexutil.Bytes, error) {
+	items, err := api.db.AncientRange(kind, start, count, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	blobs := make([]hexutil.Bytes, len(items))
+	for i, item := range items {
+		blobs[i] = item
+	}
+	return blobs, nil
+}
+
+// Ancients returns the ancient item numbers in the ancient store.
+func (api *API) Ancients() (uint64, error) {
+	return api.db.Ancients()
+}
+
+// Tail returns the number of first stored item in the ancient store.
+func (api *API) Tail() (uint64, error) {
+	return api.db.Tail()
+}
+
+// AncientSize returns the ancient size of the specified category.
+func (api *API) AncientSize(kind string) (uint64, error) {
+	return api.db.AncientSize(kind)
+}
+
+// Stat returns the statistic data of the database.
+func (api *API) Stat() (string, error) {
+	return api.db.Stat()
+}
@@ -15,24 +15,64 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
 // Package remotedb implements the key-value database layer based on a remote geth
-// node. Under the hood, it utilises the `debug_dbGet` method to implement a
-// read-only database.
+// node, which exports its database in the `remotedb` RPC namespace (see API).
+// Reads are served directly by the remote node, iterators are paged through in
+// chunks and batches are sent in a single request on write. If the remote node
+// doesn't export the namespace, the client falls back to the `debug_dbGet` family
+// of methods, which only allows for basic key and ancient lookups.
+//
 // There really are no guarantees in this database, since the local geth does not
-// exclusive access, but it can be used for basic diagnostics of a remote node.
+// have exclusive access: iterators are not consistent snapshots, and writes are
+// only accepted if the remote database was exported as writable. Geth only does
+// so from 'geth db serve', where no blockchain is operating on the database.
 package remotedb
 
 import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
-// Database is a key-value lookup for a remote database via debug_dbGet.
+// methodNotFoundCode is the JSON-RPC error code of calls to unknown methods.
+const methodNotFoundCode = -32601
+
+// errNotSupported is returned for the operations which are not supported by the
+// remote database.
+var errNotSupported = errors.New("not supported")
+
+// Database is a key-value lookup for a remote database via the remotedb RPC
+// namespace, or via debug_dbGet if the former is not available.
 type Database struct {
 	remote *rpc.Client
+	legacy atomic.Bool // Whether the remote node only supports the debug methods
+}
+
+// call invokes the given method of the remotedb namespace, falling back to the
+// given legacy method if the remote node doesn't support the namespace.
+func (db *Database) call(result interface{}, method string, legacy string, args ...interface{}) error {
+	if !db.legacy.Load() {
+		err := db.remote.Call(result, "remotedb_"+method, args...)
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != methodNotFoundCode {
+			return err
+		}
+		db.legacy.Store(true)
+	}
+	if legacy == "" {
+		return errNotSupported
+	}
+	return db.remote.Call(result, legacy, args...)
 }
 
 func (db *Database) Has(key []byte) (bool, error) {
+	var resp bool
+	if err := db.call(&resp, "has", "", hexutil.Bytes(key)); err != errNotSupported {
+		return resp, err
+	}
 	if _, err := db.Get(key); err != nil {
 		return false, err
 	}
@@ -41,13 +81,41 @@ func (db *Database) Has(key []byte) (bool, error) {
 
 func (db *Database) Get(key []byte) ([]byte, error) {
 	var resp hexutil.Bytes
-	err := db.remote.Call(&resp, "debug_dbGet", hexutil.Bytes(key))
+	err := db.call(&resp, "get", "debug_dbGet", hexutil.Bytes(key))
 	if err != nil {
 		return nil, err
 	}
 	return resp, nil
 }
 
+// GetMany retrieves the values of the given keys in a single request, with nil
+// in place of the missing ones.
+func (db *Database) GetMany(keys [][]byte) ([][]byte, error) {
+	var (
+		values = make([][]byte, 0, len(keys))
+		args   = make([]hexutil.Bytes, 0, maxBatchReads)
+	)
+	for len(keys) > 0 {
+		args = args[:0]
+		for _, key := range keys[:min(len(keys), maxBatchReads)] {
+			args = append(args, key)
+		}
+		var resp []*hexutil.Bytes
+		if err := db.call(&resp, "getMany", "", args); err != nil {
+			return nil, err
+		}
+		for _, value := range resp {
+			if value == nil {
+				values = append(values, nil)
+			} else {
+				values = append(values, *value)
+			}
+		}
+		keys = keys[len(args):]
+	}
+	return values, nil
+}
+
 func (db *Database) HasAncient(kind string, number uint64) (bool, error) {
 	if _, err := db.Ancient(kind, number); err != nil {
 		return false, err
@@ -57,7 +125,7 @@ func (db *Database) HasAncient(kind string, number uint64) (bool, error) {
 
 func (db *Database) Ancient(kind string, number uint64) ([]byte, error) {
 	var resp hexutil.Bytes
-	err := db.remote.Call(&resp, "debug_dbAncient", kind, number)
+	err := db.call(&resp, "ancient", "debug_dbAncient", kind, number)
 	if err != nil {
 		return nil, err
 	}
@@ -65,21 +133,33 @@ func (db *Database) Ancient(kind string, number uint64) ([]byte, error) {
 }
 
 func (db *Database) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
-	panic("not supported")
+	var resp []hexutil.Bytes
+	if err := db.call(&resp, "ancientRange", "", kind, start, count, maxBytes); err != nil {
+		return nil, err
+	}
+	items := make([][]byte, len(resp))
+	for i, item := range resp {
+		items[i] = item
+	}
+	return items, nil
 }
 
 func (db *Database) Ancients() (uint64, error) {
 	var resp uint64
-	err := db.remote.Call(&resp, "debug_dbAncients")
+	err := db.call(&resp, "ancients", "debug_dbAncients")
 	return resp, err
 }
 
 func (db *Database) Tail() (uint64, error) {
-	panic("not supported")
+	var resp uint64
+	err := db.call(&resp, "tail", "")
+	return resp, err
 }
 
 func (db *Database) AncientSize(kind string) (uint64, error) {
-	panic("not supported")
+	var resp uint64
+	err := db.call(&resp, "ancientSize", "", kind)
+	return resp, err
 }
 
 func (db *Database) ReadAncients(fn func(op ethdb.AncientReaderOp) error) (err error) {
@@ -87,27 +167,32 @@ func (db *Database) ReadAncients(fn func(op ethdb.AncientReaderOp) error) (err e
 }
 
 func (db *Database) Put(key []byte, value []byte) error {
-	panic("not supported")
+	return db.write([]BatchOp{{Key: key, Value: value}})
 }
 
 func (db *Database) Delete(key []byte) error {
-	panic("not supported")
+	return db.write([]BatchOp{{Key: key, Delete: true}})
 }
 
 func (db *Database) DeleteRange(start, end []byte) error {
-	panic("not supported")
+	return db.call(nil, "deleteRange", "", hexutil.Bytes(start), hexutil.Bytes(end))
+}
+
+// write sends the given operations to the remote database to be applied atomically.
+func (db *Database) write(ops []BatchOp) error {
+	return db.call(nil, "write", "", ops)
 }
 
 func (db *Database) ModifyAncients(f func(ethdb.AncientWriteOp) error) (int64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
 func (db *Database) TruncateHead(n uint64) (uint64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
 func (db *Database) TruncateTail(n uint64) (uint64, error) {
-	panic("not supported")
+	return 0, errNotSupported
 }
 
 func (db *Database) Sync() error {
@@ -115,23 +200,32 @@ func (db *Database) Sync() error {
 }
 
 func (db *Database) NewBatch() ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
 func (db *Database) NewBatchWithSize(size int) ethdb.Batch {
-	panic("not supported")
+	return &batch{db: db}
 }
 
 func (db *Database) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
-	panic("not supported")
+	return &iterator{
+		db:     db,
+		prefix: prefix,
+		next:   start,
+		more:   true,
+	}
 }
 
 func (db *Database) Stat() (string, error) {
+	var resp string
+	if err := db.call(&resp, "stat", ""); err != errNotSupported {
+		return resp, err
+	}
 	return "", nil
 }
 
 func (db *Database) AncientDatadir() (string, error) {
-	panic("not supported")
+	return "", errNotSupported
 }
 
 func (db *Database) Compact(start []byte, limit []byte) error {
@@ -149,3 +243,116 @@ func New(client *rpc.Client) ethdb.Database {
 	}
 	return &Database{remote: client}
 }
+
+// batch is a write-only batch that sends the accumulated operations to the
+// remote database in a single request when Write is called.
+type batch struct {
+	db   *Database
+	ops  []BatchOp
+	size int
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, BatchOp{Key: common.CopyBytes(key), Value: common.CopyBytes(value)})
+	b.size += len(key) + len(value)
+	return nil
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, BatchOp{Key: common.CopyBytes(key), Delete: true})
+	b.size += len(key)
+	return nil
+}
+
+func (b *batch) ValueSize() int {
+	return b.size
+}
+
+func (b *batch) Write() error {
+	return b.db.write(b.ops)
+}
+
+func (b *batch) Reset() {
+	b.ops = b.ops[:0]
+	b.size = 0
+}
+
+func (b *batch) Replay(w ethdb.KeyValueWriter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+		} else {
+			if err := w.Put(op.Key, op.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// iterator pages through the key-value pairs of the remote database. The pages
+// are retrieved on demand, hence the iteration is not a consistent snapshot.
+type iterator struct {
+	db     *Database
+	prefix []byte
+	next   []byte // Start position of the next page, relative to the prefix
+	more   bool   // Whether there are more pages to retrieve
+
+	keys   []hexutil.Bytes
+	values []hexutil.Bytes
+	index  int
+	err    error
+}
+
+func (it *iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.keys) {
+		it.index++
+		return true
+	}
+	if !it.more {
+		it.keys, it.values = nil, nil
+		return false
+	}
+	var page IteratePage
+	if err := it.db.call(&page, "iterate", "", hexutil.Bytes(it.prefix), hexutil.Bytes(it.next), maxIteratePairs); err != nil {
+		it.err = err
+		it.keys, it.values = nil, nil
+		return false
+	}
+	it.keys, it.values, it.index, it.more = page.Keys, page.Values, 0, page.More
+	if len(it.keys) == 0 {
+		return false
+	}
+	// Continue right after the last key of the page
+	last := it.keys[len(it.keys)-1]
+	it.next = append(common.CopyBytes(last[len(it.prefix):]), 0)
+	return true
+}
+
+func (it *iterator) Error() error {
+	return it.err
+}
+
+func (it *iterator) Key() []byte {
+	if it.index >= len(it.keys) {
+		return nil
+	}
+	return it.keys[it.index]
+}
+
+func (it *iterator) Value() []byte {
+	if it.index >= len(it.values) {
+		return nil
+	}
+	return it.values[it.index]
+}
+
+func (it *iterator) Release() {
+	it.keys, it.values = nil, nil
+	it.more = false
+}
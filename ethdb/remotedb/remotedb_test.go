@@ -0,0 +1,125 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package remotedb
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/dbtest"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newTestDatabase exports the given database over an in-process RPC server and
+// returns a client connected to it.
+func newTestDatabase(db ethdb.Database, writable bool) *Database {
+	server := rpc.NewServer()
+	server.RegisterName("remotedb", NewAPI(db, writable))
+	return New(rpc.DialInProc(server)).(*Database)
+}
+
+func TestRemoteDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			return newTestDatabase(rawdb.NewMemoryDatabase(), true)
+		})
+	})
+}
+
+func TestRemoteDBPaging(t *testing.T) {
+	backend := rawdb.NewMemoryDatabase()
+	for i := 0; i < 3*maxIteratePairs+10; i++ {
+		backend.Put([]byte(fmt.Sprintf("a%06d", i)), []byte{byte(i)})
+	}
+	backend.Put([]byte("b"), []byte{0xff})
+
+	db := newTestDatabase(backend, false)
+	defer db.Close()
+
+	// Iterate over multiple pages, the entries outside the prefix must be skipped
+	it := db.NewIterator([]byte("a"), []byte("000005"))
+	defer it.Release()
+
+	count := 5
+	for it.Next() {
+		if want := fmt.Sprintf("a%06d", count); string(it.Key()) != want {
+			t.Fatalf("Unexpected key: have %s, want %s", it.Key(), want)
+		}
+		count++
+	}
+	if err := it.Error(); err != nil {
+		t.Fatalf("Iteration failed: %v", err)
+	}
+	if count != 3*maxIteratePairs+10 {
+		t.Fatalf("Unexpected number of iterated entries: %d", count)
+	}
+	// Batched reads should report the missing keys as nil
+	keys := [][]byte{[]byte("a000001"), []byte("missing"), []byte("b")}
+	values, err := db.GetMany(keys)
+	if err != nil {
+		t.Fatalf("Failed to read batch: %v", err)
+	}
+	if !bytes.Equal(values[0], []byte{1}) || values[1] != nil || !bytes.Equal(values[2], []byte{0xff}) {
+		t.Fatalf("Unexpected batch values: %v", values)
+	}
+	// Writes should be rejected by a read-only remote database, leaving it untouched
+	if err := db.Put([]byte("c"), []byte{1}); err == nil || err.Error() != errReadOnly.Error() {
+		t.Fatalf("Unexpected error for write to read-only database: %v", err)
+	}
+	if err := db.DeleteRange([]byte("a"), []byte("b")); err == nil || err.Error() != errReadOnly.Error() {
+		t.Fatalf("Unexpected error for range deletion: %v", err)
+	}
+	batch := db.NewBatch()
+	batch.Delete([]byte("b"))
+	if err := batch.Write(); err == nil || err.Error() != errReadOnly.Error() {
+		t.Fatalf("Unexpected error for batch write: %v", err)
+	}
+	if has, _ := backend.Has([]byte("b")); !has {
+		t.Fatal("Remote database modified by a rejected write")
+	}
+}
+
+func TestRemoteDBLegacy(t *testing.T) {
+	// Only export the legacy debug methods
+	server := rpc.NewServer()
+	server.RegisterName("debug", &legacyAPI{db: rawdb.NewMemoryDatabase()})
+	db := New(rpc.DialInProc(server))
+	defer db.Close()
+
+	if _, err := db.Get([]byte("key")); err == nil {
+		t.Fatal("Missing key retrieved")
+	}
+	if has, err := db.Has([]byte("key")); has || err == nil {
+		t.Fatalf("Unexpected key presence: %t, %v", has, err)
+	}
+	if err := db.Put([]byte("key"), []byte("value")); err != errNotSupported {
+		t.Fatalf("Unexpected error for unsupported write: %v", err)
+	}
+}
+
+// legacyAPI is a stand-in of the debug namespace of older nodes.
+type legacyAPI struct {
+	db ethdb.Database
+}
+
+func (api *legacyAPI) DbGet(key hexutil.Bytes) (hexutil.Bytes, error) {
+	return api.db.Get(key)
+}
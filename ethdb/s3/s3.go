@@ -0,0 +1,286 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package s3 implements the remote tier of the ancient store on top of an S3
+// compatible object storage, e.g. AWS S3 or MinIO.
+//
+// Only the few object operations needed by the freezer are supported, requests
+// are signed with AWS signature V4 and use path-style addressing, which is what
+// most of the S3 compatible services expect.
+package s3
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// defaultRegion is the signing region used if none is configured.
+	defaultRegion = "us-east-1"
+
+	// maxRetries is the maximum number of attempts of a request failed with
+	// a network error or a server side error.
+	maxRetries = 3
+
+	// emptyPayloadHash is the SHA256 hash of an empty request body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// retryDelay is the delay before the first retry of a failed request, which
+// grows linearly with the attempts.
+var retryDelay = time.Second
+
+// requestTimeout bounds the object reads, lookups and deletions including their
+// retries, so a stalled storage can't block the freezer forever. Uploads are
+// bounded by the context of the caller and the timeouts of the transport.
+var requestTimeout = time.Minute
+
+// newTransport creates the HTTP transport of the bucket clients, timing out the
+// connection setup and the wait for the response headers.
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	return transport
+}
+
+// Bucket is a client of a bucket in an S3 compatible object storage. All the
+// objects are stored under the configured key prefix.
+type Bucket struct {
+	endpoint *url.URL // Service endpoint, the bucket is addressed in the path
+	bucket   string   // Name of the bucket
+	prefix   string   // Key prefix of all the objects
+	region   string   // Region used for signing the requests
+
+	creds  aws.CredentialsProvider
+	signer *v4.Signer
+	client *http.Client
+}
+
+// New creates a bucket client from the given URL of the following format:
+//
+//	s3://<bucket>[/<prefix>][?endpoint=<url>&region=<region>]
+//
+// The endpoint defaults to the AWS S3 endpoint of the region. The credentials
+// are resolved from the standard AWS sources, e.g. the AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY environment variables or the shared credentials file.
+func New(rawurl string) (*Bucket, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "s3" {
+		return nil, fmt.Errorf("invalid scheme %q, want s3", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("missing bucket name")
+	}
+	region := u.Query().Get("region")
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = defaultRegion
+	}
+	endpoint := u.Query().Get("endpoint")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	ep, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %v", err)
+	}
+	if ep.Scheme != "http" && ep.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint scheme %q", ep.Scheme)
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+	if err != nil {
+		return nil, err
+	}
+	return &Bucket{
+		endpoint: ep,
+		bucket:   u.Host,
+		prefix:   strings.Trim(u.Path, "/"),
+		region:   region,
+		creds:    cfg.Credentials,
+		signer: v4.NewSigner(func(o *v4.SignerOptions) {
+			o.DisableURIPathEscaping = true
+		}),
+		client: &http.Client{Transport: newTransport()},
+	}, nil
+}
+
+// String implements fmt.Stringer, returning the location of the objects.
+func (b *Bucket) String() string {
+	return fmt.Sprintf("s3://%s/%s (%s)", b.bucket, b.prefix, b.endpoint.Host)
+}
+
+// Upload stores the content of the given reader under the given object name.
+func (b *Bucket) Upload(ctx context.Context, name string, r io.ReaderAt, size int64) error {
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, io.NewSectionReader(r, 0, size)); err != nil {
+		return err
+	}
+	hash := hex.EncodeToString(hasher.Sum(nil))
+
+	res, err := b.do(ctx, http.MethodPut, name, hash, size, func() io.ReadCloser {
+		return io.NopCloser(io.NewSectionReader(r, 0, size))
+	}, nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// ReadAt reads len(p) bytes of the named object, starting at the given offset.
+func (b *Bucket) ReadAt(name string, p []byte, off int64) error {
+	if len(p) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	header := http.Header{"Range": []string{fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1)}}
+	res, err := b.do(ctx, http.MethodGet, name, emptyPayloadHash, 0, nil, header)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusPartialContent && off != 0 {
+		return fmt.Errorf("s3: ranged read of %s not supported", name)
+	}
+	if _, err := io.ReadFull(res.Body, p); err != nil {
+		return fmt.Errorf("s3: failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// Size returns the size of the named object. An error wrapping os.ErrNotExist
+// is returned if the object is not present.
+func (b *Bucket) Size(name string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	res, err := b.do(ctx, http.MethodHead, name, emptyPayloadHash, 0, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	res.Body.Close()
+	return res.ContentLength, nil
+}
+
+// Delete removes the named object. Deleting a non-existent object is not an error.
+func (b *Bucket) Delete(name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	res, err := b.do(ctx, http.MethodDelete, name, emptyPayloadHash, 0, nil, nil)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if res != nil {
+		res.Body.Close()
+	}
+	return nil
+}
+
+// do sends a signed request for the named object, retrying the attempts failed
+// due to network or server side errors. The response is returned only if the
+// request is successful, with the body to be closed by the caller.
+func (b *Bucket) do(ctx context.Context, method string, name string, payloadHash string, size int64, body func() io.ReadCloser, header http.Header) (*http.Response, error) {
+	key := path.Join(b.prefix, name)
+	target := b.endpoint.JoinPath(b.bucket, key)
+
+	var err error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		if attempt > 0 {
+			log.Debug("Retrying object storage request", "method", method, "key", key, "attempt", attempt, "err", err)
+			select {
+			case <-time.After(time.Duration(attempt) * retryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, method, target.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if body != nil {
+			req.Body = body()
+			req.ContentLength = size
+		}
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+		var creds aws.Credentials
+		creds, err = b.creds.Retrieve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err = b.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", b.region, time.Now()); err != nil {
+			return nil, err
+		}
+		var res *http.Response
+		res, err = b.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		if res.StatusCode >= 200 && res.StatusCode < 300 {
+			return res, nil
+		}
+		err = responseError(method, key, res)
+		res.Body.Close()
+
+		if res.StatusCode < 500 {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// responseError converts a failed response into an error.
+func responseError(method string, key string, res *http.Response) error {
+	if res.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("s3: %s: %w", key, os.ErrNotExist)
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("s3: %s %s: %s %s", method, key, res.Status, strings.TrimSpace(string(msg)))
+}
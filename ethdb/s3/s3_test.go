@@ -0,0 +1,193 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testServer is a minimal stand-in of an S3 compatible object storage, serving
+// the objects of a single bucket from memory.
+type testServer struct {
+	bucket  string
+	objects map[string][]byte
+	fails   int // Number of requests to fail with a server error
+	lock    sync.Mutex
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.fails > 0 {
+		s.fails--
+		http.Error(w, "SlowDown", http.StatusServiceUnavailable)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test/") {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+s.bucket+"/")
+	if !ok {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		hash := sha256.Sum256(data)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+			http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = data
+	case http.MethodGet, http.MethodHead:
+		data, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		if rng := r.Header.Get("Range"); rng != "" {
+			var start, end int
+			if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end); err != nil || start >= len(data) {
+				http.Error(w, "InvalidRange", http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			end = min(end, len(data)-1)
+			w.Header().Set("Content-Length", fmt.Sprint(end-start+1))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(data[start : end+1])
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		if r.Method == http.MethodGet {
+			w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestBucket(t *testing.T) (*Bucket, *testServer) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	backend := &testServer{bucket: "ancients", objects: make(map[string][]byte)}
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	bucket, err := New("s3://ancients/mainnet?region=eu-west-1&endpoint=" + server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+	return bucket, backend
+}
+
+func TestBucket(t *testing.T) {
+	bucket, backend := newTestBucket(t)
+
+	data := make([]byte, 4096)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := bucket.Upload(context.Background(), "bodies.0000.cdat", bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("Failed to upload object: %v", err)
+	}
+	if _, ok := backend.objects["mainnet/bodies.0000.cdat"]; !ok {
+		t.Fatal("Object is not stored under the prefix")
+	}
+	size, err := bucket.Size("bodies.0000.cdat")
+	if err != nil || size != int64(len(data)) {
+		t.Fatalf("Unexpected object size: %d, %v", size, err)
+	}
+	for _, c := range []struct{ off, length int }{{0, 1}, {0, 4096}, {100, 200}, {4000, 96}} {
+		buf := make([]byte, c.length)
+		if err := bucket.ReadAt("bodies.0000.cdat", buf, int64(c.off)); err != nil {
+			t.Fatalf("Failed to read object [%d, %d): %v", c.off, c.off+c.length, err)
+		}
+		if !bytes.Equal(buf, data[c.off:c.off+c.length]) {
+			t.Fatalf("Unexpected object content [%d, %d)", c.off, c.off+c.length)
+		}
+	}
+	// Reading beyond the object should fail
+	if err := bucket.ReadAt("bodies.0000.cdat", make([]byte, 10), 4090); err == nil {
+		t.Fatal("Read beyond the object succeeded")
+	}
+	// Server side errors should be retried
+	defer func(delay time.Duration) { retryDelay = delay }(retryDelay)
+	retryDelay = time.Millisecond
+	backend.fails = maxRetries - 1
+	if _, err := bucket.Size("bodies.0000.cdat"); err != nil {
+		t.Fatalf("Failed to retry request: %v", err)
+	}
+	if err := bucket.Delete("bodies.0000.cdat"); err != nil {
+		t.Fatalf("Failed to delete object: %v", err)
+	}
+	if _, err := bucket.Size("bodies.0000.cdat"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Unexpected error for deleted object: %v", err)
+	}
+	if err := bucket.Delete("bodies.0000.cdat"); err != nil {
+		t.Fatalf("Failed to delete non-existent object: %v", err)
+	}
+}
+
+// Tests that requests to a stalled storage time out.
+func TestBucketTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { requestTimeout = timeout }(requestTimeout)
+	requestTimeout = 100 * time.Millisecond
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	stall := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-stall
+	}))
+	defer server.Close()
+	defer close(stall)
+
+	bucket, err := New("s3://ancients/mainnet?region=eu-west-1&endpoint=" + server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create bucket: %v", err)
+	}
+	if err := bucket.ReadAt("bodies.0000.cdat", make([]byte, 10), 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Unexpected read error: %v", err)
+	}
+	if _, err := bucket.Size("bodies.0000.cdat"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Unexpected size error: %v", err)
+	}
+	if err := bucket.Delete("bodies.0000.cdat"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Unexpected delete error: %v", err)
+	}
+}
@@ -0,0 +1,533 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package grpc provides the eth API over gRPC, gRPC-Web and the Connect protocol,
+// as defined by the EthService in ethclient/ethpb.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/ethclient/ethpb"
+	"github.com/ethereum/go-ethereum/ethclient/ethpb/ethpbconnect"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrorDataKey is the metadata key carrying the data of an execution error, such
+// as the hex encoded revert reason of a failed call.
+const ErrorDataKey = "Eth-Error-Data"
+
+// service implements the EthService on top of the backend of the JSON-RPC API.
+type service struct {
+	backend ethapi.Backend
+	filters *filters.FilterSystem
+	events  *filters.EventSystem
+}
+
+// New constructs a new gRPC service instance and registers it on the HTTP server
+// of the node.
+func New(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) error {
+	_, err := newHandler(stack, backend, filterSystem, cors, vhosts)
+	return err
+}
+
+// newHandler returns the service answering gRPC and Connect requests.
+func newHandler(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cors, vhosts []string) (*service, error) {
+	// The service doesn't go through the JSON-RPC server, so the access controls
+	// of the HTTP endpoint would not apply to it.
+	config := stack.Config()
+	switch {
+	case config.APIKeyFile != "":
+		return nil, errors.New("gRPC service can't be enabled with API keys")
+	case config.RPCRateLimit.Enabled():
+		return nil, errors.New("gRPC service can't be enabled with RPC rate limits")
+	case config.HTTPMethods.Enabled():
+		return nil, errors.New("gRPC service can't be enabled with an HTTP method filter")
+	}
+	if backend == nil || filterSystem == nil {
+		return nil, errors.New("gRPC service requires a backend and filter system")
+	}
+	s := &service{
+		backend: backend,
+		filters: filterSystem,
+		events:  filters.NewEventSystem(filterSystem),
+	}
+	path, h := ethpbconnect.NewEthServiceHandler(s)
+	stack.RegisterGRPCHandler("gRPC", path, node.NewGRPCHandlerStack(streamHandler(h), cors, vhosts))
+	return s, nil
+}
+
+// responseWriterKey is the context key of the response writer of a stream.
+type responseWriterKey struct{}
+
+// streamHandler lifts the write deadline of the HTTP server for the subscription
+// streams, which would otherwise be cut off after the timeout, and makes the
+// response writer available to flush the response headers early.
+func streamHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ethpbconnect.EthServiceSubscribeNewHeadsProcedure, ethpbconnect.EthServiceSubscribeLogsProcedure:
+			http.NewResponseController(w).SetWriteDeadline(time.Time{})
+			r = r.WithContext(context.WithValue(r.Context(), responseWriterKey{}, w))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// flushHeaders sends the response headers of a stream, which would otherwise be
+// held back until the first message. Clients wait for them before returning the
+// stream, so this signals that the subscription is installed.
+func flushHeaders(ctx context.Context) {
+	if w, ok := ctx.Value(responseWriterKey{}).(http.ResponseWriter); ok {
+		http.NewResponseController(w).Flush()
+	}
+}
+
+// toConnectError converts an error of the backend into a Connect error, carrying
+// the data of JSON-RPC errors in the metadata.
+func toConnectError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var cerr *connect.Error
+	if errors.As(err, &cerr) {
+		return cerr
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return connect.NewError(connect.CodeDeadlineExceeded, err)
+	}
+	if errors.Is(err, context.Canceled) {
+		return connect.NewError(connect.CodeCanceled, err)
+	}
+	code := connect.CodeUnknown
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3 {
+		code = connect.CodeAborted // execution reverted
+	}
+	cerr = connect.NewError(code, err)
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			cerr.Meta().Set(ErrorDataKey, data)
+		}
+	}
+	return cerr
+}
+
+// invalidArgument wraps a malformed request into a Connect error.
+func invalidArgument(err error) error {
+	return connect.NewError(connect.CodeInvalidArgument, err)
+}
+
+// notFound returns the error for an unknown chain object.
+func notFound(what string) error {
+	return connect.NewError(connect.CodeNotFound, fmt.Errorf("%s not found", what))
+}
+
+func (s *service) ChainId(ctx context.Context, req *connect.Request[ethpb.ChainIdRequest]) (*connect.Response[ethpb.ChainIdResponse], error) {
+	return connect.NewResponse(&ethpb.ChainIdResponse{ChainId: s.backend.ChainConfig().ChainID.Uint64()}), nil
+}
+
+func (s *service) BlockNumber(ctx context.Context, req *connect.Request[ethpb.BlockNumberRequest]) (*connect.Response[ethpb.BlockNumberResponse], error) {
+	return connect.NewResponse(&ethpb.BlockNumberResponse{Number: s.backend.CurrentHeader().Number.Uint64()}), nil
+}
+
+func (s *service) GetHeader(ctx context.Context, req *connect.Request[ethpb.GetHeaderRequest]) (*connect.Response[ethpb.Header], error) {
+	ref, err := req.Msg.GetBlock().ToBlockNumberOrHash()
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	header, err := s.backend.HeaderByNumberOrHash(ctx, ref)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if header == nil {
+		return nil, notFound("header")
+	}
+	msg, err := ethpb.NewHeader(header)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(msg), nil
+}
+
+func (s *service) GetBlock(ctx context.Context, req *connect.Request[ethpb.GetBlockRequest]) (*connect.Response[ethpb.Block], error) {
+	ref, err := req.Msg.GetBlock().ToBlockNumberOrHash()
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	block, err := s.backend.BlockByNumberOrHash(ctx, ref)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if block == nil {
+		return nil, notFound("block")
+	}
+	msg, err := ethpb.NewBlock(block)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(msg), nil
+}
+
+func (s *service) GetTransaction(ctx context.Context, req *connect.Request[ethpb.GetTransactionRequest]) (*connect.Response[ethpb.Transaction], error) {
+	hash, err := ethpb.ToHash(req.Msg.GetHash())
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	found, tx, blockHash, blockNumber, index, err := s.backend.GetTransaction(ctx, hash)
+	if !found {
+		if tx = s.backend.GetPoolTransaction(hash); tx != nil {
+			return s.newTransaction(tx, s.backend.CurrentHeader(), nil, 0)
+		}
+		if err != nil {
+			return nil, toConnectError(ethapi.NewTxIndexingError())
+		}
+		return nil, notFound("transaction")
+	}
+	header, err := s.backend.HeaderByHash(ctx, blockHash)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if header == nil || header.Number.Uint64() != blockNumber {
+		return nil, notFound("block")
+	}
+	return s.newTransaction(tx, header, blockHash.Bytes(), index)
+}
+
+// newTransaction assembles the response of a transaction lookup, leaving the
+// block fields empty for pooled transactions.
+func (s *service) newTransaction(tx *types.Transaction, header *types.Header, blockHash []byte, index uint64) (*connect.Response[ethpb.Transaction], error) {
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	signer := types.MakeSigner(s.backend.ChainConfig(), header.Number, header.Time)
+	from, _ := types.Sender(signer, tx)
+
+	msg := &ethpb.Transaction{Hash: tx.Hash().Bytes(), Data: data, From: from.Bytes(), Pending: blockHash == nil}
+	if blockHash != nil {
+		msg.BlockHash = blockHash
+		msg.BlockNumber = header.Number.Uint64()
+		msg.Index = index
+	}
+	return connect.NewResponse(msg), nil
+}
+
+func (s *service) GetTransactionReceipt(ctx context.Context, req *connect.Request[ethpb.GetTransactionReceiptRequest]) (*connect.Response[ethpb.Receipt], error) {
+	hash, err := ethpb.ToHash(req.Msg.GetHash())
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	found, _, blockHash, _, index, err := s.backend.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, toConnectError(ethapi.NewTxIndexingError())
+	}
+	if !found {
+		return nil, notFound("receipt")
+	}
+	receipts, err := s.backend.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if uint64(len(receipts)) <= index {
+		return nil, notFound("receipt")
+	}
+	return connect.NewResponse(ethpb.NewReceipt(receipts[index])), nil
+}
+
+func (s *service) GetBlockReceipts(ctx context.Context, req *connect.Request[ethpb.GetBlockReceiptsRequest]) (*connect.Response[ethpb.GetBlockReceiptsResponse], error) {
+	ref, err := req.Msg.GetBlock().ToBlockNumberOrHash()
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	block, err := s.backend.BlockByNumberOrHash(ctx, ref)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if block == nil {
+		return nil, notFound("block")
+	}
+	receipts, err := s.backend.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if len(receipts) != len(block.Transactions()) {
+		return nil, toConnectError(fmt.Errorf("receipts length mismatch: %d vs %d", len(block.Transactions()), len(receipts)))
+	}
+	msg := &ethpb.GetBlockReceiptsResponse{Receipts: make([]*ethpb.Receipt, len(receipts))}
+	for i, receipt := range receipts {
+		msg.Receipts[i] = ethpb.NewReceipt(receipt)
+	}
+	return connect.NewResponse(msg), nil
+}
+
+func (s *service) GetLogs(ctx context.Context, req *connect.Request[ethpb.FilterQuery]) (*connect.Response[ethpb.GetLogsResponse], error) {
+	query, err := req.Msg.ToFilterQuery()
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	var filter *filters.Filter
+	if query.BlockHash != nil {
+		filter = s.filters.NewBlockFilter(*query.BlockHash, query.Addresses, query.Topics)
+	} else {
+		begin, end := rpc.LatestBlockNumber.Int64(), rpc.LatestBlockNumber.Int64()
+		if query.FromBlock != nil {
+			begin = query.FromBlock.Int64()
+		}
+		if query.ToBlock != nil {
+			end = query.ToBlock.Int64()
+		}
+		if begin > 0 && end > 0 && begin > end {
+			return nil, invalidArgument(errors.New("invalid block range"))
+		}
+		filter = s.filters.NewRangeFilter(begin, end, query.Addresses, query.Topics)
+	}
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	msg := &ethpb.GetLogsResponse{Logs: make([]*ethpb.Log, len(logs))}
+	for i, log := range logs {
+		msg.Logs[i] = ethpb.NewLog(log)
+	}
+	return connect.NewResponse(msg), nil
+}
+
+func (s *service) GetBalance(ctx context.Context, req *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetBalanceResponse], error) {
+	addr, ref, err := accountRequest(req.Msg.GetAddress(), req.Msg.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	state, _, err := s.backend.StateAndHeaderByNumberOrHash(ctx, ref)
+	if state == nil || err != nil {
+		return nil, stateError(err)
+	}
+	balance := state.GetBalance(addr)
+	return connect.NewResponse(&ethpb.GetBalanceResponse{Balance: ethpb.NewBig(balance.ToBig())}), toConnectError(state.Error())
+}
+
+func (s *service) GetNonce(ctx context.Context, req *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetNonceResponse], error) {
+	addr, ref, err := accountRequest(req.Msg.GetAddress(), req.Msg.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	// Ask transaction pool for the nonce which includes pending transactions
+	if number, ok := ref.Number(); ok && number == rpc.PendingBlockNumber {
+		nonce, err := s.backend.GetPoolNonce(ctx, addr)
+		if err != nil {
+			return nil, toConnectError(err)
+		}
+		return connect.NewResponse(&ethpb.GetNonceResponse{Nonce: nonce}), nil
+	}
+	state, _, err := s.backend.StateAndHeaderByNumberOrHash(ctx, ref)
+	if state == nil || err != nil {
+		return nil, stateError(err)
+	}
+	return connect.NewResponse(&ethpb.GetNonceResponse{Nonce: state.GetNonce(addr)}), toConnectError(state.Error())
+}
+
+func (s *service) GetCode(ctx context.Context, req *connect.Request[ethpb.GetAccountRequest]) (*connect.Response[ethpb.GetCodeResponse], error) {
+	addr, ref, err := accountRequest(req.Msg.GetAddress(), req.Msg.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	state, _, err := s.backend.StateAndHeaderByNumberOrHash(ctx, ref)
+	if state == nil || err != nil {
+		return nil, stateError(err)
+	}
+	return connect.NewResponse(&ethpb.GetCodeResponse{Code: state.GetCode(addr)}), toConnectError(state.Error())
+}
+
+func (s *service) GetStorageAt(ctx context.Context, req *connect.Request[ethpb.GetStorageAtRequest]) (*connect.Response[ethpb.GetStorageAtResponse], error) {
+	addr, ref, err := accountRequest(req.Msg.GetAddress(), req.Msg.GetBlock())
+	if err != nil {
+		return nil, err
+	}
+	key, err := ethpb.ToHash(req.Msg.GetKey())
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	state, _, err := s.backend.StateAndHeaderByNumberOrHash(ctx, ref)
+	if state == nil || err != nil {
+		return nil, stateError(err)
+	}
+	value := state.GetState(addr, key)
+	return connect.NewResponse(&ethpb.GetStorageAtResponse{Value: value.Bytes()}), toConnectError(state.Error())
+}
+
+// accountRequest validates the address and block reference of a state query.
+func accountRequest(address []byte, block *ethpb.BlockRef) (common.Address, rpc.BlockNumberOrHash, error) {
+	addr, err := ethpb.ToAddress(address)
+	if err != nil {
+		return common.Address{}, rpc.BlockNumberOrHash{}, invalidArgument(err)
+	}
+	ref, err := block.ToBlockNumberOrHash()
+	if err != nil {
+		return common.Address{}, rpc.BlockNumberOrHash{}, invalidArgument(err)
+	}
+	return addr, ref, nil
+}
+
+// stateError converts the failure to open the state of a block.
+func stateError(err error) error {
+	if err == nil {
+		return notFound("block")
+	}
+	return toConnectError(err)
+}
+
+// callArgs converts a call request into the arguments of the JSON-RPC API.
+func callArgs(req *ethpb.CallRequest) (ethapi.TransactionArgs, rpc.BlockNumberOrHash, error) {
+	msg, err := req.ToCallMsg()
+	if err != nil {
+		return ethapi.TransactionArgs{}, rpc.BlockNumberOrHash{}, invalidArgument(err)
+	}
+	ref, err := req.GetBlock().ToBlockNumberOrHash()
+	if err != nil {
+		return ethapi.TransactionArgs{}, rpc.BlockNumberOrHash{}, invalidArgument(err)
+	}
+	args := ethapi.TransactionArgs{
+		To:                   msg.To,
+		GasPrice:             (*hexutil.Big)(msg.GasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(msg.GasFeeCap),
+		MaxPriorityFeePerGas: (*hexutil.Big)(msg.GasTipCap),
+		Value:                (*hexutil.Big)(msg.Value),
+		Input:                (*hexutil.Bytes)(&msg.Data),
+	}
+	if len(req.GetFrom()) > 0 {
+		args.From = &msg.From
+	}
+	if req.Gas != nil {
+		args.Gas = (*hexutil.Uint64)(&msg.Gas)
+	}
+	if msg.AccessList != nil {
+		args.AccessList = &msg.AccessList
+	}
+	return args, ref, nil
+}
+
+func (s *service) Call(ctx context.Context, req *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.CallResponse], error) {
+	args, ref, err := callArgs(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	result, err := ethapi.DoCall(ctx, s.backend, args, ref, nil, nil, s.backend.RPCEVMTimeout(), s.backend.RPCGasCap())
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	if revert := result.Revert(); len(revert) > 0 {
+		cerr := connect.NewError(connect.CodeAborted, result.Err)
+		cerr.Meta().Set(ErrorDataKey, hexutil.Encode(revert))
+		return nil, cerr
+	}
+	if result.Err != nil {
+		return nil, connect.NewError(connect.CodeAborted, result.Err)
+	}
+	return connect.NewResponse(&ethpb.CallResponse{Data: result.Return()}), nil
+}
+
+func (s *service) EstimateGas(ctx context.Context, req *connect.Request[ethpb.CallRequest]) (*connect.Response[ethpb.EstimateGasResponse], error) {
+	args, ref, err := callArgs(req.Msg)
+	if err != nil {
+		return nil, err
+	}
+	if req.Msg.GetBlock() == nil {
+		ref = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	}
+	gas, err := ethapi.DoEstimateGas(ctx, s.backend, args, ref, nil, nil, s.backend.RPCGasCap())
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&ethpb.EstimateGasResponse{Gas: uint64(gas)}), nil
+}
+
+func (s *service) SendRawTransaction(ctx context.Context, req *connect.Request[ethpb.SendRawTransactionRequest]) (*connect.Response[ethpb.SendRawTransactionResponse], error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(req.Msg.GetData()); err != nil {
+		return nil, invalidArgument(err)
+	}
+	hash, err := ethapi.SubmitTransaction(ctx, s.backend, tx)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	return connect.NewResponse(&ethpb.SendRawTransactionResponse{Hash: hash.Bytes()}), nil
+}
+
+func (s *service) SubscribeNewHeads(ctx context.Context, req *connect.Request[ethpb.SubscribeNewHeadsRequest], stream *connect.ServerStream[ethpb.Header]) error {
+	headers := make(chan *types.Header)
+	sub := s.events.SubscribeNewHeads(headers)
+	defer sub.Unsubscribe()
+	flushHeaders(ctx)
+
+	for {
+		select {
+		case header := <-headers:
+			msg, err := ethpb.NewHeader(header)
+			if err != nil {
+				return toConnectError(err)
+			}
+			if err := stream.Send(msg); err != nil {
+				return err
+			}
+		case <-sub.Err():
+			return connect.NewError(connect.CodeUnavailable, errors.New("subscription closed"))
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *service) SubscribeLogs(ctx context.Context, req *connect.Request[ethpb.FilterQuery], stream *connect.ServerStream[ethpb.Log]) error {
+	query, err := req.Msg.ToFilterQuery()
+	if err != nil {
+		return invalidArgument(err)
+	}
+	logs := make(chan []*types.Log)
+	sub, err := s.events.SubscribeLogs(ethereum.FilterQuery(query), logs)
+	if err != nil {
+		return invalidArgument(err)
+	}
+	defer sub.Unsubscribe()
+	flushHeaders(ctx)
+
+	for {
+		select {
+		case batch := <-logs:
+			for _, log := range batch {
+				if err := stream.Send(ethpb.NewLog(log)); err != nil {
+					return err
+				}
+			}
+		case <-sub.Err():
+			return connect.NewError(connect.CodeUnavailable, errors.New("subscription closed"))
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/ethclient/ethpb"
+	"github.com/ethereum/go-ethereum/ethclient/ethpb/ethpbconnect"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/net/http2"
+)
+
+var (
+	testKey, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddr     = crypto.PubkeyToAddress(testKey.PublicKey)
+	testEmitter  = common.HexToAddress("0x0000000000000000000000000000000000000e01")
+	testReverter = common.HexToAddress("0x0000000000000000000000000000000000000e02")
+)
+
+// newTestService starts a node serving the gRPC service on top of a chain with
+// two blocks, each calling a contract emitting an empty log. The second block is
+// returned for importing it later.
+func newTestService(t *testing.T) (*node.Node, *eth.Ethereum, []*types.Block) {
+	stack, err := node.New(&node.Config{
+		HTTPHost:         "127.0.0.1",
+		HTTPPort:         0,
+		HTTPTimeouts:     node.DefaultConfig.HTTPTimeouts,
+		GRPCVirtualHosts: []string{"*"},
+	})
+	if err != nil {
+		t.Fatalf("could not create node: %v", err)
+	}
+	t.Cleanup(func() { stack.Close() })
+
+	genesis := &core.Genesis{
+		Config:     params.AllEthashProtocolChanges,
+		GasLimit:   11500000,
+		Difficulty: big.NewInt(1048576),
+		Alloc: types.GenesisAlloc{
+			testAddr: {Balance: big.NewInt(params.Ether)},
+			// PUSH1 0 PUSH1 0 LOG0
+			testEmitter: {Code: []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG0)}},
+			// PUSH1 1 PUSH1 0 REVERT
+			testReverter: {Code: []byte{byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.REVERT)}},
+		},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	backend, err := eth.New(stack, &ethconfig.Config{
+		Genesis:        genesis,
+		NetworkId:      1337,
+		TrieCleanCache: 5,
+		TrieDirtyCache: 5,
+		TrieTimeout:    60 * time.Minute,
+		SnapshotCache:  5,
+		RPCGasCap:      1000000,
+		RPCTxFeeCap:    1,
+		StateScheme:    rawdb.HashScheme,
+	})
+	if err != nil {
+		t.Fatalf("could not create eth backend: %v", err)
+	}
+	signer := types.LatestSigner(genesis.Config)
+	chain, _ := core.GenerateChain(genesis.Config, backend.BlockChain().Genesis(), ethash.NewFaker(), backend.ChainDb(), 2, func(i int, gen *core.BlockGen) {
+		tx, _ := types.SignNewTx(testKey, signer, &types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &testEmitter,
+			Gas:      50000,
+			GasPrice: gen.BaseFee(),
+		})
+		gen.AddTx(tx)
+	})
+	if _, err := backend.BlockChain().InsertChain(chain[:1]); err != nil {
+		t.Fatalf("could not import blocks: %v", err)
+	}
+	filterSystem := filters.NewFilterSystem(backend.APIBackend, filters.Config{})
+	if err := New(stack, backend.APIBackend, filterSystem, nil, []string{"*"}); err != nil {
+		t.Fatalf("could not create grpc service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("could not start node: %v", err)
+	}
+	return stack, backend, chain
+}
+
+// Tests that the service refuses to start if access controls are configured on
+// the HTTP endpoint, which it would bypass.
+func TestAccessControls(t *testing.T) {
+	configs := []node.Config{
+		{APIKeyFile: "apikeys.json"},
+		{RPCRateLimit: rpc.RateLimitConfig{Default: rpc.RateLimit{Rate: 10}}},
+		{HTTPMethods: rpc.MethodFilter{Deny: []string{"eth_call"}}},
+	}
+	for i, config := range configs {
+		stack, err := node.New(&config)
+		if err != nil {
+			t.Fatalf("test %d: could not create node: %v", i, err)
+		}
+		if err := New(stack, nil, nil, nil, nil); err == nil || !strings.Contains(err.Error(), "can't be enabled") {
+			t.Errorf("test %d: wrong error: %v", i, err)
+		}
+		stack.Close()
+	}
+}
+
+// Tests the unary calls of the service over the Connect protocol.
+func TestUnary(t *testing.T) {
+	stack, _, chain := newTestService(t)
+	client := ethpbconnect.NewEthServiceClient(http.DefaultClient, stack.HTTPEndpoint())
+	ctx := context.Background()
+
+	number, err := client.BlockNumber(ctx, connect.NewRequest(&ethpb.BlockNumberRequest{}))
+	if err != nil {
+		t.Fatalf("BlockNumber failed: %v", err)
+	}
+	if number.Msg.Number != 1 {
+		t.Errorf("wrong block number: have %d, want 1", number.Msg.Number)
+	}
+	block, err := client.GetBlock(ctx, connect.NewRequest(&ethpb.GetBlockRequest{Block: ethpb.NewBlockHashRef(chain[0].Hash())}))
+	if err != nil {
+		t.Fatalf("GetBlock failed: %v", err)
+	}
+	decoded, err := block.Msg.ToBlock()
+	if err != nil {
+		t.Fatalf("could not decode block: %v", err)
+	}
+	if decoded.Hash() != chain[0].Hash() || len(decoded.Transactions()) != 1 {
+		t.Errorf("wrong block: have %x with %d txs", decoded.Hash(), len(decoded.Transactions()))
+	}
+	_, err = client.GetHeader(ctx, connect.NewRequest(&ethpb.GetHeaderRequest{Block: ethpb.NewBlockNumberRef(big.NewInt(5))}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	txHash := chain[0].Transactions()[0].Hash()
+	tx, err := client.GetTransaction(ctx, connect.NewRequest(&ethpb.GetTransactionRequest{Hash: txHash.Bytes()}))
+	if err != nil {
+		t.Fatalf("GetTransaction failed: %v", err)
+	}
+	if common.BytesToAddress(tx.Msg.From) != testAddr || tx.Msg.BlockNumber != 1 || tx.Msg.Pending {
+		t.Errorf("wrong transaction: %v", tx.Msg)
+	}
+	receipt, err := client.GetTransactionReceipt(ctx, connect.NewRequest(&ethpb.GetTransactionReceiptRequest{Hash: txHash.Bytes()}))
+	if err != nil {
+		t.Fatalf("GetTransactionReceipt failed: %v", err)
+	}
+	if r := receipt.Msg.ToReceipt(); r.Status != types.ReceiptStatusSuccessful || len(r.Logs) != 1 || r.Logs[0].Address != testEmitter {
+		t.Errorf("wrong receipt: %v", receipt.Msg)
+	}
+	logs, err := client.GetLogs(ctx, connect.NewRequest(ethpb.NewFilterQuery(ethereum.FilterQuery{
+		FromBlock: big.NewInt(0),
+		Addresses: []common.Address{testEmitter},
+	})))
+	if err != nil {
+		t.Fatalf("GetLogs failed: %v", err)
+	}
+	if len(logs.Msg.Logs) != 1 || logs.Msg.Logs[0].ToLog().TxHash != txHash {
+		t.Errorf("wrong logs: %v", logs.Msg.Logs)
+	}
+	balance, err := client.GetBalance(ctx, connect.NewRequest(&ethpb.GetAccountRequest{Address: testEmitter.Bytes()}))
+	if err != nil {
+		t.Fatalf("GetBalance failed: %v", err)
+	}
+	if len(balance.Msg.Balance) != 0 {
+		t.Errorf("wrong balance: %x", balance.Msg.Balance)
+	}
+	nonce, err := client.GetNonce(ctx, connect.NewRequest(&ethpb.GetAccountRequest{Address: testAddr.Bytes()}))
+	if err != nil {
+		t.Fatalf("GetNonce failed: %v", err)
+	}
+	if nonce.Msg.Nonce != 1 {
+		t.Errorf("wrong nonce: have %d, want 1", nonce.Msg.Nonce)
+	}
+	// Reverting calls carry the revert data in the metadata
+	_, err = client.Call(ctx, connect.NewRequest(ethpb.NewCallRequest(ethereum.CallMsg{To: &testReverter}, nil)))
+	var cerr *connect.Error
+	if !errors.As(err, &cerr) || cerr.Code() != connect.CodeAborted || cerr.Meta().Get(ErrorDataKey) != "0x00" {
+		t.Errorf("expected revert error, got %v", err)
+	}
+	// Malformed requests are rejected
+	_, err = client.GetCode(ctx, connect.NewRequest(&ethpb.GetAccountRequest{Address: []byte{1}}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("expected invalid argument error, got %v", err)
+	}
+}
+
+// Tests the subscription streams of the service over the gRPC protocol on a
+// cleartext HTTP/2 connection.
+func TestSubscribe(t *testing.T) {
+	stack, backend, chain := newTestService(t)
+	h2c := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, network, addr)
+		},
+	}}
+	client := ethpbconnect.NewEthServiceClient(h2c, stack.HTTPEndpoint(), connect.WithGRPC())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	heads, err := client.SubscribeNewHeads(ctx, connect.NewRequest(&ethpb.SubscribeNewHeadsRequest{}))
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+	defer heads.Close()
+	logs, err := client.SubscribeLogs(ctx, connect.NewRequest(ethpb.NewFilterQuery(ethereum.FilterQuery{Addresses: []common.Address{testEmitter}})))
+	if err != nil {
+		t.Fatalf("SubscribeLogs failed: %v", err)
+	}
+	defer logs.Close()
+
+	// Both subscriptions are installed once the streams are returned
+	if _, err := backend.BlockChain().InsertChain(chain[1:]); err != nil {
+		t.Fatalf("could not import blocks: %v", err)
+	}
+	if !heads.Receive() {
+		t.Fatalf("head stream closed: %v", heads.Err())
+	}
+	header, err := heads.Msg().ToHeader()
+	if err != nil {
+		t.Fatalf("could not decode header: %v", err)
+	}
+	if header.Hash() != chain[1].Hash() {
+		t.Errorf("wrong head: have %x, want %x", header.Hash(), chain[1].Hash())
+	}
+	if !logs.Receive() {
+		t.Fatalf("log stream closed: %v", logs.Err())
+	}
+	if log := logs.Msg().ToLog(); log.BlockHash != chain[1].Hash() || log.Address != testEmitter {
+		t.Errorf("wrong log: %v", logs.Msg())
+	}
+}
@@ -31,7 +31,7 @@ import (
 	"github.com/golang/snappy"
 )
 
-// Builder is used to create Era1 archives of block data.
+// Builder is used to create Era1 and execution era archives of block data.
 //
 // Era1 files are themselves e2store files. For more information on this format,
 // see https://github.com/status-im/nimbus-eth2/blob/stable/docs/e2store.md.
@@ -71,31 +71,62 @@ import (
 //
 // Due to the accumulator size limit of 8192, the maximum number of blocks in
 // an Era1 batch is also 8192.
+//
+// Execution era files (FormatEraE) may start at any block number and group the
+// entries by component instead of by block. Receipts are stored without their
+// bloom filters, and the total difficulty and the accumulator are only present
+// if the archive holds pre-merge blocks:
+//
+//	erae := Version | CompressedHeader* | CompressedBody* | CompressedSlimReceipts* |
+//	        TotalDifficulty* | other-entries* | AccumulatorRoot? | ComponentIndex
+//
+//	CompressedSlimReceipts = { type: [0x0a, 0x00], data: snappyFramed(rlp(slim-receipts)) }
+//	ComponentIndex         = { type: [0x32, 0x67], data: component-index }
+//
+//	slim-receipt    := [tx-type, post-state-or-status, cumulative-gas, logs]
+//	component-index := starting-number | indexes | indexes | ... | component-count | count
+//
+// Every indexes element holds the relative offsets of the header, body,
+// receipts and, if present, total difficulty entries of a block. Block proofs
+// are not written.
 type Builder struct {
 	w        *e2store.Writer
+	format   Format
 	startNum *uint64
-	startTd  *big.Int
 	indexes  []uint64
 	hashes   []common.Hash
 	tds      []*big.Int
 	written  int
 
+	// Compressed entries of execution era archives, written on finalization.
+	headers  [][]byte
+	bodies   [][]byte
+	receipts [][]byte
+
 	buf    *bytes.Buffer
 	snappy *snappy.Writer
 }
 
 // NewBuilder returns a new Builder instance.
 func NewBuilder(w io.Writer) *Builder {
+	return NewBuilderWithFormat(w, FormatEra1)
+}
+
+// NewBuilderWithFormat returns a new Builder instance creating archives of
+// the given format.
+func NewBuilderWithFormat(w io.Writer, format Format) *Builder {
 	buf := bytes.NewBuffer(nil)
 	return &Builder{
 		w:      e2store.NewWriter(w),
+		format: format,
 		buf:    buf,
 		snappy: snappy.NewBufferedWriter(buf),
 	}
 }
 
 // Add writes a compressed block entry and compressed receipts entry to the
-// underlying e2store file.
+// underlying e2store file. In execution era archives, the total difficulty
+// must be given either for all or for none of the blocks.
 func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int) error {
 	eh, err := rlp.EncodeToBytes(block.Header())
 	if err != nil {
@@ -105,7 +136,12 @@ func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int)
 	if err != nil {
 		return err
 	}
-	er, err := rlp.EncodeToBytes(receipts)
+	var er []byte
+	if b.format == FormatEraE {
+		er, err = rlp.EncodeToBytes(toSlimReceipts(receipts))
+	} else {
+		er, err = rlp.EncodeToBytes(receipts)
+	}
 	if err != nil {
 		return err
 	}
@@ -113,7 +149,8 @@ func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int)
 }
 
 // AddRLP writes a compressed block entry and compressed receipts entry to the
-// underlying e2store file.
+// underlying e2store file. The receipts of execution era archives must be in
+// the slim encoding.
 func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash common.Hash, td, difficulty *big.Int) error {
 	// Write Era1 version entry before first block.
 	if b.startNum == nil {
@@ -123,13 +160,17 @@ func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash comm
 		}
 		startNum := number
 		b.startNum = &startNum
-		b.startTd = new(big.Int).Sub(td, difficulty)
 		b.written += n
 	}
-	if len(b.indexes) >= MaxEra1Size {
+	if len(b.hashes) >= MaxEra1Size {
 		return fmt.Errorf("exceeds maximum batch size of %d", MaxEra1Size)
 	}
-
+	if want := *b.startNum + uint64(len(b.hashes)); number != want {
+		return fmt.Errorf("non-contiguous block: have %d, want %d", number, want)
+	}
+	if b.format == FormatEraE {
+		return b.addComponents(header, body, receipts, hash, td)
+	}
 	b.indexes = append(b.indexes, uint64(b.written))
 	b.hashes = append(b.hashes, hash)
 	b.tds = append(b.tds, td)
@@ -156,12 +197,39 @@ func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash comm
 	return nil
 }
 
+// addComponents compresses the entries of a block of an execution era archive,
+// which are only written on finalization, grouped by component.
+func (b *Builder) addComponents(header, body, receipts []byte, hash common.Hash, td *big.Int) error {
+	if len(b.hashes) > 0 && (td != nil) != (len(b.tds) > 0) {
+		return errors.New("total difficulty must be given for all blocks or none")
+	}
+	for _, entry := range []struct {
+		list *[][]byte
+		data []byte
+	}{{&b.headers, header}, {&b.bodies, body}, {&b.receipts, receipts}} {
+		data, err := b.compress(entry.data)
+		if err != nil {
+			return err
+		}
+		*entry.list = append(*entry.list, data)
+	}
+	b.hashes = append(b.hashes, hash)
+	if td != nil {
+		b.tds = append(b.tds, td)
+	}
+	return nil
+}
+
 // Finalize computes the accumulator and block index values, then writes the
-// corresponding e2store entries.
+// corresponding e2store entries. The accumulator root is returned, or the hash
+// of the last block for execution era archives without an accumulator.
 func (b *Builder) Finalize() (common.Hash, error) {
 	if b.startNum == nil {
 		return common.Hash{}, errors.New("finalize called on empty builder")
 	}
+	if b.format == FormatEraE {
+		return b.finalizeComponents()
+	}
 	// Compute accumulator root and write entry.
 	root, err := ComputeAccumulator(b.hashes, b.tds)
 	if err != nil {
@@ -203,21 +271,103 @@ func (b *Builder) Finalize() (common.Hash, error) {
 	return root, nil
 }
 
-// snappyWrite is a small helper to take care snappy encoding and writing an e2store entry.
-func (b *Builder) snappyWrite(typ uint16, in []byte) error {
+// finalizeComponents writes the buffered entries of an execution era archive,
+// followed by the accumulator if the total difficulties are known, and the
+// component index.
+func (b *Builder) finalizeComponents() (common.Hash, error) {
+	var (
+		count      = len(b.hashes)
+		components = componentTotalDifficulty
+		root       = b.hashes[count-1]
+	)
+	if len(b.tds) > 0 {
+		components++
+	}
+	offsets := make([]uint64, count*components)
+
+	write := func(component int, typ uint16, entries [][]byte) error {
+		for i, data := range entries {
+			offsets[i*components+component] = uint64(b.written)
+			n, err := b.w.Write(typ, data)
+			b.written += n
+			if err != nil {
+				return fmt.Errorf("error writing e2store entry: %w", err)
+			}
+		}
+		return nil
+	}
+	if err := write(componentHeader, TypeCompressedHeader, b.headers); err != nil {
+		return common.Hash{}, err
+	}
+	if err := write(componentBody, TypeCompressedBody, b.bodies); err != nil {
+		return common.Hash{}, err
+	}
+	if err := write(componentReceipts, TypeCompressedSlimReceipts, b.receipts); err != nil {
+		return common.Hash{}, err
+	}
+	if len(b.tds) > 0 {
+		tds := make([][]byte, count)
+		for i, td := range b.tds {
+			btd := bigToBytes32(td)
+			tds[i] = btd[:]
+		}
+		if err := write(componentTotalDifficulty, TypeTotalDifficulty, tds); err != nil {
+			return common.Hash{}, err
+		}
+		var err error
+		if root, err = ComputeAccumulator(b.hashes, b.tds); err != nil {
+			return common.Hash{}, fmt.Errorf("error calculating accumulator root: %w", err)
+		}
+		n, err := b.w.Write(TypeAccumulator, root[:])
+		b.written += n
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("error writing accumulator: %w", err)
+		}
+	}
+	// Construct the component index, the offsets are relative to the start of
+	// the index record just like in the block index:
+	// "start | offsets of block 0 | offsets of block 1 | ... | components | count"
+	var (
+		base  = int64(b.written)
+		index = make([]byte, 24+len(offsets)*8)
+	)
+	binary.LittleEndian.PutUint64(index, *b.startNum)
+	for i, offset := range offsets {
+		binary.LittleEndian.PutUint64(index[8+i*8:], uint64(int64(offset)-base))
+	}
+	binary.LittleEndian.PutUint64(index[8+len(offsets)*8:], uint64(components))
+	binary.LittleEndian.PutUint64(index[16+len(offsets)*8:], uint64(count))
+
+	if _, err := b.w.Write(TypeComponentIndex, index); err != nil {
+		return common.Hash{}, fmt.Errorf("unable to write component index: %w", err)
+	}
+	return root, nil
+}
+
+// compress snappy encodes the given entry value.
+func (b *Builder) compress(in []byte) ([]byte, error) {
 	var (
 		buf = b.buf
 		s   = b.snappy
 	)
 	buf.Reset()
 	s.Reset(buf)
-	if _, err := b.snappy.Write(in); err != nil {
-		return fmt.Errorf("error snappy encoding: %w", err)
+	if _, err := s.Write(in); err != nil {
+		return nil, fmt.Errorf("error snappy encoding: %w", err)
 	}
 	if err := s.Flush(); err != nil {
-		return fmt.Errorf("error flushing snappy encoding: %w", err)
+		return nil, fmt.Errorf("error flushing snappy encoding: %w", err)
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// snappyWrite is a small helper to take care snappy encoding and writing an e2store entry.
+func (b *Builder) snappyWrite(typ uint16, in []byte) error {
+	data, err := b.compress(in)
+	if err != nil {
+		return err
 	}
-	n, err := b.w.Write(typ, b.buf.Bytes())
+	n, err := b.w.Write(typ, data)
 	b.written += n
 	if err != nil {
 		return fmt.Errorf("error writing e2store entry: %w", err)
@@ -36,26 +36,81 @@ import (
 )
 
 var (
-	TypeVersion            uint16 = 0x3265
-	TypeCompressedHeader   uint16 = 0x03
-	TypeCompressedBody     uint16 = 0x04
-	TypeCompressedReceipts uint16 = 0x05
-	TypeTotalDifficulty    uint16 = 0x06
-	TypeAccumulator        uint16 = 0x07
-	TypeBlockIndex         uint16 = 0x3266
+	TypeVersion                uint16 = 0x3265
+	TypeCompressedHeader       uint16 = 0x03
+	TypeCompressedBody         uint16 = 0x04
+	TypeCompressedReceipts     uint16 = 0x05
+	TypeTotalDifficulty        uint16 = 0x06
+	TypeAccumulator            uint16 = 0x07
+	TypeCompressedSlimReceipts uint16 = 0x0a
+	TypeBlockIndex             uint16 = 0x3266
+	TypeComponentIndex         uint16 = 0x3267
 
 	MaxEra1Size = 8192
 )
 
-// Filename returns a recognizable Era1-formatted file name for the specified
-// epoch and network.
-func Filename(network string, epoch int, root common.Hash) string {
-	return fmt.Sprintf("%s-%05d-%s.era1", network, epoch, root.Hex()[2:10])
+// Components of a block in the index of an execution era archive, in the order
+// of their offsets in every index entry.
+const (
+	componentHeader = iota
+	componentBody
+	componentReceipts
+	componentTotalDifficulty
+)
+
+// Format identifies the layout of an archive.
+type Format int
+
+const (
+	// FormatEra1 is the pre-merge archive format, storing the total difficulty
+	// of every block and accumulating header records. Era1 archives always start
+	// at a multiple of the epoch size, counted from genesis.
+	FormatEra1 Format = iota
+
+	// FormatEraE is the execution era archive format, suitable for any range
+	// of the chain. The entries are grouped by component, receipts are stored
+	// without their bloom filters, and the total difficulty and accumulator are
+	// only present in archives of pre-merge blocks.
+	FormatEraE
+)
+
+// ParseFormat returns the archive format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "era1":
+		return FormatEra1, nil
+	case "erae":
+		return FormatEraE, nil
+	}
+	return 0, fmt.Errorf("unknown archive format %q, available: era1, erae", name)
 }
 
-// ReadDir reads all the era1 files in a directory for a given network.
-// Format: <network>-<epoch>-<hexroot>.era1
-func ReadDir(dir, network string) ([]string, error) {
+// String implements fmt.Stringer.
+func (f Format) String() string {
+	if f == FormatEraE {
+		return "erae"
+	}
+	return "era1"
+}
+
+// Extension returns the file extension of the archive format.
+func (f Format) Extension() string {
+	if f == FormatEraE {
+		return ".erae"
+	}
+	return ".era1"
+}
+
+// Filename returns a recognizable archive file name for the specified epoch
+// and network.
+func (f Format) Filename(network string, epoch int, root common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%s%s", network, epoch, root.Hex()[2:10], f.Extension())
+}
+
+// ReadDir reads all the archives of the format in a directory for a given
+// network. The epochs must be contiguous, Era1 archives must also start from
+// the genesis epoch.
+func (f Format) ReadDir(dir, network string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("error reading directory %s: %w", dir, err)
@@ -65,17 +120,22 @@ func ReadDir(dir, network string) ([]string, error) {
 		eras []string
 	)
 	for _, entry := range entries {
-		if path.Ext(entry.Name()) != ".era1" {
+		if path.Ext(entry.Name()) != f.Extension() {
 			continue
 		}
 		parts := strings.Split(entry.Name(), "-")
 		if len(parts) != 3 || parts[0] != network {
-			// Invalid era1 filename, skip.
+			// Invalid archive filename, skip.
 			continue
 		}
-		if epoch, err := strconv.ParseUint(parts[1], 10, 64); err != nil {
-			return nil, fmt.Errorf("malformed era1 filename: %s", entry.Name())
-		} else if epoch != next {
+		epoch, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed %s filename: %s", f, entry.Name())
+		}
+		if len(eras) == 0 && f == FormatEraE {
+			next = epoch
+		}
+		if epoch != next {
 			return nil, fmt.Errorf("missing epoch %d", next)
 		}
 		next += 1
@@ -84,13 +144,25 @@ func ReadDir(dir, network string) ([]string, error) {
 	return eras, nil
 }
 
+// Filename returns a recognizable Era1-formatted file name for the specified
+// epoch and network.
+func Filename(network string, epoch int, root common.Hash) string {
+	return FormatEra1.Filename(network, epoch, root)
+}
+
+// ReadDir reads all the era1 files in a directory for a given network.
+// Format: <network>-<epoch>-<hexroot>.era1
+func ReadDir(dir, network string) ([]string, error) {
+	return FormatEra1.ReadDir(dir, network)
+}
+
 type ReadAtSeekCloser interface {
 	io.ReaderAt
 	io.Seeker
 	io.Closer
 }
 
-// Era reads and Era1 file.
+// Era reads an Era1 or an execution era file.
 type Era struct {
 	f   ReadAtSeekCloser // backing era1 file
 	s   *e2store.Reader  // e2store reader over f
@@ -131,7 +203,7 @@ func (e *Era) GetHeaderByNumber(num uint64) (*types.Header, error) {
 	if e.m.start > num || e.m.start+e.m.count <= num {
 		return nil, errors.New("out-of-bounds")
 	}
-	off, err := e.readOffset(num)
+	off, err := e.componentOffset(num, componentHeader)
 	if err != nil {
 		return nil, err
 	}
@@ -153,11 +225,11 @@ func (e *Era) GetBlockByNumber(num uint64) (*types.Block, error) {
 	if e.m.start > num || e.m.start+e.m.count <= num {
 		return nil, errors.New("out-of-bounds")
 	}
-	off, err := e.readOffset(num)
+	off, err := e.componentOffset(num, componentHeader)
 	if err != nil {
 		return nil, err
 	}
-	r, n, err := newSnappyReader(e.s, TypeCompressedHeader, off)
+	r, _, err := newSnappyReader(e.s, TypeCompressedHeader, off)
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +237,9 @@ func (e *Era) GetBlockByNumber(num uint64) (*types.Block, error) {
 	if err := rlp.Decode(r, &header); err != nil {
 		return nil, err
 	}
-	off += n
+	if off, err = e.componentOffset(num, componentBody); err != nil {
+		return nil, err
+	}
 	r, _, err = newSnappyReader(e.s, TypeCompressedBody, off)
 	if err != nil {
 		return nil, err
@@ -182,21 +256,48 @@ func (e *Era) GetReceiptsByNumber(num uint64) (types.Receipts, error) {
 	if e.m.start > num || e.m.start+e.m.count <= num {
 		return nil, errors.New("out-of-bounds")
 	}
-	off, err := e.readOffset(num)
+	off, err := e.componentOffset(num, componentReceipts)
 	if err != nil {
 		return nil, err
 	}
 
-	// Skip over header and body.
-	off, err = e.s.SkipN(off, 2)
+	// Read and decompress receipts.
+	r, _, err := newSnappyReader(e.s, e.receiptsType(), off)
 	if err != nil {
 		return nil, err
 	}
+	return e.decodeReceipts(r)
+}
 
-	// Read and decompress receipts.
-	r, _, err := newSnappyReader(e.s, TypeCompressedReceipts, off)
-	if err != nil {
-		return nil, err
+// Format returns the layout of the archive.
+func (e *Era) Format() Format {
+	return e.m.format
+}
+
+// HasTotalDifficulty reports whether the archive stores the total difficulty of
+// its blocks, which is always the case for Era1 archives, and for execution era
+// archives of pre-merge blocks.
+func (e *Era) HasTotalDifficulty() bool {
+	return e.m.format == FormatEra1 || e.m.components > componentTotalDifficulty
+}
+
+// receiptsType returns the entry type of the receipts in the archive.
+func (e *Era) receiptsType() uint16 {
+	if e.m.format == FormatEraE {
+		return TypeCompressedSlimReceipts
+	}
+	return TypeCompressedReceipts
+}
+
+// decodeReceipts decodes the receipts of a block in the encoding used by the
+// archive.
+func (e *Era) decodeReceipts(r io.Reader) (types.Receipts, error) {
+	if e.m.format == FormatEraE {
+		var slim []*slimReceipt
+		if err := rlp.Decode(r, &slim); err != nil {
+			return nil, err
+		}
+		return fromSlimReceipts(slim)
 	}
 	var receipts types.Receipts
 	if err := rlp.Decode(r, &receipts); err != nil {
@@ -205,7 +306,8 @@ func (e *Era) GetReceiptsByNumber(num uint64) (types.Receipts, error) {
 	return receipts, nil
 }
 
-// Accumulator reads the accumulator entry in the Era1 file.
+// Accumulator reads the accumulator entry in the archive. Execution era
+// archives only contain one if they hold pre-merge blocks.
 func (e *Era) Accumulator() (common.Hash, error) {
 	entry, err := e.s.Find(TypeAccumulator)
 	if err != nil {
@@ -217,30 +319,28 @@ func (e *Era) Accumulator() (common.Hash, error) {
 // InitialTD returns initial total difficulty before the difficulty of the
 // first block of the Era1 is applied.
 func (e *Era) InitialTD() (*big.Int, error) {
+	if !e.HasTotalDifficulty() {
+		return nil, errors.New("total difficulty not stored in archive")
+	}
 	var (
 		r      io.Reader
 		header types.Header
 		rawTd  []byte
-		n      int64
 		off    int64
 		err    error
 	)
 
 	// Read first header.
-	if off, err = e.readOffset(e.m.start); err != nil {
+	if off, err = e.componentOffset(e.m.start, componentHeader); err != nil {
 		return nil, err
 	}
-	if r, n, err = newSnappyReader(e.s, TypeCompressedHeader, off); err != nil {
+	if r, _, err = newSnappyReader(e.s, TypeCompressedHeader, off); err != nil {
 		return nil, err
 	}
 	if err := rlp.Decode(r, &header); err != nil {
 		return nil, err
 	}
-	off += n
-
-	// Skip over header and body.
-	off, err = e.s.SkipN(off, 2)
-	if err != nil {
+	if off, err = e.componentOffset(e.m.start, componentTotalDifficulty); err != nil {
 		return nil, err
 	}
 
@@ -266,6 +366,33 @@ func (e *Era) Count() uint64 {
 	return e.m.count
 }
 
+// componentOffset returns the absolute offset of a component of the block n.
+// Era1 archives only index the first entry of every block tuple, the other
+// components follow it in order.
+func (e *Era) componentOffset(n uint64, component int) (int64, error) {
+	if e.m.format == FormatEra1 {
+		off, err := e.readOffset(n)
+		if err != nil {
+			return 0, err
+		}
+		return e.s.SkipN(off, uint64(component))
+	}
+	if uint64(component) >= e.m.components {
+		return 0, fmt.Errorf("component %d not present in archive", component)
+	}
+	// The offsets are relative to the start of the component index record,
+	// past the header and starting number.
+	pos := e.m.index + 16 + int64((n-e.m.start)*e.m.components+uint64(component))*8
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clear(e.buf[:])
+	if _, err := e.f.ReadAt(e.buf[:], pos); err != nil {
+		return 0, err
+	}
+	return e.m.index + int64(binary.LittleEndian.Uint64(e.buf[:])), nil
+}
+
 // readOffset reads a specific block's offset from the block index. The value n
 // is the absolute block number desired.
 func (e *Era) readOffset(n uint64) (int64, error) {
@@ -296,14 +423,18 @@ func newSnappyReader(e *e2store.Reader, expectedType uint16, off int64) (io.Read
 	return snappy.NewReader(r), int64(n), err
 }
 
-// metadata wraps the metadata in the block index.
+// metadata wraps the metadata in the block or component index.
 type metadata struct {
-	start  uint64
-	count  uint64
-	length int64
+	format     Format
+	start      uint64
+	count      uint64
+	components uint64 // number of indexed components per block, execution era only
+	index      int64  // offset of the index record, execution era only
+	length     int64
 }
 
-// readMetadata reads the metadata stored in an Era1 file's block index.
+// readMetadata reads the metadata stored in the index of an archive. The format
+// of the archive is determined by the type of its index record.
 func readMetadata(f ReadAtSeekCloser) (m metadata, err error) {
 	// Determine length of reader.
 	if m.length, err = f.Seek(0, io.SeekEnd); err != nil {
@@ -315,11 +446,49 @@ func readMetadata(f ReadAtSeekCloser) (m metadata, err error) {
 		return
 	}
 	m.count = binary.LittleEndian.Uint64(b)
-	// Read start. It's at the offset -sizeof(m.count) -
-	// count*sizeof(indexEntry) - sizeof(m.start)
-	if _, err = f.ReadAt(b[8:], m.length-16-int64(m.count*8)); err != nil {
+	if m.count > uint64(m.length) {
+		return m, fmt.Errorf("invalid block count %d", m.count)
+	}
+	// Era1 block index: header | start | index * count | count
+	r := e2store.NewReader(f)
+	if off := m.length - 24 - int64(m.count*8); off >= 0 {
+		if typ, _, err := r.ReadMetadataAt(off); err == nil && typ == TypeBlockIndex {
+			m.format = FormatEra1
+			// Read start. It's at the offset -sizeof(m.count) -
+			// count*sizeof(indexEntry) - sizeof(m.start)
+			if _, err = f.ReadAt(b[8:], m.length-16-int64(m.count*8)); err != nil {
+				return m, err
+			}
+			m.start = binary.LittleEndian.Uint64(b[8:])
+			return m, nil
+		}
+	}
+	// Execution era component index:
+	// header | start | index * count * components | components | count
+	if _, err = f.ReadAt(b[:8], m.length-16); err != nil {
 		return
 	}
+	m.format = FormatEraE
+	// Only archives without block proofs are supported, which index the header,
+	// body and receipts of every block, and possibly the total difficulty.
+	m.components = binary.LittleEndian.Uint64(b)
+	if m.components != componentTotalDifficulty && m.components != componentTotalDifficulty+1 {
+		return m, fmt.Errorf("unsupported component count %d", m.components)
+	}
+	m.index = m.length - 32 - int64(m.count*m.components*8)
+	if m.index < 0 {
+		return m, errors.New("index larger than archive")
+	}
+	typ, _, err := r.ReadMetadataAt(m.index)
+	if err != nil {
+		return m, err
+	}
+	if typ != TypeComponentIndex {
+		return m, fmt.Errorf("unknown index type %#x", typ)
+	}
+	if _, err = f.ReadAt(b[8:], m.index+8); err != nil {
+		return m, err
+	}
 	m.start = binary.LittleEndian.Uint64(b[8:])
-	return
+	return m, nil
 }
@@ -22,6 +22,7 @@ import (
 	"io"
 	"math/big"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -154,6 +155,178 @@ func TestEra1Builder(t *testing.T) {
 	}
 }
 
+func TestEraEBuilder(t *testing.T) {
+	t.Parallel()
+
+	for _, premerge := range []bool{false, true} {
+		f, err := os.CreateTemp(t.TempDir(), "erae-test")
+		if err != nil {
+			t.Fatalf("error creating temp file: %v", err)
+		}
+		defer f.Close()
+
+		// Write blocks starting at an arbitrary number, with total difficulty
+		// only for pre-merge blocks.
+		var (
+			builder = NewBuilderWithFormat(f, FormatEraE)
+			start   = uint64(1000)
+			count   = uint64(64)
+			hashes  []common.Hash
+			tds     []*big.Int
+		)
+		for i := start; i < start+count; i++ {
+			var (
+				header   = mustEncode(&types.Header{Number: new(big.Int).SetUint64(i), Difficulty: big.NewInt(1)})
+				body     = mustEncode(&types.Body{})
+				receipts = mustEncode([]*slimReceipt{{CumulativeGasUsed: i, PostStateOrStatus: receiptStatusSuccessful}})
+				hash     = common.Hash{byte(i)}
+				td       *big.Int
+			)
+			if premerge {
+				td = new(big.Int).SetUint64(i)
+				tds = append(tds, td)
+			}
+			if err := builder.AddRLP(header, body, receipts, i, hash, td, big.NewInt(1)); err != nil {
+				t.Fatalf("error adding entry: %v", err)
+			}
+			hashes = append(hashes, hash)
+		}
+		if err := builder.AddRLP(nil, nil, nil, start+count+1, common.Hash{}, nil, nil); err == nil {
+			t.Fatal("non-contiguous block accepted")
+		}
+		if !premerge {
+			if err := builder.AddRLP(nil, nil, nil, start+count, common.Hash{}, big.NewInt(1), nil); err == nil {
+				t.Fatal("total difficulty accepted for some blocks only")
+			}
+		}
+		root, err := builder.Finalize()
+		if err != nil {
+			t.Fatalf("error finalizing era: %v", err)
+		}
+		want := hashes[len(hashes)-1]
+		if premerge {
+			want, _ = ComputeAccumulator(hashes, tds)
+		}
+		if root != want {
+			t.Fatalf("root mismatch: have %s, want %s", root, want)
+		}
+
+		// The entries must be grouped by component.
+		e, err := Open(f.Name())
+		if err != nil {
+			t.Fatalf("failed to open era: %v", err)
+		}
+		defer e.Close()
+		layout := []uint16{TypeVersion}
+		for _, typ := range []uint16{TypeCompressedHeader, TypeCompressedBody, TypeCompressedSlimReceipts} {
+			for range count {
+				layout = append(layout, typ)
+			}
+		}
+		if premerge {
+			for range count {
+				layout = append(layout, TypeTotalDifficulty)
+			}
+			layout = append(layout, TypeAccumulator)
+		}
+		layout = append(layout, TypeComponentIndex)
+		var off int64
+		for i, want := range layout {
+			typ, length, err := e.s.ReadMetadataAt(off)
+			if err != nil || typ != want {
+				t.Fatalf("entry %d: have type %#x, want %#x, err %v", i, typ, want, err)
+			}
+			off += 8 + int64(length)
+		}
+		if off != e.m.length {
+			t.Fatalf("trailing data after component index")
+		}
+
+		// Verify the archive contents.
+		if e.Format() != FormatEraE {
+			t.Fatalf("format mismatch: have %s, want %s", e.Format(), FormatEraE)
+		}
+		if e.Start() != start || e.Count() != count {
+			t.Fatalf("range mismatch: have %d+%d, want %d+%d", e.Start(), e.Count(), start, count)
+		}
+		if e.HasTotalDifficulty() != premerge {
+			t.Fatalf("total difficulty presence mismatch: have %t, want %t", e.HasTotalDifficulty(), premerge)
+		}
+		if premerge {
+			if have, err := e.Accumulator(); err != nil || have != root {
+				t.Fatalf("stored accumulator mismatch: have %s, want %s, err %v", have, root, err)
+			}
+			if td, err := e.InitialTD(); err != nil || td.Uint64() != start-1 {
+				t.Fatalf("initial total difficulty mismatch: have %v, want %d, err %v", td, start-1, err)
+			}
+		} else {
+			if _, err := e.Accumulator(); err == nil {
+				t.Fatal("accumulator stored in post-merge archive")
+			}
+			if _, err := e.InitialTD(); err == nil {
+				t.Fatal("total difficulty available in post-merge archive")
+			}
+		}
+		receipts, err := e.GetReceiptsByNumber(start + 1)
+		if err != nil || receipts[0].CumulativeGasUsed != start+1 || receipts[0].Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("mismatched receipts: %v, err %v", receipts, err)
+		}
+		it, err := NewIterator(e)
+		if err != nil {
+			t.Fatalf("failed to make iterator: %v", err)
+		}
+		for i := start; it.Next(); i++ {
+			if it.Error() != nil {
+				t.Fatalf("unexpected error %v", it.Error())
+			}
+			block, receipts, err := it.BlockAndReceipts()
+			if err != nil {
+				t.Fatalf("error reading block %d: %v", i, err)
+			}
+			if block.NumberU64() != i || receipts[0].CumulativeGasUsed != i {
+				t.Fatalf("mismatched block %d", i)
+			}
+			td, err := it.TotalDifficulty()
+			if premerge && (err != nil || td.Uint64() != i) {
+				t.Fatalf("mismatched total difficulty %d: have %v, err %v", i, td, err)
+			}
+			if !premerge && err == nil {
+				t.Fatal("total difficulty available in post-merge archive")
+			}
+		}
+	}
+}
+
+func TestSlimReceipts(t *testing.T) {
+	t.Parallel()
+
+	receipts := types.Receipts{
+		{Type: types.LegacyTxType, PostState: common.Hash{1}.Bytes(), CumulativeGasUsed: 1, Logs: []*types.Log{}},
+		{Type: types.DynamicFeeTxType, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 2, Logs: []*types.Log{}},
+		{Type: types.BlobTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 3, Logs: []*types.Log{
+			{Address: common.Address{1}, Topics: []common.Hash{{2}}, Data: []byte{3}},
+		}},
+	}
+	for _, r := range receipts {
+		r.Bloom = types.CreateBloom(r)
+	}
+	var slim []*slimReceipt
+	if err := rlp.DecodeBytes(mustEncode(toSlimReceipts(receipts)), &slim); err != nil {
+		t.Fatalf("error decoding slim receipts: %v", err)
+	}
+	restored, err := fromSlimReceipts(slim)
+	if err != nil {
+		t.Fatalf("error restoring receipts: %v", err)
+	}
+	for i := range receipts {
+		have, _ := restored[i].MarshalBinary()
+		want, _ := receipts[i].MarshalBinary()
+		if !bytes.Equal(have, want) {
+			t.Fatalf("receipt %d mismatch: have %x, want %x", i, have, want)
+		}
+	}
+}
+
 func TestEraFilename(t *testing.T) {
 	t.Parallel()
 
@@ -170,6 +343,40 @@ func TestEraFilename(t *testing.T) {
 			t.Errorf("test %d: invalid filename: want %s, got %s", i, tt.expected, got)
 		}
 	}
+	if got, want := FormatEraE.Filename("mainnet", 2, common.Hash{1}), "mainnet-00002-01000000.erae"; got != want {
+		t.Errorf("invalid filename: want %s, got %s", want, got)
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{
+		FormatEraE.Filename("mainnet", 3, common.Hash{}),
+		FormatEraE.Filename("mainnet", 4, common.Hash{}),
+		FormatEraE.Filename("sepolia", 7, common.Hash{}),
+		FormatEra1.Filename("mainnet", 3, common.Hash{}),
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Execution era archives may start at any epoch.
+	if entries, err := FormatEraE.ReadDir(dir, "mainnet"); err != nil || len(entries) != 2 {
+		t.Fatalf("unexpected era entries: %v, err %v", entries, err)
+	}
+	// Era1 archives must start from genesis.
+	if _, err := FormatEra1.ReadDir(dir, "mainnet"); err == nil {
+		t.Fatal("era1 archives without genesis epoch accepted")
+	}
+	// Epochs must be contiguous.
+	if err := os.WriteFile(filepath.Join(dir, FormatEraE.Filename("mainnet", 6, common.Hash{})), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FormatEraE.ReadDir(dir, "mainnet"); err == nil {
+		t.Fatal("non-contiguous epochs accepted")
+	}
 }
 
 func mustEncode(obj any) []byte {
@@ -81,9 +81,7 @@ func (it *Iterator) Receipts() (types.Receipts, error) {
 	if it.inner.Receipts == nil {
 		return nil, errors.New("receipts must be non-nil")
 	}
-	var receipts types.Receipts
-	err := rlp.Decode(it.inner.Receipts, &receipts)
-	return receipts, err
+	return it.inner.e.decodeReceipts(it.inner.Receipts)
 }
 
 // BlockAndReceipts returns the block and receipts for the iterator's current
@@ -103,6 +101,9 @@ func (it *Iterator) BlockAndReceipts() (*types.Block, types.Receipts, error) {
 // TotalDifficulty returns the total difficulty for the iterator's current
 // position.
 func (it *Iterator) TotalDifficulty() (*big.Int, error) {
+	if it.inner.TotalDifficulty == nil {
+		return nil, errors.New("total difficulty not available")
+	}
 	td, err := io.ReadAll(it.inner.TotalDifficulty)
 	if err != nil {
 		return nil, err
@@ -134,7 +135,9 @@ func NewRawIterator(e *Era) (*RawIterator, error) {
 // Next moves the iterator to the next block entry. It returns false when all
 // items have been read or an error has halted its progress. Header, Body,
 // Receipts, TotalDifficulty will be set to nil in the case returning false or
-// finding an error and should therefore no longer be read from.
+// finding an error and should therefore no longer be read from. TotalDifficulty
+// is nil for execution era archives of post-merge blocks, and Receipts are in
+// the slim encoding in execution era archives.
 func (it *RawIterator) Next() bool {
 	// Clear old errors.
 	it.err = nil
@@ -142,7 +145,7 @@ func (it *RawIterator) Next() bool {
 		it.clear()
 		return false
 	}
-	off, err := it.e.readOffset(it.next)
+	off, err := it.e.componentOffset(it.next, componentHeader)
 	if err != nil {
 		// Error here means block index is corrupted, so don't
 		// continue.
@@ -155,25 +158,47 @@ func (it *RawIterator) Next() bool {
 		it.clear()
 		return true
 	}
-	off += n
+	// The components of a block follow each other in Era1 archives, but have to
+	// be looked up in the index of execution era archives.
+	if off, it.err = it.nextComponent(componentBody, off+n); it.err != nil {
+		it.clear()
+		return true
+	}
 	if it.Body, n, it.err = newSnappyReader(it.e.s, TypeCompressedBody, off); it.err != nil {
 		it.clear()
 		return true
 	}
-	off += n
-	if it.Receipts, n, it.err = newSnappyReader(it.e.s, TypeCompressedReceipts, off); it.err != nil {
+	if off, it.err = it.nextComponent(componentReceipts, off+n); it.err != nil {
 		it.clear()
 		return true
 	}
-	off += n
-	if it.TotalDifficulty, _, it.err = it.e.s.ReaderAt(TypeTotalDifficulty, off); it.err != nil {
+	if it.Receipts, n, it.err = newSnappyReader(it.e.s, it.e.receiptsType(), off); it.err != nil {
 		it.clear()
 		return true
 	}
+	if it.e.HasTotalDifficulty() {
+		if off, it.err = it.nextComponent(componentTotalDifficulty, off+n); it.err != nil {
+			it.clear()
+			return true
+		}
+		if it.TotalDifficulty, _, it.err = it.e.s.ReaderAt(TypeTotalDifficulty, off); it.err != nil {
+			it.clear()
+			return true
+		}
+	}
 	it.next += 1
 	return true
 }
 
+// nextComponent returns the offset of a component of the block being loaded,
+// given the end of the entry of the previous component.
+func (it *RawIterator) nextComponent(component int, end int64) (int64, error) {
+	if it.e.Format() == FormatEra1 {
+		return end, nil
+	}
+	return it.e.componentOffset(it.next, component)
+}
+
 // Number returns the current number block the iterator will return.
 func (it *RawIterator) Number() uint64 {
 	return it.next - 1
@@ -0,0 +1,94 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package era
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	receiptStatusFailed     = []byte{}
+	receiptStatusSuccessful = []byte{0x01}
+)
+
+// slimReceipt is the receipt encoding of execution era archives. It omits the
+// bloom filter, which is derived from the logs.
+//
+//	slim-receipt := [tx-type, post-state-or-status, cumulative-gas, logs]
+type slimReceipt struct {
+	Type              uint64
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Logs              []*types.Log
+}
+
+// toSlimReceipts converts the receipts of a block into their slim encoding.
+func toSlimReceipts(receipts types.Receipts) []*slimReceipt {
+	slim := make([]*slimReceipt, len(receipts))
+	for i, r := range receipts {
+		status := r.PostState
+		if len(status) == 0 {
+			status = receiptStatusFailed
+			if r.Status == types.ReceiptStatusSuccessful {
+				status = receiptStatusSuccessful
+			}
+		}
+		slim[i] = &slimReceipt{
+			Type:              uint64(r.Type),
+			PostStateOrStatus: status,
+			CumulativeGasUsed: r.CumulativeGasUsed,
+			Logs:              r.Logs,
+		}
+	}
+	return slim
+}
+
+// fromSlimReceipts restores the consensus fields of the receipts of a block,
+// recomputing their bloom filters.
+func fromSlimReceipts(slim []*slimReceipt) (types.Receipts, error) {
+	receipts := make(types.Receipts, len(slim))
+	for i, s := range slim {
+		if s.Type > 0xff {
+			return nil, fmt.Errorf("receipt %d: invalid type %d", i, s.Type)
+		}
+		r := &types.Receipt{
+			Type:              uint8(s.Type),
+			CumulativeGasUsed: s.CumulativeGasUsed,
+			Logs:              s.Logs,
+		}
+		switch {
+		case bytes.Equal(s.PostStateOrStatus, receiptStatusSuccessful):
+			r.Status = types.ReceiptStatusSuccessful
+		case bytes.Equal(s.PostStateOrStatus, receiptStatusFailed):
+			r.Status = types.ReceiptStatusFailed
+		case len(s.PostStateOrStatus) == common.HashLength:
+			r.PostState = s.PostStateOrStatus
+		default:
+			return nil, fmt.Errorf("receipt %d: invalid post state or status %x", i, s.PostStateOrStatus)
+		}
+		if r.Logs == nil {
+			r.Logs = []*types.Log{}
+		}
+		r.Bloom = types.CreateBloom(r)
+		receipts[i] = r
+	}
+	return receipts, nil
+}
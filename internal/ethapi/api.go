@@ -962,6 +962,8 @@ type RPCTransaction struct {
 	ChainID             *hexutil.Big                 `json:"chainId,omitempty"`
 	BlobVersionedHashes []common.Hash                `json:"blobVersionedHashes,omitempty"`
 	AuthorizationList   []types.SetCodeAuthorization `json:"authorizationList,omitempty"`
+	ValidAfter          *hexutil.Uint64              `json:"validAfter,omitempty"`
+	ValidUntil          *hexutil.Uint64              `json:"validUntil,omitempty"`
 	V                   *hexutil.Big                 `json:"v"`
 	R                   *hexutil.Big                 `json:"r"`
 	S                   *hexutil.Big                 `json:"s"`
@@ -1056,6 +1058,24 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
 		}
 		result.AuthorizationList = tx.SetCodeAuthorizations()
+
+	case types.TimedTxType:
+		al := tx.AccessList()
+		yparity := hexutil.Uint64(v.Sign())
+		result.Accesses = &al
+		result.ChainID = (*hexutil.Big)(tx.ChainId())
+		result.YParity = &yparity
+		result.GasFeeCap = (*hexutil.Big)(tx.GasFeeCap())
+		result.GasTipCap = (*hexutil.Big)(tx.GasTipCap())
+		// if the transaction has been mined, compute the effective gas price
+		if baseFee != nil && blockHash != (common.Hash{}) {
+			result.GasPrice = (*hexutil.Big)(effectiveGasPrice(tx, baseFee))
+		} else {
+			result.GasPrice = (*hexutil.Big)(tx.GasFeeCap())
+		}
+		validAfter, validUntil := hexutil.Uint64(tx.ValidAfter()), hexutil.Uint64(tx.ValidUntil())
+		result.ValidAfter = &validAfter
+		result.ValidUntil = &validUntil
 	}
 	return result
 }
@@ -1544,6 +1564,38 @@ func (api *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
+// SendTransactionGroup will add the signed transactions to the transaction pool
+// as an atomic group. The block builder will include all the transactions of the
+// group contiguously and in the given order, or none of them at all.
+//
+// The group is not propagated to the network, it is only included in blocks built
+// by the local node. The method returns the hashes of the member transactions.
+func (api *TransactionAPI) SendTransactionGroup(ctx context.Context, inputs []hexutil.Bytes) ([]common.Hash, error) {
+	var (
+		txs    = make(types.Transactions, len(inputs))
+		hashes = make([]common.Hash, len(inputs))
+	)
+	for i, input := range inputs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		if !api.b.UnprotectedAllowed() && !tx.Protected() {
+			return nil, fmt.Errorf("transaction %d: only replay-protected (EIP-155) transactions allowed over RPC", i)
+		}
+		txs[i], hashes[i] = tx, tx.Hash()
+	}
+	group, err := api.b.SendTxGroup(ctx, txs)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Submitted transaction group", "hash", group, "txs", len(txs))
+	return hashes, nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
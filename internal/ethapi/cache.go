@@ -0,0 +1,237 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	responseCacheHitMeter   = metrics.NewRegisteredMeter("rpc/responsecache/hit", nil)
+	responseCacheMissMeter  = metrics.NewRegisteredMeter("rpc/responsecache/miss", nil)
+	responseCachePurgeMeter = metrics.NewRegisteredMeter("rpc/responsecache/purge", nil)
+)
+
+// cachePolicy decides whether the result of a call may be cached, given the
+// decoded positional parameters, the result and the current finalized block.
+type cachePolicy func(params []json.RawMessage, result json.RawMessage, final *types.Header) bool
+
+// cachePolicies are the methods whose results may be cached, along with the
+// conditions under which they are immutable.
+var cachePolicies = map[string]cachePolicy{
+	"eth_getBlockByHash":        pinnedBlockPolicy(0),
+	"eth_getBlockByNumber":      pinnedBlockPolicy(0),
+	"eth_getHeaderByHash":       pinnedBlockPolicy(0),
+	"eth_getHeaderByNumber":     pinnedBlockPolicy(0),
+	"eth_getBlockReceipts":      pinnedBlockPolicy(0),
+	"eth_getBalance":            pinnedBlockPolicy(1),
+	"eth_getCode":               pinnedBlockPolicy(1),
+	"eth_getTransactionCount":   pinnedBlockPolicy(1),
+	"eth_getStorageAt":          pinnedBlockPolicy(2),
+	"eth_getProof":              pinnedBlockPolicy(2),
+	"eth_call":                  pinnedBlockPolicy(1),
+	"eth_getTransactionByHash":  includedTxPolicy,
+	"eth_getTransactionReceipt": includedTxPolicy,
+}
+
+// pinnedBlockPolicy allows caching calls whose block parameter at the given
+// position is either a block hash or the number of a finalized block.
+func pinnedBlockPolicy(index int) cachePolicy {
+	return func(params []json.RawMessage, result json.RawMessage, final *types.Header) bool {
+		if index >= len(params) {
+			return false // defaults to the latest block
+		}
+		var block rpc.BlockNumberOrHash
+		if err := json.Unmarshal(params[index], &block); err != nil {
+			return false
+		}
+		if _, ok := block.Hash(); ok {
+			return !block.RequireCanonical // canonicality may change with reorgs
+		}
+		number, ok := block.Number()
+		return ok && number >= 0 && final != nil && uint64(number) <= final.Number.Uint64()
+	}
+}
+
+// includedTxPolicy allows caching transaction lookups whose result is included
+// in a finalized block.
+func includedTxPolicy(params []json.RawMessage, result json.RawMessage, final *types.Header) bool {
+	var tx struct {
+		BlockNumber *hexutil.Big `json:"blockNumber"`
+	}
+	if final == nil || json.Unmarshal(result, &tx) != nil || tx.BlockNumber == nil {
+		return false
+	}
+	return tx.BlockNumber.ToInt().Cmp(final.Number) <= 0
+}
+
+// ResponseCache is a memory bounded rpc.ResponseCache for the results of calls
+// which are pinned to a block hash or a finalized block, and are therefore not
+// affected by new blocks. The cache is purged if a reorg below the previously
+// finalized block is detected.
+type ResponseCache struct {
+	b    Backend
+	size uint64
+
+	lock  sync.Mutex
+	cache *lru.SizeConstrainedCache[string, []byte]
+	final *types.Header // Finalized header as of the last access
+}
+
+// NewResponseCache creates a response cache holding up to size bytes of results.
+func NewResponseCache(b Backend, size uint64) *ResponseCache {
+	return &ResponseCache{
+		b:     b,
+		size:  size,
+		cache: lru.NewSizeConstrainedCache[string, []byte](size),
+	}
+}
+
+// finalized returns the current finalized header, purging the cache if the
+// previously seen one is no longer canonical.
+func (c *ResponseCache) finalized(ctx context.Context) *types.Header {
+	final, err := c.b.HeaderByNumber(ctx, rpc.FinalizedBlockNumber)
+	if err != nil {
+		final = nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.final == nil || (final != nil && final.Hash() == c.final.Hash()) {
+		c.final = final
+		return final
+	}
+	// The finalized block changed, make sure the previous one is still canonical
+	prev := c.final
+	if final == nil || final.Number.Cmp(prev.Number) < 0 {
+		c.purge(prev)
+	} else if canon, _ := c.b.HeaderByNumber(ctx, rpc.BlockNumber(prev.Number.Int64())); canon == nil || canon.Hash() != prev.Hash() {
+		c.purge(prev)
+	}
+	c.final = final
+	return final
+}
+
+// purge drops all cached results. The caller must hold the lock.
+func (c *ResponseCache) purge(final *types.Header) {
+	log.Warn("Finalized block reorged, purging RPC response cache", "number", final.Number, "hash", final.Hash())
+	responseCachePurgeMeter.Mark(1)
+	c.cache = lru.NewSizeConstrainedCache[string, []byte](c.size)
+}
+
+// Get implements rpc.ResponseCache.
+func (c *ResponseCache) Get(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, bool) {
+	if _, ok := cachePolicies[method]; !ok {
+		return nil, false
+	}
+	key, ok := responseCacheKey(method, params)
+	if !ok {
+		return nil, false
+	}
+	c.finalized(ctx)
+
+	c.lock.Lock()
+	result, ok := c.cache.Get(key)
+	c.lock.Unlock()
+
+	if ok {
+		responseCacheHitMeter.Mark(1)
+	} else {
+		responseCacheMissMeter.Mark(1)
+	}
+	return result, ok
+}
+
+// Put implements rpc.ResponseCache.
+func (c *ResponseCache) Put(ctx context.Context, method string, params json.RawMessage, result json.RawMessage) {
+	policy, ok := cachePolicies[method]
+	if !ok || len(result) == 0 || bytes.Equal(result, []byte("null")) {
+		return
+	}
+	key, ok := responseCacheKey(method, params)
+	if !ok {
+		return
+	}
+	var args []json.RawMessage
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &args); err != nil {
+			return
+		}
+	}
+	if !policy(args, result, c.finalized(ctx)) {
+		return
+	}
+	c.lock.Lock()
+	c.cache.Add(key, bytes.Clone(result))
+	c.lock.Unlock()
+}
+
+// responseCacheKey returns the cache key of a call, consisting of the method and
+// its parameters in canonical form: without insignificant whitespace, with
+// object keys sorted and hex strings lowercased.
+func responseCacheKey(method string, params json.RawMessage) (string, bool) {
+	var args []interface{}
+	if len(params) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(params))
+		dec.UseNumber()
+		if err := dec.Decode(&args); err != nil {
+			return "", false
+		}
+	}
+	for i := range args {
+		args[i] = canonicalizeParam(args[i])
+	}
+	enc, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return method + string(enc), true
+}
+
+// canonicalizeParam lowercases all hex strings within a decoded JSON value.
+func canonicalizeParam(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X") {
+			return strings.ToLower(v)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = canonicalizeParam(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key, val := range v {
+			v[key] = canonicalizeParam(val)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// cacheTestBackend serves canonical headers and a finalized block number, which
+// is all the response cache needs from the backend.
+type cacheTestBackend struct {
+	Backend
+	canonical map[uint64]*types.Header
+	final     uint64
+}
+
+func (b *cacheTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	if number == rpc.FinalizedBlockNumber {
+		number = rpc.BlockNumber(b.final)
+	}
+	if header := b.canonical[uint64(number)]; header != nil {
+		return header, nil
+	}
+	return nil, errors.New("header not found")
+}
+
+func TestResponseCache(t *testing.T) {
+	backend := &cacheTestBackend{canonical: make(map[uint64]*types.Header), final: 10}
+	for i := uint64(0); i <= 20; i++ {
+		backend.canonical[i] = &types.Header{Number: new(big.Int).SetUint64(i)}
+	}
+	cache := NewResponseCache(backend, 1024*1024)
+	ctx := context.Background()
+
+	tests := []struct {
+		method string
+		params string
+		result string
+		cached bool
+	}{
+		// Calls pinned to a block hash are cached, unless canonicality is required
+		{"eth_getBalance", `["0xAA00000000000000000000000000000000000000", "0x1111111111111111111111111111111111111111111111111111111111111111"]`, `"0x1"`, true},
+		{"eth_call", `[{"to": "0xaa00000000000000000000000000000000000000"}, {"blockHash": "0x1111111111111111111111111111111111111111111111111111111111111111", "requireCanonical": true}]`, `"0x"`, false},
+		// Calls at finalized block numbers are cached, but not at later or tagged ones
+		{"eth_getBlockByNumber", `["0xa", false]`, `{"number": "0xa"}`, true},
+		{"eth_getBlockByNumber", `["0xb", false]`, `{"number": "0xb"}`, false},
+		{"eth_getBlockByNumber", `["finalized", false]`, `{"number": "0xa"}`, false},
+		{"eth_getCode", `["0xaa00000000000000000000000000000000000000"]`, `"0x"`, false},
+		// Transaction lookups are cached if included in a finalized block
+		{"eth_getTransactionReceipt", `["0x2222222222222222222222222222222222222222222222222222222222222222"]`, `{"blockNumber": "0x3"}`, true},
+		{"eth_getTransactionReceipt", `["0x3333333333333333333333333333333333333333333333333333333333333333"]`, `{"blockNumber": "0xc"}`, false},
+		// Missing results and non-deterministic methods are never cached
+		{"eth_getBlockByHash", `["0x4444444444444444444444444444444444444444444444444444444444444444", false]`, `null`, false},
+		{"eth_blockNumber", `[]`, `"0x14"`, false},
+	}
+	for i, test := range tests {
+		cache.Put(ctx, test.method, json.RawMessage(test.params), json.RawMessage(test.result))
+		result, ok := cache.Get(ctx, test.method, json.RawMessage(test.params))
+		if ok != test.cached {
+			t.Errorf("test %d (%s): cached mismatch: have %v, want %v", i, test.method, ok, test.cached)
+		}
+		if ok && string(result) != test.result {
+			t.Errorf("test %d (%s): result mismatch: have %s, want %s", i, test.method, result, test.result)
+		}
+	}
+	// Parameters are canonicalized before lookup
+	if _, ok := cache.Get(ctx, "eth_getBalance", json.RawMessage(`["0xaa00000000000000000000000000000000000000","0x1111111111111111111111111111111111111111111111111111111111111111"]`)); !ok {
+		t.Error("canonicalized parameters not found")
+	}
+	// Advancing finality keeps the cache, reorging the finalized block purges it
+	backend.final = 12
+	if _, ok := cache.Get(ctx, "eth_getBlockByNumber", json.RawMessage(`["0xa", false]`)); !ok {
+		t.Error("cache purged on finality advance")
+	}
+	backend.canonical[12] = &types.Header{Number: big.NewInt(12), Extra: []byte("reorg")}
+	backend.final = 11
+	if _, ok := cache.Get(ctx, "eth_getBlockByNumber", json.RawMessage(`["0xa", false]`)); ok {
+		t.Error("cache not purged on finalized reorg")
+	}
+}
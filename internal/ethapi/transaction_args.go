@@ -71,6 +71,10 @@ type TransactionArgs struct {
 	// For SetCodeTxType
 	AuthorizationList []types.SetCodeAuthorization `json:"authorizationList"`
 
+	// For TimedTxType
+	ValidAfter *hexutil.Uint64 `json:"validAfter,omitempty"`
+	ValidUntil *hexutil.Uint64 `json:"validUntil,omitempty"`
+
 	// This configures whether blobs are allowed to be passed.
 	blobSidecarAllowed bool
 }
@@ -96,6 +100,9 @@ func (args *TransactionArgs) data() []byte {
 
 // setDefaults fills in default values for unspecified tx fields.
 func (args *TransactionArgs) setDefaults(ctx context.Context, b Backend, skipGasEstimation bool) error {
+	if args.isTimed() && args.GasPrice != nil {
+		return errors.New("both gasPrice and validity window specified")
+	}
 	if err := args.setBlobTxSidecar(ctx); err != nil {
 		return err
 	}
@@ -461,6 +468,8 @@ func (args *TransactionArgs) ToMessage(baseFee *big.Int, skipNonceCheck, skipEoA
 		BlobGasFeeCap:         (*big.Int)(args.BlobFeeCap),
 		BlobHashes:            args.BlobHashes,
 		SetCodeAuthorizations: args.AuthorizationList,
+		ValidAfter:            uint64(args.validAfter()),
+		ValidUntil:            uint64(args.validUntil()),
 		SkipNonceChecks:       skipNonceCheck,
 		SkipFromEOACheck:      skipEoACheck,
 	}
@@ -475,6 +484,8 @@ func (args *TransactionArgs) ToTransaction(defaultType int) *types.Transaction {
 		usedType = types.SetCodeTxType
 	case args.BlobHashes != nil || defaultType == types.BlobTxType:
 		usedType = types.BlobTxType
+	case args.isTimed():
+		usedType = types.TimedTxType
 	case args.MaxFeePerGas != nil || defaultType == types.DynamicFeeTxType:
 		usedType = types.DynamicFeeTxType
 	case args.AccessList != nil || defaultType == types.AccessListTxType:
@@ -534,6 +545,25 @@ func (args *TransactionArgs) ToTransaction(defaultType int) *types.Transaction {
 			}
 		}
 
+	case types.TimedTxType:
+		al := types.AccessList{}
+		if args.AccessList != nil {
+			al = *args.AccessList
+		}
+		data = &types.TimedTx{
+			To:         args.To,
+			ChainID:    (*big.Int)(args.ChainID),
+			Nonce:      uint64(*args.Nonce),
+			Gas:        uint64(*args.Gas),
+			GasFeeCap:  (*big.Int)(args.MaxFeePerGas),
+			GasTipCap:  (*big.Int)(args.MaxPriorityFeePerGas),
+			Value:      (*big.Int)(args.Value),
+			Data:       args.data(),
+			AccessList: al,
+			ValidAfter: uint64(args.validAfter()),
+			ValidUntil: uint64(args.validUntil()),
+		}
+
 	case types.DynamicFeeTxType:
 		al := types.AccessList{}
 		if args.AccessList != nil {
@@ -576,6 +606,27 @@ func (args *TransactionArgs) ToTransaction(defaultType int) *types.Transaction {
 	return types.NewTx(data)
 }
 
+// isTimed returns an indicator if the args contains a validity window.
+func (args *TransactionArgs) isTimed() bool {
+	return args.ValidAfter != nil || args.ValidUntil != nil
+}
+
+// validAfter retrieves the lower bound of the validity window, if any.
+func (args *TransactionArgs) validAfter() hexutil.Uint64 {
+	if args.ValidAfter == nil {
+		return 0
+	}
+	return *args.ValidAfter
+}
+
+// validUntil retrieves the upper bound of the validity window, if any.
+func (args *TransactionArgs) validUntil() hexutil.Uint64 {
+	if args.ValidUntil == nil {
+		return 0
+	}
+	return *args.ValidUntil
+}
+
 // IsEIP4844 returns an indicator if the args contains EIP4844 fields.
 func (args *TransactionArgs) IsEIP4844() bool {
 	return args.BlobHashes != nil || args.BlobFeeCap != nil
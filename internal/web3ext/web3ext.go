@@ -121,6 +121,11 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'backup',
+			call: 'admin_backup',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -158,6 +163,11 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'setTxPoolPolicy',
+			call: 'admin_setTxPoolPolicy',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -172,6 +182,10 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'txPoolPolicy',
+			getter: 'admin_txPoolPolicy'
+		}),
 	]
 });
 `
@@ -518,6 +532,11 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'sendTransactionGroup',
+			call: 'eth_sendTransactionGroup',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'fillTransaction',
 			call: 'eth_fillTransaction',
@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
@@ -52,6 +53,10 @@ var (
 	testUserKey, _  = crypto.GenerateKey()
 	testUserAddress = crypto.PubkeyToAddress(testUserKey.PublicKey)
 
+	// Contract unconditionally reverting on any call (PUSH1 0, PUSH1 0, REVERT)
+	testRevertAddress = common.HexToAddress("0xfd")
+	testRevertCode    = []byte{byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.REVERT)}
+
 	// Test transactions
 	pendingTxs []*types.Transaction
 	newTxs     []*types.Transaction
@@ -107,7 +112,10 @@ type testWorkerBackend struct {
 func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine consensus.Engine, db ethdb.Database, n int) *testWorkerBackend {
 	var gspec = &core.Genesis{
 		Config: chainConfig,
-		Alloc:  types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+		Alloc: types.GenesisAlloc{
+			testBankAddress:   {Balance: testBankFunds},
+			testRevertAddress: {Code: testRevertCode},
+		},
 	}
 	switch e := engine.(type) {
 	case *clique.Clique:
@@ -194,6 +202,74 @@ func TestBuildPayload(t *testing.T) {
 	}
 }
 
+// Tests that transaction groups are included atomically: either all of their
+// transactions make it into the block, or none of them.
+func TestBuildPayloadGroups(t *testing.T) {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		b      = newTestWorkerBackend(t, params.TestChainConfig, ethash.NewFaker(), db, 0)
+		w      = New(b, testConfig, ethash.NewFaker())
+		signer = types.LatestSigner(params.TestChainConfig)
+	)
+	transfer := func(nonce uint64, to common.Address, value int64, gas uint64) *types.Transaction {
+		return types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    big.NewInt(value),
+			Gas:      gas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		})
+	}
+	groups := []types.Transactions{
+		// Valid group, should be included
+		{transfer(0, testUserAddress, 1000, params.TxGas)},
+		// Second transaction reverts, the first one must be rolled back too
+		{transfer(1, testUserAddress, 2000, params.TxGas), transfer(2, testRevertAddress, 0, 50000)},
+		// Valid group on top of the first one, should be included
+		{transfer(1, testUserAddress, 3000, params.TxGas)},
+	}
+	for i, txs := range groups {
+		if _, err := b.txPool.AddGroup(txs); err != nil {
+			t.Fatalf("failed to add group %d: %v", i, err)
+		}
+	}
+	if have := len(b.txPool.PendingGroups()); have != len(groups) {
+		t.Fatalf("pending group count mismatch: have %d, want %d", have, len(groups))
+	}
+	// Group members should be known to the pool, but not addable on their own
+	member := groups[1][0]
+	if !b.txPool.Has(member.Hash()) || b.txPool.Get(member.Hash()) == nil {
+		t.Fatalf("group member not known to the pool")
+	}
+	if err := b.txPool.Add([]*types.Transaction{member}, true)[0]; !errors.Is(err, txpool.ErrAlreadyKnown) {
+		t.Fatalf("group member addition error mismatch: have %v, want %v", err, txpool.ErrAlreadyKnown)
+	}
+	result := w.generateWork(&generateParams{
+		parentHash: b.chain.CurrentBlock().Hash(),
+		timestamp:  b.chain.CurrentHeader().Time + 1,
+		coinbase:   testBankAddress,
+	}, false)
+	if result.err != nil {
+		t.Fatalf("failed to generate work: %v", result.err)
+	}
+	want := []common.Hash{groups[0][0].Hash(), groups[2][0].Hash()}
+	txs := result.block.Transactions()
+	if len(txs) != len(want) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(want))
+	}
+	for i, tx := range txs {
+		if tx.Hash() != want[i] {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, tx.Hash(), want[i])
+		}
+	}
+	if balance := result.stateDB.GetBalance(testUserAddress); balance.Uint64() != 4000 {
+		t.Errorf("recipient balance mismatch: have %v, want %v", balance, 4000)
+	}
+	if used := result.block.GasUsed(); used != 2*params.TxGas {
+		t.Errorf("gas used mismatch: have %d, want %d", used, 2*params.TxGas)
+	}
+}
+
 func TestPayloadId(t *testing.T) {
 	t.Parallel()
 	ids := make(map[string]int)
@@ -0,0 +1,156 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ErrBlobSpaceExhausted is passed to TxSelector.Failure if a blob transaction
+// does not fit into the remaining blob space of the block.
+var ErrBlobSpaceExhausted = errors.New("not enough blob space left")
+
+// TxSelector decides the order in which pending transactions are attempted to
+// be included into a block. A new selector is created for every block built.
+//
+// The block builder repeatedly calls Next and tries to include the returned
+// transaction, reporting the outcome via either Success or Failure before the
+// next call to Next.
+type TxSelector interface {
+	// Next returns the next transaction to attempt including, or nil if there
+	// are no more transactions to consider.
+	Next() *txpool.LazyTransaction
+
+	// Success is called after the transaction last returned by Next has been
+	// included in the block.
+	Success(tx *txpool.LazyTransaction)
+
+	// Failure is called after the transaction last returned by Next could not
+	// be included in the block. A core.ErrNonceTooLow error means the sender's
+	// subsequent transactions may still be includable, any other error means
+	// they are most probably not.
+	Failure(tx *txpool.LazyTransaction, err error)
+}
+
+// TxSelectionContext contains the block building details a transaction selector
+// may base its decisions on.
+type TxSelectionContext struct {
+	Header   *types.Header    // Header of the block being built
+	Signer   types.Signer     // Signer valid for the block being built
+	Priority []common.Address // Senders configured to be prioritized
+}
+
+// TxSelectorFactory creates a transaction selector for building a block out of
+// the given pending plain and blob transactions, both grouped by sender and
+// sorted by nonce.
+type TxSelectorFactory func(ctx *TxSelectionContext, plainTxs, blobTxs map[common.Address][]*txpool.LazyTransaction) TxSelector
+
+// priceSelector is the default transaction selector. It first includes all the
+// transactions of the prioritized senders and then all the rest, in both cases
+// ordered by effective tip and nonce.
+type priceSelector struct {
+	stages [][2]*transactionsByPriceAndNonce // Plain and blob txs of each stage, in order
+	last   *transactionsByPriceAndNonce      // Set the last transaction was picked from
+}
+
+// NewPriceSelector creates the default transaction selector, ordering the txs
+// by price and nonce with the priority senders' transactions first.
+func NewPriceSelector(ctx *TxSelectionContext, plainTxs, blobTxs map[common.Address][]*txpool.LazyTransaction) TxSelector {
+	// Split the pending transactions into priority and normal ones
+	prioPlainTxs, normalPlainTxs := make(map[common.Address][]*txpool.LazyTransaction), plainTxs
+	prioBlobTxs, normalBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), blobTxs
+
+	for _, account := range ctx.Priority {
+		if txs := normalPlainTxs[account]; len(txs) > 0 {
+			delete(normalPlainTxs, account)
+			prioPlainTxs[account] = txs
+		}
+		if txs := normalBlobTxs[account]; len(txs) > 0 {
+			delete(normalBlobTxs, account)
+			prioBlobTxs[account] = txs
+		}
+	}
+	s := new(priceSelector)
+	for _, stage := range [][2]map[common.Address][]*txpool.LazyTransaction{
+		{prioPlainTxs, prioBlobTxs},
+		{normalPlainTxs, normalBlobTxs},
+	} {
+		if len(stage[0]) == 0 && len(stage[1]) == 0 {
+			continue
+		}
+		s.stages = append(s.stages, [2]*transactionsByPriceAndNonce{
+			newTransactionsByPriceAndNonce(ctx.Signer, stage[0], ctx.Header.BaseFee),
+			newTransactionsByPriceAndNonce(ctx.Signer, stage[1], ctx.Header.BaseFee),
+		})
+	}
+	return s
+}
+
+// Next implements TxSelector, returning the best priced transaction of the
+// current stage among both plain and blob transactions.
+func (s *priceSelector) Next() *txpool.LazyTransaction {
+	for len(s.stages) > 0 {
+		plainTxs, blobTxs := s.stages[0][0], s.stages[0][1]
+
+		pltx, ptip := plainTxs.Peek()
+		bltx, btip := blobTxs.Peek()
+
+		switch {
+		case pltx == nil && bltx == nil:
+			s.stages = s.stages[1:]
+			continue
+		case pltx == nil:
+			s.last = blobTxs
+			return bltx
+		case bltx == nil:
+			s.last = plainTxs
+			return pltx
+		case ptip.Lt(btip):
+			s.last = blobTxs
+			return bltx
+		default:
+			s.last = plainTxs
+			return pltx
+		}
+	}
+	return nil
+}
+
+// Success implements TxSelector, shifting in the next transaction of the same
+// sender.
+func (s *priceSelector) Success(tx *txpool.LazyTransaction) {
+	s.last.Shift()
+}
+
+// Failure implements TxSelector, skipping either only the failed transaction,
+// or all the transactions of its sender if subsequent ones cannot succeed.
+func (s *priceSelector) Failure(tx *txpool.LazyTransaction, err error) {
+	switch {
+	case errors.Is(err, core.ErrNonceTooLow):
+		s.last.Shift()
+	case errors.Is(err, ErrBlobSpaceExhausted):
+		// No blob transaction fits, drop all of them in the current stage
+		s.stages[0][1].Clear()
+	default:
+		s.last.Pop()
+	}
+}
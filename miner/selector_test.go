@@ -0,0 +1,112 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// recordingSelector is a transaction selector yielding the transactions in the
+// order they were given, dropping a sender's transactions at its first failure.
+type recordingSelector struct {
+	txs      []*txpool.LazyTransaction
+	skip     map[common.Hash]bool // Transactions to reject before inclusion
+	included []common.Hash
+	failed   []common.Hash
+}
+
+func (s *recordingSelector) Next() *txpool.LazyTransaction {
+	for len(s.txs) > 0 {
+		tx := s.txs[0]
+		if s.skip[tx.Hash] {
+			s.txs = s.txs[1:]
+			continue
+		}
+		return tx
+	}
+	return nil
+}
+
+func (s *recordingSelector) Success(tx *txpool.LazyTransaction) {
+	s.included = append(s.included, tx.Hash)
+	s.txs = s.txs[1:]
+}
+
+func (s *recordingSelector) Failure(tx *txpool.LazyTransaction, err error) {
+	s.failed = append(s.failed, tx.Hash)
+	s.txs = s.txs[1:]
+}
+
+// Tests that a custom transaction selector configured in the miner is used to
+// order the transactions of the built block.
+func TestCustomTxSelector(t *testing.T) {
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		b        = newTestWorkerBackend(t, params.TestChainConfig, ethash.NewFaker(), db, 0)
+		signer   = types.LatestSigner(params.TestChainConfig)
+		selector *recordingSelector
+	)
+	var txs []*types.Transaction
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		txs = append(txs, types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(params.InitialBaseFee),
+		}))
+	}
+	b.txPool.Add(txs, true)
+
+	config := testConfig
+	config.TxSelector = func(ctx *TxSelectionContext, plainTxs, blobTxs map[common.Address][]*txpool.LazyTransaction) TxSelector {
+		// Yield the transactions in nonce order, but skip the middle one, which
+		// renders the last one unincludable
+		selector = &recordingSelector{
+			txs:  plainTxs[testBankAddress],
+			skip: map[common.Hash]bool{txs[1].Hash(): true},
+		}
+		return selector
+	}
+	w := New(b, config, ethash.NewFaker())
+
+	result := w.generateWork(&generateParams{
+		parentHash: b.chain.CurrentBlock().Hash(),
+		timestamp:  b.chain.CurrentHeader().Time + 1,
+		coinbase:   testBankAddress,
+	}, false)
+	if result.err != nil {
+		t.Fatalf("failed to generate work: %v", result.err)
+	}
+	if have := result.block.Transactions(); len(have) != 1 || have[0].Hash() != txs[0].Hash() {
+		t.Fatalf("block transactions mismatch: have %d, want [%x]", len(have), txs[0].Hash())
+	}
+	if len(selector.included) != 1 || selector.included[0] != txs[0].Hash() {
+		t.Errorf("included transactions mismatch: have %x, want [%x]", selector.included, txs[0].Hash())
+	}
+	if len(selector.failed) != 1 || selector.failed[0] != txs[2].Hash() {
+		t.Errorf("failed transactions mismatch: have %x, want [%x]", selector.failed, txs[2].Hash())
+	}
+}
@@ -41,6 +41,9 @@ var (
 	errBlockInterruptedByNewHead  = errors.New("new head arrived while building block")
 	errBlockInterruptedByRecommit = errors.New("recommit interrupt while building block")
 	errBlockInterruptedByTimeout  = errors.New("timeout while building block")
+	errMaxBlobsReached            = errors.New("max data blobs reached")
+	errTxEvicted                  = errors.New("transaction evicted from pool")
+	errTxReplayProtected          = errors.New("replay protected transaction before EIP155")
 )
 
 // environment is the worker's current environment and holds all
@@ -287,7 +290,7 @@ func (miner *Miner) commitBlobTransaction(env *environment, tx *types.Transactio
 	// tx has too many blobs. So we have to explicitly check it here.
 	maxBlobs := eip4844.MaxBlobsPerBlock(miner.chainConfig, env.header.Time)
 	if env.blobs+len(sc.Blobs) > maxBlobs {
-		return errors.New("max data blobs reached")
+		return errMaxBlobsReached
 	}
 	receipt, err := miner.applyTransaction(env, tx)
 	if err != nil {
@@ -316,7 +319,10 @@ func (miner *Miner) applyTransaction(env *environment, tx *types.Transaction) (*
 	return receipt, err
 }
 
-func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce, interrupt *atomic.Int32) error {
+// commitTransactions fills the block with the transactions yielded by the given
+// selector until the block is full, the selector runs dry or building is
+// interrupted.
+func (miner *Miner) commitTransactions(env *environment, selector TxSelector, interrupt *atomic.Int32) error {
 	gasLimit := env.header.GasLimit
 	if env.gasPool == nil {
 		env.gasPool = new(core.GasPool).AddGas(gasLimit)
@@ -333,51 +339,33 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			log.Trace("Not enough gas for further transactions", "have", env.gasPool, "want", params.TxGas)
 			break
 		}
-		// If we don't have enough blob space for any further blob transactions,
-		// skip that list altogether
-		if !blobTxs.Empty() && env.blobs >= eip4844.MaxBlobsPerBlock(miner.chainConfig, env.header.Time) {
-			log.Trace("Not enough blob space for further blob transactions")
-			blobTxs.Clear()
-			// Fall though to pick up any plain txs
-		}
 		// Retrieve the next transaction and abort if all done.
-		var (
-			ltx *txpool.LazyTransaction
-			txs *transactionsByPriceAndNonce
-		)
-		pltx, ptip := plainTxs.Peek()
-		bltx, btip := blobTxs.Peek()
-
-		switch {
-		case pltx == nil:
-			txs, ltx = blobTxs, bltx
-		case bltx == nil:
-			txs, ltx = plainTxs, pltx
-		default:
-			if ptip.Lt(btip) {
-				txs, ltx = blobTxs, bltx
-			} else {
-				txs, ltx = plainTxs, pltx
-			}
-		}
+		ltx := selector.Next()
 		if ltx == nil {
 			break
 		}
 		// If we don't have enough space for the next transaction, skip the account.
 		if env.gasPool.Gas() < ltx.Gas {
 			log.Trace("Not enough gas left for transaction", "hash", ltx.Hash, "left", env.gasPool.Gas(), "needed", ltx.Gas)
-			txs.Pop()
+			selector.Failure(ltx, core.ErrGasLimitReached)
 			continue
 		}
 
 		// Most of the blob gas logic here is agnostic as to if the chain supports
 		// blobs or not, however the max check panics when called on a chain without
 		// a defined schedule, so we need to verify it's safe to call.
-		if miner.chainConfig.IsCancun(env.header.Number, env.header.Time) {
+		if ltx.BlobGas > 0 && miner.chainConfig.IsCancun(env.header.Number, env.header.Time) {
 			left := eip4844.MaxBlobsPerBlock(miner.chainConfig, env.header.Time) - env.blobs
+			if left <= 0 {
+				// If we don't have enough blob space for any further blob transactions,
+				// skip them altogether
+				log.Trace("Not enough blob space for further blob transactions")
+				selector.Failure(ltx, ErrBlobSpaceExhausted)
+				continue
+			}
 			if left < int(ltx.BlobGas/params.BlobTxBlobGasPerBlob) {
 				log.Trace("Not enough blob space left for transaction", "hash", ltx.Hash, "left", left, "needed", ltx.BlobGas/params.BlobTxBlobGasPerBlob)
-				txs.Pop()
+				selector.Failure(ltx, errMaxBlobsReached)
 				continue
 			}
 		}
@@ -386,7 +374,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		tx := ltx.Resolve()
 		if tx == nil {
 			log.Trace("Ignoring evicted transaction", "hash", ltx.Hash)
-			txs.Pop()
+			selector.Failure(ltx, errTxEvicted)
 			continue
 		}
 
@@ -398,7 +386,13 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// phase, start ignoring the sender until we do.
 		if tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number) {
 			log.Trace("Ignoring replay protected transaction", "hash", ltx.Hash, "eip155", miner.chainConfig.EIP155Block)
-			txs.Pop()
+			selector.Failure(ltx, errTxReplayProtected)
+			continue
+		}
+		// Skip the sender if the transaction is outside of its validity window
+		if err := tx.ValidAt(env.header.Time); err != nil {
+			log.Trace("Ignoring transaction outside of validity window", "hash", ltx.Hash, "err", err)
+			selector.Failure(ltx, err)
 			continue
 		}
 		// Start executing the transaction
@@ -409,17 +403,99 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		case errors.Is(err, core.ErrNonceTooLow):
 			// New head notification data race between the transaction pool and miner, shift
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
-			txs.Shift()
+			selector.Failure(ltx, err)
 
 		case errors.Is(err, nil):
 			// Everything ok, collect the logs and shift in the next transaction from the same account
-			txs.Shift()
+			selector.Success(ltx)
 
 		default:
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
 			log.Debug("Transaction failed, account skipped", "hash", ltx.Hash, "err", err)
-			txs.Pop()
+			selector.Failure(ltx, err)
+		}
+	}
+	return nil
+}
+
+// commitGroups tries to include the given atomic transaction groups in order.
+// A group is either included in its entirety, or the block is left untouched.
+func (miner *Miner) commitGroups(env *environment, groups []*txpool.TxGroup, tip *big.Int, interrupt *atomic.Int32) error {
+	if env.gasPool == nil {
+		env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	}
+	// Reverting a group relies on the state not being flushed into the tries
+	// after each transaction, which only holds from Byzantium on.
+	if !miner.chainConfig.IsByzantium(env.header.Number) {
+		return nil
+	}
+	for _, group := range groups {
+		// Check interruption signal and abort building if it's fired.
+		if interrupt != nil {
+			if signal := interrupt.Load(); signal != commitInterruptNone {
+				return signalToErr(signal)
+			}
+		}
+		// If we don't have enough space for the entire group, skip it
+		if env.gasPool.Gas() < group.Gas() {
+			log.Trace("Not enough gas left for transaction group", "hash", group.Hash, "left", env.gasPool.Gas(), "needed", group.Gas())
+			continue
+		}
+		if err := miner.commitGroup(env, group, tip); err != nil {
+			log.Debug("Transaction group failed, skipped", "hash", group.Hash, "err", err)
+		}
+	}
+	return nil
+}
+
+// commitGroup applies all the transactions of a group on top of the environment.
+// If any of them is rejected or its execution fails, all the changes made by the
+// group are reverted.
+func (miner *Miner) commitGroup(env *environment, group *txpool.TxGroup, tip *big.Int) error {
+	// The journal is flushed after each transaction, so plain snapshots cannot
+	// span multiple ones. Retain it across the group with a checkpoint instead.
+	var (
+		gas      = env.gasPool.Gas()
+		gasUsed  = env.header.GasUsed
+		tcount   = env.tcount
+		txs      = len(env.txs)
+		receipts = len(env.receipts)
+	)
+	env.state.Checkpoint()
+	err := miner.applyGroup(env, group, tip)
+	if err != nil {
+		env.state.RevertToCheckpoint()
+		env.gasPool.SetGas(gas)
+		env.header.GasUsed = gasUsed
+		env.tcount = tcount
+		env.txs = env.txs[:txs]
+		env.receipts = env.receipts[:receipts]
+	} else {
+		env.state.DiscardCheckpoint()
+	}
+	return err
+}
+
+// applyGroup executes the transactions of a group one after the other, aborting
+// at the first one which cannot be included or whose execution fails.
+func (miner *Miner) applyGroup(env *environment, group *txpool.TxGroup, tip *big.Int) error {
+	for _, tx := range group.Txs {
+		if tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number) {
+			return fmt.Errorf("replay protected transaction %x before EIP155", tx.Hash())
+		}
+		if err := tx.ValidAt(env.header.Time); err != nil {
+			return fmt.Errorf("transaction %x: %w", tx.Hash(), err)
+		}
+		if tip != nil && tx.EffectiveGasTipIntCmp(tip, env.header.BaseFee) < 0 {
+			return fmt.Errorf("transaction %x: tip below miner minimum", tx.Hash())
+		}
+		env.state.SetTxContext(tx.Hash(), env.tcount)
+		if err := miner.commitTransaction(env, tx); err != nil {
+			return fmt.Errorf("transaction %x: %w", tx.Hash(), err)
+		}
+		if receipt := env.receipts[len(env.receipts)-1]; receipt.Status != types.ReceiptStatusSuccessful {
+			return fmt.Errorf("transaction %x: execution reverted", tx.Hash())
 		}
 	}
 	return nil
@@ -427,11 +503,12 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 
 // fillTransactions retrieves the pending transactions from the txpool and fills them
 // into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// be customized via the TxSelector of the miner config.
 func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment) error {
 	miner.confMu.RLock()
 	tip := miner.config.GasPrice
 	prio := miner.prio
+	newSelector := miner.config.TxSelector
 	miner.confMu.RUnlock()
 
 	// Retrieve the pending transactions pre-filtered by the 1559/4844 dynamic fees
@@ -450,38 +527,23 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := miner.txpool.Pending(filter)
 
-	// Split the pending transactions into locals and remotes.
-	prioPlainTxs, normalPlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
-	prioBlobTxs, normalBlobTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
-
-	for _, account := range prio {
-		if txs := normalPlainTxs[account]; len(txs) > 0 {
-			delete(normalPlainTxs, account)
-			prioPlainTxs[account] = txs
-		}
-		if txs := normalBlobTxs[account]; len(txs) > 0 {
-			delete(normalBlobTxs, account)
-			prioBlobTxs[account] = txs
+	// Include the atomic transaction groups first, they are the most sensitive
+	// to ordering and are not considered by the transaction selector.
+	if groups := miner.txpool.PendingGroups(); len(groups) > 0 {
+		if err := miner.commitGroups(env, groups, tip, interrupt); err != nil {
+			return err
 		}
 	}
 	// Fill the block with all available pending transactions.
-	if len(prioPlainTxs) > 0 || len(prioBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, prioPlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, prioBlobTxs, env.header.BaseFee)
-
-		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
-			return err
-		}
+	if newSelector == nil {
+		newSelector = NewPriceSelector
 	}
-	if len(normalPlainTxs) > 0 || len(normalBlobTxs) > 0 {
-		plainTxs := newTransactionsByPriceAndNonce(env.signer, normalPlainTxs, env.header.BaseFee)
-		blobTxs := newTransactionsByPriceAndNonce(env.signer, normalBlobTxs, env.header.BaseFee)
-
-		if err := miner.commitTransactions(env, plainTxs, blobTxs, interrupt); err != nil {
-			return err
-		}
+	ctx := &TxSelectionContext{
+		Header:   env.header,
+		Signer:   env.signer,
+		Priority: prio,
 	}
-	return nil
+	return miner.commitTransactions(env, newSelector(ctx, pendingPlainTxs, pendingBlobTxs), interrupt)
 }
 
 // totalFees computes total consumed miner fees in Wei. Block transactions and receipts have to have the same order.
@@ -0,0 +1,330 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	apiKeyHeader         = "X-API-Key"    // Request header carrying the API key
+	apiKeyQueryParam     = "apikey"       // Query parameter carrying the API key, if enabled in the key file
+	apiKeyIdentityPrefix = "apikey:"      // Prefix of the RPC client identity of API key holders
+	apiKeyMinLength      = 16             // Minimum length of API key secrets
+	apiKeyReloadInterval = time.Second    // Minimum interval between checks for API key file changes
+	apiKeyDefaultWindow  = 24 * time.Hour // Default quota window
+)
+
+var errInvalidAPIKey = errors.New("invalid API key")
+
+// apiKeyFile is the format of the API key file.
+//
+//	{
+//	  "keys": [
+//	    {"name": "alice", "key": "...", "modules": ["eth", "net"], "quota": 1000000, "quotaWindow": "24h"}
+//	  ],
+//	  "computeUnits": {"eth_getLogs": 75, "debug": 100},
+//	  "allowQueryParam": false
+//	}
+//
+// Keys are read from the X-API-Key header. Clients unable to set headers, such
+// as browser WebSockets, may pass them in the apikey query parameter instead if
+// allowQueryParam is set. This is off by default, as URLs end up in proxy and
+// access logs.
+type apiKeyFile struct {
+	Keys            []apiKeyConfig    `json:"keys"`
+	ComputeUnits    map[string]uint64 `json:"computeUnits,omitempty"`    // Cost of methods or namespaces, 1 by default
+	AllowQueryParam bool              `json:"allowQueryParam,omitempty"` // Whether keys are accepted in the URL query
+}
+
+// apiKeyConfig is the definition of a single API key in the API key file.
+type apiKeyConfig struct {
+	Name        string   `json:"name"`                  // Name of the key holder, used in logs and metrics
+	Key         string   `json:"key"`                   // Secret presented by the key holder
+	Modules     []string `json:"modules,omitempty"`     // Modules accessible with the key, all exposed ones if empty
+	Quota       uint64   `json:"quota,omitempty"`       // Compute units available per quota window, unlimited if zero
+	QuotaWindow string   `json:"quotaWindow,omitempty"` // Length of the quota window, 24h by default
+}
+
+// apiKey is a validated API key along with its usage state.
+type apiKey struct {
+	name    string
+	modules map[string]bool
+	quota   uint64
+	window  time.Duration
+
+	requests *metrics.Counter
+	units    *metrics.Counter
+	rejected *metrics.Counter
+}
+
+// apiKeyUsage tracks the compute units consumed by a key in the current quota
+// window. It is kept by key name, so it survives reloads of the key file.
+type apiKeyUsage struct {
+	start time.Time
+	used  uint64
+}
+
+// apiKeyStore holds the API keys loaded from a file, reloading them whenever the
+// file changes. It authenticates HTTP requests and authorizes individual calls
+// of the authenticated clients.
+type apiKeyStore struct {
+	path string
+	log  log.Logger
+	now  func() time.Time
+
+	lock    sync.Mutex
+	secrets map[string]*apiKey // Keys indexed by secret
+	names   map[string]*apiKey // Keys indexed by name
+	costs   map[string]uint64  // Compute units of methods and namespaces
+	query   bool               // Whether keys are accepted in the URL query
+	usage   map[string]*apiKeyUsage
+	modTime time.Time // Modification time of the loaded file
+	checked time.Time // Last time the file was checked for changes
+}
+
+// newAPIKeyStore loads the API keys from the given file.
+func newAPIKeyStore(path string, logger log.Logger) (*apiKeyStore, error) {
+	s := &apiKeyStore{
+		path:  path,
+		log:   logger,
+		now:   time.Now,
+		usage: make(map[string]*apiKeyUsage),
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open API key file: %w", err)
+	}
+	if err := s.load(info.ModTime()); err != nil {
+		return nil, err
+	}
+	s.checked = s.now()
+	return s, nil
+}
+
+// load reads and validates the API key file, replacing the current keys if
+// successful. The caller must hold the lock, unless the store is not yet used.
+func (s *apiKeyStore) load(modTime time.Time) error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read API key file: %w", err)
+	}
+	var file apiKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("invalid API key file: %w", err)
+	}
+	var (
+		secrets = make(map[string]*apiKey)
+		names   = make(map[string]*apiKey)
+	)
+	for i, config := range file.Keys {
+		switch {
+		case config.Name == "" || strings.ContainsAny(config.Name, "/ "):
+			return fmt.Errorf("API key %d: invalid name %q", i, config.Name)
+		case names[config.Name] != nil:
+			return fmt.Errorf("API key %d: duplicate name %q", i, config.Name)
+		case len(config.Key) < apiKeyMinLength:
+			return fmt.Errorf("API key %q: key shorter than %d characters", config.Name, apiKeyMinLength)
+		case secrets[config.Key] != nil:
+			return fmt.Errorf("API key %q: duplicate key", config.Name)
+		}
+		window := apiKeyDefaultWindow
+		if config.QuotaWindow != "" {
+			if window, err = time.ParseDuration(config.QuotaWindow); err != nil || window <= 0 {
+				return fmt.Errorf("API key %q: invalid quota window %q", config.Name, config.QuotaWindow)
+			}
+		}
+		key := &apiKey{
+			name:     config.Name,
+			quota:    config.Quota,
+			window:   window,
+			requests: metrics.GetOrRegisterCounter("rpc/apikey/"+config.Name+"/requests", nil),
+			units:    metrics.GetOrRegisterCounter("rpc/apikey/"+config.Name+"/computeunits", nil),
+			rejected: metrics.GetOrRegisterCounter("rpc/apikey/"+config.Name+"/rejected", nil),
+		}
+		if len(config.Modules) > 0 {
+			key.modules = make(map[string]bool)
+			for _, module := range config.Modules {
+				key.modules[module] = true
+			}
+		}
+		secrets[config.Key] = key
+		names[config.Name] = key
+	}
+	s.secrets, s.names, s.costs = secrets, names, file.ComputeUnits
+	s.query = file.AllowQueryParam
+	s.modTime = modTime
+
+	// Drop the usage of removed keys
+	for name := range s.usage {
+		if names[name] == nil {
+			delete(s.usage, name)
+		}
+	}
+	s.log.Info("Loaded API key file", "path", s.path, "keys", len(secrets))
+	return nil
+}
+
+// maybeReload reloads the API key file if it changed since last loaded. Invalid
+// files are ignored, keeping the previous keys in effect. The caller must hold
+// the lock.
+func (s *apiKeyStore) maybeReload() {
+	now := s.now()
+	if now.Sub(s.checked) < apiKeyReloadInterval {
+		return
+	}
+	s.checked = now
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		s.log.Warn("Failed to check API key file", "path", s.path, "err", err)
+		return
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return
+	}
+	if err := s.load(info.ModTime()); err != nil {
+		s.log.Error("Failed to reload API key file", "path", s.path, "err", err)
+		s.modTime = info.ModTime() // don't retry until changed again
+	}
+}
+
+// lookup returns the key with the given secret, or nil if there is none. Keys
+// taken from the URL query are only accepted if enabled in the key file.
+func (s *apiKeyStore) lookup(secret string, fromQuery bool) *apiKey {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.maybeReload()
+	if fromQuery && !s.query {
+		return nil
+	}
+	return s.secrets[secret]
+}
+
+// cost returns the compute units of a method. The caller must hold the lock.
+func (s *apiKeyStore) cost(method string) uint64 {
+	if cost, ok := s.costs[method]; ok {
+		return cost
+	}
+	if namespace, _, ok := strings.Cut(method, "_"); ok {
+		if cost, ok := s.costs[namespace]; ok {
+			return cost
+		}
+	}
+	return 1
+}
+
+// Authorize implements rpc.Authorizer, checking the module permissions and the
+// quota of the API key the call was authenticated with, and accounting for its
+// usage.
+func (s *apiKeyStore) Authorize(ctx context.Context, method string) error {
+	name, ok := strings.CutPrefix(rpc.PeerInfoFromContext(ctx).Identity, apiKeyIdentityPrefix)
+	if !ok {
+		return errInvalidAPIKey
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.maybeReload()
+	key := s.names[name]
+	if key == nil {
+		return errInvalidAPIKey // revoked since the connection was established
+	}
+	if key.modules != nil {
+		namespace, _, _ := strings.Cut(method, "_")
+		if namespace != rpc.MetadataApi && !key.modules[namespace] {
+			key.rejected.Inc(1)
+			return fmt.Errorf("the method %s is not permitted for API key %s", method, name)
+		}
+	}
+	cost := s.cost(method)
+	if key.quota > 0 {
+		now := s.now()
+		usage := s.usage[name]
+		if usage == nil || now.Sub(usage.start) >= key.window {
+			usage = &apiKeyUsage{start: now}
+			s.usage[name] = usage
+		}
+		if usage.used+cost > key.quota {
+			key.rejected.Inc(1)
+			return &quotaExceededError{name: name, retryAfter: usage.start.Add(key.window).Sub(now)}
+		}
+		usage.used += cost
+	}
+	key.requests.Inc(1)
+	key.units.Inc(int64(cost))
+	return nil
+}
+
+// quotaExceededError is returned for calls exceeding the quota of an API key.
+type quotaExceededError struct {
+	name       string
+	retryAfter time.Duration
+}
+
+func (e *quotaExceededError) ErrorCode() int { return rpc.ErrcodeRateLimited }
+
+func (e *quotaExceededError) Error() string {
+	return fmt.Sprintf("quota of API key %s exceeded", e.name)
+}
+
+func (e *quotaExceededError) ErrorData() interface{} {
+	return map[string]int{"retryAfter": int(e.retryAfter.Round(time.Second).Seconds())}
+}
+
+// apiKeyHandler is a http.Handler authenticating requests with an API key.
+type apiKeyHandler struct {
+	keys *apiKeyStore
+	next http.Handler
+}
+
+// newAPIKeyHandler creates a http.Handler with API key authentication support.
+func newAPIKeyHandler(keys *apiKeyStore, next http.Handler) http.Handler {
+	return &apiKeyHandler{keys: keys, next: next}
+}
+
+// ServeHTTP implements http.Handler
+func (handler *apiKeyHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
+	secret, fromQuery := r.Header.Get(apiKeyHeader), false
+	if secret == "" {
+		secret, fromQuery = r.URL.Query().Get(apiKeyQueryParam), true
+	}
+	if secret == "" {
+		http.Error(out, "missing API key", http.StatusUnauthorized)
+		return
+	}
+	key := handler.keys.lookup(secret, fromQuery)
+	if key == nil {
+		http.Error(out, errInvalidAPIKey.Error(), http.StatusUnauthorized)
+		return
+	}
+	r = r.WithContext(rpc.WithClientIdentity(r.Context(), apiKeyIdentityPrefix+key.name))
+	handler.next.ServeHTTP(out, r)
+}
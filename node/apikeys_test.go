@@ -0,0 +1,137 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	testAliceKey = "alice-0123456789abcdef"
+	testBobKey   = "bob-0123456789abcdef"
+)
+
+// Tests that API keys are required on the endpoints, and that the module
+// permissions, quotas and reloads of the keys are respected.
+func TestAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apikeys.json")
+	writeKeys := func(content string, mtime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeKeys(`{
+		"keys": [
+			{"name": "alice", "key": "`+testAliceKey+`", "quota": 5},
+			{"name": "bob", "key": "`+testBobKey+`", "modules": ["web3"]}
+		],
+		"computeUnits": {"test_greet": 2},
+		"allowQueryParam": true
+	}`, time.Unix(1000, 0))
+
+	keys, err := newAPIKeyStore(path, testlog.Logger(t, log.LvlDebug))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &httpConfig{Modules: []string{"test"}, rpcEndpointConfig: rpcEndpointConfig{apiKeys: keys}}
+	srv := createAndStartServer(t, conf, true, &wsConfig{Modules: []string{"test"}, rpcEndpointConfig: rpcEndpointConfig{apiKeys: keys}}, nil)
+	defer srv.stop()
+	url := "http://" + srv.listenAddr()
+
+	// Requests without a valid key are rejected
+	if resp := rpcRequest(t, url, "test_greet"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("missing key: wrong status %d", resp.StatusCode)
+	}
+	if resp := rpcRequest(t, url, "test_greet", apiKeyHeader, "bogus"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("invalid key: wrong status %d", resp.StatusCode)
+	}
+	if err := wsRequest(t, "ws://"+srv.listenAddr()); err == nil {
+		t.Fatal("websocket without key accepted")
+	}
+	dial := func(rawurl, key string) *rpc.Client {
+		client, err := rpc.DialOptions(context.Background(), rawurl, rpc.WithHeader(apiKeyHeader, key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+	// Calls are restricted to the modules of the key
+	bob := dial("ws://"+srv.listenAddr()+"?"+apiKeyQueryParam+"="+testBobKey, "")
+	defer bob.Close()
+	var greeting string
+	if err := bob.Call(&greeting, "test_greet"); err == nil || !strings.Contains(err.Error(), "not permitted") {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+	if err := bob.Call(nil, "rpc_modules"); err != nil {
+		t.Fatalf("metadata call failed: %v", err)
+	}
+	if err := bob.Call(nil, "rpc.discover"); err != nil {
+		t.Fatalf("discovery call failed: %v", err)
+	}
+	// Calls are restricted to the quota of the key
+	alice := dial(url, testAliceKey)
+	defer alice.Close()
+	for i := 0; i < 2; i++ {
+		if err := alice.Call(&greeting, "test_greet"); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+	}
+	err = alice.Call(&greeting, "test_greet")
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != rpc.ErrcodeRateLimited {
+		t.Fatalf("expected quota error, got %v", err)
+	}
+	if err := alice.Call(nil, "rpc_modules"); err != nil {
+		t.Fatalf("call within quota failed: %v", err)
+	}
+	if have := keys.names["alice"].units.Snapshot().Count(); have != 5 {
+		t.Errorf("wrong compute unit count: have %d, want 5", have)
+	}
+	if have := keys.names["alice"].rejected.Snapshot().Count(); have != 1 {
+		t.Errorf("wrong rejected count: have %d, want 1", have)
+	}
+	// Revoked keys are rejected after reloading, also on open connections
+	writeKeys(`{"keys": [{"name": "alice", "key": "`+testAliceKey+`"}]}`, time.Unix(2000, 0))
+	keys.lock.Lock()
+	keys.checked = time.Time{}
+	keys.lock.Unlock()
+
+	if err := bob.Call(nil, "rpc_modules"); err == nil || err.Error() != errInvalidAPIKey.Error() {
+		t.Fatalf("expected revoked key error, got %v", err)
+	}
+	if err := alice.Call(&greeting, "test_greet"); err != nil {
+		t.Fatalf("call with lifted quota failed: %v", err)
+	}
+	// Keys in the URL query are rejected unless enabled in the key file
+	if resp := rpcRequest(t, url+"/?"+apiKeyQueryParam+"="+testAliceKey, "test_greet"); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("query key: wrong status %d", resp.StatusCode)
+	}
+}
@@ -130,6 +130,10 @@ type Config struct {
 	// exposed.
 	HTTPModules []string
 
+	// HTTPMethods restricts the individual methods callable via the HTTP RPC
+	// interface within the exposed modules.
+	HTTPMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// HTTPTimeouts allows for customization of the timeout values used by the HTTP RPC
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
@@ -137,6 +141,10 @@ type Config struct {
 	// HTTPPathPrefix specifies a path prefix on which http-rpc is to be served.
 	HTTPPathPrefix string `toml:",omitempty"`
 
+	// HTTPSSE enables subscriptions over the HTTP RPC interface, served as streams
+	// of server-sent events.
+	HTTPSSE bool `toml:",omitempty"`
+
 	// AuthAddr is the listening address on which authenticated APIs are provided.
 	AuthAddr string `toml:",omitempty"`
 
@@ -147,6 +155,10 @@ type Config struct {
 	// for the authenticated api. This is by default {'localhost'}.
 	AuthVirtualHosts []string `toml:",omitempty"`
 
+	// AuthMethods restricts the individual methods callable via the authenticated
+	// HTTP and WebSocket RPC interfaces within the exposed modules.
+	AuthMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
@@ -169,6 +181,10 @@ type Config struct {
 	// exposed.
 	WSModules []string
 
+	// WSMethods restricts the individual methods callable via the websocket RPC
+	// interface within the exposed modules.
+	WSMethods rpc.MethodFilter `toml:",omitempty"`
+
 	// WSExposeAll exposes all API modules via the WebSocket RPC interface rather
 	// than just the public ones.
 	//
@@ -190,6 +206,14 @@ type Config struct {
 	// Requests using ip address directly are not affected
 	GraphQLVirtualHosts []string `toml:",omitempty"`
 
+	// GRPCCors is the Cross-Origin Resource Sharing header to send to requesting
+	// gRPC-Web and Connect clients.
+	GRPCCors []string `toml:",omitempty"`
+
+	// GRPCVirtualHosts is the list of virtual hostnames which are allowed on incoming
+	// gRPC requests. This is by default {'localhost'}, see GraphQLVirtualHosts.
+	GRPCVirtualHosts []string `toml:",omitempty"`
+
 	// Logger is a custom logger to use with the p2p.Server.
 	Logger log.Logger `toml:",omitempty"`
 
@@ -204,13 +228,36 @@ type Config struct {
 	// BatchResponseMaxSize is the maximum number of bytes returned from a batched rpc call.
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// RPCRateLimit configures the per-client call rate limits of the public HTTP
+	// and WebSocket RPC endpoints. Clients are identified by their JWT subject if
+	// authenticated, or by their IP address otherwise.
+	RPCRateLimit rpc.RateLimitConfig `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
 
+	// APIKeyFile is the path to a JSON file of named API keys required to access
+	// the public HTTP and WebSocket RPC endpoints. Each key may be restricted to
+	// certain modules and a compute unit quota. The file is reloaded on change.
+	APIKeyFile string `toml:",omitempty"`
+
 	// EnablePersonal enables the deprecated personal namespace.
 	EnablePersonal bool `toml:"-"`
 
 	DBEngine string `toml:",omitempty"`
+
+	// DBProfile is the name of the tuning profile of the pebble database, one of
+	// "default", "validator", "rpc" or "archive".
+	DBProfile string `toml:",omitempty"`
+
+	// DBNamespaces splits the index keyspaces of the chain database off into
+	// separate pebble instances, tuned independently by the profile. The first
+	// startup with it enabled blocks until the existing indices are moved.
+	DBNamespaces bool `toml:",omitempty"`
+
+	// AncientRemote is the URL of an S3 compatible bucket where the sealed data
+	// files of the chain freezer are offloaded to. Empty keeps all data locally.
+	AncientRemote string `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
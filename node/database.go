@@ -18,23 +18,30 @@ package node
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethdb/leveldb"
 	"github.com/ethereum/go-ethereum/ethdb/pebble"
+	"github.com/ethereum/go-ethereum/ethdb/s3"
 	"github.com/ethereum/go-ethereum/log"
 )
 
 // openOptions contains the options to apply when opening a database.
 // OBS: If AncientsDirectory is empty, it indicates that no freezer is to be used.
 type openOptions struct {
-	Type              string // "leveldb" | "pebble"
-	Directory         string // the datadir
-	AncientsDirectory string // the ancients-dir
-	Namespace         string // the namespace for database relevant metrics
-	Cache             int    // the capacity(in megabytes) of the data caching
-	Handles           int    // number of files to be open simultaneously
+	Type              string            // "leveldb" | "pebble"
+	Profile           string            // the tuning profile of pebble
+	Keyspaces         map[string][]byte // the keyspaces which may be stored in separate pebble instances
+	SplitKeyspaces    bool              // whether to split the keyspaces off, if not split yet
+	Directory         string            // the datadir
+	AncientsDirectory string            // the ancients-dir
+	AncientsRemote    string            // the URL of the remote tier of the ancients, if any
+	Namespace         string            // the namespace for database relevant metrics
+	Cache             int               // the capacity(in megabytes) of the data caching
+	Handles           int               // number of files to be open simultaneously
 	ReadOnly          bool
 
 	// Ephemeral means that filesystem sync operations should be avoided:
@@ -56,7 +63,17 @@ func openDatabase(o openOptions) (ethdb.Database, error) {
 	if len(o.AncientsDirectory) == 0 {
 		return kvdb, nil
 	}
-	frdb, err := rawdb.NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly)
+	var remote rawdb.RemoteStore
+	if o.AncientsRemote != "" {
+		bucket, err := s3.New(o.AncientsRemote)
+		if err != nil {
+			kvdb.Close()
+			return nil, fmt.Errorf("failed to open remote ancient store: %v", err)
+		}
+		log.Info("Offloading ancient data files to remote store", "location", bucket)
+		remote = bucket
+	}
+	frdb, err := rawdb.NewDatabaseWithTieredFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly, remote)
 	if err != nil {
 		kvdb.Close()
 		return nil, err
@@ -83,15 +100,18 @@ func openKeyValueDatabase(o openOptions) (ethdb.Database, error) {
 	}
 	if o.Type == rawdb.DBPebble || existingDb == rawdb.DBPebble {
 		log.Info("Using pebble as the backing database")
-		return newPebbleDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly, o.Ephemeral)
+		return newPebbleDBDatabase(o)
 	}
 	if o.Type == rawdb.DBLeveldb || existingDb == rawdb.DBLeveldb {
 		log.Info("Using leveldb as the backing database")
+		if o.SplitKeyspaces {
+			log.Warn("Database namespaces are only supported by pebble")
+		}
 		return newLevelDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly)
 	}
 	// No pre-existing database, no user-requested one either. Default to Pebble.
 	log.Info("Defaulting to pebble as the backing database")
-	return newPebbleDBDatabase(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly, o.Ephemeral)
+	return newPebbleDBDatabase(o)
 }
 
 // newLevelDBDatabase creates a persistent key-value database without a freezer
@@ -107,8 +127,20 @@ func newLevelDBDatabase(file string, cache int, handles int, namespace string, r
 
 // newPebbleDBDatabase creates a persistent key-value database without a freezer
 // moving immutable chain segments into cold storage.
-func newPebbleDBDatabase(file string, cache int, handles int, namespace string, readonly bool, ephemeral bool) (ethdb.Database, error) {
-	db, err := pebble.New(file, cache, handles, namespace, readonly, ephemeral)
+func newPebbleDBDatabase(o openOptions) (ethdb.Database, error) {
+	profile, err := pebble.ProfileByName(o.Profile)
+	if err != nil {
+		return nil, err
+	}
+	var spaces []pebble.Namespace
+	for name, prefix := range o.Keyspaces {
+		spaces = append(spaces, pebble.Namespace{Name: name, Prefix: prefix})
+	}
+	slices.SortFunc(spaces, func(a, b pebble.Namespace) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	log.Info("Using pebble database profile", "profile", profile.Name)
+	db, err := pebble.NewNamespaced(o.Directory, o.Cache, o.Handles, o.Namespace, o.ReadOnly, o.Ephemeral, profile, spaces, o.SplitKeyspaces)
 	if err != nil {
 		return nil, err
 	}
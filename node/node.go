@@ -66,6 +66,8 @@ type Node struct {
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
+	rpcCache rpc.ResponseCache // Optional response cache of the public RPC endpoints
+
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
 
@@ -359,6 +361,21 @@ func ObtainJWTSecret(fileName string) ([]byte, error) {
 	return jwtSecret, nil
 }
 
+// LoadJWTSecret loads the jwt-secret from the provided file. Unlike ObtainJWTSecret
+// it never generates a new secret, so clients which have to share the secret of an
+// existing server fail instead of silently authenticating with a fresh one.
+func LoadJWTSecret(fileName string) ([]byte, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	jwtSecret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(jwtSecret) != 32 {
+		return nil, fmt.Errorf("invalid JWT secret in %s: length %d", fileName, len(jwtSecret))
+	}
+	return jwtSecret, nil
+}
+
 // obtainJWTSecret loads the jwt-secret, either from the provided config,
 // or from the default location. If neither of those are present, it generates
 // a new secret and stores to the default location.
@@ -393,18 +410,30 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		rateLimits:             n.config.RPCRateLimit,
+		responseCache:          n.rpcCache,
+	}
+	if n.config.APIKeyFile != "" {
+		keys, err := newAPIKeyStore(n.config.APIKeyFile, n.log)
+		if err != nil {
+			return err
+		}
+		rpcConfig.apiKeys = keys
 	}
 
 	initHttp := func(server *httpServer, port int) error {
 		if err := server.setListenAddr(n.config.HTTPHost, port); err != nil {
 			return err
 		}
+		config := rpcConfig
+		config.methodFilter = n.config.HTTPMethods
 		if err := server.enableRPC(openAPIs, httpConfig{
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
-			rpcEndpointConfig:  rpcConfig,
+			sse:                n.config.HTTPSSE,
+			rpcEndpointConfig:  config,
 		}); err != nil {
 			return err
 		}
@@ -417,11 +446,13 @@ func (n *Node) startRPC() error {
 		if err := server.setListenAddr(n.config.WSHost, port); err != nil {
 			return err
 		}
+		config := rpcConfig
+		config.methodFilter = n.config.WSMethods
 		if err := server.enableWS(openAPIs, wsConfig{
 			Modules:           n.config.WSModules,
 			Origins:           n.config.WSOrigins,
 			prefix:            n.config.WSPathPrefix,
-			rpcEndpointConfig: rpcConfig,
+			rpcEndpointConfig: config,
 		}); err != nil {
 			return err
 		}
@@ -429,6 +460,14 @@ func (n *Node) startRPC() error {
 		return nil
 	}
 
+	// The authenticated endpoint serves the default modules and every namespace
+	// which was explicitly registered as authenticated.
+	authModules := slices.Clone(DefaultAuthModules)
+	for _, api := range allAPIs {
+		if api.Authenticated && !slices.Contains(authModules, api.Namespace) {
+			authModules = append(authModules, api.Namespace)
+		}
+	}
 	initAuth := func(port int, secret []byte) error {
 		// Enable auth via HTTP
 		server := n.httpAuth
@@ -440,11 +479,12 @@ func (n *Node) startRPC() error {
 			batchItemLimit:         engineAPIBatchItemLimit,
 			batchResponseSizeLimit: engineAPIBatchResponseSizeLimit,
 			httpBodyLimit:          engineAPIBodyLimit,
+			methodFilter:           n.config.AuthMethods,
 		}
 		err := server.enableRPC(allAPIs, httpConfig{
 			CorsAllowedOrigins: DefaultAuthCors,
 			Vhosts:             n.config.AuthVirtualHosts,
-			Modules:            DefaultAuthModules,
+			Modules:            authModules,
 			prefix:             DefaultAuthPrefix,
 			rpcEndpointConfig:  sharedConfig,
 		})
@@ -459,7 +499,7 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(allAPIs, wsConfig{
-			Modules:           DefaultAuthModules,
+			Modules:           authModules,
 			Origins:           DefaultAuthOrigins,
 			prefix:            DefaultAuthPrefix,
 			rpcEndpointConfig: sharedConfig,
@@ -529,6 +569,7 @@ func (n *Node) startInProc(apis []rpc.API) error {
 		if err := n.inprocHandler.RegisterName(api.Namespace, api.Service); err != nil {
 			return err
 		}
+		n.inprocHandler.SetMethodDocs(api.Docs)
 	}
 	return nil
 }
@@ -579,6 +620,18 @@ func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.rpcAPIs = append(n.rpcAPIs, apis...)
 }
 
+// SetRPCResponseCache sets the response cache of the public HTTP and WebSocket
+// RPC endpoints. It must be called before the node is started.
+func (n *Node) SetRPCResponseCache(cache rpc.ResponseCache) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != initializingState {
+		panic("can't set RPC response cache on running/stopped node")
+	}
+	n.rpcCache = cache
+}
+
 // getAPIs return two sets of APIs, both the ones that do not require
 // authentication, and the complete set
 func (n *Node) getAPIs() (unauthenticated, all []rpc.API) {
@@ -606,6 +659,17 @@ func (n *Node) RegisterHandler(name, path string, handler http.Handler) {
 	n.http.handlerNames[path] = name
 }
 
+// RegisterGRPCHandler mounts a gRPC handler on the given path on the canonical
+// HTTP server, like RegisterHandler. The server additionally accepts cleartext
+// HTTP/2 connections, as required by native gRPC clients.
+func (n *Node) RegisterGRPCHandler(name, path string, handler http.Handler) {
+	n.RegisterHandler(name, path, handler)
+
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.http.h2c = true
+}
+
 // Attach creates an RPC client attached to an in-process API handler.
 func (n *Node) Attach() *rpc.Client {
 	return rpc.DialInProc(n.inprocHandler)
@@ -713,6 +777,7 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string, r
 	} else {
 		db, err = openDatabase(openOptions{
 			Type:      n.config.DBEngine,
+			Profile:   n.config.DBProfile,
 			Directory: n.ResolvePath(name),
 			Namespace: namespace,
 			Cache:     cache,
@@ -744,8 +809,12 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 	} else {
 		db, err = openDatabase(openOptions{
 			Type:              n.config.DBEngine,
+			Profile:           n.config.DBProfile,
+			Keyspaces:         rawdb.SeparableKeyspaces(),
+			SplitKeyspaces:    n.config.DBNamespaces,
 			Directory:         n.ResolvePath(name),
 			AncientsDirectory: n.ResolveAncient(name, ancient),
+			AncientsRemote:    n.config.AncientRemote,
 			Namespace:         namespace,
 			Cache:             cache,
 			Handles:           handles,
@@ -782,6 +851,14 @@ type closeTrackingDB struct {
 	n *Node
 }
 
+// Backup implements ethdb.Backupper, if the wrapped database supports it.
+func (db *closeTrackingDB) Backup(dir string) error {
+	if b, ok := db.Database.(ethdb.Backupper); ok {
+		return b.Backup(dir)
+	}
+	return errors.New("database backup is not supported")
+}
+
 func (db *closeTrackingDB) Close() error {
 	db.n.lock.Lock()
 	delete(db.n.databases, db)
@@ -135,6 +135,12 @@ func TestAuthEndpoints(t *testing.T) {
 			Public:        true,
 			Authenticated: true,
 		},
+		{
+			Namespace:     "custom",
+			Version:       "1.0",
+			Service:       helloRPC("hello custom"),
+			Authenticated: true,
+		},
 	})
 	if err := node.Start(); err != nil {
 		t.Fatalf("failed to start test node: %v", err)
@@ -156,6 +162,17 @@ func TestAuthEndpoints(t *testing.T) {
 	}
 	badAuth := NewJWTAuth(otherSecret)
 
+	// authenticated namespaces outside the default modules are served too
+	cl, err := rpc.DialOptions(context.Background(), node.HTTPAuthEndpoint(), rpc.WithHTTPAuth(goodAuth))
+	if err != nil {
+		t.Fatalf("failed to dial auth endpoint: %v", err)
+	}
+	var hello string
+	if err := cl.Call(&hello, "custom_helloWorld"); err != nil {
+		t.Fatalf("authenticated custom namespace not served: %v", err)
+	}
+	cl.Close()
+
 	notTooLong := time.Second * 57
 	tooLong := time.Second * 60
 	requestDelay := time.Second
@@ -197,6 +214,38 @@ func TestAuthEndpoints(t *testing.T) {
 	}
 }
 
+// TestLoadJWTSecret checks that loading a secret never creates the file.
+func TestLoadJWTSecret(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	if _, err := LoadJWTSecret(missing); err == nil {
+		t.Fatal("expected error for missing secret file")
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Fatalf("secret file was created: %v", err)
+	}
+	short := filepath.Join(dir, "short")
+	if err := os.WriteFile(short, []byte("0x1234"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJWTSecret(short); err == nil {
+		t.Fatal("expected error for short secret")
+	}
+	var secret [32]byte
+	crand.Read(secret[:])
+	valid := filepath.Join(dir, "valid")
+	if err := os.WriteFile(valid, []byte(hexutil.Encode(secret[:])+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadJWTSecret(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if [32]byte(loaded) != secret {
+		t.Fatalf("wrong secret loaded: %x", loaded)
+	}
+}
+
 func noneAuth(secret [32]byte) rpc.HTTPAuth {
 	return func(header http.Header) error {
 		token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
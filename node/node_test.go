@@ -474,6 +474,41 @@ type rpcPrefixTest struct {
 	wantNoWS   []string
 }
 
+// Tests that the method annotations of registered APIs end up in the document
+// served by rpc.discover.
+func TestRegisterAPIDocs(t *testing.T) {
+	config := &Config{HTTPHost: "127.0.0.1", HTTPModules: []string{"hello"}}
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create node: %v", err)
+	}
+	defer stack.Close()
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "hello",
+		Service:   helloRPC("hello"),
+		Docs:      map[string]rpc.MethodDoc{"hello_helloWorld": {Summary: "Greets the world."}},
+	}})
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start node: %v", err)
+	}
+	httpClient, err := rpc.Dial(stack.HTTPEndpoint())
+	if err != nil {
+		t.Fatalf("failed to dial: %v", err)
+	}
+	defer httpClient.Close()
+
+	for name, client := range map[string]*rpc.Client{"http": httpClient, "inproc": stack.Attach()} {
+		var doc rpc.OpenRPCDocument
+		if err := client.Call(&doc, "rpc.discover"); err != nil {
+			t.Fatalf("%s: discovery failed: %v", name, err)
+		}
+		idx := slices.IndexFunc(doc.Methods, func(m rpc.OpenRPCMethod) bool { return m.Name == "hello_helloWorld" })
+		if idx < 0 || doc.Methods[idx].Summary != "Greets the world." {
+			t.Errorf("%s: method not annotated: %+v", name, doc.Methods)
+		}
+	}
+}
+
 func TestNodeRPCPrefix(t *testing.T) {
 	t.Parallel()
 
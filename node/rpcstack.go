@@ -34,6 +34,8 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/cors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // httpConfig is the JSON-RPC/HTTP configuration.
@@ -42,6 +44,7 @@ type httpConfig struct {
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
+	sse                bool   // serve subscriptions as server-sent events
 	rpcEndpointConfig
 }
 
@@ -58,6 +61,10 @@ type rpcEndpointConfig struct {
 	batchItemLimit         int
 	batchResponseSizeLimit int
 	httpBodyLimit          int
+	rateLimits             rpc.RateLimitConfig
+	methodFilter           rpc.MethodFilter
+	apiKeys                *apiKeyStore      // optional API key authentication
+	responseCache          rpc.ResponseCache // optional cache of deterministic calls
 }
 
 type rpcHandler struct {
@@ -73,6 +80,7 @@ type httpServer struct {
 	mu       sync.Mutex
 	server   *http.Server
 	listener net.Listener // non-nil when server is running
+	h2c      bool         // accept cleartext HTTP/2, set if a gRPC handler is mounted
 
 	// HTTP RPC handler things.
 
@@ -138,8 +146,12 @@ func (h *httpServer) start() error {
 		return nil // already running or not configured
 	}
 
-	// Initialize the server.
+	// Initialize the server. Cleartext HTTP/2 is only accepted if native gRPC
+	// clients are served.
 	h.server = &http.Server{Handler: h}
+	if h.h2c {
+		h.server.Handler = h2c.NewHandler(h, &http2.Server{IdleTimeout: h.timeouts.IdleTimeout})
+	}
 	if h.timeouts != (rpc.HTTPTimeouts{}) {
 		CheckTimeouts(&h.timeouts)
 		h.server.ReadTimeout = h.timeouts.ReadTimeout
@@ -309,12 +321,30 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetRateLimits(config.rateLimits)
+	srv.SetMethodFilter(config.methodFilter)
+	srv.SetResponseCache(config.responseCache)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	var handler http.Handler = srv
+	if config.sse {
+		sse := srv.SSEHandler()
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rpc.IsSSERequest(r) {
+				sse.ServeHTTP(w, r)
+				return
+			}
+			srv.ServeHTTP(w, r)
+		})
+	}
+	if config.apiKeys != nil {
+		srv.SetAuthorizer(config.apiKeys)
+		handler = newAPIKeyHandler(config.apiKeys, handler)
+	}
 	h.httpConfig = config
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
+		Handler: NewHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -344,12 +374,20 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
+	srv.SetRateLimits(config.rateLimits)
+	srv.SetMethodFilter(config.methodFilter)
+	srv.SetResponseCache(config.responseCache)
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
+	handler := srv.WebsocketHandler(config.Origins)
+	if config.apiKeys != nil {
+		srv.SetAuthorizer(config.apiKeys)
+		handler = newAPIKeyHandler(config.apiKeys, handler)
+	}
 	h.wsConfig = config
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret),
+		Handler: NewWSHandlerStack(handler, config.jwtSecret),
 		server:  srv,
 	})
 	return nil
@@ -404,6 +442,13 @@ func NewHTTPHandlerStack(srv http.Handler, cors []string, vhosts []string, jwtSe
 	return newGzipHandler(handler)
 }
 
+// NewGRPCHandlerStack returns a wrapped gRPC-related handler. Responses are not
+// gzipped, as the Connect protocol negotiates its own compression.
+func NewGRPCHandlerStack(srv http.Handler, cors []string, vhosts []string) http.Handler {
+	handler := newCorsHandler(srv, cors)
+	return newVHostHandler(vhosts, handler)
+}
+
 // NewWSHandlerStack returns a wrapped ws-related handler.
 func NewWSHandlerStack(srv http.Handler, jwtSecret []byte) http.Handler {
 	if len(jwtSecret) != 0 {
@@ -561,6 +606,10 @@ func (w *gzipResponseWriter) Flush() {
 	}
 }
 
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.resp
+}
+
 func (w *gzipResponseWriter) close() {
 	if w.gz == nil {
 		return
@@ -646,6 +695,7 @@ func RegisterApis(apis []rpc.API, modules []string, srv *rpc.Server) error {
 			if err := srv.RegisterName(api.Namespace, api.Service); err != nil {
 				return err
 			}
+			srv.SetMethodDocs(api.Docs)
 		}
 	}
 	return nil
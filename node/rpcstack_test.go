@@ -18,8 +18,11 @@ package node
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -34,6 +37,7 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/http2"
 )
 
 const testMethod = "rpc_modules"
@@ -595,6 +599,36 @@ func TestHTTPWriteTimeout(t *testing.T) {
 	})
 }
 
+// Tests that cleartext HTTP/2 is only accepted if a gRPC handler is mounted.
+func TestH2C(t *testing.T) {
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return new(net.Dialer).DialContext(ctx, network, addr)
+		},
+	}}
+	for _, grpc := range []bool{false, true} {
+		srv := newHTTPServer(testlog.Logger(t, log.LvlDebug), rpc.DefaultHTTPTimeouts)
+		assert.NoError(t, srv.enableRPC(apis(), httpConfig{Modules: []string{"test"}}))
+		srv.h2c = grpc
+		assert.NoError(t, srv.setListenAddr("localhost", 0))
+		assert.NoError(t, srv.start())
+
+		body := strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_greet"}`)
+		resp, err := client.Post("http://"+srv.listenAddr(), "application/json", body)
+		if grpc {
+			if err != nil {
+				t.Fatalf("cleartext HTTP/2 request rejected with gRPC handler: %v", err)
+			}
+			resp.Body.Close()
+		} else if err == nil {
+			resp.Body.Close()
+			t.Fatal("cleartext HTTP/2 request accepted without gRPC handler")
+		}
+		srv.stop()
+	}
+}
+
 func apis() []rpc.API {
 	return []rpc.API{
 		{
@@ -207,6 +207,7 @@ var (
 		CancunTime:              newUint64(0),
 		TerminalTotalDifficulty: big.NewInt(0),
 		PragueTime:              newUint64(0),
+		TimedTxTime:             newUint64(0),
 		BlobScheduleConfig: &BlobScheduleConfig{
 			Cancun: DefaultCancunBlobConfig,
 			Prague: DefaultPragueBlobConfig,
@@ -298,6 +299,7 @@ var (
 		PragueTime:              newUint64(0),
 		OsakaTime:               nil,
 		VerkleTime:              nil,
+		TimedTxTime:             newUint64(0),
 		TerminalTotalDifficulty: big.NewInt(0),
 		Ethash:                  new(EthashConfig),
 		Clique:                  nil,
@@ -411,6 +413,11 @@ type ChainConfig struct {
 	OsakaTime    *uint64 `json:"osakaTime,omitempty"`    // Osaka switch time (nil = no fork, 0 = already on osaka)
 	VerkleTime   *uint64 `json:"verkleTime,omitempty"`   // Verkle switch time (nil = no fork, 0 = already on verkle)
 
+	// TimedTxTime is the switch time of the timed transaction type (0x10) and
+	// its validity window rules. It is an optional upgrade on top of Prague,
+	// not scheduled on any public network (nil = no fork, 0 = already active).
+	TimedTxTime *uint64 `json:"timedTxTime,omitempty"`
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -530,6 +537,9 @@ func (c *ChainConfig) Description() string {
 	if c.VerkleTime != nil {
 		banner += fmt.Sprintf(" - Verkle:                      @%-10v\n", *c.VerkleTime)
 	}
+	if c.TimedTxTime != nil {
+		banner += fmt.Sprintf(" - Timed transactions:          @%-10v\n", *c.TimedTxTime)
+	}
 	return banner
 }
 
@@ -653,6 +663,12 @@ func (c *ChainConfig) IsVerkle(num *big.Int, time uint64) bool {
 	return c.IsLondon(num) && isTimestampForked(c.VerkleTime, time)
 }
 
+// IsTimedTx returns whether time is either equal to the timed transaction fork
+// time or greater. The fork can only be activated on top of Prague.
+func (c *ChainConfig) IsTimedTx(num *big.Int, time uint64) bool {
+	return c.IsPrague(num, time) && isTimestampForked(c.TimedTxTime, time)
+}
+
 // IsVerkleGenesis checks whether the verkle fork is activated at the genesis block.
 //
 // Verkle mode is considered enabled if the verkle fork time is configured,
@@ -877,6 +893,9 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	if isForkTimestampIncompatible(c.VerkleTime, newcfg.VerkleTime, headTimestamp) {
 		return newTimestampCompatError("Verkle fork timestamp", c.VerkleTime, newcfg.VerkleTime)
 	}
+	if isForkTimestampIncompatible(c.TimedTxTime, newcfg.TimedTxTime, headTimestamp) {
+		return newTimestampCompatError("Timed transaction fork timestamp", c.TimedTxTime, newcfg.TimedTxTime)
+	}
 	return nil
 }
 
@@ -1067,7 +1086,7 @@ type Rules struct {
 	IsByzantium, IsConstantinople, IsPetersburg, IsIstanbul bool
 	IsBerlin, IsLondon                                      bool
 	IsMerge, IsShanghai, IsCancun, IsPrague, IsOsaka        bool
-	IsVerkle                                                bool
+	IsVerkle, IsTimedTx                                     bool
 }
 
 // Rules ensures c's ChainID is not nil.
@@ -1099,5 +1118,6 @@ func (c *ChainConfig) Rules(num *big.Int, isMerge bool, timestamp uint64) Rules
 		IsOsaka:          isMerge && c.IsOsaka(num, timestamp),
 		IsVerkle:         isVerkle,
 		IsEIP4762:        isVerkle,
+		IsTimedTx:        isMerge && c.IsTimedTx(num, timestamp),
 	}
 }
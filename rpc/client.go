@@ -77,8 +77,10 @@ type BatchElem struct {
 
 // Client represents a connection to an RPC server.
 type Client struct {
-	idgen    func() ID // for subscriptions
-	isHTTP   bool      // connection type: http, ws or ipc
+	idgen    func() ID     // for subscriptions
+	isHTTP   bool          // connection type: http, ws or ipc
+	sse      bool          // whether HTTP subscriptions use server-sent events
+	pool     *endpointPool // set for clients of several endpoints, see DialMulti
 	services *serviceRegistry
 
 	idCounter atomic.Uint32
@@ -89,6 +91,10 @@ type Client struct {
 	// config fields
 	batchItemLimit       int
 	batchResponseMaxSize int
+	rateLimiter          *rateLimiter
+	methodFilter         *methodFilter
+	authorizer           Authorizer
+	responseCache        ResponseCache
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -121,6 +127,10 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize)
+	handler.rateLimiter = c.rateLimiter
+	handler.methodFilter = c.methodFilter
+	handler.authorizer = c.authorizer
+	handler.responseCache = c.responseCache
 	return &clientConn{conn, handler}
 }
 
@@ -244,10 +254,15 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
 		isHTTP:               isHTTP,
+		sse:                  cfg.sseSubscriptions,
 		services:             services,
 		idgen:                cfg.idgen,
 		batchItemLimit:       cfg.batchItemLimit,
 		batchResponseMaxSize: cfg.batchResponseLimit,
+		rateLimiter:          cfg.rateLimiter,
+		methodFilter:         cfg.methodFilter,
+		authorizer:           cfg.authorizer,
+		responseCache:        cfg.responseCache,
 		writeConn:            conn,
 		close:                make(chan struct{}),
 		closing:              make(chan struct{}),
@@ -297,7 +312,12 @@ func (c *Client) SupportedModules() (map[string]string, error) {
 
 // Close closes the client, aborting any in-flight requests.
 func (c *Client) Close() {
+	if c.pool != nil {
+		c.pool.close()
+		return
+	}
 	if c.isHTTP {
+		c.writeConn.(*httpConn).close() // ends event streams
 		return
 	}
 	select {
@@ -311,6 +331,10 @@ func (c *Client) Close() {
 // This method only works for clients using HTTP, it doesn't have
 // any effect for clients using another transport.
 func (c *Client) SetHeader(key, value string) {
+	if c.pool != nil {
+		c.pool.setHeader(key, value)
+		return
+	}
 	if !c.isHTTP {
 		return
 	}
@@ -339,6 +363,9 @@ func (c *Client) CallContext(ctx context.Context, result interface{}, method str
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
+	if c.pool != nil {
+		return c.pool.call(ctx, result, method, args...)
+	}
 	msg, err := c.newMessage(method, args...)
 	if err != nil {
 		return err
@@ -398,6 +425,9 @@ func (c *Client) BatchCall(b []BatchElem) error {
 //
 // Note that batch calls may not be executed atomically on the server side.
 func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	if c.pool != nil {
+		return c.pool.batchCall(ctx, b)
+	}
 	var (
 		msgs = make([]*jsonrpcMessage, len(b))
 		byID = make(map[string]int, len(b))
@@ -469,6 +499,9 @@ func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
 
 // Notify sends a notification, i.e. a method call that doesn't expect a response.
 func (c *Client) Notify(ctx context.Context, method string, args ...interface{}) error {
+	if c.pool != nil {
+		return c.pool.notify(ctx, method, args...)
+	}
 	op := new(requestOp)
 	msg, err := c.newMessage(method, args...)
 	if err != nil {
@@ -514,8 +547,14 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 	if chanVal.IsNil() {
 		panic("channel given to Subscribe must not be nil")
 	}
+	if c.pool != nil {
+		return c.pool.subscribe(ctx, namespace, channel, args...)
+	}
 	if c.isHTTP {
-		return nil, ErrNotificationsUnsupported
+		if !c.sse {
+			return nil, ErrNotificationsUnsupported
+		}
+		return c.subscribeSSE(ctx, namespace, chanVal, args...)
 	}
 
 	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
@@ -543,7 +582,10 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 // transport. When this returns false, Subscribe and related methods will return
 // ErrNotificationsUnsupported.
 func (c *Client) SupportsSubscriptions() bool {
-	return !c.isHTTP
+	if c.pool != nil {
+		return c.pool.supportsSubscriptions()
+	}
+	return !c.isHTTP || c.sse
 }
 
 func (c *Client) newMessage(method string, paramsIn ...interface{}) (*jsonrpcMessage, error) {
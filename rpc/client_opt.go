@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -33,14 +34,26 @@ type clientConfig struct {
 	httpHeaders http.Header
 	httpAuth    HTTPAuth
 
+	sseSubscriptions bool // subscribe over server-sent events
+
 	// WebSocket options
 	wsDialer           *websocket.Dialer
 	wsMessageSizeLimit *int64 // wsMessageSizeLimit nil = default, 0 = no limit
 
+	// Multi-endpoint options, see DialMulti
+	quorum              int
+	quorumMethods       []string
+	healthCheckInterval time.Duration
+	healthCheckMethod   string
+
 	// RPC handler options
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	rateLimiter        *rateLimiter
+	methodFilter       *methodFilter
+	authorizer         Authorizer
+	responseCache      ResponseCache
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -114,6 +127,15 @@ func WithHTTPAuth(a HTTPAuth) ClientOption {
 	})
 }
 
+// WithSSESubscriptions enables subscriptions on HTTP connections. Every subscription
+// is served as a stream of server-sent events, which the server must support, see
+// Server.SSEHandler. Streams which are cut are resumed after the last received block.
+func WithSSESubscriptions() ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.sseSubscriptions = true
+	})
+}
+
 // A HTTPAuth function is called by the client whenever a HTTP request is sent.
 // The function must be safe for concurrent use.
 //
@@ -142,3 +164,24 @@ func WithBatchResponseSizeLimit(sizeLimit int) ClientOption {
 		cfg.batchResponseLimit = sizeLimit
 	})
 }
+
+// WithQuorum makes a client of several endpoints send calls of the given methods
+// to n endpoints, returning ErrQuorumMismatch unless all of them answer the same.
+// This is meant for calls with deterministic results, such as state queries at a
+// block hash. The option only applies to clients created with DialMulti.
+func WithQuorum(n int, methods ...string) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.quorum = n
+		cfg.quorumMethods = methods
+	})
+}
+
+// WithHealthCheck configures how a client of several endpoints probes them. The
+// given method is called on every endpoint at the interval, rpc_modules every 10s
+// by default. The option only applies to clients created with DialMulti.
+func WithHealthCheck(interval time.Duration, method string) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.healthCheckInterval = interval
+		cfg.healthCheckMethod = method
+	})
+}
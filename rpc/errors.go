@@ -55,8 +55,13 @@ var (
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
 	_ Error = new(internalServerError)
+	_ Error = new(rateLimitError)
 )
 
+// ErrcodeRateLimited is the error code of calls rejected because the client
+// exceeded its request rate or quota.
+const ErrcodeRateLimited = -32005
+
 const (
 	errcodeDefault          = -32000
 	errcodeTimeout          = -32002
@@ -65,6 +65,10 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	rateLimiter          *rateLimiter  // per-client call rate limits, nil if unlimited
+	methodFilter         *methodFilter // method allow/deny rules, nil if unfiltered
+	authorizer           Authorizer    // per-call authorization, nil if none
+	responseCache        ResponseCache // cache of deterministic call results, nil if none
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
@@ -494,6 +498,19 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if h.methodFilter != nil && !h.methodFilter.allowed(msg.Method) {
+		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
+	}
+	if h.authorizer != nil {
+		if err := h.authorizer.Authorize(cp.ctx, canonicalMethod(msg.Method)); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
+	if h.rateLimiter != nil && !msg.isUnsubscribe() {
+		if err := h.rateLimiter.allow(cp.ctx, msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg)
 	}
@@ -506,6 +523,12 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	cacheable := h.responseCache != nil && callb != h.unsubscribeCb
+	if cacheable {
+		if result, ok := h.responseCache.Get(cp.ctx, msg.Method, msg.Params); ok {
+			return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}
+		}
+	}
 
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
@@ -513,6 +536,9 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	}
 	start := time.Now()
 	answer := h.runMethod(cp.ctx, msg, callb, args)
+	if cacheable && answer.Error == nil {
+		h.responseCache.Put(cp.ctx, msg.Method, msg.Params, answer.Result)
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -172,6 +172,15 @@ func (c *Client) sendHTTP(ctx context.Context, op *requestOp, msg interface{}) e
 	hc := c.writeConn.(*httpConn)
 	respBody, err := hc.doRequest(ctx, msg)
 	if err != nil {
+		// Rate limited requests still carry the JSON-RPC error in the body
+		var httpErr HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+			var resp jsonrpcMessage
+			if json.Unmarshal(httpErr.Body, &resp) == nil && resp.Error != nil {
+				op.resp <- []*jsonrpcMessage{&resp}
+				return nil
+			}
+		}
 		return err
 	}
 	defer respBody.Close()
@@ -323,8 +332,15 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.Identity = clientIdentityFromContext(r.Context())
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
+	ctx = withRetryAfterHook(ctx, func(d time.Duration) {
+		// Only called for rate limited single requests, before the response
+		// body is written.
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(d)))
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
 
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
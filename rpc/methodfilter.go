@@ -0,0 +1,92 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import "strings"
+
+// MethodFilter restricts the methods callable on a server beyond the namespaces
+// registered on it. Rules are either full method names (e.g. "debug_setHead")
+// or namespaces (e.g. "debug").
+//
+// Method rules take precedence over namespace rules, so a single method can be
+// allowed out of a denied namespace and vice versa. For rules of the same kind,
+// deny takes precedence over allow. Methods not matched by any rule are allowed
+// only if there are no allow rules.
+type MethodFilter struct {
+	Allow []string `toml:",omitempty"` // Methods and namespaces to allow
+	Deny  []string `toml:",omitempty"` // Methods and namespaces to deny
+}
+
+// Enabled returns whether the filter has any rules.
+func (f *MethodFilter) Enabled() bool {
+	return len(f.Allow) > 0 || len(f.Deny) > 0
+}
+
+// methodFilter is the compiled form of a MethodFilter.
+type methodFilter struct {
+	allowMethods    map[string]bool
+	allowNamespaces map[string]bool
+	denyMethods     map[string]bool
+	denyNamespaces  map[string]bool
+	defaultAllow    bool
+}
+
+func newMethodFilter(config MethodFilter) *methodFilter {
+	f := &methodFilter{
+		allowMethods:    make(map[string]bool),
+		allowNamespaces: make(map[string]bool),
+		denyMethods:     make(map[string]bool),
+		denyNamespaces:  make(map[string]bool),
+	}
+	add := func(rules []string, methods, namespaces map[string]bool) {
+		for _, rule := range rules {
+			rule = strings.TrimSpace(rule)
+			switch {
+			case rule == "":
+				continue
+			case strings.Contains(rule, serviceMethodSeparator):
+				methods[rule] = true
+			default:
+				namespaces[rule] = true
+			}
+		}
+	}
+	add(config.Deny, f.denyMethods, f.denyNamespaces)
+	add(config.Allow, f.allowMethods, f.allowNamespaces)
+	f.defaultAllow = len(f.allowMethods) == 0 && len(f.allowNamespaces) == 0
+	return f
+}
+
+// allowed returns whether the given method may be called.
+func (f *methodFilter) allowed(method string) bool {
+	method = canonicalMethod(method)
+	switch {
+	case f.denyMethods[method]:
+		return false
+	case f.allowMethods[method]:
+		return true
+	}
+	if namespace, _, ok := strings.Cut(method, serviceMethodSeparator); ok {
+		switch {
+		case f.denyNamespaces[namespace]:
+			return false
+		case f.allowNamespaces[namespace]:
+			return true
+		}
+	}
+	return f.defaultAllow
+}
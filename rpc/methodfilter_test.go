@@ -0,0 +1,93 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMethodFilterRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filter  MethodFilter
+		allowed []string
+		denied  []string
+	}{
+		// Deny rules only, everything else is allowed
+		{
+			filter:  MethodFilter{Deny: []string{"debug_setHead", "admin"}},
+			allowed: []string{"debug_traceTransaction", "eth_call"},
+			denied:  []string{"debug_setHead", "admin_addPeer"},
+		},
+		// Allow rules only, everything else is denied
+		{
+			filter:  MethodFilter{Allow: []string{"eth", "debug_traceTransaction"}},
+			allowed: []string{"eth_call", "debug_traceTransaction"},
+			denied:  []string{"debug_setHead", "net_version"},
+		},
+		// Method rules override namespace rules
+		{
+			filter:  MethodFilter{Allow: []string{"debug_traceTransaction", "eth"}, Deny: []string{"debug", "eth_sign"}},
+			allowed: []string{"debug_traceTransaction", "eth_call"},
+			denied:  []string{"debug_setHead", "eth_sign", "web3_clientVersion"},
+		},
+		// Deny overrides allow at the same level
+		{
+			filter:  MethodFilter{Allow: []string{"eth"}, Deny: []string{"eth"}},
+			allowed: nil,
+			denied:  []string{"eth_call"},
+		},
+	}
+	for i, test := range tests {
+		f := newMethodFilter(test.filter)
+		for _, method := range test.allowed {
+			if !f.allowed(method) {
+				t.Errorf("test %d: method %s denied", i, method)
+			}
+		}
+		for _, method := range test.denied {
+			if f.allowed(method) {
+				t.Errorf("test %d: method %s allowed", i, method)
+			}
+		}
+	}
+}
+
+// Tests that filtered methods cannot be called through the server.
+func TestMethodFilter(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	server.SetMethodFilter(MethodFilter{Allow: []string{"test"}, Deny: []string{"test_echo"}})
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	if err := client.Call(nil, "test_noArgsRets"); err != nil {
+		t.Fatalf("allowed method failed: %v", err)
+	}
+	for _, method := range []string{"test_echo", "nftest_echo"} {
+		err := client.Call(nil, method, "x", 1)
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != -32601 {
+			t.Errorf("%s: expected method not found error, got %v", method, err)
+		}
+	}
+}
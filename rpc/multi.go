@@ -0,0 +1,464 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultHealthCheckMethod   = "rpc_modules"
+	latencyWeight              = 0.3 // weight of a new sample in the latency average
+)
+
+var (
+	ErrNoEndpoint       = errors.New("no endpoint available")
+	ErrQuorumMismatch   = errors.New("endpoints returned different results")
+	errQuorumNotReached = errors.New("not enough endpoints answered")
+)
+
+// DialMulti creates a client which spreads its requests over several endpoints.
+//
+// Calls are sent to the healthy endpoint with the lowest latency, and reads fail
+// over to the next one if an endpoint can't be reached. Methods configured with
+// WithQuorum are sent to several endpoints, and only succeed if all of them return
+// the same result. Subscriptions stick to a single endpoint as long as it is healthy.
+//
+// Endpoints are checked periodically, see WithHealthCheck. The client options are
+// applied to the connections of all endpoints.
+func DialMulti(ctx context.Context, urls []string, options ...ClientOption) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, ErrNoEndpoint
+	}
+	cfg := new(clientConfig)
+	for _, opt := range options {
+		opt.applyOption(cfg)
+	}
+	if len(cfg.quorumMethods) > 0 && (cfg.quorum < 1 || cfg.quorum > len(urls)) {
+		return nil, fmt.Errorf("invalid quorum %d for %d endpoints", cfg.quorum, len(urls))
+	}
+	p := &endpointPool{
+		options:     options,
+		quorum:      cfg.quorum,
+		quorumCalls: make(map[string]bool),
+		interval:    cfg.healthCheckInterval,
+		probe:       cfg.healthCheckMethod,
+		closeCh:     make(chan struct{}),
+	}
+	for _, method := range cfg.quorumMethods {
+		p.quorumCalls[method] = true
+	}
+	if p.interval == 0 {
+		p.interval = defaultHealthCheckInterval
+	}
+	if p.probe == "" {
+		p.probe = defaultHealthCheckMethod
+	}
+	// Dial all endpoints, at least one has to be reachable.
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(urls))
+	)
+	for i, url := range urls {
+		ep := &endpoint{url: url, healthy: true}
+		p.endpoints = append(p.endpoints, ep)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ep.client, errs[i] = DialOptions(ctx, url, options...)
+			ep.healthy = errs[i] == nil
+		}()
+	}
+	wg.Wait()
+	if !slices.Contains(errs, nil) {
+		return nil, errs[0]
+	}
+	p.wg.Add(1)
+	go p.loop()
+	return &Client{pool: p, services: new(serviceRegistry)}, nil
+}
+
+// endpointPool routes the requests of a client over several endpoints.
+type endpointPool struct {
+	endpoints   []*endpoint
+	options     []ClientOption // protected by mu
+	quorum      int
+	quorumCalls map[string]bool
+	interval    time.Duration
+	probe       string
+
+	mu     sync.Mutex
+	sticky *endpoint // endpoint serving subscriptions
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+	wg        sync.WaitGroup
+}
+
+// endpoint is an upstream node of a pool.
+type endpoint struct {
+	url string
+
+	mu      sync.Mutex
+	client  *Client // nil if not connected
+	healthy bool
+	latency time.Duration // moving average of the response time
+}
+
+// status returns the client, health and latency of the endpoint.
+func (ep *endpoint) status() (*Client, bool, time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return ep.client, ep.healthy && ep.client != nil, ep.latency
+}
+
+// update records the outcome of a request.
+func (ep *endpoint) update(healthy bool, elapsed time.Duration) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+
+	if !healthy && ep.healthy {
+		log.Debug("RPC endpoint unhealthy", "url", ep.url)
+	}
+	ep.healthy = healthy
+	if healthy {
+		if ep.latency == 0 {
+			ep.latency = elapsed
+		} else {
+			ep.latency += time.Duration(latencyWeight * float64(elapsed-ep.latency))
+		}
+	}
+}
+
+// ordered returns the connected endpoints, the healthy ones first and ordered by
+// latency. Unhealthy endpoints are kept as a last resort.
+func (p *endpointPool) ordered() []*endpoint {
+	type candidate struct {
+		ep      *endpoint
+		healthy bool
+		latency time.Duration
+	}
+	var list []candidate
+	for _, ep := range p.endpoints {
+		if client, healthy, latency := ep.status(); client != nil {
+			list = append(list, candidate{ep, healthy, latency})
+		}
+	}
+	slices.SortStableFunc(list, func(a, b candidate) int {
+		if a.healthy != b.healthy {
+			if a.healthy {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.latency, b.latency)
+	})
+	eps := make([]*endpoint, len(list))
+	for i, c := range list {
+		eps[i] = c.ep
+	}
+	return eps
+}
+
+// call performs a call on the best endpoint, failing over to the next ones.
+func (p *endpointPool) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if p.quorumCalls[method] {
+		return p.callQuorum(ctx, result, method, args...)
+	}
+	var err error = ErrNoEndpoint
+	for _, ep := range p.ordered() {
+		if err = p.callEndpoint(ctx, ep, result, method, args...); !p.failover(ctx, err) {
+			return err
+		}
+		if isWriteMethod(method) {
+			return err // the request may have been delivered, don't send it twice
+		}
+	}
+	return err
+}
+
+// callEndpoint performs a call on the given endpoint, recording its health.
+func (p *endpointPool) callEndpoint(ctx context.Context, ep *endpoint, result interface{}, method string, args ...interface{}) error {
+	client, _, _ := ep.status()
+	start := time.Now()
+	err := client.CallContext(ctx, result, method, args...)
+	if !isTransportError(ctx, err) {
+		ep.update(true, time.Since(start))
+	} else if ctx.Err() == nil {
+		ep.update(false, 0)
+	}
+	return err
+}
+
+// failover reports whether a request failed because of the endpoint, so it should
+// be retried on another one.
+func (p *endpointPool) failover(ctx context.Context, err error) bool {
+	return isTransportError(ctx, err) && ctx.Err() == nil
+}
+
+// quorumAnswer is the answer of an endpoint to a quorum call.
+type quorumAnswer struct {
+	result json.RawMessage
+	err    error
+}
+
+func (a quorumAnswer) equal(b quorumAnswer) bool {
+	if a.err != nil || b.err != nil {
+		var errA, errB Error
+		return errors.As(a.err, &errA) && errors.As(b.err, &errB) && errA.ErrorCode() == errB.ErrorCode() && errA.Error() == errB.Error()
+	}
+	return bytes.Equal(a.result, b.result)
+}
+
+// callQuorum performs a call on several endpoints, and only succeeds if they all
+// return the same answer.
+func (p *endpointPool) callQuorum(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var (
+		eps     = p.ordered()
+		answers []quorumAnswer
+		lastErr error = ErrNoEndpoint
+	)
+	for len(answers) < p.quorum && len(eps) > 0 {
+		n := min(p.quorum-len(answers), len(eps))
+		batch := make([]quorumAnswer, n)
+		var wg sync.WaitGroup
+		for i, ep := range eps[:n] {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				batch[i].err = p.callEndpoint(ctx, ep, &batch[i].result, method, args...)
+			}()
+		}
+		wg.Wait()
+		eps = eps[n:]
+
+		for _, answer := range batch {
+			if p.failover(ctx, answer.err) {
+				lastErr = answer.err
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			answers = append(answers, answer)
+		}
+	}
+	if len(answers) < p.quorum {
+		return fmt.Errorf("%w for %s: %d of %d (%v)", errQuorumNotReached, method, len(answers), p.quorum, lastErr)
+	}
+	for _, answer := range answers[1:] {
+		if !answer.equal(answers[0]) {
+			return fmt.Errorf("%w for %s", ErrQuorumMismatch, method)
+		}
+	}
+	switch {
+	case answers[0].err != nil:
+		return answers[0].err
+	case result == nil:
+		return nil
+	default:
+		return json.Unmarshal(answers[0].result, result)
+	}
+}
+
+// batchCall sends a batch to the best endpoint, failing over to the next ones.
+func (p *endpointPool) batchCall(ctx context.Context, b []BatchElem) error {
+	write := slices.ContainsFunc(b, func(elem BatchElem) bool { return isWriteMethod(elem.Method) })
+
+	var err error = ErrNoEndpoint
+	for _, ep := range p.ordered() {
+		client, _, _ := ep.status()
+		start := time.Now()
+		err = client.BatchCallContext(ctx, b)
+		if !p.failover(ctx, err) {
+			if err == nil {
+				ep.update(true, time.Since(start))
+			}
+			return err
+		}
+		ep.update(false, 0)
+		if write {
+			return err
+		}
+	}
+	return err
+}
+
+// notify sends a notification to the best endpoint.
+func (p *endpointPool) notify(ctx context.Context, method string, args ...interface{}) error {
+	eps := p.ordered()
+	if len(eps) == 0 {
+		return ErrNoEndpoint
+	}
+	client, _, _ := eps[0].status()
+	return client.Notify(ctx, method, args...)
+}
+
+// subscribe creates a subscription on the sticky endpoint, choosing a new one if
+// it is unhealthy.
+func (p *endpointPool) subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*ClientSubscription, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	eps := p.ordered()
+	if p.sticky != nil {
+		if _, healthy, _ := p.sticky.status(); healthy {
+			eps = append([]*endpoint{p.sticky}, eps...)
+		}
+	}
+	var err error = ErrNotificationsUnsupported
+	for _, ep := range eps {
+		client, _, _ := ep.status()
+		if !client.SupportsSubscriptions() {
+			continue
+		}
+		var sub *ClientSubscription
+		if sub, err = client.Subscribe(ctx, namespace, channel, args...); !p.failover(ctx, err) {
+			if err == nil {
+				p.sticky = ep
+			}
+			return sub, err
+		}
+		ep.update(false, 0)
+	}
+	return nil, err
+}
+
+// supportsSubscriptions reports whether any endpoint supports subscriptions.
+func (p *endpointPool) supportsSubscriptions() bool {
+	for _, ep := range p.endpoints {
+		if client, _, _ := ep.status(); client != nil && client.SupportsSubscriptions() {
+			return true
+		}
+	}
+	return false
+}
+
+// setHeader sets a header on the connections of all endpoints.
+func (p *endpointPool) setHeader(key, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.options = append(p.options, WithHeader(key, value))
+	for _, ep := range p.endpoints {
+		if client, _, _ := ep.status(); client != nil {
+			client.SetHeader(key, value)
+		}
+	}
+}
+
+// loop checks the health of the endpoints until the pool is closed.
+func (p *endpointPool) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.checkHealth()
+		select {
+		case <-ticker.C:
+		case <-p.closeCh:
+			return
+		}
+	}
+}
+
+// checkHealth probes all endpoints, reconnecting the ones which couldn't be dialed.
+func (p *endpointPool) checkHealth() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+	go func() {
+		select {
+		case <-p.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	p.mu.Lock()
+	options := p.options
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, ep := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client, _, _ := ep.status()
+			if client == nil {
+				client, err := DialOptions(ctx, ep.url, options...)
+				if err != nil {
+					return
+				}
+				ep.mu.Lock()
+				ep.client = client
+				ep.mu.Unlock()
+			}
+			p.callEndpoint(ctx, ep, nil, p.probe)
+		}()
+	}
+	wg.Wait()
+}
+
+// close stops the health checks and closes the connections of all endpoints.
+func (p *endpointPool) close() {
+	p.closeOnce.Do(func() {
+		close(p.closeCh)
+		p.wg.Wait()
+		for _, ep := range p.endpoints {
+			if client, _, _ := ep.status(); client != nil {
+				client.Close()
+			}
+		}
+	})
+}
+
+// isTransportError reports whether a request failed without an answer of the
+// endpoint. JSON-RPC errors are answers, as is a result which can't be decoded.
+func isTransportError(ctx context.Context, err error) bool {
+	if err == nil || errors.Is(err, ErrNoResult) {
+		return false
+	}
+	var (
+		rpcErr  Error
+		typeErr *json.UnmarshalTypeError
+		synErr  *json.SyntaxError
+	)
+	if errors.As(err, &rpcErr) || errors.As(err, &typeErr) || errors.As(err, &synErr) {
+		return false
+	}
+	return ctx.Err() == nil || !errors.Is(err, ctx.Err())
+}
+
+// isWriteMethod reports whether a method submits something, so it must not be
+// sent to another endpoint after a failed attempt.
+func isWriteMethod(method string) bool {
+	_, name, _ := strings.Cut(method, "_")
+	return strings.HasPrefix(name, "send")
+}
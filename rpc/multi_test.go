@@ -0,0 +1,265 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// valueService returns a fixed value, and counts the values sent to it.
+type valueService struct {
+	value int
+	sent  atomic.Int32
+}
+
+func (s *valueService) Get() int {
+	return s.value
+}
+
+func (s *valueService) SendValue(v int) {
+	s.sent.Add(1)
+}
+
+// newValueEndpoint starts an HTTP endpoint serving a valueService.
+func newValueEndpoint(t *testing.T, value int, wrap func(http.Handler) http.Handler) (*httptest.Server, *valueService) {
+	t.Helper()
+
+	service := &valueService{value: value}
+	srv := NewServer()
+	srv.RegisterName("val", service)
+	t.Cleanup(srv.Stop)
+	var handler http.Handler = srv
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+	httpsrv := httptest.NewServer(handler)
+	t.Cleanup(httpsrv.Close)
+	return httpsrv, service
+}
+
+// failing makes an endpoint respond with server errors.
+func failing(http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusBadGateway)
+	})
+}
+
+func TestMultiFailover(t *testing.T) {
+	t.Parallel()
+
+	down, _ := newValueEndpoint(t, 1, failing)
+	up, _ := newValueEndpoint(t, 2, nil)
+	client, err := DialMulti(context.Background(), []string{down.URL, up.URL}, WithHealthCheck(time.Hour, "val_get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		var v int
+		if err := client.Call(&v, "val_get"); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+		if v != 2 {
+			t.Fatalf("call %d answered by wrong endpoint: %d", i, v)
+		}
+	}
+	if eps := client.pool.ordered(); eps[0].url != up.URL {
+		t.Errorf("failing endpoint preferred")
+	}
+}
+
+// Tests that headers can be set while unreachable endpoints are redialed by the
+// health checks.
+func TestMultiSetHeader(t *testing.T) {
+	t.Parallel()
+
+	up, _ := newValueEndpoint(t, 1, nil)
+	client, err := DialMulti(context.Background(), []string{"ws://127.0.0.1:1", up.URL}, WithHealthCheck(time.Millisecond, "val_get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	for i := 0; i < 100; i++ {
+		client.SetHeader("X-Test", strconv.Itoa(i))
+		time.Sleep(100 * time.Microsecond)
+	}
+}
+
+func TestMultiWriteNoFailover(t *testing.T) {
+	t.Parallel()
+
+	// The first endpoint fails for writes only, and is preferred.
+	down, _ := newValueEndpoint(t, 1, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if bytes.Contains(body, []byte("val_sendValue")) {
+				failing(h).ServeHTTP(w, r)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			h.ServeHTTP(w, r)
+		})
+	})
+	up, service := newValueEndpoint(t, 2, nil)
+	client, err := DialMulti(context.Background(), []string{down.URL, up.URL}, WithHealthCheck(time.Hour, "val_get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.pool.endpoints[0].update(true, time.Nanosecond)
+	client.pool.endpoints[1].update(true, time.Second)
+
+	var httpErr HTTPError
+	if err := client.Call(nil, "val_sendValue", 1); !errors.As(err, &httpErr) {
+		t.Fatalf("expected HTTP error, got %v", err)
+	}
+	if n := service.sent.Load(); n != 0 {
+		t.Fatalf("write failed over to other endpoint")
+	}
+}
+
+func TestMultiLatency(t *testing.T) {
+	t.Parallel()
+
+	slow, _ := newValueEndpoint(t, 1, func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			h.ServeHTTP(w, r)
+		})
+	})
+	fast, _ := newValueEndpoint(t, 2, nil)
+	client, err := DialMulti(context.Background(), []string{slow.URL, fast.URL}, WithHealthCheck(time.Hour, "val_get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Wait for the initial health check to measure both endpoints.
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		_, _, l0 := client.pool.endpoints[0].status()
+		_, _, l1 := client.pool.endpoints[1].status()
+		if l0 > 0 && l1 > 0 {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("endpoints not probed")
+		}
+	}
+	var v int
+	if err := client.Call(&v, "val_get"); err != nil {
+		t.Fatal(err)
+	}
+	if v != 2 {
+		t.Fatalf("call not routed to the fastest endpoint")
+	}
+}
+
+func TestMultiQuorum(t *testing.T) {
+	t.Parallel()
+
+	a, _ := newValueEndpoint(t, 1, nil)
+	b, _ := newValueEndpoint(t, 1, nil)
+	c, _ := newValueEndpoint(t, 2, nil)
+	down, _ := newValueEndpoint(t, 1, failing)
+
+	// Endpoints which fail are replaced by the next ones.
+	client, err := DialMulti(context.Background(), []string{down.URL, a.URL, b.URL}, WithQuorum(2, "val_get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var v int
+	if err := client.Call(&v, "val_get"); err != nil {
+		t.Fatal("quorum call failed:", err)
+	}
+	if v != 1 {
+		t.Fatalf("wrong result %d", v)
+	}
+
+	// Different answers are rejected.
+	client, err = DialMulti(context.Background(), []string{a.URL, c.URL}, WithQuorum(2, "val_get"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Call(&v, "val_get"); !errors.Is(err, ErrQuorumMismatch) {
+		t.Fatalf("expected quorum mismatch, got %v", err)
+	}
+	// Other methods are not affected.
+	if err := client.Call(nil, "val_sendValue", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// Quorums which can't be reached are rejected.
+	for _, n := range []int{-1, 0, 3} {
+		if _, err := DialMulti(context.Background(), []string{a.URL, b.URL}, WithQuorum(n, "val_get")); err == nil {
+			t.Fatalf("quorum %d accepted", n)
+		}
+	}
+}
+
+func TestMultiStickySubscriptions(t *testing.T) {
+	t.Parallel()
+
+	var urls []string
+	for i := 0; i < 3; i++ {
+		srv := newTestServer()
+		t.Cleanup(srv.Stop)
+		httpsrv := httptest.NewServer(srv.WebsocketHandler(nil))
+		t.Cleanup(httpsrv.Close)
+		urls = append(urls, "ws:"+strings.TrimPrefix(httpsrv.URL, "http:"))
+	}
+	client, err := DialMulti(context.Background(), urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if !client.SupportsSubscriptions() {
+		t.Fatal("subscriptions not supported")
+	}
+
+	var subs []*ClientSubscription
+	for i := 0; i < 5; i++ {
+		// Shuffle the latencies, the subscriptions must not follow them.
+		for j, ep := range client.pool.endpoints {
+			ep.update(true, time.Duration((i+j)%3+1)*time.Millisecond)
+		}
+		sub, err := client.Subscribe(context.Background(), "nftest", make(chan int, 10), "someSubscription", 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Unsubscribe()
+		subs = append(subs, sub)
+	}
+	for _, sub := range subs[1:] {
+		if sub.client != subs[0].client {
+			t.Fatal("subscriptions on different endpoints")
+		}
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	openRPCVersion = "1.3.2"
+
+	// discoverMethod is the method name defined by the OpenRPC specification for
+	// service discovery. It is served by the rpc_discover method.
+	discoverMethod = "rpc.discover"
+)
+
+// canonicalMethod maps rpc.discover to the name of the method serving it.
+func canonicalMethod(method string) string {
+	if method == discoverMethod {
+		return MetadataApi + serviceMethodSeparator + "discover"
+	}
+	return method
+}
+
+// OpenRPCDocument is an OpenRPC service description, as returned by rpc.discover.
+// See https://spec.open-rpc.org for the format.
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo is the metadata of an OpenRPC document.
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCComponents holds the schemas of named types, which are referenced from
+// method parameters and results.
+type OpenRPCComponents struct {
+	Schemas map[string]*JSONSchema `json:"schemas"`
+}
+
+// OpenRPCMethod describes a single RPC method.
+type OpenRPCMethod struct {
+	Name        string               `json:"name"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Params      []*ContentDescriptor `json:"params"`
+	Result      *ContentDescriptor   `json:"result,omitempty"`
+
+	// Subscriptions describes the subscriptions available through a subscribe
+	// method, keyed by subscription name.
+	Subscriptions map[string]*OpenRPCSubscription `json:"x-subscriptions,omitempty"`
+}
+
+// OpenRPCSubscription describes a subscription. The parameters follow the
+// subscription name in the call to the subscribe method.
+type OpenRPCSubscription struct {
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Params      []*ContentDescriptor `json:"params"`
+}
+
+// ContentDescriptor describes a method parameter or result.
+type ContentDescriptor struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
+}
+
+// JSONSchema is the subset of JSON Schema used to describe parameter and result types.
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	ContentEncoding      string                 `json:"contentEncoding,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+// MethodDoc annotates a method in the OpenRPC document. Empty fields keep the values
+// derived from the method signature.
+type MethodDoc struct {
+	Summary     string
+	Description string
+	Deprecated  bool
+	Params      []ContentDescriptor // by position
+	Result      *ContentDescriptor
+}
+
+// SetMethodDocs adds annotations for the given methods to the document served by
+// rpc.discover. Methods are identified by their full name, e.g. "eth_getBalance".
+// Subscriptions are annotated with the name of the subscribe method followed by the
+// subscription name, e.g. "eth_subscribe.newHeads".
+func (s *Server) SetMethodDocs(docs map[string]MethodDoc) {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	if s.services.docs == nil {
+		s.services.docs = make(map[string]MethodDoc)
+	}
+	for name, doc := range docs {
+		s.services.docs[name] = doc
+	}
+}
+
+// Discover returns the OpenRPC description of the server's methods. It is served as
+// rpc.discover, as defined by the OpenRPC specification.
+func (s *RPCService) Discover() *OpenRPCDocument {
+	return s.server.services.openRPC(s.server.methodFilter)
+}
+
+// openRPC generates the OpenRPC document of all registered services, omitting
+// the methods denied by the given filter, if any.
+func (r *serviceRegistry) openRPC(filter *methodFilter) *OpenRPCDocument {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		gen = &schemaGenerator{schemas: make(map[string]*JSONSchema), names: make(map[reflect.Type]string)}
+		doc = &OpenRPCDocument{
+			OpenRPC:    openRPCVersion,
+			Info:       OpenRPCInfo{Title: "JSON-RPC API", Version: "1.0"},
+			Methods:    []OpenRPCMethod{},
+			Components: OpenRPCComponents{Schemas: gen.schemas},
+		}
+	)
+	allowed := func(method string) bool {
+		return filter == nil || filter.allowed(method)
+	}
+	for _, svc := range r.services {
+		for name, cb := range svc.callbacks {
+			method := svc.name + serviceMethodSeparator + name
+			if method == canonicalMethod(discoverMethod) {
+				continue // listed as rpc.discover, see the specification
+			}
+			if !allowed(method) {
+				continue
+			}
+			m := OpenRPCMethod{
+				Name:   method,
+				Params: gen.params(cb.argTypes),
+				Result: gen.result(cb),
+			}
+			r.annotate(&m, method)
+			doc.Methods = append(doc.Methods, m)
+		}
+		if len(svc.subscriptions) > 0 {
+			if allowed(svc.name + subscribeMethodSuffix) {
+				doc.Methods = append(doc.Methods, r.subscribeMethod(gen, svc))
+			}
+			if allowed(svc.name + unsubscribeMethodSuffix) {
+				doc.Methods = append(doc.Methods, r.unsubscribeMethod(svc))
+			}
+		}
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool {
+		return doc.Methods[i].Name < doc.Methods[j].Name
+	})
+	return doc
+}
+
+// subscribeMethod describes the subscribe method of a service. Its first parameter
+// selects the subscription, the remaining parameters depend on the subscription.
+func (r *serviceRegistry) subscribeMethod(gen *schemaGenerator, svc service) OpenRPCMethod {
+	var (
+		method = svc.name + subscribeMethodSuffix
+		names  = make([]string, 0, len(svc.subscriptions))
+		subs   = make(map[string]*OpenRPCSubscription, len(svc.subscriptions))
+	)
+	for name, cb := range svc.subscriptions {
+		names = append(names, name)
+		sub := &OpenRPCSubscription{Params: gen.params(cb.argTypes)}
+		if doc, ok := r.docs[method+"."+name]; ok {
+			sub.Summary, sub.Description = doc.Summary, doc.Description
+			annotateParams(sub.Params, doc.Params)
+		}
+		subs[name] = sub
+	}
+	sort.Strings(names)
+	m := OpenRPCMethod{
+		Name: method,
+		Params: []*ContentDescriptor{{
+			Name:     "subscription",
+			Required: true,
+			Schema:   &JSONSchema{Type: "string", Enum: names},
+		}},
+		Result: &ContentDescriptor{
+			Name:        "subscriptionID",
+			Description: "Identifies the subscription in notifications and " + svc.name + unsubscribeMethodSuffix + ".",
+			Schema:      &JSONSchema{Type: "string"},
+		},
+		Subscriptions: subs,
+	}
+	r.annotate(&m, method)
+	return m
+}
+
+// unsubscribeMethod describes the unsubscribe method of a service.
+func (r *serviceRegistry) unsubscribeMethod(svc service) OpenRPCMethod {
+	m := OpenRPCMethod{
+		Name: svc.name + unsubscribeMethodSuffix,
+		Params: []*ContentDescriptor{{
+			Name:     "subscriptionID",
+			Required: true,
+			Schema:   &JSONSchema{Type: "string"},
+		}},
+		Result: &ContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "boolean"}},
+	}
+	r.annotate(&m, m.Name)
+	return m
+}
+
+// annotate applies the annotations of the given method.
+func (r *serviceRegistry) annotate(m *OpenRPCMethod, method string) {
+	doc, ok := r.docs[method]
+	if !ok {
+		return
+	}
+	m.Summary, m.Description, m.Deprecated = doc.Summary, doc.Description, doc.Deprecated
+	annotateParams(m.Params, doc.Params)
+	if doc.Result != nil && m.Result != nil {
+		annotateDescriptor(m.Result, *doc.Result)
+	}
+}
+
+func annotateParams(params []*ContentDescriptor, docs []ContentDescriptor) {
+	for i := range docs {
+		if i < len(params) {
+			annotateDescriptor(params[i], docs[i])
+		}
+	}
+}
+
+func annotateDescriptor(cd *ContentDescriptor, doc ContentDescriptor) {
+	if doc.Name != "" {
+		cd.Name = doc.Name
+	}
+	if doc.Description != "" {
+		cd.Description = doc.Description
+	}
+	if doc.Schema != nil {
+		cd.Schema = doc.Schema
+	}
+}
+
+var (
+	bigIntType            = reflect.TypeOf(big.Int{})
+	hashType              = reflect.TypeOf(common.Hash{})
+	addressType           = reflect.TypeOf(common.Address{})
+	hexBigType            = reflect.TypeOf(hexutil.Big{})
+	hexUint64Type         = reflect.TypeOf(hexutil.Uint64(0))
+	hexUintType           = reflect.TypeOf(hexutil.Uint(0))
+	hexBytesType          = reflect.TypeOf(hexutil.Bytes{})
+	blockNumberType       = reflect.TypeOf(BlockNumber(0))
+	blockNumberOrHashType = reflect.TypeOf(BlockNumberOrHash{})
+	jsonMarshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+var (
+	quantityPattern   = "^0x(0|[1-9a-f][0-9a-f]*)$"
+	blockTagPattern   = "^(earliest|latest|pending|safe|finalized|0x(0|[1-9a-f][0-9a-f]*))$"
+	blockTagOrHashPat = "^(earliest|latest|pending|safe|finalized|0x(0|[1-9a-f][0-9a-f]*)|0x[0-9a-fA-F]{64})$"
+)
+
+// knownSchemas holds the schemas of types with custom JSON encodings.
+var knownSchemas = map[reflect.Type]func() *JSONSchema{
+	bigIntType: func() *JSONSchema { return &JSONSchema{Type: "integer"} },
+	hashType:   func() *JSONSchema { return &JSONSchema{Title: "hash", Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"} },
+	addressType: func() *JSONSchema {
+		return &JSONSchema{Title: "address", Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"}
+	},
+	hexBigType:    func() *JSONSchema { return &JSONSchema{Title: "quantity", Type: "string", Pattern: quantityPattern} },
+	hexUint64Type: func() *JSONSchema { return &JSONSchema{Title: "quantity", Type: "string", Pattern: quantityPattern} },
+	hexUintType:   func() *JSONSchema { return &JSONSchema{Title: "quantity", Type: "string", Pattern: quantityPattern} },
+	hexBytesType: func() *JSONSchema {
+		return &JSONSchema{Title: "bytes", Type: "string", Pattern: "^0x([0-9a-fA-F]{2})*$"}
+	},
+	blockNumberType: func() *JSONSchema {
+		return &JSONSchema{Title: "block number or tag", Type: "string", Pattern: blockTagPattern}
+	},
+	blockNumberOrHashType: func() *JSONSchema {
+		return &JSONSchema{Title: "block number, tag or hash", OneOf: []*JSONSchema{
+			{Type: "string", Pattern: blockTagOrHashPat},
+			{Type: "object", Properties: map[string]*JSONSchema{
+				"blockNumber":      {Type: "string", Pattern: blockTagPattern},
+				"blockHash":        {Type: "string", Pattern: "^0x[0-9a-fA-F]{64}$"},
+				"requireCanonical": {Type: "boolean"},
+			}},
+		}}
+	},
+}
+
+// schemaGenerator derives JSON schemas from Go types. Named struct types are added
+// to the schemas of the document and referenced.
+type schemaGenerator struct {
+	schemas map[string]*JSONSchema
+	names   map[reflect.Type]string
+}
+
+// params describes the given argument types. Trailing pointer arguments may be
+// omitted by callers, and are not required.
+func (g *schemaGenerator) params(types []reflect.Type) []*ContentDescriptor {
+	params := make([]*ContentDescriptor, len(types))
+	required := false
+	for i := len(types) - 1; i >= 0; i-- {
+		if types[i].Kind() != reflect.Ptr {
+			required = true
+		}
+		params[i] = &ContentDescriptor{
+			Name:     fmt.Sprintf("param%d", i),
+			Required: required,
+			Schema:   g.schema(types[i]),
+		}
+	}
+	return params
+}
+
+// result describes the return value of a callback.
+func (g *schemaGenerator) result(cb *callback) *ContentDescriptor {
+	fntype := cb.fn.Type()
+	if fntype.NumOut() == 0 || cb.errPos == 0 {
+		return &ContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "null"}}
+	}
+	return &ContentDescriptor{Name: "result", Schema: g.schema(fntype.Out(0))}
+}
+
+// schema returns the schema of values of type t.
+func (g *schemaGenerator) schema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if known, ok := knownSchemas[t]; ok {
+		return known()
+	}
+	switch {
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &JSONSchema{Title: t.Name()} // unknown encoding
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &JSONSchema{Title: t.Name(), Type: "string"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &JSONSchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return &JSONSchema{Ref: "#/components/schemas/" + g.define(t)}
+	default:
+		// Interfaces can hold any value, functions and channels can't be encoded.
+		return &JSONSchema{}
+	}
+}
+
+// define adds the schema of a named struct type to the document, returning its name.
+func (g *schemaGenerator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	for i := 2; g.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s.%s%d", path.Base(t.PkgPath()), t.Name(), i)
+	}
+	// Register the name before generating the schema, to support recursive types.
+	g.names[t] = name
+	g.schemas[name] = &JSONSchema{}
+	schema := g.object(t)
+	schema.Title = t.Name()
+	g.schemas[name] = schema
+	return name
+}
+
+// object returns the schema of a struct type, following the encoding/json rules
+// for field names and embedded structs.
+func (g *schemaGenerator) object(t reflect.Type) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	g.fields(t, schema)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (g *schemaGenerator) fields(t reflect.Type, schema *JSONSchema) {
+	// Fields of embedded structs are added last, as they are shadowed by the
+	// fields of the outer struct.
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ftype := field.Type
+		for ftype.Kind() == reflect.Ptr {
+			ftype = ftype.Elem()
+		}
+		if field.Anonymous && name == "" && ftype.Kind() == reflect.Struct {
+			embedded = append(embedded, ftype)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, dup := schema.Properties[name]; dup {
+			continue
+		}
+		schema.Properties[name] = g.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	for _, t := range embedded {
+		g.fields(t, schema)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type treeNode struct {
+	Hash     common.Hash  `json:"hash"`
+	Value    hexutil.Big  `json:"value"`
+	Children []*treeNode  `json:"children,omitempty"`
+	Ignored  string       `json:"-"`
+	Block    *BlockNumber `json:"block,omitempty"`
+}
+
+type treeService struct{}
+
+func (s *treeService) Root(number BlockNumberOrHash) *treeNode {
+	return nil
+}
+
+func discover(t *testing.T, server *Server) *OpenRPCDocument {
+	t.Helper()
+
+	client := DialInProc(server)
+	defer client.Close()
+	var doc *OpenRPCDocument
+	if err := client.Call(&doc, "rpc.discover"); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func findMethod(t *testing.T, doc *OpenRPCDocument, name string) *OpenRPCMethod {
+	t.Helper()
+
+	for i := range doc.Methods {
+		if doc.Methods[i].Name == name {
+			return &doc.Methods[i]
+		}
+	}
+	t.Fatalf("method %s not found", name)
+	return nil
+}
+
+func TestOpenRPCDiscover(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	doc := discover(t, server)
+
+	if doc.OpenRPC != openRPCVersion {
+		t.Errorf("wrong version %q", doc.OpenRPC)
+	}
+	for _, m := range doc.Methods {
+		if m.Name == "rpc_discover" {
+			t.Error("discovery method listed")
+		}
+	}
+	echo := findMethod(t, doc, "test_echo")
+	wantTypes := []string{"string", "integer", ""}
+	wantRequired := []bool{true, true, false}
+	if len(echo.Params) != 3 {
+		t.Fatalf("wrong number of params: %d", len(echo.Params))
+	}
+	for i, p := range echo.Params {
+		if p.Schema.Type != wantTypes[i] || p.Required != wantRequired[i] {
+			t.Errorf("param %d: type %q required %t, want %q %t", i, p.Schema.Type, p.Required, wantTypes[i], wantRequired[i])
+		}
+	}
+	if ref := echo.Result.Schema.Ref; ref != "#/components/schemas/echoResult" {
+		t.Fatalf("wrong result ref %q", ref)
+	}
+	result := doc.Components.Schemas["echoResult"]
+	if result == nil || result.Properties["Int"].Type != "integer" || result.Properties["Args"].Ref != "#/components/schemas/echoArgs" {
+		t.Fatalf("wrong result schema %+v", result)
+	}
+	if noRets := findMethod(t, doc, "test_noArgsRets"); noRets.Result.Schema.Type != "null" {
+		t.Errorf("wrong result type for method without return value: %q", noRets.Result.Schema.Type)
+	}
+
+	sub := findMethod(t, doc, "nftest_subscribe")
+	if want := []string{"hangSubscription", "someSubscription"}; !reflect.DeepEqual(sub.Params[0].Schema.Enum, want) {
+		t.Errorf("wrong subscriptions %v", sub.Params[0].Schema.Enum)
+	}
+	if params := sub.Subscriptions["someSubscription"].Params; len(params) != 2 {
+		t.Errorf("wrong number of subscription params: %d", len(params))
+	}
+	findMethod(t, doc, "nftest_unsubscribe")
+}
+
+// Tests that the methods denied by the method filter are not advertised.
+func TestOpenRPCMethodFilter(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	server.SetMethodFilter(MethodFilter{Deny: []string{"test_echo", "nftest_unsubscribe"}})
+	doc := discover(t, server)
+
+	for _, m := range doc.Methods {
+		if m.Name == "test_echo" || m.Name == "nftest_unsubscribe" {
+			t.Errorf("denied method %s listed", m.Name)
+		}
+	}
+	findMethod(t, doc, "test_noArgsRets")
+	findMethod(t, doc, "nftest_subscribe")
+}
+
+func TestOpenRPCSchemas(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer()
+	defer server.Stop()
+	server.RegisterName("tree", new(treeService))
+	doc := discover(t, server)
+
+	root := findMethod(t, doc, "tree_root")
+	if root.Params[0].Schema.OneOf == nil {
+		t.Error("block number or hash has no alternatives")
+	}
+	node := doc.Components.Schemas["treeNode"]
+	if node == nil {
+		t.Fatal("recursive type not defined")
+	}
+	if node.Properties["hash"].Pattern == "" || node.Properties["value"].Pattern != quantityPattern {
+		t.Error("hex encoded types have no patterns")
+	}
+	if node.Properties["children"].Items.Ref != "#/components/schemas/treeNode" {
+		t.Errorf("wrong recursive reference %+v", node.Properties["children"].Items)
+	}
+	if _, ok := node.Properties["Ignored"]; ok {
+		t.Error("ignored field included")
+	}
+	if want := []string{"hash", "value"}; !reflect.DeepEqual(node.Required, want) {
+		t.Errorf("wrong required fields %v", node.Required)
+	}
+}
+
+func TestOpenRPCAnnotations(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+	server.SetMethodDocs(map[string]MethodDoc{
+		"test_repeat": {
+			Summary: "Repeats a message.",
+			Params:  []ContentDescriptor{{Name: "message"}, {Name: "count", Schema: &JSONSchema{Type: "integer", Description: "at least 1"}}},
+			Result:  &ContentDescriptor{Name: "repeated"},
+		},
+		"nftest_subscribe.someSubscription": {
+			Summary: "Counts up.",
+			Params:  []ContentDescriptor{{Name: "n"}},
+		},
+	})
+	doc := discover(t, server)
+
+	repeat := findMethod(t, doc, "test_repeat")
+	if repeat.Summary != "Repeats a message." || repeat.Result.Name != "repeated" {
+		t.Errorf("method not annotated: %+v", repeat)
+	}
+	if repeat.Params[0].Name != "message" || repeat.Params[0].Schema.Type != "string" {
+		t.Errorf("wrong first param %+v", repeat.Params[0])
+	}
+	if repeat.Params[1].Name != "count" || repeat.Params[1].Schema.Description != "at least 1" {
+		t.Errorf("wrong second param %+v", repeat.Params[1])
+	}
+	sub := findMethod(t, doc, "nftest_subscribe").Subscriptions["someSubscription"]
+	if sub.Summary != "Counts up." || sub.Params[0].Name != "n" || sub.Params[1].Name != "param1" {
+		t.Errorf("subscription not annotated: %+v", sub)
+	}
+}
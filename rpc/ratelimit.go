@@ -0,0 +1,233 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is the interval at which idle token buckets are
+// dropped from the limiter.
+const rateLimitSweepInterval = time.Minute
+
+// RateLimit configures a token bucket, which is refilled at Rate tokens per
+// second up to a capacity of Burst tokens. Every call consumes one token.
+type RateLimit struct {
+	Rate  float64 // Sustained number of calls permitted per second
+	Burst int     // Maximum number of calls permitted in a burst
+}
+
+// enabled returns whether the limit restricts anything.
+func (l RateLimit) enabled() bool {
+	return l.Rate > 0
+}
+
+// RateLimitConfig configures the per-client rate limits of an RPC server. Each
+// client identity gets its own token bucket for every configured limit.
+type RateLimitConfig struct {
+	// Default is the limit applied to all methods without a more specific one.
+	// The zero value means such methods are not limited.
+	Default RateLimit `toml:",omitempty"`
+
+	// Methods holds the limits of specific method namespaces (e.g. "debug") or
+	// methods (e.g. "eth_getLogs"). Method limits take precedence over namespace
+	// limits, which take precedence over the default. A zero limit exempts the
+	// namespace or method from rate limiting.
+	Methods map[string]RateLimit `toml:",omitempty"`
+}
+
+// Enabled returns whether any rate limits are configured.
+func (c *RateLimitConfig) Enabled() bool {
+	if c.Default.enabled() {
+		return true
+	}
+	for _, limit := range c.Methods {
+		if limit.enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenBucket is the rate limiting state of a single client for a single limit.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter tracks the token buckets of all clients.
+type rateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	lock    sync.Mutex
+	buckets map[string]*tokenBucket
+	swept   time.Time
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	return &rateLimiter{
+		config:  config,
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// limit returns the limit applicable to a method, along with the key it is
+// configured under.
+func (l *rateLimiter) limit(method string) (string, RateLimit) {
+	if limit, ok := l.config.Methods[method]; ok {
+		return method, limit
+	}
+	if namespace, _, ok := strings.Cut(method, serviceMethodSeparator); ok {
+		if limit, ok := l.config.Methods[namespace]; ok {
+			return namespace, limit
+		}
+	}
+	return "*", l.config.Default
+}
+
+// allow consumes a token for the given method from the bucket of the client
+// issuing the call, returning an error if the client is over its limit.
+func (l *rateLimiter) allow(ctx context.Context, method string) error {
+	key, limit := l.limit(method)
+	if !limit.enabled() {
+		return nil
+	}
+	identity := clientIdentity(PeerInfoFromContext(ctx))
+	if identity == "" {
+		return nil // in-process or IPC, don't limit
+	}
+	burst := float64(max(limit.Burst, 1))
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if now.Sub(l.swept) > rateLimitSweepInterval {
+		l.sweep(now)
+	}
+	id := key + "\x00" + identity
+	bucket := l.buckets[id]
+	if bucket == nil {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[id] = bucket
+	}
+	bucket.tokens = min(burst, bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		rateLimitedMeter.Mark(1)
+		retry := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+		if hook, ok := ctx.Value(retryAfterContextKey{}).(func(time.Duration)); ok {
+			hook(retry)
+		}
+		return &rateLimitError{limit: key, retryAfter: retry}
+	}
+	bucket.tokens--
+	return nil
+}
+
+// sweep drops all buckets which would be full by now, as they are equivalent
+// to freshly created ones. The caller must hold the lock.
+func (l *rateLimiter) sweep(now time.Time) {
+	for id, bucket := range l.buckets {
+		key, _, _ := strings.Cut(id, "\x00")
+		limit := l.config.Default
+		if key != "*" {
+			limit = l.config.Methods[key]
+		}
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate >= float64(max(limit.Burst, 1)) {
+			delete(l.buckets, id)
+		}
+	}
+	l.swept = now
+}
+
+// clientIdentity returns the identity rate limits are tracked by: the client's
+// authenticated identity if any, or its IP address otherwise.
+func clientIdentity(info PeerInfo) string {
+	if info.Identity != "" {
+		return info.Identity
+	}
+	if info.RemoteAddr == "" || info.Transport == "ipc" {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		return "ip:" + info.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+type clientIdentityContextKey struct{}
+
+// WithClientIdentity returns a copy of the context carrying the authenticated
+// identity of the client, e.g. a JWT subject or an API key. HTTP middlewares
+// wrapping the RPC server can use it to attach the identity to the request
+// context, which is then used to track the client's rate limits.
+func WithClientIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, clientIdentityContextKey{}, identity)
+}
+
+// clientIdentityFromContext returns the client identity attached to the context
+// by WithClientIdentity, if any.
+func clientIdentityFromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(clientIdentityContextKey{}).(string)
+	return identity
+}
+
+type retryAfterContextKey struct{}
+
+// withRetryAfterHook returns a copy of the context carrying a callback invoked
+// with the suggested back-off duration when a call is rate limited.
+func withRetryAfterHook(ctx context.Context, hook func(time.Duration)) context.Context {
+	return context.WithValue(ctx, retryAfterContextKey{}, hook)
+}
+
+// withoutRetryAfterHook returns a copy of the context with the back-off callback
+// removed.
+func withoutRetryAfterHook(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryAfterContextKey{}, nil)
+}
+
+// retryAfterSeconds converts a back-off duration into a Retry-After value.
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
+
+// rateLimitError is returned when a client exceeds its rate limit.
+type rateLimitError struct {
+	limit      string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) ErrorCode() int { return ErrcodeRateLimited }
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s, retry after %ds", e.limit, retryAfterSeconds(e.retryAfter))
+}
+
+func (e *rateLimitError) ErrorData() interface{} {
+	return map[string]int{"retryAfter": retryAfterSeconds(e.retryAfter)}
+}
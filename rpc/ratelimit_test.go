@@ -0,0 +1,180 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newRateLimitedServer() *Server {
+	s := newTestServer()
+	s.SetRateLimits(RateLimitConfig{
+		Default: RateLimit{Rate: 0.01, Burst: 2},
+		Methods: map[string]RateLimit{
+			"test_echo": {Rate: 0.01, Burst: 1},
+			"nftest":    {}, // unlimited
+		},
+	})
+	return s
+}
+
+func expectRateLimited(t *testing.T, err error) {
+	t.Helper()
+
+	var rpcErr Error
+	if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != ErrcodeRateLimited {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+}
+
+// Tests that calls are limited per method and namespace, and that limited
+// calls are rejected with the dedicated error code.
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	s := newRateLimitedServer()
+	defer s.Stop()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	c, err := Dial(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// The method limit of test_echo is separate from the default one.
+	var res echoResult
+	if err := c.Call(&res, "test_echo", "x", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	expectRateLimited(t, c.Call(&res, "test_echo", "x", 1, nil))
+
+	for i := 0; i < 2; i++ {
+		if err := c.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	expectRateLimited(t, c.Call(nil, "test_null"))
+
+	// The nftest namespace is configured to be unlimited.
+	for i := 0; i < 5; i++ {
+		var out int
+		if err := c.Call(&out, "nftest_echo", i); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	// In-process clients are never limited.
+	inproc := DialInProc(s)
+	defer inproc.Close()
+	for i := 0; i < 5; i++ {
+		if err := inproc.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("in-process call %d: %v", i, err)
+		}
+	}
+}
+
+// Tests that rate limited HTTP requests are answered with status 429 and carry a
+// Retry-After header, unless they are batches.
+func TestRateLimitRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	s := newRateLimitedServer()
+	defer s.Stop()
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	post := func() (*http.Response, *jsonrpcMessage) {
+		body := `{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}`
+		resp, err := http.Post(ts.URL, contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var msg jsonrpcMessage
+		if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+			t.Fatal(err)
+		}
+		return resp, &msg
+	}
+	resp, msg := post()
+	if msg.Error != nil || resp.Header.Get("Retry-After") != "" {
+		t.Fatalf("first request limited: error %v, Retry-After %q", msg.Error, resp.Header.Get("Retry-After"))
+	}
+	resp, msg = post()
+	if msg.Error == nil || msg.Error.Code != ErrcodeRateLimited {
+		t.Fatalf("expected rate limit error, got %v", msg.Error)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("wrong status code: have %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+	if have := resp.Header.Get("Retry-After"); have != "100" {
+		t.Fatalf("wrong Retry-After header: have %q, want %q", have, "100")
+	}
+	// Batches can't be limited as a whole, the limited calls only carry errors
+	body := `[{"jsonrpc":"2.0","id":1,"method":"test_echo","params":["x",1]}]`
+	resp, err := http.Post(ts.URL, contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Retry-After") != "" {
+		t.Fatalf("batch limited as a whole: status %d, Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+}
+
+// Tests that clients with distinct identities are limited independently.
+func TestRateLimitClientIdentity(t *testing.T) {
+	t.Parallel()
+
+	s := newRateLimitedServer()
+	defer s.Stop()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("X-Api-Key"); key != "" {
+			r = r.WithContext(WithClientIdentity(r.Context(), "key:"+key))
+		}
+		s.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	for _, key := range []string{"alice", "bob"} {
+		c, err := Dial(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SetHeader("X-Api-Key", key)
+
+		var info PeerInfo
+		if err := c.Call(&info, "test_peerInfo"); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		if info.Identity != "key:"+key {
+			t.Errorf("%s: wrong identity %q", key, info.Identity)
+		}
+		if err := c.Call(nil, "test_noArgsRets"); err != nil {
+			t.Fatalf("%s: %v", key, err)
+		}
+		expectRateLimited(t, c.Call(nil, "test_noArgsRets"))
+		c.Close()
+	}
+}
@@ -18,6 +18,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net"
@@ -54,6 +55,10 @@ type Server struct {
 	batchItemLimit     int
 	batchResponseLimit int
 	httpBodyLimit      int
+	rateLimiter        *rateLimiter
+	methodFilter       *methodFilter
+	authorizer         Authorizer
+	responseCache      ResponseCache
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -89,6 +94,71 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// SetRateLimits sets the per-client rate limits of the server. Clients are told
+// apart by their authenticated identity (see WithClientIdentity) if any, or by
+// their IP address otherwise. In-process and IPC clients are never limited.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetRateLimits(config RateLimitConfig) {
+	if !config.Enabled() {
+		s.rateLimiter = nil
+		return
+	}
+	s.rateLimiter = newRateLimiter(config)
+}
+
+// SetMethodFilter sets the method allow and deny rules of the server, which are
+// evaluated before dispatching any call. Calls to filtered methods fail as if
+// the method did not exist.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetMethodFilter(filter MethodFilter) {
+	if !filter.Enabled() {
+		s.methodFilter = nil
+		return
+	}
+	s.methodFilter = newMethodFilter(filter)
+}
+
+// Authorizer decides whether individual calls may be dispatched, e.g. based on
+// the client identity contained in the PeerInfo of the call context.
+type Authorizer interface {
+	// Authorize is invoked before dispatching every method call. A non-nil error
+	// rejects the call and is returned to the client. The method is given by its
+	// canonical namespace_method name, i.e. rpc.discover as rpc_discover.
+	Authorize(ctx context.Context, method string) error
+}
+
+// SetAuthorizer sets the authorizer consulted before dispatching any call. It is
+// consulted after the method filter, but before the rate limits.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetAuthorizer(authorizer Authorizer) {
+	s.authorizer = authorizer
+}
+
+// ResponseCache stores the results of calls which are known to be deterministic,
+// allowing them to be served without invoking the method again.
+type ResponseCache interface {
+	// Get returns the cached result of a call with the given parameters, if any.
+	Get(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, bool)
+
+	// Put offers the result of a successful call for caching. The cache decides
+	// whether the call is deterministic and the result may be stored.
+	Put(ctx context.Context, method string, params json.RawMessage, result json.RawMessage)
+}
+
+// SetResponseCache sets the cache consulted before dispatching any call.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetResponseCache(cache ResponseCache) {
+	s.responseCache = cache
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -114,6 +184,10 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		rateLimiter:        s.rateLimiter,
+		methodFilter:       s.methodFilter,
+		authorizer:         s.authorizer,
+		responseCache:      s.responseCache,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -147,10 +221,6 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
-	h.allowSubscribe = false
-	defer h.close(io.EOF, nil)
-
 	reqs, batch, err := codec.readBatch()
 	if err != nil {
 		if msg := messageForReadError(err); msg != "" {
@@ -159,6 +229,15 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		}
 		return
 	}
+	// The transport can only signal a back-off for the request as a whole, not
+	// for individual calls of a batch.
+	if batch {
+		ctx = withoutRetryAfterHook(ctx)
+	}
+	h := s.newServerHandler(ctx, codec)
+	h.allowSubscribe = false
+	defer h.close(io.EOF, nil)
+
 	if batch {
 		h.handleBatch(reqs)
 	} else {
@@ -166,6 +245,17 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 	}
 }
 
+// newServerHandler creates a handler for serving requests read outside of the
+// connection handling of ServeCodec.
+func (s *Server) newServerHandler(ctx context.Context, codec ServerCodec) *handler {
+	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
+	h.rateLimiter = s.rateLimiter
+	h.methodFilter = s.methodFilter
+	h.authorizer = s.authorizer
+	h.responseCache = s.responseCache
+	return h
+}
+
 func messageForReadError(err error) string {
 	var netErr net.Error
 	if errors.As(err, &netErr) {
@@ -220,12 +310,16 @@ func (s *RPCService) Modules() map[string]string {
 // the current method call.
 type PeerInfo struct {
 	// Transport is name of the protocol used by the client.
-	// This can be "http", "ws" or "ipc".
+	// This can be "http", "sse", "ws" or "ipc".
 	Transport string
 
 	// Address of client. This will usually contain the IP address and port.
 	RemoteAddr string
 
+	// Authenticated identity of the client, e.g. a JWT subject or an API key,
+	// as attached to the request by WithClientIdentity. Empty if unknown.
+	Identity string
+
 	// Additional information for HTTP and WebSocket connections.
 	HTTP struct {
 		// Protocol version, i.e. "HTTP/1.1". This is not set for WebSocket.
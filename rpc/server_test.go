@@ -19,6 +19,8 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
 	"net"
 	"os"
@@ -202,3 +204,52 @@ func TestServerBatchResponseSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+// mapResponseCache caches the results of all calls.
+type mapResponseCache struct {
+	results map[string]json.RawMessage
+	puts    int
+}
+
+func (c *mapResponseCache) Get(ctx context.Context, method string, params json.RawMessage) (json.RawMessage, bool) {
+	result, ok := c.results[method+string(params)]
+	return result, ok
+}
+
+func (c *mapResponseCache) Put(ctx context.Context, method string, params json.RawMessage, result json.RawMessage) {
+	c.puts++
+	c.results[method+string(params)] = result
+}
+
+// Tests that cached responses are served without invoking the method.
+func TestServerResponseCache(t *testing.T) {
+	t.Parallel()
+
+	cache := &mapResponseCache{results: make(map[string]json.RawMessage)}
+	server := newTestServer()
+	server.SetResponseCache(cache)
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		var res echoResult
+		if err := client.Call(&res, "test_echo", "x", 1); err != nil {
+			t.Fatal(err)
+		}
+		if res.String != "x" || res.Int != 1 {
+			t.Fatalf("wrong result: %+v", res)
+		}
+	}
+	if cache.puts != 1 {
+		t.Fatalf("wrong number of cache insertions: have %d, want 1", cache.puts)
+	}
+	// Failed calls must not be cached
+	if err := client.Call(nil, "test_returnError"); err == nil {
+		t.Fatal("expected error")
+	}
+	if cache.puts != 1 {
+		t.Fatalf("failed call cached")
+	}
+}
@@ -38,6 +38,7 @@ var (
 type serviceRegistry struct {
 	mu       sync.Mutex
 	services map[string]service
+	docs     map[string]MethodDoc // OpenRPC annotations by method name
 }
 
 // service represents a registered object.
@@ -93,7 +94,7 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 
 // callback returns the callback corresponding to the given RPC method name.
 func (r *serviceRegistry) callback(method string) *callback {
-	before, after, found := strings.Cut(method, serviceMethodSeparator)
+	before, after, found := strings.Cut(canonicalMethod(method), serviceMethodSeparator)
 	if !found {
 		return nil
 	}
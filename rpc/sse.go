@@ -0,0 +1,487 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	sseContentType       = "text/event-stream"
+	sseLastEventIDHeader = "Last-Event-ID"
+	sseKeepaliveInterval = 30 * time.Second
+	sseReconnectAttempts = 3
+	sseReconnectDelay    = time.Second
+)
+
+var errSSEStreamClosed = errors.New("event stream closed")
+
+type resumeBlockKey struct{}
+
+// ResumeBlockFromContext returns the number of the last block delivered to the
+// client, if the subscription being created resumes an earlier one. Subscriptions
+// supporting resumption should first deliver the events of all later blocks.
+func ResumeBlockFromContext(ctx context.Context) (uint64, bool) {
+	number, ok := ctx.Value(resumeBlockKey{}).(uint64)
+	return number, ok
+}
+
+// IsSSERequest reports whether the HTTP request asks for a server-sent event stream.
+func IsSSERequest(r *http.Request) bool {
+	return r.Method != http.MethodOptions && strings.Contains(strings.ToLower(r.Header.Get("Accept")), sseContentType)
+}
+
+// SSEHandler returns a handler that serves subscriptions as server-sent events.
+//
+// Every stream carries a single subscription, requested either by a GET request
+// with the method and params query parameters, or by a POST request carrying the
+// JSON-RPC call. The first event holds the response to the call and the following
+// ones its notifications. Closing the stream ends the subscription.
+//
+// Notifications carrying a block, such as headers and logs, are tagged with the
+// block number as event ID. Clients reconnecting with the Last-Event-ID header
+// resume the subscription after that block, see ResumeBlockFromContext.
+func (s *Server) SSEHandler() http.Handler {
+	return http.HandlerFunc(s.serveSSE)
+}
+
+func (s *Server) serveSSE(w http.ResponseWriter, r *http.Request) {
+	msg, code, err := s.readSSERequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	connInfo := PeerInfo{Transport: "sse", RemoteAddr: r.RemoteAddr}
+	connInfo.HTTP.Version = r.Proto
+	connInfo.HTTP.Host = r.Host
+	connInfo.HTTP.Origin = r.Header.Get("Origin")
+	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.Identity = clientIdentityFromContext(r.Context())
+	ctx := context.WithValue(r.Context(), peerInfoContextKey{}, connInfo)
+	if id := r.Header.Get(sseLastEventIDHeader); id != "" {
+		if number, err := strconv.ParseUint(id, 10, 64); err == nil {
+			ctx = context.WithValue(ctx, resumeBlockKey{}, number)
+		}
+	}
+	codec := newSSECodec(w, connInfo)
+	if !s.trackCodec(codec) {
+		http.Error(w, "server stopped", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.untrackCodec(codec)
+
+	// The stream outlives the write timeout of the HTTP server.
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set("content-type", sseContentType)
+	w.Header().Set("cache-control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	codec.flush()
+
+	h := s.newServerHandler(ctx, codec)
+	h.handleMsg(msg)
+
+	keepalive := time.NewTicker(sseKeepaliveInterval)
+	defer keepalive.Stop()
+	for done := false; !done; {
+		select {
+		case <-keepalive.C:
+			codec.ping()
+		case <-codec.closed():
+			done = true
+		case <-r.Context().Done():
+			done = true
+		}
+	}
+	h.close(io.EOF, nil)
+	codec.shutdown()
+}
+
+// readSSERequest extracts the subscription request of an event stream.
+func (s *Server) readSSERequest(r *http.Request) (*jsonrpcMessage, int, error) {
+	msg := new(jsonrpcMessage)
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		msg.Version, msg.ID, msg.Method = vsn, json.RawMessage("1"), query.Get("method")
+		if params := query.Get("params"); params != "" {
+			msg.Params = json.RawMessage(params)
+		}
+	case http.MethodPost:
+		if code, err := s.validateRequest(r); err != nil {
+			return nil, code, err
+		}
+		dec := json.NewDecoder(io.LimitReader(r.Body, int64(s.httpBodyLimit)))
+		dec.UseNumber()
+		if err := dec.Decode(msg); err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		if msg.ID == nil {
+			msg.ID = json.RawMessage("1")
+		}
+	default:
+		return nil, http.StatusMethodNotAllowed, errors.New("method not allowed")
+	}
+	if !msg.isCall() || !msg.isSubscribe() {
+		return nil, http.StatusBadRequest, errors.New("event streams only serve subscriptions")
+	}
+	return msg, 0, nil
+}
+
+// sseCodec writes the messages of a subscription as server-sent events. It does
+// not read any messages, the subscription request is handled separately.
+type sseCodec struct {
+	w    http.ResponseWriter
+	rc   *http.ResponseController
+	info PeerInfo
+
+	mu        sync.Mutex // serializes writes
+	closeOnce sync.Once
+	closeCh   chan interface{}
+}
+
+func newSSECodec(w http.ResponseWriter, info PeerInfo) *sseCodec {
+	return &sseCodec{w: w, rc: http.NewResponseController(w), info: info, closeCh: make(chan interface{})}
+}
+
+func (c *sseCodec) peerInfo() PeerInfo {
+	return c.info
+}
+
+func (c *sseCodec) remoteAddr() string {
+	return c.info.RemoteAddr
+}
+
+func (c *sseCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	<-c.closeCh
+	return nil, false, io.EOF
+}
+
+func (c *sseCodec) close() {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+}
+
+// shutdown closes the stream, waiting for pending writes. The response writer
+// is not used anymore afterwards.
+func (c *sseCodec) shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.close()
+}
+
+func (c *sseCodec) closed() <-chan interface{} {
+	return c.closeCh
+}
+
+// writeJSON sends a message as an event. The stream is closed after an error
+// response, as there is nothing left to deliver.
+func (c *sseCodec) writeJSON(ctx context.Context, v interface{}, isError bool) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var event bytes.Buffer
+	if _, ok := v.(*jsonrpcSubscriptionNotification); ok {
+		if number, ok := sseEventID(data); ok {
+			fmt.Fprintf(&event, "id: %d\n", number)
+		}
+	}
+	event.WriteString("data: ")
+	event.Write(data)
+	event.WriteString("\n\n")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.closeCh:
+		return errSSEStreamClosed
+	default:
+	}
+	if _, err = c.w.Write(event.Bytes()); err == nil {
+		err = c.rc.Flush()
+	}
+	if resp, ok := v.(*jsonrpcMessage); err != nil || isError || (ok && resp.Error != nil) {
+		c.close()
+	}
+	return err
+}
+
+// ping sends a comment to keep idle connections from being cut by proxies.
+func (c *sseCodec) ping() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := io.WriteString(c.w, ": ping\n\n"); err != nil {
+		c.close()
+		return
+	}
+	c.rc.Flush()
+}
+
+func (c *sseCodec) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rc.Flush()
+}
+
+// sseEventID returns the number of the block carried by an encoded notification,
+// which is the number of a header or the block number of a log or transaction.
+func sseEventID(notification []byte) (uint64, bool) {
+	var msg struct {
+		Params struct {
+			Result struct {
+				Number      *hexutil.Uint64 `json:"number"`
+				BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+			} `json:"result"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(notification, &msg); err != nil {
+		return 0, false
+	}
+	switch result := msg.Params.Result; {
+	case result.BlockNumber != nil:
+		return uint64(*result.BlockNumber), true
+	case result.Number != nil:
+		return uint64(*result.Number), true
+	}
+	return 0, false
+}
+
+// sseStream is the client side of a subscription over server-sent events. Streams
+// which are cut are reopened, resuming after the last received event.
+type sseStream struct {
+	hc     *httpConn
+	msg    *jsonrpcMessage
+	ctx    context.Context // cancelled when the stream is closed
+	cancel context.CancelFunc
+
+	body   io.ReadCloser
+	reader *bufio.Reader
+	lastID string
+}
+
+// subscribeSSE establishes a subscription on an HTTP connection.
+func (c *Client) subscribeSSE(ctx context.Context, namespace string, channel reflect.Value, args ...interface{}) (*ClientSubscription, error) {
+	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
+	if err != nil {
+		return nil, err
+	}
+	stream := &sseStream{hc: c.writeConn.(*httpConn), msg: msg}
+	stream.ctx, stream.cancel = context.WithCancel(context.Background())
+
+	subid, err := stream.open(ctx)
+	if err != nil {
+		stream.release()
+		return nil, err
+	}
+	sub := newClientSubscription(c, namespace, channel)
+	sub.subid = subid
+	sub.stream = stream
+	go sub.run()
+	go stream.forward(sub)
+	return sub, nil
+}
+
+// open sends the subscription request and waits for its response, which is the
+// first event of the stream.
+func (s *sseStream) open(ctx context.Context) (string, error) {
+	body, err := json.Marshal(s.msg)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.hc.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	s.hc.mu.Lock()
+	req.Header = s.hc.headers.Clone()
+	s.hc.mu.Unlock()
+	setHeaders(req.Header, headersFromContext(ctx))
+	req.Header.Set("accept", sseContentType)
+	if s.lastID != "" {
+		req.Header.Set(sseLastEventIDHeader, s.lastID)
+	}
+	if s.hc.auth != nil {
+		if err := s.hc.auth(req.Header); err != nil {
+			return "", err
+		}
+	}
+	// Abort when the caller gives up before the subscription is established.
+	stop := context.AfterFunc(ctx, s.cancel)
+	defer stop()
+
+	resp, err := s.hc.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return "", HTTPError{Status: resp.Status, StatusCode: resp.StatusCode, Body: body}
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("content-type")); mt != sseContentType {
+		resp.Body.Close()
+		return "", ErrNotificationsUnsupported
+	}
+	if s.body != nil {
+		s.body.Close()
+	}
+	s.body, s.reader = resp.Body, bufio.NewReader(resp.Body)
+
+	data, err := s.readEvent()
+	if err != nil {
+		return "", err
+	}
+	var answer jsonrpcMessage
+	if err := json.Unmarshal(data, &answer); err != nil {
+		return "", err
+	}
+	if answer.Error != nil {
+		return "", answer.Error
+	}
+	var subid string
+	if err := json.Unmarshal(answer.Result, &subid); err != nil {
+		return "", err
+	}
+	return subid, nil
+}
+
+// readEvent reads the data of the next event from the stream, skipping comments
+// and tracking the event ID.
+func (s *sseStream) readEvent() ([]byte, error) {
+	var data []byte
+	for {
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if data != nil {
+				return data, nil
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			s.lastID = value
+		case "data":
+			if data != nil {
+				data = append(data, '\n')
+			}
+			data = append(data, value...)
+		}
+	}
+}
+
+// forward delivers the notifications of the stream to the subscription until
+// either side is closed, reopening the stream if it is cut.
+func (s *sseStream) forward(sub *ClientSubscription) {
+	defer s.release()
+
+	go func() {
+		select {
+		case <-s.hc.closed():
+			s.cancel()
+		case <-s.ctx.Done():
+		}
+	}()
+	for {
+		data, err := s.readEvent()
+		if err != nil {
+			if err = s.reopen(); err != nil {
+				sub.close(err)
+				return
+			}
+			continue
+		}
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(data, &msg); err != nil || !msg.isNotification() {
+			continue
+		}
+		var result subscriptionResult
+		if err := json.Unmarshal(msg.Params, &result); err != nil {
+			log.Debug("Dropping invalid subscription message", "err", err)
+			continue
+		}
+		if !sub.deliver(result.Result) {
+			return
+		}
+	}
+}
+
+// reopen re-establishes a cut stream, resuming after the last received event.
+func (s *sseStream) reopen() error {
+	var err error
+	for attempt := 0; attempt < sseReconnectAttempts; attempt++ {
+		if s.ctx.Err() != nil {
+			break
+		}
+		if _, err = s.open(s.ctx); err == nil {
+			return nil
+		}
+		var rpcErr Error
+		if errors.As(err, &rpcErr) {
+			return err // rejected by the server, retrying won't help
+		}
+		select {
+		case <-time.After(sseReconnectDelay << attempt):
+		case <-s.ctx.Done():
+		}
+	}
+	select {
+	case <-s.hc.closed():
+		return ErrClientQuit
+	default:
+	}
+	if s.ctx.Err() != nil {
+		return errSSEStreamClosed
+	}
+	return err
+}
+
+// close ends the stream, which also ends the subscription on the server.
+func (s *sseStream) close() {
+	s.cancel()
+}
+
+// release frees the resources of the stream. It must only be called by the
+// goroutine reading the stream.
+func (s *sseStream) release() {
+	s.cancel()
+	if s.body != nil {
+		s.body.Close()
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// blockTestService notifies about consecutive blocks, starting after the resumed
+// block if there is one.
+type blockTestService struct{}
+
+type testBlock struct {
+	Number hexutil.Uint64 `json:"number"`
+}
+
+func (s *blockTestService) Blocks(ctx context.Context, count int) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	next, _ := ResumeBlockFromContext(ctx)
+	subscription := notifier.CreateSubscription()
+	go func() {
+		for i := 0; i < count; i++ {
+			next++
+			if err := notifier.Notify(subscription.ID, testBlock{hexutil.Uint64(next)}); err != nil {
+				return
+			}
+		}
+	}()
+	return subscription, nil
+}
+
+func TestSSESubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		srv     = NewServer()
+		service = &notificationTestService{unsubscribed: make(chan string, 1)}
+	)
+	srv.RegisterName("nftest", service)
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv.SSEHandler())
+	defer httpsrv.Close()
+
+	client, err := DialOptions(context.Background(), httpsrv.URL, WithSSESubscriptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if !client.SupportsSubscriptions() {
+		t.Fatal("client doesn't support subscriptions")
+	}
+
+	nc := make(chan int)
+	sub, err := client.Subscribe(context.Background(), "nftest", nc, "someSubscription", 3, 1)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	for i := 1; i <= 3; i++ {
+		select {
+		case v := <-nc:
+			if v != i {
+				t.Fatalf("wrong notification: have %d, want %d", v, i)
+			}
+		case err := <-sub.Err():
+			t.Fatal("subscription error:", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for notification")
+		}
+	}
+	// Closing the stream ends the subscription on the server.
+	sub.Unsubscribe()
+	select {
+	case <-service.unsubscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not ended on the server")
+	}
+}
+
+func TestSSERequest(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer()
+	srv.RegisterName("block", new(blockTestService))
+	defer srv.Stop()
+	httpsrv := httptest.NewServer(srv.SSEHandler())
+	defer httpsrv.Close()
+
+	// Plain calls are rejected.
+	resp, err := http.Get(httpsrv.URL + "?method=block_blocks&params=[1]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("wrong status for plain call: %v", resp.Status)
+	}
+
+	// Subscriptions resume after the block given as last event ID.
+	query := url.Values{"method": {"block_subscribe"}, "params": {`["blocks",1]`}}
+	req, _ := http.NewRequest(http.MethodGet, httpsrv.URL+"?"+query.Encode(), nil)
+	req.Header.Set("accept", "text/event-stream")
+	req.Header.Set("last-event-id", "41")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("content-type"); ct != "text/event-stream" {
+		t.Fatalf("wrong content type %q", ct)
+	}
+	var (
+		events []string
+		reader = bufio.NewReader(resp.Body)
+	)
+	for len(events) < 3 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal("can't read stream:", err)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			events = append(events, line)
+		}
+	}
+	if !strings.HasPrefix(events[0], `data: {"jsonrpc":"2.0","id":1,"result":"0x`) {
+		t.Errorf("wrong response event: %s", events[0])
+	}
+	if events[1] != "id: 42" {
+		t.Errorf("wrong event ID: %s", events[1])
+	}
+	if !strings.HasSuffix(events[2], `"result":{"number":"0x2a"}}}`) {
+		t.Errorf("wrong notification event: %s", events[2])
+	}
+}
+
+// cuttingWriter fails the writes of a response after the given number of events.
+type cuttingWriter struct {
+	http.ResponseWriter
+	events int
+}
+
+func (w *cuttingWriter) Write(b []byte) (int, error) {
+	if w.events == 0 {
+		return 0, errors.New("stream cut")
+	}
+	w.events--
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cuttingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func TestSSEResume(t *testing.T) {
+	t.Parallel()
+
+	srv := NewServer()
+	srv.RegisterName("block", new(blockTestService))
+	defer srv.Stop()
+
+	// Cut the first stream after the response and two notifications.
+	var requests atomic.Int32
+	handler := srv.SSEHandler()
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w = &cuttingWriter{ResponseWriter: w, events: 3}
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer httpsrv.Close()
+
+	client, err := DialOptions(context.Background(), httpsrv.URL, WithSSESubscriptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ch := make(chan testBlock)
+	sub, err := client.Subscribe(context.Background(), "block", ch, "blocks", 5)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	defer sub.Unsubscribe()
+	for i := 1; i <= 5; i++ {
+		select {
+		case b := <-ch:
+			if uint64(b.Number) != uint64(i) {
+				t.Fatalf("wrong block: have %d, want %d", b.Number, i)
+			}
+		case err := <-sub.Err():
+			t.Fatal("subscription error:", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for block", i)
+		}
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("wrong number of requests: %d", n)
+	}
+}
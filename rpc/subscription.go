@@ -208,6 +208,7 @@ type ClientSubscription struct {
 	channel   reflect.Value
 	namespace string
 	subid     string
+	stream    *sseStream // set for subscriptions over server-sent events
 
 	// The in channel receives notification values from client dispatcher.
 	in chan json.RawMessage
@@ -370,6 +371,10 @@ func (sub *ClientSubscription) unmarshal(result json.RawMessage) (interface{}, e
 }
 
 func (sub *ClientSubscription) requestUnsubscribe() error {
+	if sub.stream != nil {
+		sub.stream.close()
+		return nil
+	}
 	var result interface{}
 	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
 	defer cancel()
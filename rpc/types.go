@@ -35,6 +35,10 @@ type API struct {
 	Service       interface{} // receiver instance which holds the methods
 	Public        bool        // deprecated - this field is no longer used, but retained for compatibility
 	Authenticated bool        // whether the api should only be available behind authentication.
+
+	// Docs annotates the methods of Service in the document served by rpc.discover,
+	// keyed by full method name. See Server.SetMethodDocs.
+	Docs map[string]MethodDoc
 }
 
 // ServerCodec implements reading, parsing and writing RPC messages for the server side of
@@ -243,6 +243,19 @@ func (db *Database) Reference(root common.Hash, parent common.Hash) error {
 	return nil
 }
 
+// SetWriteHook registers a callback which is invoked with the hash of every
+// trie node persisted into the disk database, or removes it if nil is given.
+//
+// It's only supported by hash-based database and will return an error for others.
+func (db *Database) SetWriteHook(hook func(hash common.Hash)) error {
+	hdb, ok := db.backend.(*hashdb.Database)
+	if !ok {
+		return errors.New("not supported")
+	}
+	hdb.SetWriteHook(hook)
+	return nil
+}
+
 // Dereference removes an existing reference from a root node. It's only
 // supported by hash-based database and will return an error for others.
 func (db *Database) Dereference(root common.Hash) error {
@@ -277,6 +290,17 @@ func (db *Database) Recoverable(root common.Hash) (bool, error) {
 	return pdb.Recoverable(root), nil
 }
 
+// HistoricReader constructs a reader for accessing the historical state with the
+// given root, resolved from the indexed state histories. It's only supported by
+// path-based database and will return an error for others.
+func (db *Database) HistoricReader(root common.Hash) (*pathdb.HistoricalStateReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.HistoricReader(root)
+}
+
 // Disable deactivates the database and invalidates all available state layers
 // as stale to prevent access to the persistent state, which is in the syncing
 // stage.
@@ -95,6 +95,8 @@ type Database struct {
 	dirtiesSize  common.StorageSize // Storage size of the dirty node cache (exc. metadata)
 	childrenSize common.StorageSize // Storage size of the external children tracking
 
+	onWrite func(hash common.Hash) // Callback invoked for every node flushed to disk
+
 	lock sync.RWMutex
 }
 
@@ -343,6 +345,9 @@ func (db *Database) Cap(limit common.StorageSize) error {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.dirties[oldest]
 		rawdb.WriteLegacyTrieNode(batch, oldest, node.node)
+		if db.onWrite != nil {
+			db.onWrite(oldest)
+		}
 
 		// If we exceeded the ideal batch size, commit and reset
 		if batch.ValueSize() >= ethdb.IdealBatchSize {
@@ -394,6 +399,16 @@ func (db *Database) Cap(limit common.StorageSize) error {
 	return nil
 }
 
+// SetWriteHook registers a callback which is invoked with the hash of every
+// trie node flushed from the dirty cache into the disk database. Nil can be
+// passed to remove the hook.
+func (db *Database) SetWriteHook(hook func(hash common.Hash)) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.onWrite = hook
+}
+
 // Commit iterates over all the children of a particular node, writes them out
 // to disk, forcefully tearing down all references in both directions. As a side
 // effect, all pre-images accumulated up to this point are also written.
@@ -466,6 +481,9 @@ func (db *Database) commit(hash common.Hash, batch ethdb.Batch, uncacher *cleane
 	}
 	// If we've reached an optimal batch size, commit and start over
 	rawdb.WriteLegacyTrieNode(batch, hash, node.node)
+	if db.onWrite != nil {
+		db.onWrite(hash)
+	}
 	if batch.ValueSize() >= ethdb.IdealBatchSize {
 		if err := batch.Write(); err != nil {
 			return err
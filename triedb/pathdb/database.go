@@ -110,10 +110,11 @@ type layer interface {
 
 // Config contains the settings for database.
 type Config struct {
-	StateHistory    uint64 // Number of recent blocks to maintain state history for
-	CleanCacheSize  int    // Maximum memory allowance (in bytes) for caching clean nodes
-	WriteBufferSize int    // Maximum memory allowance (in bytes) for write buffer
-	ReadOnly        bool   // Flag whether the database is opened in read only mode.
+	StateHistory        uint64 // Number of recent blocks to maintain state history for
+	EnableStateIndexing bool   // Whether to index state history for historical state access
+	CleanCacheSize      int    // Maximum memory allowance (in bytes) for caching clean nodes
+	WriteBufferSize     int    // Maximum memory allowance (in bytes) for write buffer
+	ReadOnly            bool   // Flag whether the database is opened in read only mode.
 }
 
 // sanitize checks the provided user configurations and changes anything that's
@@ -136,6 +137,9 @@ func (c *Config) fields() []interface{} {
 	list = append(list, "cache", common.StorageSize(c.CleanCacheSize))
 	list = append(list, "buffer", common.StorageSize(c.WriteBufferSize))
 	list = append(list, "history", c.StateHistory)
+	if c.EnableStateIndexing {
+		list = append(list, "index", true)
+	}
 	return list
 }
 
@@ -197,6 +201,7 @@ type Database struct {
 	diskdb  ethdb.Database               // Persistent storage for matured trie nodes
 	tree    *layerTree                   // The group for all known layers
 	freezer ethdb.ResettableAncientStore // Freezer for storing trie histories, nil possible in tests
+	indexer *historyIndexer              // Indexer of state histories, nil if indexing is disabled
 	lock    sync.RWMutex                 // Lock to prevent mutations from happening at the same time
 }
 
@@ -283,11 +288,21 @@ func (db *Database) repairHistory() error {
 			}
 			log.Info("Truncated extraneous state history")
 		}
+	}
+	// Start indexing the state histories if enabled, or drop the leftover index
+	// otherwise.
+	if db.config.EnableStateIndexing && !db.isVerkle {
+		db.indexer = newHistoryIndexer(db.diskdb, db.freezer, db.readOnly)
+	} else if !db.readOnly && rawdb.ReadStateHistoryIndexHead(db.diskdb) != nil {
+		purgeHistoryIndex(db.diskdb)
+		log.Info("Dropped state history index")
+	}
+	if id == 0 {
 		return nil
 	}
 	// Truncate the extra state histories above in freezer in case it's not
 	// aligned with the disk layer. It might happen after a unclean shutdown.
-	pruned, err := truncateFromHead(db.diskdb, db.freezer, id)
+	pruned, err := db.truncateHistoryHead(id)
 	if err != nil {
 		log.Crit("Failed to truncate extra state histories", "err", err)
 	}
@@ -399,6 +414,12 @@ func (db *Database) Enable(root common.Hash) error {
 	// mappings can be huge and might take a while to clear
 	// them, just leave them in disk and wait for overwriting.
 	if db.freezer != nil {
+		if db.indexer != nil {
+			db.indexer.lock.Lock()
+			defer db.indexer.lock.Unlock()
+
+			db.indexer.reset()
+		}
 		if err := db.freezer.Reset(); err != nil {
 			return err
 		}
@@ -454,7 +475,7 @@ func (db *Database) Recover(root common.Hash) error {
 		db.tree.reset(dl)
 	}
 	rawdb.DeleteTrieJournal(db.diskdb)
-	_, err := truncateFromHead(db.diskdb, db.freezer, dl.stateID())
+	_, err := db.truncateHistoryHead(dl.stateID())
 	if err != nil {
 		return err
 	}
@@ -512,9 +533,40 @@ func (db *Database) Close() error {
 	if db.freezer == nil {
 		return nil
 	}
+	if db.indexer != nil {
+		db.indexer.close()
+	}
 	return db.freezer.Close()
 }
 
+// truncateHistoryHead removes the state histories above the given id, along
+// with their index.
+func (db *Database) truncateHistoryHead(nhead uint64) (int, error) {
+	if db.indexer != nil {
+		db.indexer.lock.Lock()
+		defer db.indexer.lock.Unlock()
+
+		if err := db.indexer.truncateHead(nhead); err != nil {
+			return 0, err
+		}
+	}
+	return truncateFromHead(db.diskdb, db.freezer, nhead)
+}
+
+// truncateHistoryTail removes the state histories up to and including the given
+// id, along with their index.
+func (db *Database) truncateHistoryTail(ntail uint64) (int, error) {
+	if db.indexer != nil {
+		db.indexer.lock.Lock()
+		defer db.indexer.lock.Unlock()
+
+		if err := db.indexer.truncateTail(ntail); err != nil {
+			return 0, err
+		}
+	}
+	return truncateFromTail(db.diskdb, db.freezer, ntail)
+}
+
 // Size returns the current storage size of the memory cache in front of the
 // persistent database layer.
 func (db *Database) Size() (diffs common.StorageSize, nodes common.StorageSize) {
@@ -125,9 +125,10 @@ func newTester(t *testing.T, historyLimit uint64, isVerkle bool, layers int) *te
 	var (
 		disk, _ = rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
 		db      = New(disk, &Config{
-			StateHistory:    historyLimit,
-			CleanCacheSize:  256 * 1024,
-			WriteBufferSize: 256 * 1024,
+			StateHistory:        historyLimit,
+			CleanCacheSize:      256 * 1024,
+			WriteBufferSize:     256 * 1024,
+			EnableStateIndexing: true,
 		}, isVerkle)
 
 		obj = &tester{
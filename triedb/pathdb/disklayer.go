@@ -275,10 +275,13 @@ func (dl *diskLayer) commit(bottom *diffLayer, force bool) (*diskLayer, error) {
 	}
 	ndl := newDiskLayer(bottom.root, bottom.stateID(), dl.db, dl.nodes, combined)
 
+	if ndl.db.indexer != nil {
+		ndl.db.indexer.notify()
+	}
 	// To remove outdated history objects from the end, we set the 'tail' parameter
 	// to 'oldest-1' due to the offset between the freezer index and the history ID.
 	if overflow {
-		pruned, err := truncateFromTail(ndl.db.diskdb, ndl.db.freezer, oldest-1)
+		pruned, err := ndl.db.truncateHistoryTail(oldest - 1)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,254 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// The state history index records, for each account and storage slot, the ids
+// of the state histories in which the element was modified. The value of an
+// element at state n can then be resolved from the first state history above n
+// which modifies it, since state histories store the original values, or from
+// the current state if there is no such history.
+//
+// The ids of an element are stored in blocks of a fixed number of entries. Full
+// blocks are keyed by the last id they contain, the last block of an element,
+// which is still being extended, is keyed by the maximum id. The block which
+// may contain the first id above n is thus the first block whose key is above n.
+//
+//   +------------------+     +------------------+     +------------------+
+//   | key: 4092        |---->| key: 8710        |---->| key: 2^64-1      |
+//   | ids: 1, 5, ...   |     | ids: 4097, ...   |     | ids: 8711, ...   |
+//   +------------------+     +------------------+     +------------------+
+
+const (
+	indexBlockEntries = 4096           // Number of ids in a full index block
+	openIndexBlock    = math.MaxUint64 // Key of the index block being extended
+)
+
+// stateIdent identifies an account or a storage slot in the state history index.
+type stateIdent struct {
+	account common.Hash // Hash of the account address
+	storage common.Hash // Hash of the storage slot key, for storage slots
+	slot    bool        // Whether the element is a storage slot
+}
+
+func accountIdent(account common.Hash) stateIdent {
+	return stateIdent{account: account}
+}
+
+func storageIdent(account common.Hash, storage common.Hash) stateIdent {
+	return stateIdent{account: account, storage: storage, slot: true}
+}
+
+// encodeIndexBlock packs a sorted list of ids as deltas.
+func encodeIndexBlock(ids []uint64) []byte {
+	var (
+		buf  = make([]byte, 0, len(ids)*2)
+		prev uint64
+	)
+	for _, id := range ids {
+		buf = binary.AppendUvarint(buf, id-prev)
+		prev = id
+	}
+	return buf
+}
+
+// decodeIndexBlock unpacks a list of ids.
+func decodeIndexBlock(blob []byte) ([]uint64, error) {
+	var (
+		ids  []uint64
+		prev uint64
+	)
+	for len(blob) > 0 {
+		delta, n := binary.Uvarint(blob)
+		if n <= 0 {
+			return nil, errors.New("corrupted state history index block")
+		}
+		if delta == 0 && len(ids) > 0 {
+			return nil, errors.New("unordered state history index block")
+		}
+		prev += delta
+		ids = append(ids, prev)
+		blob = blob[n:]
+	}
+	return ids, nil
+}
+
+// seekIndexBlock retrieves the first index block of the element whose key is not
+// lower than the given id. Nil is returned if there is no such block.
+func seekIndexBlock(db ethdb.Iteratee, ident stateIdent, id uint64) (uint64, []uint64, error) {
+	var (
+		last uint64
+		blob []byte
+	)
+	if ident.slot {
+		last, blob = rawdb.SeekStorageHistoryIndexBlock(db, ident.account, ident.storage, id)
+	} else {
+		last, blob = rawdb.SeekAccountHistoryIndexBlock(db, ident.account, id)
+	}
+	if blob == nil {
+		return 0, nil, nil
+	}
+	ids, err := decodeIndexBlock(blob)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(ids) == 0 || (last != openIndexBlock && ids[len(ids)-1] != last) {
+		return 0, nil, fmt.Errorf("invalid state history index block %d", last)
+	}
+	return last, ids, nil
+}
+
+func writeIndexBlock(db ethdb.KeyValueWriter, ident stateIdent, last uint64, ids []uint64) {
+	if ident.slot {
+		rawdb.WriteStorageHistoryIndexBlock(db, ident.account, ident.storage, last, encodeIndexBlock(ids))
+	} else {
+		rawdb.WriteAccountHistoryIndexBlock(db, ident.account, last, encodeIndexBlock(ids))
+	}
+}
+
+func deleteIndexBlock(db ethdb.KeyValueWriter, ident stateIdent, last uint64) {
+	if ident.slot {
+		rawdb.DeleteStorageHistoryIndexBlock(db, ident.account, ident.storage, last)
+	} else {
+		rawdb.DeleteAccountHistoryIndexBlock(db, ident.account, last)
+	}
+}
+
+// lookupIndex returns the first id above the given one in which the element was
+// modified, and whether there is such an id in the index.
+func lookupIndex(db ethdb.Iteratee, ident stateIdent, id uint64) (uint64, bool, error) {
+	_, ids, err := seekIndexBlock(db, ident, id+1)
+	if err != nil || ids == nil {
+		return 0, false, err
+	}
+	n := sort.Search(len(ids), func(i int) bool { return ids[i] > id })
+	if n == len(ids) {
+		return 0, false, nil
+	}
+	return ids[n], true, nil
+}
+
+// appendIndex appends the given ids, which must be above all indexed ones, to
+// the index of the element.
+func appendIndex(db ethdb.Iteratee, batch ethdb.KeyValueWriter, ident stateIdent, ids []uint64) error {
+	_, open, err := seekIndexBlock(db, ident, openIndexBlock)
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 && open[len(open)-1] >= ids[0] {
+		return fmt.Errorf("state history %d already indexed", ids[0])
+	}
+	ids = append(open, ids...)
+	for len(ids) >= indexBlockEntries {
+		writeIndexBlock(batch, ident, ids[indexBlockEntries-1], ids[:indexBlockEntries])
+		ids = ids[indexBlockEntries:]
+	}
+	switch {
+	case len(ids) > 0:
+		writeIndexBlock(batch, ident, openIndexBlock, ids)
+	case open != nil:
+		deleteIndexBlock(batch, ident, openIndexBlock)
+	}
+	return nil
+}
+
+// truncateIndexHead removes all ids above the given one from the index of the
+// element.
+func truncateIndexHead(db ethdb.Iteratee, batch ethdb.KeyValueWriter, ident stateIdent, nhead uint64) error {
+	var kept []uint64
+	for seek := nhead + 1; ; {
+		last, ids, err := seekIndexBlock(db, ident, seek)
+		if err != nil {
+			return err
+		}
+		if ids == nil {
+			break
+		}
+		// Only the first block may contain ids to keep, all following ones
+		// are entirely above the new head.
+		if seek == nhead+1 {
+			kept = ids[:sort.Search(len(ids), func(i int) bool { return ids[i] > nhead })]
+		}
+		deleteIndexBlock(batch, ident, last)
+		if last == openIndexBlock {
+			break
+		}
+		seek = last + 1
+	}
+	// The remaining ids form the block being extended.
+	if len(kept) > 0 {
+		writeIndexBlock(batch, ident, openIndexBlock, kept)
+	}
+	return nil
+}
+
+// truncateIndexTail removes all ids up to and including the given one from the
+// index of the element.
+func truncateIndexTail(db ethdb.Iteratee, batch ethdb.KeyValueWriter, ident stateIdent, ntail uint64) error {
+	for seek := uint64(0); ; {
+		last, ids, err := seekIndexBlock(db, ident, seek)
+		if err != nil || ids == nil {
+			return err
+		}
+		n := sort.Search(len(ids), func(i int) bool { return ids[i] > ntail })
+		switch {
+		case n == 0:
+			return nil
+		case n < len(ids):
+			writeIndexBlock(batch, ident, last, ids[n:])
+			return nil
+		}
+		deleteIndexBlock(batch, ident, last)
+		if last == openIndexBlock {
+			return nil
+		}
+		seek = last + 1
+	}
+}
+
+// historyIdents returns the identifiers of all elements modified in the history.
+func historyIdents(h *history) []stateIdent {
+	var (
+		buff   = crypto.NewKeccakState()
+		idents = make([]stateIdent, 0, len(h.accountList))
+	)
+	for _, addr := range h.accountList {
+		addrHash := crypto.HashData(buff, addr.Bytes())
+		idents = append(idents, accountIdent(addrHash))
+
+		for _, slot := range h.storageList[addr] {
+			slotHash := slot
+			if h.meta.version != stateHistoryV0 {
+				slotHash = crypto.HashData(buff, slot.Bytes())
+			}
+			idents = append(idents, storageIdent(addrHash, slotHash))
+		}
+	}
+	return idents
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/testrand"
+)
+
+func TestIndexBlockEncoding(t *testing.T) {
+	ids := []uint64{1, 2, 5, 300, 70000, 1 << 40}
+	dec, err := decodeIndexBlock(encodeIndexBlock(ids))
+	if err != nil {
+		t.Fatalf("Failed to decode index block: %v", err)
+	}
+	if !reflect.DeepEqual(dec, ids) {
+		t.Fatalf("Unexpected ids, want: %v, got: %v", ids, dec)
+	}
+	if _, err := decodeIndexBlock([]byte{0x1, 0x0}); err == nil {
+		t.Fatal("Expected error for unordered block")
+	}
+	if _, err := decodeIndexBlock([]byte{0x80}); err == nil {
+		t.Fatal("Expected error for truncated block")
+	}
+}
+
+// indexedIDs returns all ids in the index of the element.
+func indexedIDs(t *testing.T, db ethdb.Iteratee, ident stateIdent) []uint64 {
+	var all []uint64
+	for seek := uint64(0); ; {
+		last, ids, err := seekIndexBlock(db, ident, seek)
+		if err != nil {
+			t.Fatalf("Failed to read index block: %v", err)
+		}
+		if ids == nil {
+			return all
+		}
+		if len(ids) > indexBlockEntries {
+			t.Fatalf("Oversized index block %d: %d", last, len(ids))
+		}
+		all = append(all, ids...)
+		if last == openIndexBlock {
+			return all
+		}
+		seek = last + 1
+	}
+}
+
+func TestIndexAppendTruncate(t *testing.T) {
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		ident = storageIdent(testrand.Hash(), testrand.Hash())
+		other = accountIdent(ident.account)
+		ids   []uint64
+	)
+	// Append ids in batches, spanning a few full blocks
+	for n := uint64(1); n <= 7*indexBlockEntries; n += 1000 {
+		var batch []uint64
+		for id := n; id < n+1000; id += 2 {
+			batch = append(batch, id)
+		}
+		b := db.NewBatch()
+		if err := appendIndex(db, b, ident, batch); err != nil {
+			t.Fatalf("Failed to append index: %v", err)
+		}
+		b.Write()
+		ids = append(ids, batch...)
+	}
+	if err := appendIndex(db, db.NewBatch(), ident, []uint64{ids[len(ids)-1]}); err == nil {
+		t.Fatal("Expected error for re-indexed history")
+	}
+	if got := indexedIDs(t, db, ident); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("Unexpected index, want %d ids, got %d", len(ids), len(got))
+	}
+	if got := indexedIDs(t, db, other); got != nil {
+		t.Fatalf("Unexpected index of unrelated element: %v", got)
+	}
+	// Lookup the first id above the given one
+	for _, id := range []uint64{0, 1, 2, 8191, 8192, ids[len(ids)-2]} {
+		want := ids[0]
+		for _, n := range ids {
+			if n > id {
+				want = n
+				break
+			}
+		}
+		got, found, err := lookupIndex(db, ident, id)
+		if err != nil || !found || got != want {
+			t.Fatalf("Unexpected lookup for %d, want %d, got %d (%t, %v)", id, want, got, found, err)
+		}
+	}
+	if _, found, _ := lookupIndex(db, ident, ids[len(ids)-1]); found {
+		t.Fatal("Unexpected id above the last one")
+	}
+	// Truncate the head and tail, crossing the block boundaries
+	nhead := ids[2*indexBlockEntries+10]
+	b := db.NewBatch()
+	if err := truncateIndexHead(db, b, ident, nhead); err != nil {
+		t.Fatalf("Failed to truncate head: %v", err)
+	}
+	b.Write()
+	ids = ids[:2*indexBlockEntries+11]
+	if got := indexedIDs(t, db, ident); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("Unexpected index after head truncation, want %d ids, got %d", len(ids), len(got))
+	}
+	ntail := ids[indexBlockEntries+5]
+	b = db.NewBatch()
+	if err := truncateIndexTail(db, b, ident, ntail); err != nil {
+		t.Fatalf("Failed to truncate tail: %v", err)
+	}
+	b.Write()
+	ids = ids[indexBlockEntries+6:]
+	if got := indexedIDs(t, db, ident); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("Unexpected index after tail truncation, want %d ids, got %d", len(ids), len(got))
+	}
+	// Appending after truncation must extend the open block
+	b = db.NewBatch()
+	if err := appendIndex(db, b, ident, []uint64{nhead + 1}); err != nil {
+		t.Fatalf("Failed to append index: %v", err)
+	}
+	b.Write()
+	ids = append(ids, nhead+1)
+	if got := indexedIDs(t, db, ident); !reflect.DeepEqual(got, ids) {
+		t.Fatalf("Unexpected index after append, want %d ids, got %d", len(ids), len(got))
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// indexBatchSize is the maximum number of state histories indexed at once.
+const indexBatchSize = 64
+
+// historyIndexer maintains the state history index in the background. New state
+// histories are indexed after they are written to the freezer, the index of the
+// removed ones is dropped before they are truncated from the freezer.
+type historyIndexer struct {
+	disk    ethdb.KeyValueStore
+	freezer ethdb.AncientReader
+
+	lock sync.RWMutex // Lock protecting the index against concurrent mutations
+	head uint64       // The id of the last indexed state history
+
+	wake    chan struct{}
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newHistoryIndexer constructs the state history indexer, and starts indexing
+// the state histories not yet indexed unless the database is read only.
+func newHistoryIndexer(disk ethdb.KeyValueStore, freezer ethdb.AncientReader, readOnly bool) *historyIndexer {
+	i := &historyIndexer{
+		disk:    disk,
+		freezer: freezer,
+		wake:    make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+	}
+	if head := rawdb.ReadStateHistoryIndexHead(disk); head != nil {
+		i.head = *head
+	}
+	if readOnly {
+		return i
+	}
+	// Drop the index if it's not aligned with the freezer, which might happen
+	// if the state histories were removed externally.
+	if frozen, err := freezer.Ancients(); err == nil && i.head > frozen {
+		log.Warn("Dropping unaligned state history index", "indexed", i.head, "histories", frozen)
+		i.reset()
+	}
+	i.wg.Add(1)
+	go i.loop()
+	i.notify()
+	return i
+}
+
+// notify signals the indexer that new state histories are available.
+func (i *historyIndexer) notify() {
+	select {
+	case i.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close terminates the background indexing.
+func (i *historyIndexer) close() {
+	select {
+	case <-i.closeCh:
+	default:
+		close(i.closeCh)
+	}
+	i.wg.Wait()
+}
+
+func (i *historyIndexer) loop() {
+	defer i.wg.Done()
+
+	var (
+		start  time.Time
+		logged time.Time
+		done   uint64
+	)
+	for {
+		select {
+		case <-i.wake:
+		case <-i.closeCh:
+			return
+		}
+		for {
+			more, indexed, err := i.indexBatch()
+			if err != nil {
+				log.Error("Failed to index state history", "err", err)
+				break
+			}
+			if indexed > 0 && done == 0 {
+				start, logged = time.Now(), time.Now()
+			}
+			done += indexed
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Indexing state history", "indexed", done, "head", i.indexedHead(), "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+			if !more {
+				break
+			}
+			select {
+			case <-i.closeCh:
+				return
+			default:
+			}
+		}
+		if done > indexBatchSize {
+			log.Info("Indexed state history", "indexed", done, "head", i.indexedHead(), "elapsed", common.PrettyDuration(time.Since(start)))
+		}
+		done = 0
+	}
+}
+
+// indexedHead returns the id of the last indexed state history.
+func (i *historyIndexer) indexedHead() uint64 {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.head
+}
+
+// indexRange returns the range of state histories to index next.
+func (i *historyIndexer) indexRange() (uint64, uint64, error) {
+	tail, err := i.freezer.Tail()
+	if err != nil {
+		return 0, 0, err
+	}
+	head, err := i.freezer.Ancients()
+	if err != nil {
+		return 0, 0, err
+	}
+	return max(i.head, tail) + 1, head, nil
+}
+
+// indexBatch indexes the next batch of state histories, returning whether
+// there are more to index.
+func (i *historyIndexer) indexBatch() (bool, uint64, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	first, last, err := i.indexRange()
+	if err != nil || first > last {
+		return false, 0, err
+	}
+	if last-first+1 > indexBatchSize {
+		last = first + indexBatchSize - 1
+	}
+	var (
+		idents []stateIdent
+		ids    = make(map[stateIdent][]uint64)
+	)
+	for id := first; id <= last; id++ {
+		h, err := readHistory(i.freezer, id)
+		if err != nil {
+			return false, 0, err
+		}
+		for _, ident := range historyIdents(h) {
+			if _, ok := ids[ident]; !ok {
+				idents = append(idents, ident)
+			}
+			ids[ident] = append(ids[ident], id)
+		}
+	}
+	batch := i.disk.NewBatch()
+	for _, ident := range idents {
+		if err := appendIndex(i.disk, batch, ident, ids[ident]); err != nil {
+			return false, 0, err
+		}
+	}
+	rawdb.WriteStateHistoryIndexHead(batch, last)
+	if err := batch.Write(); err != nil {
+		return false, 0, err
+	}
+	i.head = last
+
+	_, head, err := i.indexRange()
+	if err != nil {
+		return false, 0, err
+	}
+	return last < head, last - first + 1, nil
+}
+
+// truncateHead drops the index of the state histories above the given id. It
+// must be called before the state histories are removed from the freezer, with
+// the lock held until then to prevent them from being indexed again.
+func (i *historyIndexer) truncateHead(nhead uint64) error {
+	if i.head <= nhead {
+		return nil
+	}
+	tail, err := i.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	idents := make(map[stateIdent]struct{})
+	for id := max(nhead, tail) + 1; id <= i.head; id++ {
+		h, err := readHistory(i.freezer, id)
+		if err != nil {
+			return err
+		}
+		for _, ident := range historyIdents(h) {
+			idents[ident] = struct{}{}
+		}
+	}
+	batch := i.disk.NewBatch()
+	for ident := range idents {
+		if err := truncateIndexHead(i.disk, batch, ident, nhead); err != nil {
+			return err
+		}
+	}
+	rawdb.WriteStateHistoryIndexHead(batch, nhead)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	i.head = nhead
+	return nil
+}
+
+// truncateTail drops the index of the state histories up to and including the
+// given id. It must be called before the state histories are removed from the
+// freezer, with the lock held until then.
+func (i *historyIndexer) truncateTail(ntail uint64) error {
+	tail, err := i.freezer.Tail()
+	if err != nil {
+		return err
+	}
+	idents := make(map[stateIdent]struct{})
+	for id := tail + 1; id <= min(ntail, i.head); id++ {
+		h, err := readHistory(i.freezer, id)
+		if err != nil {
+			return err
+		}
+		for _, ident := range historyIdents(h) {
+			idents[ident] = struct{}{}
+		}
+	}
+	if len(idents) == 0 {
+		return nil
+	}
+	batch := i.disk.NewBatch()
+	for ident := range idents {
+		if err := truncateIndexTail(i.disk, batch, ident, ntail); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// reset drops the entire state history index. The lock must be held if the
+// indexer is running.
+func (i *historyIndexer) reset() {
+	purgeHistoryIndex(i.disk)
+	i.head = 0
+}
+
+// purgeHistoryIndex removes all state history index data from the database.
+func purgeHistoryIndex(disk ethdb.KeyValueStore) {
+	batch := disk.NewBatch()
+	for _, prefix := range [][]byte{rawdb.StateHistoryAccountIndexPrefix, rawdb.StateHistoryStorageIndexPrefix} {
+		it := disk.NewIterator(prefix, nil)
+		for it.Next() {
+			batch.Delete(it.Key())
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					log.Crit("Failed to purge state history index", "err", err)
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+	}
+	rawdb.DeleteStateHistoryIndexHead(batch)
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to purge state history index", "err", err)
+	}
+}
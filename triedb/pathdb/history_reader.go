@@ -0,0 +1,306 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb/database"
+)
+
+// maxUnindexedHistories is the maximum number of state histories not yet indexed
+// which are searched when reading historical state.
+const maxUnindexedHistories = 128
+
+var (
+	// errStateIndexDisabled is returned if historical state is requested while
+	// the state histories are not indexed.
+	errStateIndexDisabled = errors.New("state history index is disabled")
+
+	// errStateIndexing is returned if historical state is requested while the
+	// state histories are still being indexed.
+	errStateIndexing = errors.New("state history is being indexed")
+)
+
+// readHistoryAccount retrieves the original value of the account before the
+// state transition of the given state history, and whether the account was
+// modified in the transition at all.
+func readHistoryAccount(reader ethdb.AncientReader, id uint64, address common.Address) ([]byte, bool, error) {
+	index, found, err := findHistoryAccount(reader, id, address)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	data := rawdb.ReadStateAccountHistory(reader, id)
+	last := index.offset + uint32(index.length)
+	if uint32(len(data)) < last {
+		return nil, false, fmt.Errorf("account data of state history %d is corrupted", id)
+	}
+	return data[index.offset:last], true, nil
+}
+
+// readHistoryStorage retrieves the original value of the storage slot before the
+// state transition of the given state history, and whether the slot was modified
+// in the transition at all.
+func readHistoryStorage(reader ethdb.AncientReader, id uint64, address common.Address, key common.Hash, keyHash common.Hash) ([]byte, bool, error) {
+	var m meta
+	if err := m.decode(rawdb.ReadStateHistoryMeta(reader, id)); err != nil {
+		return nil, false, err
+	}
+	index, found, err := findHistoryAccount(reader, id, address)
+	if err != nil || !found {
+		return nil, false, err
+	}
+	slot := key
+	if m.version == stateHistoryV0 {
+		slot = keyHash
+	}
+	var (
+		indexes = rawdb.ReadStateStorageIndex(reader, id)
+		start   = int(index.storageOffset)
+		end     = start + int(index.storageSlots)
+	)
+	if end*slotIndexSize > len(indexes) {
+		return nil, false, fmt.Errorf("storage index of state history %d is corrupted", id)
+	}
+	pos := start + sort.Search(end-start, func(i int) bool {
+		offset := (start + i) * slotIndexSize
+		return bytes.Compare(indexes[offset:offset+common.HashLength], slot.Bytes()) >= 0
+	})
+	if pos == end {
+		return nil, false, nil
+	}
+	var slotIdx slotIndex
+	slotIdx.decode(indexes[pos*slotIndexSize : (pos+1)*slotIndexSize])
+	if slotIdx.id != slot {
+		return nil, false, nil
+	}
+	data := rawdb.ReadStateStorageHistory(reader, id)
+	last := slotIdx.offset + uint32(slotIdx.length)
+	if uint32(len(data)) < last {
+		return nil, false, fmt.Errorf("storage data of state history %d is corrupted", id)
+	}
+	return data[slotIdx.offset:last], true, nil
+}
+
+// findHistoryAccount searches the account in the sorted account index of the
+// given state history.
+func findHistoryAccount(reader ethdb.AncientReader, id uint64, address common.Address) (accountIndex, bool, error) {
+	indexes := rawdb.ReadStateAccountIndex(reader, id)
+	if len(indexes)%accountIndexSize != 0 || len(indexes) == 0 {
+		return accountIndex{}, false, fmt.Errorf("account index of state history %d is corrupted", id)
+	}
+	count := len(indexes) / accountIndexSize
+	pos := sort.Search(count, func(i int) bool {
+		offset := i * accountIndexSize
+		return bytes.Compare(indexes[offset:offset+common.AddressLength], address.Bytes()) >= 0
+	})
+	if pos == count {
+		return accountIndex{}, false, nil
+	}
+	var index accountIndex
+	index.decode(indexes[pos*accountIndexSize : (pos+1)*accountIndexSize])
+	return index, index.address == address, nil
+}
+
+// layerDatabase exposes a single layer as trie node database.
+type layerDatabase struct {
+	layer layer
+}
+
+// NodeReader implements database.NodeDatabase, returning a reader of the layer.
+func (db layerDatabase) NodeReader(root common.Hash) (database.NodeReader, error) {
+	return &reader{layer: db.layer}, nil
+}
+
+// HistoricalStateReader provides access to the state at a block whose state is
+// no longer maintained, by applying the original values stored in the state
+// histories to the current persistent state.
+type HistoricalStateReader struct {
+	db   *Database
+	id   uint64
+	root common.Hash
+}
+
+// HistoricReader constructs a reader for accessing the state with the given root,
+// if it's covered by the indexed state histories.
+func (db *Database) HistoricReader(root common.Hash) (*HistoricalStateReader, error) {
+	if db.indexer == nil || db.freezer == nil {
+		return nil, errStateIndexDisabled
+	}
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	tail, err := db.freezer.Tail()
+	if err != nil {
+		return nil, err
+	}
+	if *id < tail {
+		return nil, fmt.Errorf("state %#x is not available, history pruned", root)
+	}
+	if *id > db.tree.bottom().stateID() {
+		return nil, fmt.Errorf("state %#x is not historical", root)
+	}
+	return &HistoricalStateReader{db: db, id: *id, root: root}, nil
+}
+
+// AccountRLP retrieves the account associated with the given address in the slim
+// data format. An empty value is returned if the account was not existent.
+func (r *HistoricalStateReader) AccountRLP(address common.Address) ([]byte, error) {
+	ident := accountIdent(crypto.Keccak256Hash(address.Bytes()))
+	blob, err := r.read(ident, func(id uint64) ([]byte, bool, error) {
+		return readHistoryAccount(r.db.freezer, id, address)
+	}, func(db layerDatabase, root common.Hash) ([]byte, error) {
+		tr, err := trie.New(trie.StateTrieID(root), db)
+		if err != nil {
+			return nil, err
+		}
+		return tr.Get(ident.account.Bytes())
+	})
+	if err != nil || len(blob) == 0 {
+		return nil, err
+	}
+	// Normalize the account from the trie, which is not in the slim format.
+	account, err := types.FullAccount(blob)
+	if err != nil {
+		return nil, err
+	}
+	return types.SlimAccountRLP(*account), nil
+}
+
+// Account retrieves the account associated with the given address in the slim
+// data format. Nil is returned if the account was not existent.
+func (r *HistoricalStateReader) Account(address common.Address) (*types.SlimAccount, error) {
+	blob, err := r.AccountRLP(address)
+	if err != nil || len(blob) == 0 {
+		return nil, err
+	}
+	account := new(types.SlimAccount)
+	if err := rlp.DecodeBytes(blob, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// Storage retrieves the RLP-encoded value of the storage slot with the given raw
+// key. An empty value is returned if the slot was not existent.
+func (r *HistoricalStateReader) Storage(address common.Address, key common.Hash) ([]byte, error) {
+	var (
+		addrHash = crypto.Keccak256Hash(address.Bytes())
+		keyHash  = crypto.Keccak256Hash(key.Bytes())
+		ident    = storageIdent(addrHash, keyHash)
+	)
+	return r.read(ident, func(id uint64) ([]byte, bool, error) {
+		return readHistoryStorage(r.db.freezer, id, address, key, keyHash)
+	}, func(db layerDatabase, root common.Hash) ([]byte, error) {
+		tr, err := trie.New(trie.StateTrieID(root), db)
+		if err != nil {
+			return nil, err
+		}
+		blob, err := tr.Get(addrHash.Bytes())
+		if err != nil || len(blob) == 0 {
+			return nil, err
+		}
+		account, err := types.FullAccount(blob)
+		if err != nil {
+			return nil, err
+		}
+		if account.Root == types.EmptyRootHash {
+			return nil, nil
+		}
+		st, err := trie.New(trie.StorageTrieID(root, addrHash, account.Root), db)
+		if err != nil {
+			return nil, err
+		}
+		return st.Get(keyHash.Bytes())
+	})
+}
+
+// read resolves the value of a state element at the reader's state. The value is
+// the original one stored in the first state history above the state which
+// modifies the element, or the current persistent value if there is no such
+// history.
+func (r *HistoricalStateReader) read(ident stateIdent, fromHistory func(id uint64) ([]byte, bool, error), fromDisk func(db layerDatabase, root common.Hash) ([]byte, error)) ([]byte, error) {
+	for {
+		dl := r.db.tree.bottom()
+		blob, found, err := r.readHistory(ident, fromHistory)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return blob, nil
+		}
+		// The element was not modified since, resolve it from the persistent
+		// state. Retry if the disk layer was changed meanwhile.
+		blob, err = fromDisk(layerDatabase{dl}, dl.rootHash())
+		if errors.Is(err, errSnapshotStale) {
+			continue
+		}
+		return blob, err
+	}
+}
+
+// readHistory resolves the value of a state element from the first state history
+// above the reader's state which modifies it.
+func (r *HistoricalStateReader) readHistory(ident stateIdent, fromHistory func(id uint64) ([]byte, bool, error)) ([]byte, bool, error) {
+	indexer := r.db.indexer
+	indexer.lock.RLock()
+	defer indexer.lock.RUnlock()
+
+	// Ensure the state wasn't reverted or pruned meanwhile.
+	tail, err := r.db.freezer.Tail()
+	if err != nil {
+		return nil, false, err
+	}
+	head, err := r.db.freezer.Ancients()
+	if err != nil {
+		return nil, false, err
+	}
+	if r.id < tail || r.id > head {
+		return nil, false, fmt.Errorf("state %#x is not available", r.root)
+	}
+	if head-max(indexer.head, r.id) > maxUnindexedHistories {
+		return nil, false, errStateIndexing
+	}
+	// Search the index first, then the state histories not yet indexed.
+	if r.id < indexer.head {
+		id, found, err := lookupIndex(r.db.diskdb, ident, r.id)
+		if err != nil {
+			return nil, false, err
+		}
+		if found {
+			return fromHistory(id)
+		}
+	}
+	for id := max(indexer.head, r.id) + 1; id <= head; id++ {
+		blob, found, err := fromHistory(id)
+		if err != nil || found {
+			return blob, found, err
+		}
+	}
+	return nil, false, nil
+}
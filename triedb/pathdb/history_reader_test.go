@@ -0,0 +1,116 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pathdb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// waitIndexing blocks until all state histories are indexed.
+func waitIndexing(t *testing.T, db *Database) {
+	t.Helper()
+
+	head, err := db.freezer.Ancients()
+	if err != nil {
+		t.Fatalf("Failed to retrieve freezer head: %v", err)
+	}
+	for i := 0; db.indexer.indexedHead() != head; i++ {
+		if i > 500 {
+			t.Fatalf("State histories are not indexed, indexed: %d, head: %d", db.indexer.indexedHead(), head)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func checkHistoricalState(t *testing.T, env *tester, indexed bool) {
+	t.Helper()
+
+	if indexed {
+		waitIndexing(t, env.db)
+	}
+	bottom := env.bottomIndex()
+	for i, root := range env.roots[:bottom] {
+		reader, err := env.db.HistoricReader(root)
+		if err != nil {
+			t.Fatalf("Failed to open historical reader %d: %v", i, err)
+		}
+		accounts, storages := env.snapAccounts[root], env.snapStorages[root]
+		for addrHash, want := range accounts {
+			got, err := reader.AccountRLP(env.accountPreimage(addrHash))
+			if err != nil {
+				t.Fatalf("Failed to read account: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("Account %x mismatch at state %d, want %x, got %x", addrHash, i, want, got)
+			}
+			for slotHash, want := range storages[addrHash] {
+				got, err := reader.Storage(env.accountPreimage(addrHash), env.hashPreimage(slotHash))
+				if err != nil {
+					t.Fatalf("Failed to read storage: %v", err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Fatalf("Slot %x mismatch at state %d, want %x, got %x", slotHash, i, want, got)
+				}
+			}
+		}
+		// Accounts created afterwards must not exist in the historical state
+		for addrHash := range env.accounts {
+			if _, ok := accounts[addrHash]; ok {
+				continue
+			}
+			got, err := reader.AccountRLP(env.accountPreimage(addrHash))
+			if err != nil {
+				t.Fatalf("Failed to read account: %v", err)
+			}
+			if len(got) != 0 {
+				t.Fatalf("Unexpected account %x at state %d", addrHash, i)
+			}
+		}
+	}
+}
+
+func TestHistoricalStateReader(t *testing.T) {
+	// Redefine the diff layer depth allowance for faster testing.
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	tester := newTester(t, 0, false, 12)
+	defer tester.release()
+
+	if err := tester.db.Commit(tester.lastHash(), false); err != nil {
+		t.Fatalf("Failed to commit state: %v", err)
+	}
+	checkHistoricalState(t, tester, true)
+
+	// The persistent state itself is accessible as well
+	if _, err := tester.db.HistoricReader(tester.lastHash()); err != nil {
+		t.Fatalf("Failed to open reader of disk state: %v", err)
+	}
+	// Roll back the states, the index must be truncated accordingly
+	if err := tester.db.Recover(tester.roots[5]); err != nil {
+		t.Fatalf("Failed to recover state: %v", err)
+	}
+	tester.roots = tester.roots[:6]
+	if head := tester.db.indexer.indexedHead(); head > 6 {
+		t.Fatalf("Index is not truncated, head: %d", head)
+	}
+	checkHistoricalState(t, tester, true)
+}